@@ -35,7 +35,10 @@ import (
 
 	"github.com/FerretDB/FerretDB/build/version"
 	"github.com/FerretDB/FerretDB/internal/clientconn"
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
 	"github.com/FerretDB/FerretDB/internal/clientconn/connmetrics"
+	"github.com/FerretDB/FerretDB/internal/clientconn/scheduler"
+	"github.com/FerretDB/FerretDB/internal/clientconn/session"
 	"github.com/FerretDB/FerretDB/internal/handlers/registry"
 	"github.com/FerretDB/FerretDB/internal/util/debug"
 	"github.com/FerretDB/FerretDB/internal/util/debugbuild"
@@ -64,6 +67,8 @@ var cli struct {
 		TLSCertFile string `default:""                help:"TLS cert file path."`
 		TLSKeyFile  string `default:""                help:"TLS key file path."`
 		TLSCAFile   string `default:""                help:"TLS CA file path." name:"tls-ca-file"`
+
+		Extra []string `help:"Additional listener URL like 'tls://host:port?tls-cert-file=...&require-auth=true'; may be repeated."`
 	} `embed:"" prefix:"listen-"`
 
 	ProxyAddr string `default:""                help:"Proxy address."`
@@ -79,6 +84,27 @@ var cli struct {
 
 	MetricsUUID bool `default:"false" help:"Add instance UUID to all metrics." negatable:""`
 
+	Maintenance struct {
+		CompactInterval  time.Duration `default:"1h"   help:"Interval between compactions of frequently modified collections (0 to disable)."`
+		CompactThreshold int64         `default:"1000" help:"Number of modified documents after which collection is compacted."`
+		TTLInterval      time.Duration `default:"60s"  help:"Interval between removals of expired documents by TTL indexes (0 to disable)." name:"ttl-interval"`
+		CursorsInterval  time.Duration `default:"1m"   help:"Interval between removals of idle cursors (0 to disable)."`
+		CursorTimeout    time.Duration `default:"10m"  help:"Idle time after which cursor is removed."`
+		SessionsInterval time.Duration `default:"1m"   help:"Interval between expirations of idle sessions (0 to disable)."`
+	} `embed:"" prefix:"maintenance-"`
+
+	Quota struct {
+		DatabaseSize        int64 `default:"0" help:"Maximum size of a database in bytes (0 for no limit)."`
+		DatabaseDocuments   int64 `default:"0" help:"Maximum number of documents in a database (0 for no limit)."`
+		CollectionSize      int64 `default:"0" help:"Maximum size of a collection in bytes (0 for no limit)."`
+		CollectionDocuments int64 `default:"0" help:"Maximum number of documents in a collection (0 for no limit)."`
+	} `embed:"" prefix:"quota-"`
+
+	Scheduler struct {
+		MaxRunning int `default:"0"    help:"Maximum number of concurrently running operations (0 to disable scheduling)."`
+		MaxQueued  int `default:"1000" help:"Maximum number of queued operations; others are rejected as server busy."`
+	} `embed:"" prefix:"scheduler-"`
+
 	Telemetry telemetry.Flag `default:"undecided" help:"Enable or disable basic telemetry. See https://beacon.ferretdb.io."`
 
 	Test struct {
@@ -125,6 +151,12 @@ var hanaFlags struct {
 	HANAURL string `name:"hana-url" help:"SAP HANA URL for 'hana' handler"`
 }
 
+// The memoryFlags struct represents flags that are used by the "memory" handler.
+// There are none yet.
+//
+// See main_memory.go.
+var memoryFlags struct{}
+
 // handlerFlags is a map of handler names to their flags.
 var handlerFlags = map[string]any{}
 
@@ -322,6 +354,20 @@ func run() {
 		stop()
 	}()
 
+	connRegistry := conninfo.NewRegistry()
+	debug.Handle("/debug/connections", "Client connections and their metadata in JSON format", connRegistry)
+
+	sessions := session.NewRegistry(logger.Named("sessions"))
+
+	var sched *scheduler.Scheduler
+	if cli.Scheduler.MaxRunning > 0 {
+		if cli.Scheduler.MaxQueued < 0 {
+			logger.Sugar().Fatal("--scheduler-max-queued must not be negative.")
+		}
+
+		sched = scheduler.New(cli.Scheduler.MaxRunning, cli.Scheduler.MaxQueued)
+	}
+
 	var wg sync.WaitGroup
 
 	wg.Add(1)
@@ -358,6 +404,8 @@ func run() {
 		Logger:        logger,
 		ConnMetrics:   metrics.ConnMetrics,
 		StateProvider: stateProvider,
+		ConnRegistry:  connRegistry,
+		Sessions:      sessions,
 
 		PostgreSQLURL: postgreSQLFlags.PostgreSQLURL,
 
@@ -365,6 +413,22 @@ func run() {
 
 		HANAURL: hanaFlags.HANAURL,
 
+		MaintenanceOpts: registry.MaintenanceOpts{
+			CompactInterval:  cli.Maintenance.CompactInterval,
+			CompactThreshold: cli.Maintenance.CompactThreshold,
+			TTLInterval:      cli.Maintenance.TTLInterval,
+			CursorsInterval:  cli.Maintenance.CursorsInterval,
+			CursorTimeout:    cli.Maintenance.CursorTimeout,
+			SessionsInterval: cli.Maintenance.SessionsInterval,
+		},
+
+		QuotaOpts: registry.QuotaOpts{
+			DatabaseSize:        cli.Quota.DatabaseSize,
+			DatabaseDocuments:   cli.Quota.DatabaseDocuments,
+			CollectionSize:      cli.Quota.CollectionSize,
+			CollectionDocuments: cli.Quota.CollectionDocuments,
+		},
+
 		TestOpts: registry.TestOpts{
 			DisableFilterPushdown: cli.Test.DisableFilterPushdown,
 			EnableSortPushdown:    cli.Test.EnableSortPushdown,
@@ -378,6 +442,17 @@ func run() {
 		logger.Sugar().Fatalf("Failed to construct handler: %s.", err)
 	}
 
+	listeners := make([]*clientconn.ListenerConfig, len(cli.Listen.Extra))
+	for i, s := range cli.Listen.Extra {
+		if listeners[i], err = clientconn.ParseListenerConfig(s); err != nil {
+			logger.Sugar().Fatal(err)
+		}
+
+		if listeners[i].RequireAuth && !registry.VerifiesCredentials(cli.Handler) {
+			logger.Sugar().Fatalf("Listener %s requires authentication, but %q handler does not verify credentials.", s, cli.Handler)
+		}
+	}
+
 	l := clientconn.NewListener(&clientconn.NewListenerOpts{
 		TCP:         cli.Listen.Addr,
 		Unix:        cli.Listen.Unix,
@@ -385,10 +460,14 @@ func run() {
 		TLSCertFile: cli.Listen.TLSCertFile,
 		TLSKeyFile:  cli.Listen.TLSKeyFile,
 		TLSCAFile:   cli.Listen.TLSCAFile,
+		Listeners:   listeners,
 
 		ProxyAddr:      cli.ProxyAddr,
 		Mode:           clientconn.Mode(cli.Mode),
 		Metrics:        metrics,
+		ConnRegistry:   connRegistry,
+		Sessions:       sessions,
+		Scheduler:      sched,
 		Handler:        h,
 		Logger:         logger,
 		TestRecordsDir: cli.Test.RecordsDir,
@@ -28,7 +28,9 @@ import (
 
 	"github.com/FerretDB/FerretDB/build/version"
 	"github.com/FerretDB/FerretDB/internal/clientconn"
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
 	"github.com/FerretDB/FerretDB/internal/clientconn/connmetrics"
+	"github.com/FerretDB/FerretDB/internal/clientconn/session"
 	"github.com/FerretDB/FerretDB/internal/handlers/registry"
 	"github.com/FerretDB/FerretDB/internal/util/logging"
 	"github.com/FerretDB/FerretDB/internal/util/state"
@@ -38,7 +40,10 @@ import (
 type Config struct {
 	Listener ListenerConfig
 
-	// Handler to use; one of `postgresql` or `sqlite`.
+	// Handler to use; one of `postgresql`, `sqlite`, or `memory`.
+	//
+	// The `memory` handler keeps all data in memory and loses it on shutdown;
+	// it is intended for tests of applications embedding FerretDB.
 	Handler string
 
 	// PostgreSQL connection string for `postgresql` handler.
@@ -110,11 +115,15 @@ func New(config *Config) (*FerretDB, error) {
 	}
 
 	metrics := connmetrics.NewListenerMetrics()
+	connRegistry := conninfo.NewRegistry()
+	sessions := session.NewRegistry(logger.Named("sessions"))
 
 	h, err := registry.NewHandler(config.Handler, &registry.NewHandlerOpts{
 		Logger:        logger,
 		ConnMetrics:   metrics.ConnMetrics,
 		StateProvider: sp,
+		ConnRegistry:  connRegistry,
+		Sessions:      sessions,
 
 		PostgreSQLURL: config.PostgreSQLURL,
 
@@ -132,10 +141,12 @@ func New(config *Config) (*FerretDB, error) {
 		TLSKeyFile:  config.Listener.TLSKeyFile,
 		TLSCAFile:   config.Listener.TLSCAFile,
 
-		Mode:    clientconn.NormalMode,
-		Metrics: metrics,
-		Handler: h,
-		Logger:  logger,
+		Mode:         clientconn.NormalMode,
+		Metrics:      metrics,
+		ConnRegistry: connRegistry,
+		Sessions:     sessions,
+		Handler:      h,
+		Logger:       logger,
 	})
 
 	return &FerretDB{
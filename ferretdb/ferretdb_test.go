@@ -93,6 +93,38 @@ func Example_unix() {
 	// Output: mongodb://%2Ftmp%2Fferretdb.sock/
 }
 
+func Example_memory() {
+	f, err := ferretdb.New(&ferretdb.Config{
+		Listener: ferretdb.ListenerConfig{
+			TCP: "127.0.0.1:17029",
+		},
+		Handler: "memory",
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+
+	go func() {
+		log.Print(f.Run(ctx))
+		close(done)
+	}()
+
+	uri := f.MongoDBURI()
+	fmt.Println(uri)
+
+	// Use MongoDB URI as usual. All data is lost when FerretDB stops,
+	// so that handler is handy for tests.
+
+	cancel()
+	<-done
+
+	// Output: mongodb://127.0.0.1:17029/
+}
+
 func Example_tls() {
 	certPath := filepath.Join("..", "build", "certs", "server-cert.pem")
 	keyPath := filepath.Join("..", "build", "certs", "server-key.pem")
@@ -2089,6 +2089,56 @@ func TestAggregateCompatProjectSum(t *testing.T) {
 	testAggregateStagesCompat(t, testCases)
 }
 
+func TestAggregateCompatProjectConvert(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]aggregateStagesCompatTestCase{
+		"ToBool": {
+			pipeline: bson.A{
+				bson.D{{"$project", bson.D{
+					{"v", bson.D{{"$toBool", "$v"}}},
+				}}},
+			},
+		},
+		"ToLong": {
+			pipeline: bson.A{
+				bson.D{{"$project", bson.D{
+					{"v", bson.D{{"$convert", bson.D{
+						{"input", "$v"},
+						{"to", "long"},
+						{"onError", "error"},
+					}}}},
+				}}},
+			},
+		},
+		"ToObjectID": {
+			pipeline: bson.A{
+				bson.D{{"$project", bson.D{
+					{"v", bson.D{{"$convert", bson.D{
+						{"input", "$v"},
+						{"to", "objectId"},
+						{"onError", "error"},
+						{"onNull", "null"},
+					}}}},
+				}}},
+			},
+		},
+		"ToDateOnErrorField": {
+			pipeline: bson.A{
+				bson.D{{"$project", bson.D{
+					{"v", bson.D{{"$convert", bson.D{
+						{"input", "$v"},
+						{"to", int32(9)},
+						{"onError", "$_id"},
+					}}}},
+				}}},
+			},
+		},
+	}
+
+	testAggregateStagesCompat(t, testCases)
+}
+
 func TestAggregateCompatAddFields(t *testing.T) {
 	t.Parallel()
 
@@ -17,12 +17,14 @@ package integration
 import (
 	"math"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/FerretDB/FerretDB/integration/setup"
 	"github.com/FerretDB/FerretDB/integration/shareddata"
@@ -660,6 +662,1236 @@ func TestAggregateProject(t *testing.T) {
 	}
 }
 
+func TestAggregateProjectConvert(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	oid := primitive.ObjectID{0x62, 0x56, 0xc5, 0xba, 0x0b, 0xad, 0xc0, 0xff, 0xee, 0xff, 0xff, 0xff}
+	date := time.Date(2021, 11, 1, 10, 18, 42, 123000000, time.UTC)
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", "string"}, {"v", "42"}},
+		bson.D{{"_id", "string-double"}, {"v", "42.5"}},
+		bson.D{{"_id", "string-invalid"}, {"v", "foo"}},
+		bson.D{{"_id", "string-object-id"}, {"v", "6256c5ba0badc0ffeeffffff"}},
+		bson.D{{"_id", "string-date"}, {"v", "2021-11-01T10:18:42.123Z"}},
+		bson.D{{"_id", "double"}, {"v", 42.13}},
+		bson.D{{"_id", "double-big"}, {"v", 1e10}},
+		bson.D{{"_id", "int32"}, {"v", int32(42)}},
+		bson.D{{"_id", "int64"}, {"v", int64(42)}},
+		bson.D{{"_id", "bool"}, {"v", true}},
+		bson.D{{"_id", "object-id"}, {"v", oid}},
+		bson.D{{"_id", "date"}, {"v", primitive.NewDateTimeFromTime(date)}},
+		bson.D{{"_id", "null"}, {"v", nil}},
+		bson.D{{"_id", "missing"}},
+	})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct { //nolint:vet // for readability
+		id       string // required, _id of the document to convert
+		operator bson.D // required, conversion operator
+		expected any    // required, expected converted value
+
+		skipForMongoDB string // optional, skip test for MongoDB backend with a specific reason
+	}{
+		"StringToInt": {
+			id:       "string",
+			operator: bson.D{{"$toInt", "$v"}},
+			expected: int32(42),
+		},
+		"StringToLong": {
+			id:       "string",
+			operator: bson.D{{"$toLong", "$v"}},
+			expected: int64(42),
+		},
+		"StringToDouble": {
+			id:       "string-double",
+			operator: bson.D{{"$toDouble", "$v"}},
+			expected: 42.5,
+		},
+		"StringToBool": {
+			id:       "string-invalid",
+			operator: bson.D{{"$toBool", "$v"}},
+			expected: true,
+		},
+		"StringToObjectID": {
+			id:       "string-object-id",
+			operator: bson.D{{"$toObjectId", "$v"}},
+			expected: oid,
+		},
+		"StringToDate": {
+			id:       "string-date",
+			operator: bson.D{{"$toDate", "$v"}},
+			expected: primitive.NewDateTimeFromTime(date),
+		},
+		"DoubleToInt": {
+			id:       "double",
+			operator: bson.D{{"$toInt", "$v"}},
+			expected: int32(42),
+		},
+		"DoubleToString": {
+			id:       "double",
+			operator: bson.D{{"$toString", "$v"}},
+			expected: "42.13",
+		},
+		"IntToDouble": {
+			id:       "int32",
+			operator: bson.D{{"$toDouble", "$v"}},
+			expected: float64(42),
+		},
+		"LongToString": {
+			id:       "int64",
+			operator: bson.D{{"$toString", "$v"}},
+			expected: "42",
+		},
+		"BoolToInt": {
+			id:       "bool",
+			operator: bson.D{{"$toInt", "$v"}},
+			expected: int32(1),
+		},
+		"ObjectIDToString": {
+			id:       "object-id",
+			operator: bson.D{{"$toString", "$v"}},
+			expected: "6256c5ba0badc0ffeeffffff",
+		},
+		"ObjectIDToDate": {
+			id:       "object-id",
+			operator: bson.D{{"$toDate", "$v"}},
+			expected: primitive.NewDateTimeFromTime(time.Unix(0x6256c5ba, 0)),
+		},
+		"DateToLong": {
+			id:       "date",
+			operator: bson.D{{"$toLong", "$v"}},
+			expected: date.UnixMilli(),
+		},
+		"DateToString": {
+			id:       "date",
+			operator: bson.D{{"$toString", "$v"}},
+			expected: "2021-11-01T10:18:42.123Z",
+		},
+		"Null": {
+			id:       "null",
+			operator: bson.D{{"$toInt", "$v"}},
+			expected: nil,
+		},
+		"Missing": {
+			id:       "missing",
+			operator: bson.D{{"$toString", "$v"}},
+			expected: nil,
+		},
+		"ConvertTypeName": {
+			id:       "string",
+			operator: bson.D{{"$convert", bson.D{{"input", "$v"}, {"to", "long"}}}},
+			expected: int64(42),
+		},
+		"ConvertTypeCode": {
+			id:       "string",
+			operator: bson.D{{"$convert", bson.D{{"input", "$v"}, {"to", int32(1)}}}},
+			expected: float64(42),
+		},
+		"ConvertToExpression": {
+			id:       "string",
+			operator: bson.D{{"$convert", bson.D{{"input", "$v"}, {"to", bson.D{{"$type", int32(42)}}}}}},
+			expected: int32(42),
+		},
+		"ConvertToNull": {
+			id:       "string",
+			operator: bson.D{{"$convert", bson.D{{"input", "$v"}, {"to", nil}}}},
+			expected: nil,
+		},
+		"ConvertOnError": {
+			id:       "string-invalid",
+			operator: bson.D{{"$convert", bson.D{{"input", "$v"}, {"to", "int"}, {"onError", "failed"}}}},
+			expected: "failed",
+		},
+		"ConvertOnErrorExpression": {
+			id:       "string-invalid",
+			operator: bson.D{{"$convert", bson.D{{"input", "$v"}, {"to", "int"}, {"onError", "$_id"}}}},
+			expected: "string-invalid",
+		},
+		"ConvertOnErrorOverflow": {
+			id:       "double-big",
+			operator: bson.D{{"$convert", bson.D{{"input", "$v"}, {"to", "int"}, {"onError", int32(-1)}}}},
+			expected: int32(-1),
+		},
+		"ConvertOnErrorUnsupported": {
+			id:       "bool",
+			operator: bson.D{{"$convert", bson.D{{"input", "$v"}, {"to", "objectId"}, {"onError", "unsupported"}}}},
+			expected: "unsupported",
+		},
+		"ConvertOnErrorDecimal": {
+			id:             "int32",
+			operator:       bson.D{{"$convert", bson.D{{"input", "$v"}, {"to", "decimal"}, {"onError", "unsupported"}}}},
+			expected:       "unsupported",
+			skipForMongoDB: "Decimal128 values are not supported by FerretDB",
+		},
+		"ConvertOnNull": {
+			id:       "null",
+			operator: bson.D{{"$convert", bson.D{{"input", "$v"}, {"to", "int"}, {"onNull", int32(0)}}}},
+			expected: int32(0),
+		},
+		"ConvertOnNullMissing": {
+			id:       "missing",
+			operator: bson.D{{"$convert", bson.D{{"input", "$v"}, {"to", "int"}, {"onNull", "none"}}}},
+			expected: "none",
+		},
+		"ConvertOnNullNotUsed": {
+			id:       "string",
+			operator: bson.D{{"$convert", bson.D{{"input", "$v"}, {"to", "int"}, {"onNull", int32(0)}}}},
+			expected: int32(42),
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			if tc.skipForMongoDB != "" {
+				setup.SkipForMongoDB(t, tc.skipForMongoDB)
+			}
+
+			t.Parallel()
+
+			cursor, err := collection.Aggregate(ctx, bson.A{
+				bson.D{{"$match", bson.D{{"_id", tc.id}}}},
+				bson.D{{"$project", bson.D{{"_id", false}, {"v", tc.operator}}}},
+			})
+			require.NoError(t, err)
+
+			res := FetchAll(t, ctx, cursor)
+			require.Len(t, res, 1)
+			assert.Equal(t, bson.D{{"v", tc.expected}}, res[0])
+		})
+	}
+}
+
+func TestAggregateProjectConvertErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", "string"}, {"v", "foo"}},
+		bson.D{{"_id", "double"}, {"v", 1e10}},
+		bson.D{{"_id", "bool"}, {"v", true}},
+	})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct { //nolint:vet // for readability
+		id       string // required, _id of the document to convert
+		operator bson.D // required, conversion operator
+
+		err        *mongo.CommandError // required, expected error from MongoDB
+		altMessage string              // optional, alternative error message for FerretDB, ignored if empty
+	}{
+		"ParseNumber": {
+			id:       "string",
+			operator: bson.D{{"$toInt", "$v"}},
+			err: &mongo.CommandError{
+				Code:    241,
+				Name:    "ConversionFailure",
+				Message: "Failed to parse number 'foo' in $convert with no onError value: Did not consume whole string.",
+			},
+			altMessage: "Failed to parse number 'foo' in $convert with no onError value",
+		},
+		"ParseObjectID": {
+			id:       "string",
+			operator: bson.D{{"$toObjectId", "$v"}},
+			err: &mongo.CommandError{
+				Code: 241,
+				Name: "ConversionFailure",
+				Message: "Failed to parse objectId 'foo' in $convert with no onError value: " +
+					"Invalid string length for parsing to OID, expected 24 but found 3",
+			},
+		},
+		"Overflow": {
+			id:       "double",
+			operator: bson.D{{"$toInt", "$v"}},
+			err: &mongo.CommandError{
+				Code:    241,
+				Name:    "ConversionFailure",
+				Message: "Conversion would overflow target type in $convert with no onError value: 1e+10",
+			},
+		},
+		"Unsupported": {
+			id:       "bool",
+			operator: bson.D{{"$toDate", "$v"}},
+			err: &mongo.CommandError{
+				Code:    241,
+				Name:    "ConversionFailure",
+				Message: "Unsupported conversion from bool to date in $convert with no onError value",
+			},
+		},
+		"UnknownType": {
+			id:       "bool",
+			operator: bson.D{{"$convert", bson.D{{"input", "$v"}, {"to", "foo"}, {"onError", nil}}}},
+			err: &mongo.CommandError{
+				Code:    2,
+				Name:    "BadValue",
+				Message: "Unknown type name: foo",
+			},
+		},
+		"InvalidTypeCode": {
+			id:       "bool",
+			operator: bson.D{{"$convert", bson.D{{"input", "$v"}, {"to", int32(42)}}}},
+			err: &mongo.CommandError{
+				Code:    9,
+				Name:    "FailedToParse",
+				Message: "In $convert, numeric value for 'to' does not correspond to a BSON type: 42",
+			},
+		},
+		"MissingInput": {
+			id:       "bool",
+			operator: bson.D{{"$convert", bson.D{{"to", "int"}}}},
+			err: &mongo.CommandError{
+				Code:    9,
+				Name:    "FailedToParse",
+				Message: "Missing 'input' parameter to $convert",
+			},
+		},
+		"MissingTo": {
+			id:       "bool",
+			operator: bson.D{{"$convert", bson.D{{"input", "$v"}}}},
+			err: &mongo.CommandError{
+				Code:    9,
+				Name:    "FailedToParse",
+				Message: "Missing 'to' parameter to $convert",
+			},
+		},
+		"UnknownArgument": {
+			id:       "bool",
+			operator: bson.D{{"$convert", bson.D{{"input", "$v"}, {"to", "int"}, {"foo", "bar"}}}},
+			err: &mongo.CommandError{
+				Code:    9,
+				Name:    "FailedToParse",
+				Message: "$convert found an unknown argument: foo",
+			},
+		},
+		"NotDocument": {
+			id:       "bool",
+			operator: bson.D{{"$convert", "$v"}},
+			err: &mongo.CommandError{
+				Code:    9,
+				Name:    "FailedToParse",
+				Message: "$convert expects an object of named arguments but found: string",
+			},
+		},
+		"TooManyArgs": {
+			id:       "bool",
+			operator: bson.D{{"$toInt", bson.A{"$v", "$v"}}},
+			err: &mongo.CommandError{
+				Code:    16020,
+				Name:    "Location16020",
+				Message: "Invalid $project :: caused by :: Expression $toInt takes exactly 1 arguments. 2 were passed in.",
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			require.NotNil(t, tc.err, "err must not be nil")
+
+			_, err := collection.Aggregate(ctx, bson.A{
+				bson.D{{"$match", bson.D{{"_id", tc.id}}}},
+				bson.D{{"$project", bson.D{{"v", tc.operator}}}},
+			})
+			AssertEqualAltCommandError(t, *tc.err, tc.altMessage, err)
+		})
+	}
+}
+
+func TestAggregateProjectStringOperators(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertOne(ctx, bson.D{
+		{"_id", "string"},
+		{"v", "  Hello, World!  "},
+		{"csv", "a,b,,c"},
+		{"email", "ferret@example.com, other@example.org"},
+		{"unicode", "café ☕"},
+		{"null", nil},
+	})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct { //nolint:vet // for readability
+		operator bson.D // required, string operator
+		expected any    // required, expected result
+	}{
+		"RegexMatch": {
+			operator: bson.D{{"$regexMatch", bson.D{{"input", "$v"}, {"regex", "world"}, {"options", "i"}}}},
+			expected: true,
+		},
+		"RegexMatchRegex": {
+			operator: bson.D{{"$regexMatch", bson.D{{"input", "$v"}, {"regex", primitive.Regex{Pattern: "^world"}}}}},
+			expected: false,
+		},
+		"RegexMatchNull": {
+			operator: bson.D{{"$regexMatch", bson.D{{"input", "$null"}, {"regex", "a"}}}},
+			expected: false,
+		},
+		"RegexFind": {
+			operator: bson.D{{"$regexFind", bson.D{{"input", "$email"}, {"regex", `(\w+)@(\w+)\.(com|net)`}}}},
+			expected: bson.D{
+				{"match", "ferret@example.com"},
+				{"idx", int32(0)},
+				{"captures", bson.A{"ferret", "example", "com"}},
+			},
+		},
+		"RegexFindCodePointIndex": {
+			operator: bson.D{{"$regexFind", bson.D{{"input", "$unicode"}, {"regex", "☕"}}}},
+			expected: bson.D{{"match", "☕"}, {"idx", int32(5)}, {"captures", bson.A{}}},
+		},
+		"RegexFindUnmatchedCapture": {
+			operator: bson.D{{"$regexFind", bson.D{{"input", "abc"}, {"regex", "(x)?b"}}}},
+			expected: bson.D{{"match", "b"}, {"idx", int32(1)}, {"captures", bson.A{nil}}},
+		},
+		"RegexFindNoMatch": {
+			operator: bson.D{{"$regexFind", bson.D{{"input", "$v"}, {"regex", "foo"}}}},
+			expected: nil,
+		},
+		"RegexFindAll": {
+			operator: bson.D{{"$regexFindAll", bson.D{{"input", "$email"}, {"regex", `@(\w+)`}}}},
+			expected: bson.A{
+				bson.D{{"match", "@example"}, {"idx", int32(6)}, {"captures", bson.A{"example"}}},
+				bson.D{{"match", "@example"}, {"idx", int32(25)}, {"captures", bson.A{"example"}}},
+			},
+		},
+		"RegexFindAllMissing": {
+			operator: bson.D{{"$regexFindAll", bson.D{{"input", "$missing"}, {"regex", "a"}}}},
+			expected: bson.A{},
+		},
+		"Split": {
+			operator: bson.D{{"$split", bson.A{"$csv", ","}}},
+			expected: bson.A{"a", "b", "", "c"},
+		},
+		"SplitNull": {
+			operator: bson.D{{"$split", bson.A{"$missing", ","}}},
+			expected: nil,
+		},
+		"ReplaceOne": {
+			operator: bson.D{{"$replaceOne", bson.D{{"input", "$csv"}, {"find", ","}, {"replacement", ";"}}}},
+			expected: "a;b,,c",
+		},
+		"ReplaceAll": {
+			operator: bson.D{{"$replaceAll", bson.D{{"input", "$csv"}, {"find", ","}, {"replacement", ";"}}}},
+			expected: "a;b;;c",
+		},
+		"ReplaceAllNull": {
+			operator: bson.D{{"$replaceAll", bson.D{{"input", "$csv"}, {"find", nil}, {"replacement", ";"}}}},
+			expected: nil,
+		},
+		"Trim": {
+			operator: bson.D{{"$trim", bson.D{{"input", "$v"}}}},
+			expected: "Hello, World!",
+		},
+		"TrimChars": {
+			operator: bson.D{{"$trim", bson.D{{"input", "$v"}, {"chars", " !H"}}}},
+			expected: "ello, World",
+		},
+		"LTrim": {
+			operator: bson.D{{"$ltrim", bson.D{{"input", "$v"}}}},
+			expected: "Hello, World!  ",
+		},
+		"RTrim": {
+			operator: bson.D{{"$rtrim", bson.D{{"input", "$v"}}}},
+			expected: "  Hello, World!",
+		},
+		"TrimNull": {
+			operator: bson.D{{"$trim", bson.D{{"input", "$null"}}}},
+			expected: nil,
+		},
+		"SubstrCP": {
+			operator: bson.D{{"$substrCP", bson.A{"$unicode", int32(3), int32(3)}}},
+			expected: "é ☕",
+		},
+		"SubstrCPOutOfRange": {
+			operator: bson.D{{"$substrCP", bson.A{"$unicode", int32(10), int32(1)}}},
+			expected: "",
+		},
+		"SubstrCPNumber": {
+			operator: bson.D{{"$substrCP", bson.A{int32(12345), 1.0, int64(2)}}},
+			expected: "23",
+		},
+		"SubstrCPNull": {
+			operator: bson.D{{"$substrCP", bson.A{"$missing", int32(0), int32(1)}}},
+			expected: "",
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cursor, err := collection.Aggregate(ctx, bson.A{
+				bson.D{{"$project", bson.D{{"_id", false}, {"v", tc.operator}}}},
+			})
+			require.NoError(t, err)
+
+			res := FetchAll(t, ctx, cursor)
+			require.Len(t, res, 1)
+			assert.Equal(t, bson.D{{"v", tc.expected}}, res[0])
+		})
+	}
+}
+
+func TestAggregateProjectStringOperatorsErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertOne(ctx, bson.D{{"_id", "string"}, {"v", "foo"}, {"n", int32(42)}})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct { //nolint:vet // for readability
+		operator bson.D // required, string operator
+
+		err        *mongo.CommandError // required, expected error from MongoDB
+		altMessage string              // optional, alternative error message for FerretDB, ignored if empty
+	}{
+		"RegexMatchNotObject": {
+			operator: bson.D{{"$regexMatch", "$v"}},
+			err: &mongo.CommandError{
+				Code:    51103,
+				Name:    "Location51103",
+				Message: "$regexMatch expects an object of named arguments but found: string",
+			},
+		},
+		"RegexMatchMissingRegex": {
+			operator: bson.D{{"$regexMatch", bson.D{{"input", "$v"}}}},
+			err: &mongo.CommandError{
+				Code:    31023,
+				Name:    "Location31023",
+				Message: "$regexMatch requires 'regex' parameter",
+			},
+		},
+		"RegexFindInputNotString": {
+			operator: bson.D{{"$regexFind", bson.D{{"input", "$n"}, {"regex", "a"}}}},
+			err: &mongo.CommandError{
+				Code:    51104,
+				Name:    "Location51104",
+				Message: "$regexFind needs 'input' to be of type string",
+			},
+		},
+		"RegexFindAllBadOption": {
+			operator: bson.D{{"$regexFindAll", bson.D{{"input", "$v"}, {"regex", "a"}, {"options", "z"}}}},
+			err: &mongo.CommandError{
+				Code:    51108,
+				Name:    "Location51108",
+				Message: "$regexFindAll invalid flag in regex options: z",
+			},
+		},
+		"RegexOptionsConflict": {
+			operator: bson.D{{"$regexMatch", bson.D{
+				{"input", "$v"},
+				{"regex", primitive.Regex{Pattern: "a", Options: "i"}},
+				{"options", "m"},
+			}}},
+			err: &mongo.CommandError{
+				Code:    51107,
+				Name:    "Location51107",
+				Message: "$regexMatch found regex option(s) specified in both 'regex' and 'option' fields",
+			},
+		},
+		"SplitNotString": {
+			operator: bson.D{{"$split", bson.A{"$n", ","}}},
+			err: &mongo.CommandError{
+				Code:    40085,
+				Name:    "Location40085",
+				Message: "$split requires an expression that evaluates to a string as a first argument, found: int",
+			},
+		},
+		"SplitEmptySeparator": {
+			operator: bson.D{{"$split", bson.A{"$v", ""}}},
+			err: &mongo.CommandError{
+				Code:    40087,
+				Name:    "Location40087",
+				Message: "$split requires a non-empty separator",
+			},
+		},
+		"ReplaceAllMissingFind": {
+			operator: bson.D{{"$replaceAll", bson.D{{"input", "$v"}, {"replacement", "a"}}}},
+			err: &mongo.CommandError{
+				Code:    51748,
+				Name:    "Location51748",
+				Message: "$replaceAll requires 'find' to be specified",
+			},
+		},
+		"ReplaceOneInputNotString": {
+			operator: bson.D{{"$replaceOne", bson.D{{"input", "$n"}, {"find", "a"}, {"replacement", "b"}}}},
+			err: &mongo.CommandError{
+				Code:    51746,
+				Name:    "Location51746",
+				Message: "$replaceOne requires that 'input' be a string, found: 42",
+			},
+		},
+		"TrimNotObject": {
+			operator: bson.D{{"$trim", "$v"}},
+			err: &mongo.CommandError{
+				Code:    50696,
+				Name:    "Location50696",
+				Message: "$trim only supports an object as its argument",
+			},
+		},
+		"TrimInputNotString": {
+			operator: bson.D{{"$ltrim", bson.D{{"input", "$n"}}}},
+			err: &mongo.CommandError{
+				Code:    50699,
+				Name:    "Location50699",
+				Message: "$ltrim requires its input to be a string, got 42 (of type int) instead.",
+			},
+		},
+		"SubstrCPNegativeStart": {
+			operator: bson.D{{"$substrCP", bson.A{"$v", int32(-1), int32(1)}}},
+			err: &mongo.CommandError{
+				Code:    34454,
+				Name:    "Location34454",
+				Message: "$substrCP: starting index must be non-negative (got: -1)",
+			},
+		},
+		"SubstrCPStartNotInt": {
+			operator: bson.D{{"$substrCP", bson.A{"$v", 1.5, int32(1)}}},
+			err: &mongo.CommandError{
+				Code:    34451,
+				Name:    "Location34451",
+				Message: "$substrCP: starting index cannot be represented as a 32-bit integral value: 1.5",
+			},
+		},
+		"SubstrCPLengthNotNumber": {
+			operator: bson.D{{"$substrCP", bson.A{"$v", int32(0), "1"}}},
+			err: &mongo.CommandError{
+				Code:    34452,
+				Name:    "Location34452",
+				Message: "$substrCP: length must be a numeric type (is BSON type string)",
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			require.NotNil(t, tc.err, "err must not be nil")
+
+			_, err := collection.Aggregate(ctx, bson.A{
+				bson.D{{"$project", bson.D{{"v", tc.operator}}}},
+			})
+			AssertEqualAltCommandError(t, *tc.err, tc.altMessage, err)
+		})
+	}
+}
+
+func TestAggregateProjectArrayOperators(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertOne(ctx, bson.D{
+		{"_id", "array"},
+		{"nums", bson.A{int32(3), int32(1), int32(4), int32(1), int32(5)}},
+		{"letters", bson.A{"a", "b", "c"}},
+		{"people", bson.A{
+			bson.D{{"name", "bob"}, {"info", bson.D{{"age", int32(30)}}}},
+			bson.D{{"name", "alice"}, {"info", bson.D{{"age", int32(25)}}}},
+			bson.D{{"name", "carol"}},
+		}},
+		{"factor", int32(10)},
+	})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct { //nolint:vet // for readability
+		operator bson.D // required, array operator
+		expected any    // required, expected result
+	}{
+		"Map": {
+			operator: bson.D{{"$map", bson.D{{"input", "$nums"}, {"in", bson.D{{"$toString", "$$this"}}}}}},
+			expected: bson.A{"3", "1", "4", "1", "5"},
+		},
+		"MapAs": {
+			operator: bson.D{{"$map", bson.D{{"input", "$people"}, {"as", "p"}, {"in", "$$p.name"}}}},
+			expected: bson.A{"bob", "alice", "carol"},
+		},
+		"MapMissingField": {
+			operator: bson.D{{"$map", bson.D{{"input", "$people"}, {"as", "p"}, {"in", "$$p.info.age"}}}},
+			expected: bson.A{int32(30), int32(25), nil},
+		},
+		"MapNested": {
+			operator: bson.D{{"$map", bson.D{
+				{"input", bson.A{bson.A{int32(1), int32(2)}, bson.A{int32(3)}}},
+				{"in", bson.D{{"$map", bson.D{
+					{"input", "$$this"},
+					{"in", bson.D{{"$toString", "$$this"}}},
+				}}}},
+			}}},
+			expected: bson.A{bson.A{"1", "2"}, bson.A{"3"}},
+		},
+		"MapNestedOuterVariable": {
+			operator: bson.D{{"$map", bson.D{
+				{"input", bson.A{bson.A{int32(1), int32(2)}, bson.A{int32(3)}}},
+				{"as", "row"},
+				{"in", bson.D{{"$map", bson.D{
+					{"input", "$$row"},
+					{"in", bson.D{{"$zip", bson.D{{"inputs", bson.A{"$$row", bson.A{"$$this"}}}}}}},
+				}}}},
+			}}},
+			expected: bson.A{
+				bson.A{bson.A{bson.A{int32(1), int32(1)}}, bson.A{bson.A{int32(1), int32(2)}}},
+				bson.A{bson.A{bson.A{int32(3), int32(3)}}},
+			},
+		},
+		"MapNull": {
+			operator: bson.D{{"$map", bson.D{{"input", "$missing"}, {"in", "$$this"}}}},
+			expected: nil,
+		},
+		"Filter": {
+			operator: bson.D{{"$filter", bson.D{
+				{"input", "$people"},
+				{"as", "p"},
+				{"cond", bson.D{{"$regexMatch", bson.D{{"input", "$$p.name"}, {"regex", "o"}}}}},
+			}}},
+			expected: bson.A{
+				bson.D{{"name", "bob"}, {"info", bson.D{{"age", int32(30)}}}},
+				bson.D{{"name", "carol"}},
+			},
+		},
+		"FilterLimit": {
+			operator: bson.D{{"$filter", bson.D{{"input", "$nums"}, {"cond", true}, {"limit", int32(2)}}}},
+			expected: bson.A{int32(3), int32(1)},
+		},
+		"FilterFalsy": {
+			operator: bson.D{{"$filter", bson.D{{"input", bson.A{int32(0), nil, "", false, int32(1)}}, {"cond", "$$this"}}}},
+			expected: bson.A{"", int32(1)},
+		},
+		"Reduce": {
+			operator: bson.D{{"$reduce", bson.D{
+				{"input", "$letters"},
+				{"initialValue", ""},
+				{"in", bson.D{{"$replaceOne", bson.D{
+					{"input", "$$value"},
+					{"find", ""},
+					{"replacement", "$$this"},
+				}}}},
+			}}},
+			expected: "cba",
+		},
+		"ReduceZip": {
+			operator: bson.D{{"$reduce", bson.D{
+				{"input", "$letters"},
+				{"initialValue", bson.A{}},
+				{"in", bson.D{{"$zip", bson.D{{"inputs", bson.A{"$letters", bson.A{"$$this"}}}}}}},
+			}}},
+			expected: bson.A{bson.A{"a", "c"}},
+		},
+		"ReduceEmpty": {
+			operator: bson.D{{"$reduce", bson.D{{"input", bson.A{}}, {"initialValue", "$factor"}, {"in", "$$this"}}}},
+			expected: int32(10),
+		},
+		"Zip": {
+			operator: bson.D{{"$zip", bson.D{{"inputs", bson.A{"$letters", "$nums"}}}}},
+			expected: bson.A{
+				bson.A{"a", int32(3)},
+				bson.A{"b", int32(1)},
+				bson.A{"c", int32(4)},
+			},
+		},
+		"ZipLongestDefaults": {
+			operator: bson.D{{"$zip", bson.D{
+				{"inputs", bson.A{"$letters", bson.A{int32(1)}}},
+				{"useLongestLength", true},
+				{"defaults", bson.A{"z", "$factor"}},
+			}}},
+			expected: bson.A{
+				bson.A{"a", int32(1)},
+				bson.A{"b", int32(10)},
+				bson.A{"c", int32(10)},
+			},
+		},
+		"ZipLongestNull": {
+			operator: bson.D{{"$zip", bson.D{{"inputs", bson.A{bson.A{int32(1)}, "$letters"}}, {"useLongestLength", true}}}},
+			expected: bson.A{
+				bson.A{int32(1), "a"},
+				bson.A{nil, "b"},
+				bson.A{nil, "c"},
+			},
+		},
+		"ZipNull": {
+			operator: bson.D{{"$zip", bson.D{{"inputs", bson.A{"$letters", "$missing"}}}}},
+			expected: nil,
+		},
+		"SortArray": {
+			operator: bson.D{{"$sortArray", bson.D{{"input", "$nums"}, {"sortBy", int32(1)}}}},
+			expected: bson.A{int32(1), int32(1), int32(3), int32(4), int32(5)},
+		},
+		"SortArrayDescending": {
+			operator: bson.D{{"$sortArray", bson.D{{"input", "$letters"}, {"sortBy", int32(-1)}}}},
+			expected: bson.A{"c", "b", "a"},
+		},
+		"SortArrayNestedField": {
+			operator: bson.D{{"$sortArray", bson.D{{"input", "$people"}, {"sortBy", bson.D{{"info.age", int32(1)}}}}}},
+			expected: bson.A{
+				bson.D{{"name", "carol"}},
+				bson.D{{"name", "alice"}, {"info", bson.D{{"age", int32(25)}}}},
+				bson.D{{"name", "bob"}, {"info", bson.D{{"age", int32(30)}}}},
+			},
+		},
+		"SortArrayFields": {
+			operator: bson.D{{"$sortArray", bson.D{
+				{"input", bson.A{
+					bson.D{{"a", int32(1)}, {"b", int32(1)}},
+					bson.D{{"a", int32(2)}, {"b", int32(1)}},
+					bson.D{{"a", int32(1)}, {"b", int32(2)}},
+				}},
+				{"sortBy", bson.D{{"a", int32(-1)}, {"b", int32(-1)}}},
+			}}},
+			expected: bson.A{
+				bson.D{{"a", int32(2)}, {"b", int32(1)}},
+				bson.D{{"a", int32(1)}, {"b", int32(2)}},
+				bson.D{{"a", int32(1)}, {"b", int32(1)}},
+			},
+		},
+		"SortArrayNull": {
+			operator: bson.D{{"$sortArray", bson.D{{"input", nil}, {"sortBy", int32(1)}}}},
+			expected: nil,
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cursor, err := collection.Aggregate(ctx, bson.A{
+				bson.D{{"$project", bson.D{{"_id", false}, {"v", tc.operator}}}},
+			})
+			require.NoError(t, err)
+
+			res := FetchAll(t, ctx, cursor)
+			require.Len(t, res, 1)
+			assert.Equal(t, bson.D{{"v", tc.expected}}, res[0])
+		})
+	}
+}
+
+func TestAggregateProjectArrayOperatorsErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertOne(ctx, bson.D{{"_id", "array"}, {"arr", bson.A{int32(1)}}, {"v", "foo"}})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct { //nolint:vet // for readability
+		operator bson.D // required, array operator
+
+		err        *mongo.CommandError // required, expected error from MongoDB
+		altMessage string              // optional, alternative error message for FerretDB, ignored if empty
+	}{
+		"MapNotObject": {
+			operator: bson.D{{"$map", "$arr"}},
+			err: &mongo.CommandError{
+				Code:    16878,
+				Name:    "Location16878",
+				Message: "$map only supports an object as its argument",
+			},
+		},
+		"MapMissingIn": {
+			operator: bson.D{{"$map", bson.D{{"input", "$arr"}}}},
+			err: &mongo.CommandError{
+				Code:    16882,
+				Name:    "Location16882",
+				Message: "Missing 'in' parameter to $map",
+			},
+		},
+		"MapInputNotArray": {
+			operator: bson.D{{"$map", bson.D{{"input", "$v"}, {"in", "$$this"}}}},
+			err: &mongo.CommandError{
+				Code:    16883,
+				Name:    "Location16883",
+				Message: "input to $map must be an array not string",
+			},
+		},
+		"MapInvalidVariableName": {
+			operator: bson.D{{"$map", bson.D{{"input", "$arr"}, {"as", "Foo"}, {"in", "$$Foo"}}}},
+			err: &mongo.CommandError{
+				Code:    16867,
+				Name:    "Location16867",
+				Message: "'Foo' starts with an invalid character for a user variable name",
+			},
+		},
+		"MapVariableOutOfScope": {
+			operator: bson.D{{"$map", bson.D{
+				{"input", bson.D{{"$map", bson.D{{"input", "$arr"}, {"as", "x"}, {"in", "$$x"}}}}},
+				{"in", "$$x"},
+			}}},
+			err: &mongo.CommandError{
+				Code:    17276,
+				Name:    "Location17276",
+				Message: "Use of undefined variable: x",
+			},
+		},
+		"FilterMissingCond": {
+			operator: bson.D{{"$filter", bson.D{{"input", "$arr"}}}},
+			err: &mongo.CommandError{
+				Code:    28650,
+				Name:    "Location28650",
+				Message: "Missing 'cond' parameter to $filter",
+			},
+		},
+		"FilterUnknownArgument": {
+			operator: bson.D{{"$filter", bson.D{{"input", "$arr"}, {"cond", true}, {"foo", 1}}}},
+			err: &mongo.CommandError{
+				Code:    28647,
+				Name:    "Location28647",
+				Message: "Unrecognized parameter to $filter: foo",
+			},
+		},
+		"FilterLimitZero": {
+			operator: bson.D{{"$filter", bson.D{{"input", "$arr"}, {"cond", true}, {"limit", int32(0)}}}},
+			err: &mongo.CommandError{
+				Code:    327392,
+				Name:    "Location327392",
+				Message: "$filter: limit must be greater than 0: 0",
+			},
+		},
+		"ReduceMissingInitialValue": {
+			operator: bson.D{{"$reduce", bson.D{{"input", "$arr"}, {"in", "$$value"}}}},
+			err: &mongo.CommandError{
+				Code:    40078,
+				Name:    "Location40078",
+				Message: "$reduce requires 'initialValue' to be specified",
+			},
+		},
+		"ReduceInputNotArray": {
+			operator: bson.D{{"$reduce", bson.D{{"input", "$v"}, {"initialValue", 0}, {"in", "$$value"}}}},
+			err: &mongo.CommandError{
+				Code:    40080,
+				Name:    "Location40080",
+				Message: "input to $reduce must be an array not string",
+			},
+		},
+		"ZipMissingInputs": {
+			operator: bson.D{{"$zip", bson.D{{"useLongestLength", true}}}},
+			err: &mongo.CommandError{
+				Code:    34465,
+				Name:    "Location34465",
+				Message: "Missing 'inputs' parameter to $zip",
+			},
+		},
+		"ZipDefaultsWithoutLongest": {
+			operator: bson.D{{"$zip", bson.D{{"inputs", bson.A{"$arr"}}, {"defaults", bson.A{1}}}}},
+			err: &mongo.CommandError{
+				Code:    34466,
+				Name:    "Location34466",
+				Message: "cannot specify defaults unless useLongestLength is true",
+			},
+		},
+		"ZipInputNotArray": {
+			operator: bson.D{{"$zip", bson.D{{"inputs", bson.A{"$arr", "$v"}}}}},
+			err: &mongo.CommandError{
+				Code:    34468,
+				Name:    "Location34468",
+				Message: `$zip found a non-array expression in input: "foo"`,
+			},
+		},
+		"SortArrayMissingSortBy": {
+			operator: bson.D{{"$sortArray", bson.D{{"input", "$arr"}}}},
+			err: &mongo.CommandError{
+				Code:    2942503,
+				Name:    "Location2942503",
+				Message: "$sortArray requires 'sortBy' to be specified",
+			},
+		},
+		"SortArrayInputNotArray": {
+			operator: bson.D{{"$sortArray", bson.D{{"input", "$v"}, {"sortBy", int32(1)}}}},
+			err: &mongo.CommandError{
+				Code:    2942504,
+				Name:    "Location2942504",
+				Message: "The input argument to $sortArray must be an array, but was of type: string",
+			},
+		},
+		"SortArrayBadOrder": {
+			operator: bson.D{{"$sortArray", bson.D{{"input", "$arr"}, {"sortBy", bson.D{{"a", int32(2)}}}}}},
+			err: &mongo.CommandError{
+				Code:    15975,
+				Name:    "Location15975",
+				Message: "$sort key ordering must be 1 (for ascending) or -1 (for descending)",
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			require.NotNil(t, tc.err, "err must not be nil")
+
+			_, err := collection.Aggregate(ctx, bson.A{
+				bson.D{{"$project", bson.D{{"v", tc.operator}}}},
+			})
+			AssertEqualAltCommandError(t, *tc.err, tc.altMessage, err)
+		})
+	}
+}
+
+func TestAggregateVariables(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", int32(1)}, {"v", "foo"}},
+		bson.D{{"_id", int32(2)}, {"v", "bar"}},
+	})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct { //nolint:vet // for readability
+		pipeline bson.A // required, aggregation pipeline stages
+		let      bson.D // optional, let option
+
+		expected []bson.D // required, expected documents
+	}{
+		"MatchLet": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"$expr", bson.D{{"$regexMatch", bson.D{{"input", "$v"}, {"regex", "$$re"}}}}}}}},
+			},
+			let:      bson.D{{"re", "^b"}},
+			expected: []bson.D{{{"_id", int32(2)}, {"v", "bar"}}},
+		},
+		"MatchLetFalse": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"$expr", "$$x"}}}},
+			},
+			let:      bson.D{{"x", false}},
+			expected: []bson.D{},
+		},
+		"AddFieldsLet": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"_id", int32(1)}}}},
+				bson.D{{"$addFields", bson.D{{"f", "$$x"}}}},
+			},
+			let:      bson.D{{"x", "foo"}},
+			expected: []bson.D{{{"_id", int32(1)}, {"v", "foo"}, {"f", "foo"}}},
+		},
+		"AddFieldsLetDocumentPath": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"_id", int32(1)}}}},
+				bson.D{{"$addFields", bson.D{{"f", "$$x.a"}}}},
+			},
+			let:      bson.D{{"x", bson.D{{"a", int32(42)}}}},
+			expected: []bson.D{{{"_id", int32(1)}, {"v", "foo"}, {"f", int32(42)}}},
+		},
+		"LetExpression": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"_id", int32(1)}}}},
+				bson.D{{"$project", bson.D{{"f", "$$x"}}}},
+			},
+			let:      bson.D{{"x", bson.D{{"$toString", int32(42)}}}},
+			expected: []bson.D{{{"_id", int32(1)}, {"f", "42"}}},
+		},
+		"LetPreviousVariable": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"_id", int32(1)}}}},
+				bson.D{{"$project", bson.D{{"f", "$$y"}}}},
+			},
+			let:      bson.D{{"x", int32(42)}, {"y", bson.D{{"$toString", "$$x"}}}},
+			expected: []bson.D{{{"_id", int32(1)}, {"f", "42"}}},
+		},
+		"ProjectOperatorLet": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"_id", int32(2)}}}},
+				bson.D{{"$project", bson.D{{"_id", false}, {"f", bson.D{{"$toString", "$$x"}}}}}},
+			},
+			let:      bson.D{{"x", int64(7)}},
+			expected: []bson.D{{{"f", "7"}}},
+		},
+		"GroupLet": {
+			pipeline: bson.A{
+				bson.D{{"$group", bson.D{{"_id", "$$x"}, {"count", bson.D{{"$sum", int32(1)}}}}}},
+			},
+			let:      bson.D{{"x", "foo"}},
+			expected: []bson.D{{{"_id", "foo"}, {"count", int32(2)}}},
+		},
+		"GroupDocumentLet": {
+			pipeline: bson.A{
+				bson.D{{"$sort", bson.D{{"_id", 1}}}},
+				bson.D{{"$group", bson.D{{"_id", bson.D{{"x", "$$x"}, {"v", "$v"}}}}}},
+				bson.D{{"$sort", bson.D{{"_id.v", 1}}}},
+			},
+			let: bson.D{{"x", int32(42)}},
+			expected: []bson.D{
+				{{"_id", bson.D{{"x", int32(42)}, {"v", "bar"}}}},
+				{{"_id", bson.D{{"x", int32(42)}, {"v", "foo"}}}},
+			},
+		},
+		"GroupSumLet": {
+			pipeline: bson.A{
+				bson.D{{"$group", bson.D{{"_id", nil}, {"sum", bson.D{{"$sum", "$$x"}}}}}},
+			},
+			let:      bson.D{{"x", int32(21)}},
+			expected: []bson.D{{{"_id", nil}, {"sum", int32(42)}}},
+		},
+		"GroupSumOperatorLet": {
+			pipeline: bson.A{
+				bson.D{{"$group", bson.D{{"_id", nil}, {"sum", bson.D{{"$sum", bson.D{{"$toInt", "$$x"}}}}}}}},
+			},
+			let:      bson.D{{"x", "21"}},
+			expected: []bson.D{{{"_id", nil}, {"sum", int32(42)}}},
+		},
+		"SetRoot": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"_id", int32(1)}}}},
+				bson.D{{"$set", bson.D{{"root", "$$ROOT"}}}},
+			},
+			expected: []bson.D{{
+				{"_id", int32(1)},
+				{"v", "foo"},
+				{"root", bson.D{{"_id", int32(1)}, {"v", "foo"}}},
+			}},
+		},
+		"SetCurrentField": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"_id", int32(1)}}}},
+				bson.D{{"$set", bson.D{{"f", "$$CURRENT.v"}}}},
+			},
+			expected: []bson.D{{{"_id", int32(1)}, {"v", "foo"}, {"f", "foo"}}},
+		},
+		"ProjectRemove": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"_id", int32(1)}}}},
+				bson.D{{"$project", bson.D{{"v", true}, {"f", "$$REMOVE"}}}},
+			},
+			expected: []bson.D{{{"_id", int32(1)}, {"v", "foo"}}},
+		},
+		"AddFieldsRemove": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"_id", int32(1)}}}},
+				bson.D{{"$addFields", bson.D{{"v", "$$REMOVE"}}}},
+			},
+			expected: []bson.D{{{"_id", int32(1)}}},
+		},
+		"MatchExprRoot": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"$expr", bson.D{{"$regexMatch", bson.D{{"input", "$$ROOT.v"}, {"regex", "^f"}}}}}}}},
+			},
+			expected: []bson.D{{{"_id", int32(1)}, {"v", "foo"}}},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := options.Aggregate()
+			if tc.let != nil {
+				opts.SetLet(tc.let)
+			}
+
+			cursor, err := collection.Aggregate(ctx, tc.pipeline, opts)
+			require.NoError(t, err)
+
+			AssertEqualDocumentsSlice(t, tc.expected, FetchAll(t, ctx, cursor))
+		})
+	}
+}
+
+func TestAggregateVariablesNow(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", int32(1)}},
+		bson.D{{"_id", int32(2)}},
+	})
+	require.NoError(t, err)
+
+	start := time.Now().Truncate(time.Millisecond)
+
+	cursor, err := collection.Aggregate(ctx, bson.A{
+		bson.D{{"$sort", bson.D{{"_id", 1}}}},
+		bson.D{{"$addFields", bson.D{{"now", "$$NOW"}}}},
+	})
+	require.NoError(t, err)
+
+	res := FetchAll(t, ctx, cursor)
+	require.Len(t, res, 2)
+
+	first, ok := res[0].Map()["now"].(primitive.DateTime)
+	require.True(t, ok, "unexpected type %T", res[0].Map()["now"])
+	assert.False(t, first.Time().Before(start.Add(-time.Minute)))
+
+	// $$NOW has the same value for all documents
+	assert.Equal(t, first, res[1].Map()["now"])
+}
+
+func TestAggregateVariablesErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertOne(ctx, bson.D{{"_id", int32(1)}, {"v", int32(10)}})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct { //nolint:vet // for readability
+		pipeline bson.A // required, aggregation pipeline stages
+		let      bson.D // optional, let option
+
+		err        *mongo.CommandError // required, expected error from MongoDB
+		altMessage string              // optional, alternative error message for FerretDB, ignored if empty
+	}{
+		"UndefinedMatch": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"$expr", "$$missing"}}}},
+			},
+			err: &mongo.CommandError{
+				Code:    17276,
+				Name:    "Location17276",
+				Message: "Use of undefined variable: missing",
+			},
+		},
+		"UndefinedAddFields": {
+			pipeline: bson.A{
+				bson.D{{"$addFields", bson.D{{"f", "$$missing"}}}},
+			},
+			err: &mongo.CommandError{
+				Code:    17276,
+				Name:    "Location17276",
+				Message: "Use of undefined variable: missing",
+			},
+		},
+		"UndefinedProject": {
+			pipeline: bson.A{
+				bson.D{{"$project", bson.D{{"f", "$$missing"}}}},
+			},
+			err: &mongo.CommandError{
+				Code:    17276,
+				Name:    "Location17276",
+				Message: "Use of undefined variable: missing",
+			},
+		},
+		"LetInvalidName": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"_id", int32(1)}}}},
+			},
+			let: bson.D{{"Foo", int32(1)}},
+			err: &mongo.CommandError{
+				Code:    16867,
+				Name:    "Location16867",
+				Message: "'Foo' starts with an invalid character for a user variable name",
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			require.NotNil(t, tc.err, "err must not be nil")
+
+			opts := options.Aggregate()
+			if tc.let != nil {
+				opts.SetLet(tc.let)
+			}
+
+			_, err := collection.Aggregate(ctx, tc.pipeline, opts)
+			AssertEqualAltCommandError(t, *tc.err, tc.altMessage, err)
+		})
+	}
+}
+
 func TestAggregateSetErrors(t *testing.T) {
 	t.Parallel()
 
@@ -32,7 +32,6 @@ func TestAggregateVariablesCompatRoot(t *testing.T) {
 			pipeline: bson.A{
 				bson.D{{"$addFields", bson.D{{"field", "$$ROOT"}}}},
 			},
-			skip: "https://github.com/FerretDB/FerretDB/issues/1413",
 		},
 		"GroupID": {
 			pipeline: bson.A{
@@ -125,7 +124,6 @@ func TestAggregateVariablesCompatRoot(t *testing.T) {
 			pipeline: bson.A{
 				bson.D{{"$set", bson.D{{"field", "$$ROOT"}}}},
 			},
-			skip: "https://github.com/FerretDB/FerretDB/issues/1413",
 		},
 		"Unwind": {
 			pipeline: bson.A{
@@ -618,6 +618,133 @@ func TestGetParameterCommandAuthenticationMechanisms(t *testing.T) {
 	})
 }
 
+func TestCommandsAdministrationClusterParameter(t *testing.T) {
+	t.Parallel()
+
+	s := setup.SetupWithOpts(t, &setup.SetupOpts{
+		DatabaseName: "admin",
+	})
+
+	ctx, db := s.Ctx, s.Collection.Database()
+
+	getExpireAfterSeconds := func(t *testing.T) any {
+		t.Helper()
+
+		var res bson.D
+		err := db.RunCommand(ctx, bson.D{{"getClusterParameter", "changeStreamOptions"}}).Decode(&res)
+		require.NoError(t, err)
+
+		doc := ConvertDocument(t, res)
+		assert.Equal(t, float64(1), must.NotFail(doc.Get("ok")))
+
+		params := must.NotFail(doc.Get("clusterParameters")).(*types.Array)
+		require.Equal(t, 1, params.Len())
+
+		param := must.NotFail(params.Get(0)).(*types.Document)
+		assert.Equal(t, "changeStreamOptions", must.NotFail(param.Get("_id")))
+
+		return must.NotFail(param.GetByPath(types.NewStaticPath("preAndPostImages", "expireAfterSeconds")))
+	}
+
+	setExpireAfterSeconds := func(t *testing.T, v any) {
+		t.Helper()
+
+		var res bson.D
+		err := db.RunCommand(ctx, bson.D{{"setClusterParameter", bson.D{
+			{"changeStreamOptions", bson.D{{"preAndPostImages", bson.D{{"expireAfterSeconds", v}}}}},
+		}}}).Decode(&res)
+		require.NoError(t, err)
+		AssertEqualDocuments(t, bson.D{{"ok", float64(1)}}, res)
+	}
+
+	// tests are not parallel because they share the same cluster parameter
+
+	t.Run("SetGet", func(t *testing.T) {
+		setExpireAfterSeconds(t, int64(100))
+		assert.Equal(t, int64(100), getExpireAfterSeconds(t))
+
+		setExpireAfterSeconds(t, "off")
+		assert.Equal(t, "off", getExpireAfterSeconds(t))
+	})
+
+	t.Run("GetArray", func(t *testing.T) {
+		var res bson.D
+		err := db.RunCommand(ctx, bson.D{{"getClusterParameter", bson.A{"changeStreamOptions"}}}).Decode(&res)
+		require.NoError(t, err)
+
+		doc := ConvertDocument(t, res)
+		assert.Equal(t, 1, must.NotFail(doc.Get("clusterParameters")).(*types.Array).Len())
+	})
+
+	for name, tc := range map[string]struct { //nolint:vet // for readability
+		db      string // optional, database to run command against, defaults to admin
+		command bson.D // required, command to run
+
+		err        *mongo.CommandError // required, expected error from MongoDB
+		altMessage string              // optional, alternative error message for FerretDB, ignored if empty
+		skip       string              // optional, skip test with a specified reason
+	}{
+		"GetUnknown": {
+			command: bson.D{{"getClusterParameter", "unknownParameter"}},
+			err: &mongo.CommandError{
+				Code:    4,
+				Name:    "NoSuchKey",
+				Message: "Unknown Cluster Parameter unknownParameter",
+			},
+		},
+		"SetUnknown": {
+			command: bson.D{{"setClusterParameter", bson.D{{"unknownParameter", bson.D{}}}}},
+			err: &mongo.CommandError{
+				Code:    4,
+				Name:    "NoSuchKey",
+				Message: "Unknown Cluster Parameter unknownParameter",
+			},
+		},
+		"SetBadValue": {
+			command: bson.D{{"setClusterParameter", bson.D{
+				{"changeStreamOptions", bson.D{{"preAndPostImages", bson.D{{"expireAfterSeconds", "on"}}}}},
+			}}},
+			err: &mongo.CommandError{
+				Code: 2,
+				Name: "BadValue",
+				Message: "Invalid value 'on' for expireAfterSeconds, " +
+					"expected 'off' or a positive number",
+			},
+		},
+		"GetNotAdmin": {
+			db:      s.Collection.Name(),
+			command: bson.D{{"getClusterParameter", "changeStreamOptions"}},
+			err: &mongo.CommandError{
+				Code:    13,
+				Name:    "Unauthorized",
+				Message: "getClusterParameter may only be run against the admin database.",
+			},
+		},
+	} {
+		name, tc := name, tc
+
+		t.Run(name, func(t *testing.T) {
+			if tc.skip != "" {
+				t.Skip(tc.skip)
+			}
+
+			require.NotNil(t, tc.command, "command must not be nil")
+			require.NotNil(t, tc.err, "err must not be nil")
+
+			runDB := db
+			if tc.db != "" {
+				runDB = db.Client().Database(tc.db)
+			}
+
+			var res bson.D
+			err := runDB.RunCommand(ctx, tc.command).Decode(&res)
+
+			assert.Nil(t, res)
+			AssertEqualAltCommandError(t, *tc.err, tc.altMessage, err)
+		})
+	}
+}
+
 func TestCommandsAdministrationBuildInfo(t *testing.T) {
 	t.Parallel()
 	ctx, collection := setup.Setup(t)
@@ -1179,6 +1306,34 @@ func TestCommandsAdministrationServerStatus(t *testing.T) {
 	assert.Equal(t, int32(1), must.NotFail(catalogStats.Get("capped")))
 }
 
+func TestCommandsAdministrationServerStatusMaintenance(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	setup.SkipForMongoDB(t, "FerretDB-specific maintenance status")
+
+	var actual bson.D
+	err := collection.Database().RunCommand(ctx, bson.D{{"serverStatus", int32(1)}}).Decode(&actual)
+	require.NoError(t, err)
+
+	doc := ConvertDocument(t, actual)
+
+	maintenance, ok := must.NotFail(doc.Get("maintenance")).(*types.Document)
+	require.True(t, ok)
+	assert.Equal(t, []string{"compact", "ttl", "cursors", "sessions"}, maintenance.Keys())
+
+	// see integration/setup
+	ttl := must.NotFail(maintenance.Get("ttl")).(*types.Document)
+	assert.Equal(t, true, must.NotFail(ttl.Get("enabled")))
+	assert.Equal(t, int64(1), must.NotFail(ttl.Get("intervalSecs")))
+
+	compact := must.NotFail(maintenance.Get("compact")).(*types.Document)
+	assert.Equal(t, false, must.NotFail(compact.Get("enabled")))
+	assert.Equal(t, int64(0), must.NotFail(compact.Get("runs")))
+	assert.False(t, compact.Has("lastStarted"))
+}
+
 func TestCommandsAdministrationServerStatusMetrics(t *testing.T) {
 	t.Parallel()
 
@@ -1383,6 +1538,94 @@ func TestCommandsAdministrationCurrentOp(t *testing.T) {
 	assert.True(t, ok)
 }
 
+func TestCommandsAdministrationCurrentOpFilter(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	for name, tc := range map[string]struct {
+		command bson.D // required, command to run
+	}{
+		"IndexBuilds": {
+			command: bson.D{
+				{"currentOp", int32(1)},
+				{"command.createIndexes", bson.D{{"$exists", true}}},
+				{"ns", collection.Database().Name() + "." + collection.Name()},
+			},
+		},
+		"Or": {
+			command: bson.D{
+				{"currentOp", int32(1)},
+				{"$or", bson.A{
+					bson.D{{"ns", collection.Database().Name() + "." + collection.Name()}},
+					bson.D{{"desc", "IndexBuildsCoordinator"}, {"ns", "none.none"}},
+				}},
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var res bson.D
+			err := collection.Database().Client().Database("admin").RunCommand(ctx, tc.command).Decode(&res)
+			require.NoError(t, err)
+
+			// there are no index builds for that collection in progress
+			AssertEqualDocuments(t, bson.D{{"inprog", bson.A{}}, {"ok", float64(1)}}, res)
+		})
+	}
+}
+
+func TestCommandsAdministrationCurrentOpAll(t *testing.T) {
+	t.Parallel()
+
+	s := setup.SetupWithOpts(t, nil)
+
+	appName := testutil.DatabaseName(t)
+
+	client, err := mongo.Connect(s.Ctx, options.Client().ApplyURI(s.MongoDBURI).SetAppName(appName))
+	require.NoError(t, err)
+
+	defer client.Disconnect(s.Ctx)
+
+	require.NoError(t, client.Ping(s.Ctx, nil))
+
+	for name, tc := range map[string]struct {
+		client *mongo.Client
+		active bool
+	}{
+		"Own": {
+			client: client,
+			active: true,
+		},
+		"Other": {
+			client: s.Collection.Database().Client(),
+			active: false,
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			var res bson.D
+			err := tc.client.Database("admin").RunCommand(s.Ctx, bson.D{
+				{"currentOp", int32(1)},
+				{"$all", true},
+				{"appName", appName},
+				{"active", tc.active},
+			}).Decode(&res)
+			require.NoError(t, err)
+
+			doc := ConvertDocument(t, res)
+			inprog := must.NotFail(doc.Get("inprog")).(*types.Array)
+			require.Positive(t, inprog.Len())
+
+			op := must.NotFail(inprog.Get(0)).(*types.Document)
+			assert.Equal(t, appName, must.NotFail(op.GetByPath(types.NewStaticPath("clientMetadata", "application", "name"))))
+			assert.Equal(t, "mongo-go-driver", must.NotFail(op.GetByPath(types.NewStaticPath("clientMetadata", "driver", "name"))))
+		})
+	}
+}
+
 func TestCommandsAdministrationKillCursors(t *testing.T) {
 	t.Parallel()
 
@@ -1516,3 +1759,221 @@ func TestCommandsAdministrationKillCursors(t *testing.T) {
 		AssertMatchesCommandError(t, expectedErr, c.Err())
 	})
 }
+
+func TestCommandsAdministrationReshapeCollection(t *testing.T) {
+	setup.SkipForMongoDB(t, "FerretDB-specific command")
+
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+	db := collection.Database()
+	ns := db.Name() + "." + collection.Name()
+
+	docs := make([]any, 100)
+	for i := range docs {
+		docs[i] = bson.D{{"_id", int32(i)}, {"v", int32(i)}}
+	}
+
+	_, err := collection.InsertMany(ctx, docs)
+	require.NoError(t, err)
+
+	_, err = collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{"v", 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	require.NoError(t, err)
+
+	// concurrent writes should be caught up
+	started := make(chan struct{})
+	done := make(chan struct{})
+	writes := make(chan error, 1)
+
+	go func() {
+		defer close(writes)
+
+		for i := int32(100); ; i++ {
+			if i == 110 {
+				close(started)
+			}
+
+			select {
+			case <-done:
+				return
+			default:
+			}
+
+			if _, err := collection.InsertOne(ctx, bson.D{{"_id", i}, {"v", i}}); err != nil {
+				writes <- err
+				return
+			}
+
+			if _, err := collection.DeleteOne(ctx, bson.D{{"_id", i - 100}}); err != nil {
+				writes <- err
+				return
+			}
+		}
+	}()
+
+	<-started
+
+	var res bson.D
+	err = db.RunCommand(ctx, bson.D{{"reshapeCollection", ns}, {"batchSize", int32(3)}}).Decode(&res)
+
+	close(done)
+	require.NoError(t, <-writes)
+	require.NoError(t, err)
+
+	m := res.Map()
+	assert.Equal(t, float64(1), m["ok"])
+	assert.Contains(t, m, "copied")
+	assert.Contains(t, m, "caughtUp")
+
+	// each pair of writes keeps exactly 100 consecutive documents
+	cursor, err := collection.Find(ctx, bson.D{}, options.Find().SetSort(bson.D{{"_id", 1}}))
+	require.NoError(t, err)
+
+	var actual []bson.D
+	require.NoError(t, cursor.All(ctx, &actual))
+	require.Len(t, actual, 100)
+
+	first := actual[0].Map()["_id"].(int32)
+	for i, doc := range actual {
+		assert.Equal(t, bson.D{{"_id", first + int32(i)}, {"v", first + int32(i)}}, doc)
+	}
+
+	indexes, err := collection.Indexes().ListSpecifications(ctx)
+	require.NoError(t, err)
+	require.Len(t, indexes, 2)
+	assert.Equal(t, "v_1", indexes[1].Name)
+	assert.True(t, *indexes[1].Unique)
+
+	names, err := db.ListCollectionNames(ctx, bson.D{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{collection.Name()}, names)
+
+	t.Run("NonExistent", func(t *testing.T) {
+		err := db.RunCommand(ctx, bson.D{{"reshapeCollection", db.Name() + ".none"}}).Err()
+		AssertEqualCommandError(t, mongo.CommandError{
+			Code:    26,
+			Name:    "NamespaceNotFound",
+			Message: "Collection " + db.Name() + ".none does not exist",
+		}, err)
+	})
+
+	t.Run("BadBatchSize", func(t *testing.T) {
+		err := db.RunCommand(ctx, bson.D{{"reshapeCollection", ns}, {"batchSize", int32(0)}}).Err()
+		AssertEqualCommandError(t, mongo.CommandError{
+			Code:    2,
+			Name:    "BadValue",
+			Message: "batchSize must be a positive integer, found 0",
+		}, err)
+	})
+
+	t.Run("ClusteredIndex", func(t *testing.T) {
+		spec := bson.D{{"key", bson.D{{"_id", 1}}}, {"unique", true}}
+		err := db.RunCommand(ctx, bson.D{{"reshapeCollection", ns}, {"clusteredIndex", spec}}).Err()
+		require.NoError(t, err)
+
+		// natural order follows backend-specific _id index order, so only check that documents are kept
+		cursor, err := collection.Find(ctx, bson.D{}, options.Find().SetSort(bson.D{{"$natural", 1}}))
+		require.NoError(t, err)
+
+		var natural []bson.D
+		require.NoError(t, cursor.All(ctx, &natural))
+		assert.ElementsMatch(t, actual, natural)
+	})
+
+	t.Run("BadClusteredIndex", func(t *testing.T) {
+		spec := bson.D{{"key", bson.D{{"v", 1}}}, {"unique", true}}
+		err := db.RunCommand(ctx, bson.D{{"reshapeCollection", ns}, {"clusteredIndex", spec}}).Err()
+		AssertEqualCommandError(t, mongo.CommandError{
+			Code:    2,
+			Name:    "BadValue",
+			Message: "The clusteredIndex must be {key: {_id: 1}, unique: true}",
+		}, err)
+	})
+
+	t.Run("UnsupportedLayout", func(t *testing.T) {
+		err := db.RunCommand(ctx, bson.D{{"reshapeCollection", ns}, {"key", "v"}}).Err()
+		AssertEqualCommandError(t, mongo.CommandError{
+			Code:    238,
+			Name:    "NotImplemented",
+			Message: "reshapeCollection: support for field \"key\" with value v is not implemented yet",
+		}, err)
+	})
+}
+
+func TestCommandsAdministrationValidateDBMetadata(t *testing.T) {
+	setup.SkipForMongoDB(t, "FerretDB-specific metadata checks")
+
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t, shareddata.Scalars)
+	db := collection.Database()
+
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{"v", 1}}})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		command bson.D
+	}{
+		"Check": {
+			command: bson.D{{"validateDBMetadata", int32(1)}, {"db", db.Name()}},
+		},
+		"DryRun": {
+			command: bson.D{
+				{"validateDBMetadata", int32(1)}, {"db", db.Name()},
+				{"collection", collection.Name()}, {"repair", true}, {"dryRun", true},
+			},
+		},
+		"Repair": {
+			command: bson.D{{"validateDBMetadata", int32(1)}, {"db", db.Name()}, {"repair", true}},
+		},
+		"NonExistentDatabase": {
+			command: bson.D{{"validateDBMetadata", int32(1)}, {"db", "none"}},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var res bson.D
+			err := db.RunCommand(ctx, tc.command).Decode(&res)
+			require.NoError(t, err)
+
+			expected := bson.D{
+				{"apiVersionErrors", bson.A{}},
+				{"metadataProblems", bson.A{}},
+				{"ok", float64(1)},
+			}
+			AssertEqualDocuments(t, expected, res)
+		})
+	}
+
+	t.Run("RepairCollection", func(t *testing.T) {
+		t.Parallel()
+
+		command := bson.D{
+			{"validateDBMetadata", int32(1)}, {"db", db.Name()},
+			{"collection", collection.Name()}, {"repair", true},
+		}
+		err := db.RunCommand(ctx, command).Err()
+		AssertEqualCommandError(t, mongo.CommandError{
+			Code:    2,
+			Name:    "BadValue",
+			Message: "repair can't be used with collection, use dryRun to see repairs for the collection",
+		}, err)
+	})
+
+	t.Run("BadRepair", func(t *testing.T) {
+		t.Parallel()
+
+		command := bson.D{{"validateDBMetadata", int32(1)}, {"db", db.Name()}, {"repair", "yes"}}
+		err := db.RunCommand(ctx, command).Err()
+		AssertEqualCommandError(t, mongo.CommandError{
+			Code:    14,
+			Name:    "TypeMismatch",
+			Message: "BSON field 'repair' is the wrong type 'string', expected types '[bool, long, int, decimal, double]'",
+		}, err)
+	})
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 
 	"github.com/FerretDB/FerretDB/integration/setup"
 	"github.com/FerretDB/FerretDB/internal/handlers/common"
@@ -44,6 +45,10 @@ func TestCommandsReplication(t *testing.T) {
 			t.Log(m)
 
 			delete(m, "ismaster")
+
+			connectionID, ok := m["connectionId"].(int32)
+			require.True(t, ok, "unexpected type %T", m["connectionId"])
+			assert.Positive(t, connectionID)
 			delete(m, "connectionId")
 			delete(m, "logicalSessionTimeoutMinutes")
 			delete(m, "topologyVersion")
@@ -72,3 +77,127 @@ func TestCommandsReplication(t *testing.T) {
 		})
 	}
 }
+
+func TestCommandsReplicationApplyOps(t *testing.T) {
+	t.Parallel()
+	ctx, collection := setup.Setup(t)
+
+	db := collection.Database()
+	ns := db.Name() + "." + collection.Name()
+
+	ops := bson.A{
+		bson.D{{"op", "i"}, {"ns", ns}, {"o", bson.D{{"_id", int32(1)}, {"v", "foo"}}}},
+		bson.D{{"op", "i"}, {"ns", ns}, {"o", bson.D{{"_id", int32(2)}, {"v", "bar"}}}},
+		bson.D{{"op", "u"}, {"ns", ns}, {"o", bson.D{{"$set", bson.D{{"v", "baz"}}}}}, {"o2", bson.D{{"_id", int32(1)}}}},
+		bson.D{{"op", "d"}, {"ns", ns}, {"o", bson.D{{"_id", int32(2)}}}},
+		bson.D{{"op", "n"}, {"ns", ""}, {"o", bson.D{{"msg", "noop"}}}},
+		bson.D{{"op", "c"}, {"ns", db.Name() + ".$cmd"}, {"o", bson.D{
+			{"createIndexes", collection.Name()},
+			{"v", int32(2)},
+			{"key", bson.D{{"v", int32(1)}}},
+			{"name", "v_1"},
+		}}},
+	}
+
+	t.Run("Apply", func(t *testing.T) {
+		var res bson.D
+		err := db.RunCommand(ctx, bson.D{{"applyOps", ops}, {"allowAtomic", false}}).Decode(&res)
+		require.NoError(t, err)
+
+		expected := bson.D{
+			{"applied", int32(6)},
+			{"results", bson.A{true, true, true, true, true, true}},
+			{"ok", float64(1)},
+		}
+		AssertEqualDocuments(t, expected, res)
+
+		cursor, err := collection.Find(ctx, bson.D{})
+		require.NoError(t, err)
+
+		var docs []bson.D
+		require.NoError(t, cursor.All(ctx, &docs))
+		AssertEqualDocumentsSlice(t, []bson.D{{{"_id", int32(1)}, {"v", "baz"}}}, docs)
+
+		indexes, err := collection.Indexes().ListSpecifications(ctx)
+		require.NoError(t, err)
+		require.Len(t, indexes, 2)
+		assert.Equal(t, "v_1", indexes[1].Name)
+	})
+
+	t.Run("Replay", func(t *testing.T) {
+		// inserts of existing documents are upserts by default
+		var res bson.D
+		err := db.RunCommand(ctx, bson.D{{"applyOps", bson.A{ops[0]}}}).Decode(&res)
+		require.NoError(t, err)
+
+		var doc bson.D
+		require.NoError(t, collection.FindOne(ctx, bson.D{{"_id", int32(1)}}).Decode(&doc))
+		AssertEqualDocuments(t, bson.D{{"_id", int32(1)}, {"v", "foo"}}, doc)
+	})
+
+	t.Run("InvalidBatch", func(t *testing.T) {
+		batch := bson.A{
+			bson.D{{"op", "i"}, {"ns", ns}, {"o", bson.D{{"_id", int32(3)}}}},
+			bson.D{{"op", "x"}, {"ns", ns}, {"o", bson.D{{"_id", int32(4)}}}},
+		}
+
+		err := db.RunCommand(ctx, bson.D{{"applyOps", batch}, {"allowAtomic", false}}).Err()
+		require.Error(t, err)
+
+		// nothing is applied if any operation is invalid
+		n, err := collection.CountDocuments(ctx, bson.D{{"_id", int32(3)}})
+		require.NoError(t, err)
+		assert.Zero(t, n)
+	})
+
+	t.Run("FailedOperation", func(t *testing.T) {
+		batch := bson.A{
+			bson.D{{"op", "i"}, {"ns", ns}, {"o", bson.D{{"_id", int32(10)}}}},
+			bson.D{{"op", "u"}, {"ns", ns}, {"o", bson.D{{"$set", bson.D{{"v", "baz"}}}}}, {"o2", bson.D{{"_id", int32(11)}}}},
+			bson.D{{"op", "i"}, {"ns", ns}, {"o", bson.D{{"_id", int32(12)}}}},
+		}
+
+		err := db.RunCommand(ctx, bson.D{{"applyOps", batch}, {"allowAtomic", false}}).Err()
+
+		var ce mongo.CommandError
+		require.ErrorAs(t, err, &ce)
+		assert.Equal(t, int32(96), ce.Code)
+		assert.Equal(t, "OperationFailed", ce.Name)
+
+		// operations are not applied atomically, the reply tells which ones were applied
+		assert.Equal(t, int32(1), ce.Raw.Lookup("applied").Int32())
+
+		var results bson.A
+		require.NoError(t, ce.Raw.Lookup("results").Unmarshal(&results))
+		assert.Equal(t, bson.A{true, false}, results)
+
+		n, err := collection.CountDocuments(ctx, bson.D{{"_id", int32(10)}})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), n)
+
+		n, err = collection.CountDocuments(ctx, bson.D{{"_id", int32(12)}})
+		require.NoError(t, err)
+		assert.Zero(t, n)
+	})
+
+	t.Run("Atomic", func(t *testing.T) {
+		setup.SkipForMongoDB(t, "FerretDB can't apply several operations atomically")
+
+		batch := bson.A{
+			bson.D{{"op", "i"}, {"ns", ns}, {"o", bson.D{{"_id", int32(20)}}}},
+			bson.D{{"op", "i"}, {"ns", ns}, {"o", bson.D{{"_id", int32(21)}}}},
+		}
+
+		err := db.RunCommand(ctx, bson.D{{"applyOps", batch}}).Err()
+
+		var ce mongo.CommandError
+		require.ErrorAs(t, err, &ce)
+		assert.Equal(t, int32(238), ce.Code)
+		assert.Equal(t, "NotImplemented", ce.Name)
+
+		// nothing is applied
+		n, err := collection.CountDocuments(ctx, bson.D{{"_id", bson.D{{"$in", bson.A{int32(20), int32(21)}}}}})
+		require.NoError(t, err)
+		assert.Zero(t, n)
+	})
+}
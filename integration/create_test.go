@@ -241,3 +241,51 @@ func TestCreateStressSameCollection(t *testing.T) {
 
 	require.Equal(t, int32(1), created.Load(), "Only one attempt to create a collection should succeed")
 }
+
+func TestCreateTemp(t *testing.T) {
+	setup.SkipForMongoDB(t, "MongoDB does not drop temporary collections when sessions end")
+
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+	db := collection.Database()
+	collName := collection.Name() + "_temp"
+
+	sess, err := db.Client().StartSession()
+	require.NoError(t, err)
+
+	defer sess.EndSession(ctx)
+
+	err = mongo.WithSession(ctx, sess, func(sctx mongo.SessionContext) error {
+		return db.RunCommand(sctx, bson.D{{"create", collName}, {"temp", true}}).Err()
+	})
+	require.NoError(t, err)
+
+	names, err := db.ListCollectionNames(ctx, bson.D{})
+	require.NoError(t, err)
+	assert.Contains(t, names, collName)
+
+	var res bson.D
+	err = db.RunCommand(ctx, bson.D{{"refreshSessions", bson.A{sess.ID()}}}).Decode(&res)
+	require.NoError(t, err)
+	AssertEqualDocuments(t, bson.D{{"ok", float64(1)}}, res)
+
+	names, err = db.ListCollectionNames(ctx, bson.D{})
+	require.NoError(t, err)
+	assert.Contains(t, names, collName)
+
+	err = db.RunCommand(ctx, bson.D{{"endSessions", bson.A{sess.ID()}}}).Decode(&res)
+	require.NoError(t, err)
+	AssertEqualDocuments(t, bson.D{{"ok", float64(1)}}, res)
+
+	names, err = db.ListCollectionNames(ctx, bson.D{})
+	require.NoError(t, err)
+	assert.NotContains(t, names, collName)
+
+	err = db.RunCommand(ctx, bson.D{{"endSessions", bson.A{int32(42)}}}).Err()
+	AssertEqualCommandError(t, mongo.CommandError{
+		Code:    14,
+		Name:    "TypeMismatch",
+		Message: "BSON field 'endSessions.0' is the wrong type 'int', expected type 'object'",
+	}, err)
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/FerretDB/FerretDB/integration/setup"
 	"github.com/FerretDB/FerretDB/integration/shareddata"
@@ -135,3 +136,26 @@ func TestDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestDeleteLet(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", "foo"}, {"v", "foo"}},
+		bson.D{{"_id", "bar"}, {"v", "bar"}},
+	})
+	require.NoError(t, err)
+
+	res, err := collection.DeleteMany(
+		ctx,
+		bson.D{{"$expr", bson.D{{"$regexMatch", bson.D{{"input", "$v"}, {"regex", "$$re"}}}}}},
+		options.Delete().SetLet(bson.D{{"re", "^b"}}),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), res.DeletedCount)
+
+	actual := FilterAll(t, ctx, collection, bson.D{})
+	AssertEqualDocumentsSlice(t, []bson.D{{{"_id", "foo"}, {"v", "foo"}}}, actual)
+}
@@ -636,3 +636,25 @@ func TestFindAndModifyCommentQuery(t *testing.T) {
 	))
 	testutil.AssertEqual(t, expectedLastErrObj, lastErrObj.(*types.Document))
 }
+
+func TestFindAndModifyLet(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", "foo"}, {"v", "foo"}},
+		bson.D{{"_id", "bar"}, {"v", "bar"}},
+	})
+	require.NoError(t, err)
+
+	var actual bson.D
+	err = collection.FindOneAndUpdate(
+		ctx,
+		bson.D{{"$expr", bson.D{{"$regexMatch", bson.D{{"input", "$v"}, {"regex", "$$re"}}}}}},
+		bson.D{{"$set", bson.D{{"matched", true}}}},
+		options.FindOneAndUpdate().SetLet(bson.D{{"re", "^b"}}).SetReturnDocument(options.After),
+	).Decode(&actual)
+	require.NoError(t, err)
+	AssertEqualDocuments(t, bson.D{{"_id", "bar"}, {"v", "bar"}, {"matched", true}}, actual)
+}
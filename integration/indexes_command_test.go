@@ -16,14 +16,18 @@ package integration
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/FerretDB/FerretDB/integration/setup"
 	"github.com/FerretDB/FerretDB/integration/shareddata"
+	"github.com/FerretDB/FerretDB/internal/util/must"
 )
 
 // TestListIndexesCommandNonExistentNS tests that the listIndexes command returns a particular error
@@ -404,6 +408,51 @@ func TestCreateIndexesCommandInvalidSpec(t *testing.T) {
 			altMessage: `Error in specification { key: { v: 1 }, name: "unique_index", unique: {  } } ` +
 				`:: caused by :: The field 'unique' has value unique: {  }, which is not convertible to bool`,
 		},
+		"TTLCompound": {
+			indexes: bson.A{
+				bson.D{
+					{"key", bson.D{{"v", 1}, {"foo", 1}}},
+					{"name", "ttl_index"},
+					{"expireAfterSeconds", 10},
+				},
+			},
+			err: &mongo.CommandError{
+				Code: 67,
+				Name: "CannotCreateIndex",
+				Message: "TTL indexes are single-field indexes, compound indexes do not support TTL. " +
+					`Index spec: { key: { v: 1, foo: 1 }, name: "ttl_index", expireAfterSeconds: 10 }`,
+			},
+		},
+		"TTLNegative": {
+			indexes: bson.A{
+				bson.D{
+					{"key", bson.D{{"v", 1}}},
+					{"name", "ttl_index"},
+					{"expireAfterSeconds", -1},
+				},
+			},
+			err: &mongo.CommandError{
+				Code: 67,
+				Name: "CannotCreateIndex",
+				Message: "TTL index 'expireAfterSeconds' option must be within an acceptable range, try a lower number. " +
+					`Index spec: { key: { v: 1 }, name: "ttl_index", expireAfterSeconds: -1 }`,
+			},
+		},
+		"TTLString": {
+			indexes: bson.A{
+				bson.D{
+					{"key", bson.D{{"v", 1}}},
+					{"name", "ttl_index"},
+					{"expireAfterSeconds", "10"},
+				},
+			},
+			err: &mongo.CommandError{
+				Code: 67,
+				Name: "CannotCreateIndex",
+				Message: "TTL index 'expireAfterSeconds' option must be numeric, but received a type of 'string'. " +
+					`Index spec: { key: { v: 1 }, name: "ttl_index", expireAfterSeconds: "10" }`,
+			},
+		},
 	} {
 		name, tc := name, tc
 		t.Run(name, func(t *testing.T) {
@@ -599,3 +648,285 @@ func TestDropIndexesCommandInvalidCollection(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateIndexesCommandTTL(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	now := time.Now()
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", "expired"}, {"v", primitive.NewDateTimeFromTime(now.Add(-time.Hour))}},
+		bson.D{{"_id", "expired-array"}, {"v", bson.A{"foo", primitive.NewDateTimeFromTime(now.Add(-time.Hour))}}},
+		bson.D{{"_id", "fresh"}, {"v", primitive.NewDateTimeFromTime(now.Add(time.Hour))}},
+		bson.D{{"_id", "not-date"}, {"v", "foo"}},
+		bson.D{{"_id", "missing"}},
+	})
+	require.NoError(t, err)
+
+	var res bson.D
+	err = collection.Database().RunCommand(ctx, bson.D{
+		{"createIndexes", collection.Name()},
+		{"indexes", bson.A{bson.D{
+			{"key", bson.D{{"v", 1}}},
+			{"name", "v_ttl"},
+			{"expireAfterSeconds", int32(60)},
+		}}},
+	}).Decode(&res)
+	require.NoError(t, err)
+
+	cursor, err := collection.Indexes().List(ctx)
+	require.NoError(t, err)
+
+	var indexes []bson.D
+	require.NoError(t, cursor.All(ctx, &indexes))
+	require.Len(t, indexes, 2)
+
+	index := ConvertDocument(t, indexes[1])
+	assert.Equal(t, "v_ttl", must.NotFail(index.Get("name")))
+	assert.Equal(t, int32(60), must.NotFail(index.Get("expireAfterSeconds")))
+
+	// MongoDB's TTL monitor runs every 60 seconds by default
+	require.Eventually(t, func() bool {
+		var n int64
+		n, err = collection.CountDocuments(ctx, bson.D{})
+		require.NoError(t, err)
+
+		return n == 3
+	}, 2*time.Minute, 100*time.Millisecond)
+
+	cursor, err = collection.Find(ctx, bson.D{}, options.Find().SetSort(bson.D{{"_id", 1}}))
+	require.NoError(t, err)
+
+	var docs []bson.D
+	require.NoError(t, cursor.All(ctx, &docs))
+
+	ids := make([]any, len(docs))
+	for i, doc := range docs {
+		ids[i] = doc.Map()["_id"]
+	}
+
+	assert.Equal(t, []any{"fresh", "missing", "not-date"}, ids)
+}
+
+func TestCreateIndexesCommandComputed(t *testing.T) {
+	setup.SkipForMongoDB(t, "Computed index keys are FerretDB extension")
+
+	if !setup.IsPostgreSQL(t) {
+		t.Skip("Computed index keys are supported only by PostgreSQL backend")
+	}
+
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+	db := collection.Database()
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", "lower"}, {"email", "foo@example.com"}},
+		bson.D{{"_id", "mixed"}, {"email", "Foo@Example.com"}},
+		bson.D{{"_id", "array"}, {"email", bson.A{"bar@example.com", "Foo@Example.com"}}},
+		bson.D{{"_id", "other"}, {"email", "bar@example.com"}},
+		bson.D{{"_id", "items"}, {"items", bson.A{bson.D{{"sku", "a"}}, bson.D{{"sku", "b"}}}}},
+	})
+	require.NoError(t, err)
+
+	var res bson.D
+	err = db.RunCommand(ctx, bson.D{
+		{"createIndexes", collection.Name()},
+		{"indexes", bson.A{
+			bson.D{
+				{"key", bson.D{{"email", bson.D{{"$toLower", 1}}}}},
+				{"name", "email_lower"},
+			},
+			bson.D{
+				{"key", bson.D{{"items.sku", 1}, {"items.name", bson.D{{"$toLower", -1}}}}},
+				{"name", "items"},
+			},
+		}},
+	}).Decode(&res)
+	require.NoError(t, err)
+
+	cursor, err := collection.Indexes().List(ctx)
+	require.NoError(t, err)
+
+	var indexes []bson.D
+	require.NoError(t, cursor.All(ctx, &indexes))
+	require.Len(t, indexes, 3)
+
+	AssertEqualDocuments(t, bson.D{
+		{"v", int32(2)},
+		{"key", bson.D{{"email", bson.D{{"$toLower", int32(1)}}}}},
+		{"name", "email_lower"},
+	}, indexes[1])
+	AssertEqualDocuments(t, bson.D{
+		{"v", int32(2)},
+		{"key", bson.D{{"items.sku", int32(1)}, {"items.name", bson.D{{"$toLower", int32(-1)}}}}},
+		{"name", "items"},
+	}, indexes[2])
+
+	// filters on indexed fields still match exactly
+	for name, tc := range map[string]struct {
+		filter   bson.D
+		expected []any
+	}{
+		"Lower": {
+			filter:   bson.D{{"email", "foo@example.com"}},
+			expected: []any{"lower"},
+		},
+		"Mixed": {
+			filter:   bson.D{{"email", bson.D{{"$eq", "Foo@Example.com"}}}},
+			expected: []any{"array", "mixed"},
+		},
+		"DotNotation": {
+			filter:   bson.D{{"items.sku", "b"}},
+			expected: []any{"items"},
+		},
+		"RegexCaseInsensitive": {
+			filter:   bson.D{{"email", bson.D{{"$regex", `^foo@example\.com$`}, {"$options", "i"}}}},
+			expected: []any{"array", "lower", "mixed"},
+		},
+		"RegexCaseInsensitiveValue": {
+			filter:   bson.D{{"email", primitive.Regex{Pattern: `^FOO@example\.com$`, Options: "i"}}},
+			expected: []any{"array", "lower", "mixed"},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			cursor, err := collection.Find(ctx, tc.filter, options.Find().SetSort(bson.D{{"_id", 1}}))
+			require.NoError(t, err)
+
+			var docs []bson.D
+			require.NoError(t, cursor.All(ctx, &docs))
+
+			ids := make([]any, len(docs))
+			for i, doc := range docs {
+				ids[i] = doc.Map()["_id"]
+			}
+
+			assert.Equal(t, tc.expected, ids)
+		})
+	}
+
+	t.Run("ExplainRegexCaseInsensitive", func(t *testing.T) {
+		if setup.IsPushdownDisabled() {
+			t.Skip("Query pushdown is disabled")
+		}
+
+		var res bson.D
+		err := db.RunCommand(ctx, bson.D{{"explain", bson.D{
+			{"find", collection.Name()},
+			{"filter", bson.D{{"email", bson.D{{"$regex", `^foo@example\.com$`}, {"$options", "i"}}}}},
+		}}}).Decode(&res)
+		require.NoError(t, err)
+
+		assert.Equal(t, true, res.Map()["pushdown"])
+
+		// the same expression as in the index is used, so PostgreSQL could choose it
+		plan, err := bson.MarshalExtJSON(res.Map()["queryPlanner"], false, false)
+		require.NoError(t, err)
+		assert.Contains(t, string(plan), "jsonb_path_query_array")
+		assert.Contains(t, string(plan), "lower(")
+	})
+
+	err = db.RunCommand(ctx, bson.D{
+		{"dropIndexes", collection.Name()},
+		{"index", bson.D{{"email", bson.D{{"$toLower", 1}}}}},
+	}).Err()
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		index bson.D
+		err   *mongo.CommandError
+	}{
+		"Unique": {
+			index: bson.D{
+				{"key", bson.D{{"email", bson.D{{"$toLower", 1}}}}},
+				{"name", "email_unique"},
+				{"unique", true},
+			},
+			err: &mongo.CommandError{
+				Code: 67,
+				Name: "CannotCreateIndex",
+				Message: `Unique indexes with computed keys are not supported. ` +
+					`Index spec: { key: { email: { $toLower: 1 } }, name: "email_unique", unique: true }`,
+			},
+		},
+		"TTL": {
+			index: bson.D{
+				{"key", bson.D{{"email", bson.D{{"$toLower", 1}}}}},
+				{"name", "email_ttl"},
+				{"expireAfterSeconds", int32(60)},
+			},
+			err: &mongo.CommandError{
+				Code: 67,
+				Name: "CannotCreateIndex",
+				Message: `TTL indexes do not support computed keys. ` +
+					`Index spec: { key: { email: { $toLower: 1 } }, name: "email_ttl", expireAfterSeconds: 60 }`,
+			},
+		},
+		"ArrayPosition": {
+			index: bson.D{
+				{"key", bson.D{{"items.0.sku", bson.D{{"$toLower", 1}}}}},
+				{"name", "items_position"},
+			},
+			err: &mongo.CommandError{
+				Code: 67,
+				Name: "CannotCreateIndex",
+				Message: `Index key { items.0.sku: { $toLower: 1 } } with computed keys ` +
+					`can't contain array positions, found "items.0.sku"`,
+			},
+		},
+		"UnknownExpression": {
+			index: bson.D{
+				{"key", bson.D{{"email", bson.D{{"$toUpper", 1}}}}},
+				{"name", "email_upper"},
+			},
+			err: &mongo.CommandError{
+				Code:    238,
+				Name:    "NotImplemented",
+				Message: `Computed index key expression "$toUpper" is not implemented yet`,
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			err := db.RunCommand(ctx, bson.D{
+				{"createIndexes", collection.Name()},
+				{"indexes", bson.A{tc.index}},
+			}).Err()
+			AssertEqualCommandError(t, *tc.err, err)
+		})
+	}
+}
+
+func TestCreateIndexesCommandComputedNotSupported(t *testing.T) {
+	if !setup.IsSQLite(t) {
+		t.Skip("Only SQLite backend rejects computed index keys")
+	}
+
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	err := collection.Database().RunCommand(ctx, bson.D{
+		{"createIndexes", collection.Name()},
+		{"indexes", bson.A{bson.D{
+			{"key", bson.D{{"email", bson.D{{"$toLower", 1}}}}},
+			{"name", "email_lower"},
+		}}},
+	}).Err()
+	AssertEqualCommandError(t, mongo.CommandError{
+		Code:    238,
+		Name:    "NotImplemented",
+		Message: "Computed index keys are not supported by sqlite backend",
+	}, err)
+
+	cursor, err := collection.Indexes().List(ctx)
+	require.NoError(t, err)
+
+	// the collection is not created
+	var indexes []bson.D
+	require.NoError(t, cursor.All(ctx, &indexes))
+	assert.Empty(t, indexes)
+}
@@ -1063,3 +1063,111 @@ func TestQueryIDDoc(t *testing.T) {
 	actual = FilterAll(t, ctx, collection, bson.D{{"_id", bson.D{{"z", int32(4)}, {"a", int32(3)}}}})
 	AssertEqualDocumentsSlice(t, expected, actual)
 }
+
+func TestQueryLet(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", "foo"}, {"v", "foo"}},
+		bson.D{{"_id", "bar"}, {"v", "bar"}},
+	})
+	require.NoError(t, err)
+
+	filter := bson.D{{"$expr", bson.D{{"$regexMatch", bson.D{{"input", "$v"}, {"regex", "$$re"}}}}}}
+
+	cursor, err := collection.Find(ctx, filter, options.Find().SetLet(bson.D{{"re", "^b"}}))
+	require.NoError(t, err)
+	AssertEqualDocumentsSlice(t, []bson.D{{{"_id", "bar"}, {"v", "bar"}}}, FetchAll(t, ctx, cursor))
+
+	opts := options.Find().
+		SetLet(bson.D{{"re", "^b"}, {"x", int32(42)}}).
+		SetProjection(bson.D{{"f", "$$x"}, {"r", "$$REMOVE"}})
+	cursor, err = collection.Find(ctx, filter, opts)
+	require.NoError(t, err)
+	AssertEqualDocumentsSlice(t, []bson.D{{{"_id", "bar"}, {"f", int32(42)}}}, FetchAll(t, ctx, cursor))
+
+	_, err = collection.Find(ctx, filter)
+	AssertEqualCommandError(t, mongo.CommandError{
+		Code:    17276,
+		Name:    "Location17276",
+		Message: "Use of undefined variable: re",
+	}, err)
+}
+
+func TestQueryNatural(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", "c"}, {"v", int32(1)}},
+		bson.D{{"_id", "a"}, {"v", int32(2)}},
+		bson.D{{"_id", "b"}, {"v", int32(1)}},
+	})
+	require.NoError(t, err)
+
+	// updated document keeps its position in the natural order
+	_, err = collection.UpdateOne(ctx, bson.D{{"_id", "c"}}, bson.D{{"$set", bson.D{{"w", true}}}})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		opts     *options.FindOptions // required
+		expected []string             // required, expected _id values in order
+	}{
+		"Ascending": {
+			opts:     options.Find().SetSort(bson.D{{"$natural", 1}}),
+			expected: []string{"c", "a", "b"},
+		},
+		"Descending": {
+			opts:     options.Find().SetSort(bson.D{{"$natural", -1}}),
+			expected: []string{"b", "a", "c"},
+		},
+		"DescendingLimit": {
+			opts:     options.Find().SetSort(bson.D{{"$natural", -1}}).SetLimit(2),
+			expected: []string{"b", "a"},
+		},
+		"Hint": {
+			opts:     options.Find().SetHint(bson.D{{"$natural", -1}}),
+			expected: []string{"b", "a", "c"},
+		},
+		"TieBreak": {
+			opts:     options.Find().SetSort(bson.D{{"v", 1}, {"$natural", -1}}),
+			expected: []string{"b", "c", "a"},
+		},
+		"TieBreakLimit": {
+			opts:     options.Find().SetSort(bson.D{{"v", -1}, {"$natural", 1}}).SetLimit(2),
+			expected: []string{"a", "c"},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cursor, err := collection.Find(ctx, bson.D{}, tc.opts)
+			require.NoError(t, err)
+
+			var res []bson.D
+			require.NoError(t, cursor.All(ctx, &res))
+
+			actual := make([]string, len(res))
+			for i, doc := range res {
+				actual[i] = doc.Map()["_id"].(string)
+			}
+
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+
+	t.Run("BadValue", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := collection.Find(ctx, bson.D{}, options.Find().SetSort(bson.D{{"$natural", 2}}))
+		AssertEqualCommandError(t, mongo.CommandError{
+			Code:    15975,
+			Name:    "Location15975",
+			Message: "$sort key ordering must be 1 (for ascending) or -1 (for descending)",
+		}, err)
+	})
+}
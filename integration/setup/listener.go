@@ -22,6 +22,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -30,6 +31,8 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/FerretDB/FerretDB/internal/clientconn"
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/internal/clientconn/session"
 	"github.com/FerretDB/FerretDB/internal/handlers/registry"
 	"github.com/FerretDB/FerretDB/internal/util/observability"
 	"github.com/FerretDB/FerretDB/internal/util/state"
@@ -216,15 +219,25 @@ func setupListener(tb testtb.TB, ctx context.Context, logger *zap.Logger) string
 	sp, err := state.NewProvider("")
 	require.NoError(tb, err)
 
+	connRegistry := conninfo.NewRegistry()
+	sessions := session.NewRegistry(logger.Named("sessions"))
+
 	handlerOpts := &registry.NewHandlerOpts{
 		Logger:        logger,
 		ConnMetrics:   listenerMetrics.ConnMetrics,
 		StateProvider: sp,
+		ConnRegistry:  connRegistry,
+		Sessions:      sessions,
 
 		PostgreSQLURL: postgreSQLURLF,
 		SQLiteURL:     sqliteURL,
 		HANAURL:       *hanaURLF,
 
+		// sweep expired documents often to make TTL tests fast
+		MaintenanceOpts: registry.MaintenanceOpts{
+			TTLInterval: time.Second,
+		},
+
 		TestOpts: registry.TestOpts{
 			DisableFilterPushdown: *disableFilterPushdownF,
 			EnableSortPushdown:    *enableSortPushdownF,
@@ -240,6 +253,8 @@ func setupListener(tb testtb.TB, ctx context.Context, logger *zap.Logger) string
 		ProxyAddr:      *targetProxyAddrF,
 		Mode:           clientconn.NormalMode,
 		Metrics:        listenerMetrics,
+		ConnRegistry:   connRegistry,
+		Sessions:       sessions,
 		Handler:        h,
 		Logger:         logger,
 		TestRecordsDir: filepath.Join("..", "tmp", "records"),
@@ -656,3 +656,56 @@ func TestUpdateFieldErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateFieldLet(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", "foo"}, {"v", "foo"}},
+		bson.D{{"_id", "bar"}, {"v", "bar"}},
+	})
+	require.NoError(t, err)
+
+	res, err := collection.UpdateMany(
+		ctx,
+		bson.D{{"$expr", bson.D{{"$regexMatch", bson.D{{"input", "$v"}, {"regex", "$$re"}}}}}},
+		bson.D{{"$set", bson.D{{"matched", true}}}},
+		options.Update().SetLet(bson.D{{"re", "^b"}}),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), res.MatchedCount)
+	assert.Equal(t, int64(1), res.ModifiedCount)
+
+	actual := FilterAll(t, ctx, collection, bson.D{{"matched", true}})
+	AssertEqualDocumentsSlice(t, []bson.D{{{"_id", "bar"}, {"v", "bar"}, {"matched", true}}}, actual)
+}
+
+func TestUpdateFieldLetPipeline(t *testing.T) {
+	setup.SkipForMongoDB(t, "Pipeline-style updates with let variables are supported by MongoDB")
+
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertOne(ctx, bson.D{{"_id", "foo"}, {"v", "foo"}})
+	require.NoError(t, err)
+
+	_, err = collection.UpdateOne(
+		ctx,
+		bson.D{{"_id", "foo"}},
+		bson.A{bson.D{{"$set", bson.D{{"v", "$$x"}}}}},
+		options.Update().SetLet(bson.D{{"x", "bar"}}),
+	)
+
+	expected := mongo.CommandError{
+		Code:    238,
+		Name:    "NotImplemented",
+		Message: "let variables are not supported with pipeline-style updates yet",
+	}
+	AssertEqualCommandError(t, expected, err)
+
+	actual := FilterAll(t, ctx, collection, bson.D{})
+	AssertEqualDocumentsSlice(t, []bson.D{{{"_id", "foo"}, {"v", "foo"}}}, actual)
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/FerretDB/FerretDB/build/version"
 	"github.com/FerretDB/FerretDB/internal/backends"
 	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
 	"github.com/FerretDB/FerretDB/internal/util/testutil"
@@ -59,6 +60,8 @@ func TestVersion(t *testing.T) {
 				assert.True(t, strings.HasPrefix(s.BackendVersion, "16.0 ("), "%s", s.BackendName)
 			case "SQLite":
 				assert.Equal(t, "3.41.2", s.BackendVersion)
+			case "Memory":
+				assert.Equal(t, version.Get().Version, s.BackendVersion)
 			default:
 				t.Fatalf("unknown backend: %s", name)
 			}
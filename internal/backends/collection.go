@@ -71,7 +71,23 @@ func CollectionContract(c Collection) Collection {
 	}
 }
 
+// NaturalSortKey is a SortField key for the natural order of documents.
+//
+// Natural order is the order in which documents were inserted;
+// updates do not change it.
+const NaturalSortKey = "$natural"
+
+// DefaultIndexSortKey is a SortField key for the order of the default index on _id.
+//
+// That order is not the same as the BSON order of _id values; it is used to store documents
+// physically clustered by _id. Backends that do not have such physical order may ignore it.
+const DefaultIndexSortKey = "$" + DefaultIndexName
+
 // SortField consists of a field name and a sort order that are used in queries.
+//
+// Backends may ignore sorting by fields (the handler sorts documents anyway),
+// but they must return documents in the natural order if Key is NaturalSortKey,
+// or ErrorCodeNaturalOrderNotSupported error if the collection does not track it.
 type SortField struct {
 	Key        string
 	Descending bool
@@ -103,7 +119,7 @@ func (cc *collectionContract) Query(ctx context.Context, params *QueryParams) (*
 	defer observability.FuncCall(ctx)()
 
 	res, err := cc.c.Query(ctx, params)
-	checkError(err)
+	checkError(err, ErrorCodeNaturalOrderNotSupported)
 
 	return res, err
 }
@@ -230,7 +246,7 @@ func (cc *collectionContract) Explain(ctx context.Context, params *ExplainParams
 	defer observability.FuncCall(ctx)()
 
 	res, err := cc.c.Explain(ctx, params)
-	checkError(err)
+	checkError(err, ErrorCodeNaturalOrderNotSupported)
 
 	return res, err
 }
@@ -304,14 +320,31 @@ type IndexInfo struct {
 	Name   string
 	Key    []IndexKeyPair
 	Unique bool
+
+	// ExpireAfterSeconds is set for TTL indexes only.
+	ExpireAfterSeconds *int32
 }
 
 // IndexKeyPair consists of a field name and a sort order that are part of the index.
 type IndexKeyPair struct {
 	Field      string
 	Descending bool
+
+	// Expression is set for computed keys only.
+	// The index is built on the result of that expression applied to the field values.
+	// Only PostgreSQL backend supports computed keys.
+	Expression IndexKeyExpression
 }
 
+// IndexKeyExpression represents an expression of the computed index key.
+type IndexKeyExpression string
+
+// Supported computed index key expressions.
+const (
+	// IndexKeyExpressionToLower indexes lowercased string values.
+	IndexKeyExpressionToLower = IndexKeyExpression("$toLower")
+)
+
 // ListIndexes returns a list of collection indexes.
 //
 // The errors for non-existing database and non-existing collection are the same.
@@ -333,6 +366,11 @@ func (cc *collectionContract) ListIndexes(ctx context.Context, params *ListIndex
 // CreateIndexesParams represents the parameters of Collection.CreateIndexes method.
 type CreateIndexesParams struct {
 	Indexes []IndexInfo
+
+	// Progress, if not nil, is called periodically while indexes are being built
+	// with the amount of already done and total work.
+	// Units are backend-specific.
+	Progress func(done, total int64)
 }
 
 // CreateIndexesResult represents the results of Collection.CreateIndexes method.
@@ -343,6 +381,9 @@ type CreateIndexesResult struct{}
 // The operation should be atomic.
 // If some indexes cannot be created, the operation should be rolled back,
 // and the first encountered error should be returned.
+// The same applies if the context is canceled while indexes are being built.
+//
+// Backends should not block other operations while indexes are being built, if possible.
 //
 // Database or collection may not exist; that's not an error.
 func (cc *collectionContract) CreateIndexes(ctx context.Context, params *CreateIndexesParams) (*CreateIndexesResult, error) {
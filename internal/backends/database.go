@@ -41,6 +41,8 @@ type Database interface {
 	RenameCollection(context.Context, *RenameCollectionParams) error
 
 	Stats(context.Context, *DatabaseStatsParams) (*DatabaseStatsResult, error)
+
+	ValidateMetadata(context.Context, *ValidateMetadataParams) (*ValidateMetadataResult, error)
 }
 
 // databaseContract implements Database interface.
@@ -87,8 +89,11 @@ type ListCollectionsResult struct {
 // CollectionInfo represents information about a single collection.
 type CollectionInfo struct {
 	Name            string
-	CappedSize      int64 // TODO https://github.com/FerretDB/FerretDB/issues/3458
-	CappedDocuments int64 // TODO https://github.com/FerretDB/FerretDB/issues/3458
+	UUID            string // empty for collections created by older versions
+	CappedSize      int64  // TODO https://github.com/FerretDB/FerretDB/issues/3458
+	CappedDocuments int64  // TODO https://github.com/FerretDB/FerretDB/issues/3458
+	Temp            bool
+	TempOwner       string // set only for temporary collections
 }
 
 // Capped returns true if collection is capped.
@@ -121,6 +126,16 @@ type CreateCollectionParams struct {
 	Name            string
 	CappedSize      int64 // TODO https://github.com/FerretDB/FerretDB/issues/3458
 	CappedDocuments int64 // TODO https://github.com/FerretDB/FerretDB/issues/3458
+
+	// Temp marks the collection as temporary in the metadata.
+	// The handler drops such collections when sessions that created them end,
+	// and on startup, when all sessions of that handler are gone.
+	Temp bool
+
+	// TempOwner identifies the FerretDB instance that created the temporary collection,
+	// so other instances sharing the same database never drop it on their startup.
+	// It should be set if and only if Temp is true.
+	TempOwner string
 }
 
 // Capped returns true if capped collection creation is requested.
@@ -137,6 +152,7 @@ func (dbc *databaseContract) CreateCollection(ctx context.Context, params *Creat
 	must.BeTrue(params.CappedSize >= 0)
 	must.BeTrue(params.CappedSize%256 == 0)
 	must.BeTrue(params.CappedDocuments >= 0)
+	must.BeTrue(params.Temp == (params.TempOwner != ""))
 
 	err := validateCollectionName(params.Name)
 	if err == nil {
@@ -151,11 +167,13 @@ func (dbc *databaseContract) CreateCollection(ctx context.Context, params *Creat
 // DropCollectionParams represents the parameters of Database.DropCollection method.
 type DropCollectionParams struct {
 	Name string
+	UUID string // if set, the collection is dropped only if it has that UUID
 }
 
 // DropCollection drops existing collection with valid name in the database.
 //
 // The errors for non-existing database and non-existing collection are the same.
+// The collection with a different UUID is treated as non-existing.
 func (dbc *databaseContract) DropCollection(ctx context.Context, params *DropCollectionParams) error {
 	defer observability.FuncCall(ctx)()
 
@@ -171,14 +189,17 @@ func (dbc *databaseContract) DropCollection(ctx context.Context, params *DropCol
 
 // RenameCollectionParams represents the parameters of Database.RenameCollection method.
 type RenameCollectionParams struct {
-	OldName string
-	NewName string
+	OldName    string
+	NewName    string
+	DropTarget bool // if set, the existing collection with the new name is dropped atomically
 }
 
 // RenameCollection renames existing collection in the database.
 // Both old and new names should be valid.
+// Renamed collection is no longer temporary.
 //
 // The errors for non-existing database and non-existing collection are the same.
+// The error for existing collection with the new name is returned only if DropTarget is not set.
 func (dbc *databaseContract) RenameCollection(ctx context.Context, params *RenameCollectionParams) error {
 	defer observability.FuncCall(ctx)()
 
@@ -221,6 +242,69 @@ func (dbc *databaseContract) Stats(ctx context.Context, params *DatabaseStatsPar
 	return res, err
 }
 
+// ValidateMetadataParams represents the parameters of Database.ValidateMetadata method.
+type ValidateMetadataParams struct {
+	Repair bool
+}
+
+// ValidateMetadataResult represents the results of Database.ValidateMetadata method.
+type ValidateMetadataResult struct {
+	Problems []MetadataProblem
+}
+
+// MetadataProblemType represents a type of discrepancy between metadata and the actual database schema.
+type MetadataProblemType string
+
+const (
+	// MetadataProblemMissingTable is a registered collection without a table.
+	MetadataProblemMissingTable = MetadataProblemType("missingTable")
+
+	// MetadataProblemOrphanTable is a table without a registered collection.
+	MetadataProblemOrphanTable = MetadataProblemType("orphanTable")
+
+	// MetadataProblemMissingIndex is a registered index without a backend index.
+	MetadataProblemMissingIndex = MetadataProblemType("missingIndex")
+
+	// MetadataProblemOrphanIndex is a backend index without a registered index.
+	MetadataProblemOrphanIndex = MetadataProblemType("orphanIndex")
+
+	// MetadataProblemMissingRecordIDs is a registered collection without record IDs
+	// that define the natural order of documents.
+	MetadataProblemMissingRecordIDs = MetadataProblemType("missingRecordIDs")
+)
+
+// MetadataProblem represents a single discrepancy between metadata and the actual database schema.
+type MetadataProblem struct {
+	Type       MetadataProblemType
+	Collection string // empty if the table is not registered
+	Table      string
+	Index      string // registered index name for missing indexes, backend index name for orphan indexes
+	Action     string // empty if the problem can't be repaired automatically
+	Repaired   bool
+}
+
+// ValidateMetadata cross-checks collections and indexes metadata of the database
+// against tables and indexes that actually exist.
+//
+// If Repair is true, found problems are also repaired when possible.
+// Indexes are checked only for registered collections, including ones registered by the repair.
+//
+// Contract ensures that problems are not marked as repaired without the Repair parameter.
+//
+//nolint:lll // for readability
+func (dbc *databaseContract) ValidateMetadata(ctx context.Context, params *ValidateMetadataParams) (*ValidateMetadataResult, error) {
+	defer observability.FuncCall(ctx)()
+
+	res, err := dbc.db.ValidateMetadata(ctx, params)
+	checkError(err, ErrorCodeDatabaseDoesNotExist)
+
+	if res != nil && !params.Repair {
+		must.BeTrue(!slices.ContainsFunc(res.Problems, func(p MetadataProblem) bool { return p.Repaired }))
+	}
+
+	return res, err
+}
+
 // check interfaces
 var (
 	_ Database = (*databaseContract)(nil)
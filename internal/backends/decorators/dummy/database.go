@@ -67,6 +67,13 @@ func (db *database) Stats(ctx context.Context, params *backends.DatabaseStatsPar
 	return db.db.Stats(ctx, params)
 }
 
+// ValidateMetadata implements backends.Database interface.
+//
+//nolint:lll // for readability
+func (db *database) ValidateMetadata(ctx context.Context, params *backends.ValidateMetadataParams) (*backends.ValidateMetadataResult, error) {
+	return db.db.ValidateMetadata(ctx, params)
+}
+
 // check interfaces
 var (
 	_ backends.Database = (*database)(nil)
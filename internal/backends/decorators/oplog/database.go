@@ -77,6 +77,13 @@ func (db *database) Stats(ctx context.Context, params *backends.DatabaseStatsPar
 	return db.origDB.Stats(ctx, params)
 }
 
+// ValidateMetadata implements backends.Database interface.
+//
+//nolint:lll // for readability
+func (db *database) ValidateMetadata(ctx context.Context, params *backends.ValidateMetadataParams) (*backends.ValidateMetadataResult, error) {
+	return db.origDB.ValidateMetadata(ctx, params)
+}
+
 // check interfaces
 var (
 	_ backends.Database = (*database)(nil)
@@ -39,6 +39,8 @@ const (
 	ErrorCodeCollectionAlreadyExists
 
 	ErrorCodeInsertDuplicateID
+
+	ErrorCodeNaturalOrderNotSupported
 )
 
 // Error represents a backend error returned by all Backend, Database and Collection methods.
@@ -14,11 +14,12 @@ func _() {
 	_ = x[ErrorCodeCollectionDoesNotExist-4]
 	_ = x[ErrorCodeCollectionAlreadyExists-5]
 	_ = x[ErrorCodeInsertDuplicateID-6]
+	_ = x[ErrorCodeNaturalOrderNotSupported-7]
 }
 
-const _ErrorCode_name = "ErrorCodeDatabaseNameIsInvalidErrorCodeDatabaseDoesNotExistErrorCodeCollectionNameIsInvalidErrorCodeCollectionDoesNotExistErrorCodeCollectionAlreadyExistsErrorCodeInsertDuplicateID"
+const _ErrorCode_name = "ErrorCodeDatabaseNameIsInvalidErrorCodeDatabaseDoesNotExistErrorCodeCollectionNameIsInvalidErrorCodeCollectionDoesNotExistErrorCodeCollectionAlreadyExistsErrorCodeInsertDuplicateIDErrorCodeNaturalOrderNotSupported"
 
-var _ErrorCode_index = [...]uint8{0, 30, 59, 91, 122, 154, 180}
+var _ErrorCode_index = [...]uint8{0, 30, 59, 91, 122, 154, 180, 213}
 
 func (i ErrorCode) String() string {
 	i -= 1
@@ -56,6 +56,13 @@ func (db *database) Stats(ctx context.Context, params *backends.DatabaseStatsPar
 	return nil, lazyerrors.New("not implemented yet")
 }
 
+// ValidateMetadata implements backends.Database interface.
+//
+//nolint:lll // for readability
+func (db *database) ValidateMetadata(ctx context.Context, params *backends.ValidateMetadataParams) (*backends.ValidateMetadataResult, error) {
+	return nil, lazyerrors.New("not implemented yet")
+}
+
 // check interfaces
 var (
 	_ backends.Database = (*database)(nil)
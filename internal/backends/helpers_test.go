@@ -22,6 +22,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/backends/memory"
 	"github.com/FerretDB/FerretDB/internal/backends/postgresql"
 	"github.com/FerretDB/FerretDB/internal/backends/sqlite"
 	"github.com/FerretDB/FerretDB/internal/util/state"
@@ -82,6 +83,23 @@ func testBackends(t *testing.T) map[string]*testBackend {
 		}
 	}
 
+	{
+		sp, err := state.NewProvider("")
+		require.NoError(t, err)
+
+		b, err := memory.NewBackend(&memory.NewBackendParams{
+			L: l.Named("memory"),
+			P: sp,
+		})
+		require.NoError(t, err)
+		t.Cleanup(b.Close)
+
+		res["memory"] = &testBackend{
+			Backend: b,
+			sp:      sp,
+		}
+	}
+
 	return res
 }
 
@@ -0,0 +1,168 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"github.com/FerretDB/FerretDB/build/version"
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/state"
+)
+
+// backend implements backends.Backend interface.
+type backend struct {
+	s  *storage
+	l  *zap.Logger
+	sp *state.Provider
+}
+
+// NewBackendParams represents the parameters of NewBackend function.
+//
+//nolint:vet // for readability
+type NewBackendParams struct {
+	L *zap.Logger
+	P *state.Provider
+}
+
+// NewBackend creates a new backend.
+func NewBackend(params *NewBackendParams) (backends.Backend, error) {
+	return backends.BackendContract(&backend{
+		s: &storage{
+			dbs: map[string]map[string]*collectionData{},
+		},
+		l:  params.L,
+		sp: params.P,
+	}), nil
+}
+
+// Close implements backends.Backend interface.
+func (b *backend) Close() {
+	b.s.rw.Lock()
+	defer b.s.rw.Unlock()
+
+	b.s.dbs = map[string]map[string]*collectionData{}
+}
+
+// Status implements backends.Backend interface.
+func (b *backend) Status(ctx context.Context, params *backends.StatusParams) (*backends.StatusResult, error) {
+	b.s.rw.RLock()
+	defer b.s.rw.RUnlock()
+
+	var res backends.StatusResult
+
+	for _, colls := range b.s.dbs {
+		res.CountCollections += int64(len(colls))
+	}
+
+	return &res, nil
+}
+
+// Database implements backends.Backend interface.
+func (b *backend) Database(name string) (backends.Database, error) {
+	return newDatabase(b, name), nil
+}
+
+// ListDatabases implements backends.Backend interface.
+//
+//nolint:lll // for readability
+func (b *backend) ListDatabases(ctx context.Context, params *backends.ListDatabasesParams) (*backends.ListDatabasesResult, error) {
+	b.s.rw.RLock()
+	defer b.s.rw.RUnlock()
+
+	res := &backends.ListDatabasesResult{
+		Databases: make([]backends.DatabaseInfo, 0, len(b.s.dbs)),
+	}
+
+	for dbName := range b.s.dbs {
+		res.Databases = append(res.Databases, backends.DatabaseInfo{
+			Name: dbName,
+		})
+	}
+
+	slices.SortFunc(res.Databases, func(a, b backends.DatabaseInfo) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	return res, nil
+}
+
+// DropDatabase implements backends.Backend interface.
+func (b *backend) DropDatabase(ctx context.Context, params *backends.DropDatabaseParams) error {
+	b.s.rw.Lock()
+	defer b.s.rw.Unlock()
+
+	if _, ok := b.s.dbs[params.Name]; !ok {
+		return backends.NewError(backends.ErrorCodeDatabaseDoesNotExist, nil)
+	}
+
+	delete(b.s.dbs, params.Name)
+
+	return nil
+}
+
+// Describe implements prometheus.Collector.
+func (b *backend) Describe(ch chan<- *prometheus.Desc) {}
+
+// Collect implements prometheus.Collector.
+func (b *backend) Collect(ch chan<- prometheus.Metric) {}
+
+// collectionCreate creates a collection (and database) if needed and returns it.
+// It also returns true if collection was created.
+//
+// It does not hold the lock.
+func (b *backend) collectionCreate(dbName, collectionName string) (*collectionData, bool, error) {
+	colls := b.s.dbs[dbName]
+	if colls == nil {
+		if b.sp.Get().BackendName == "" {
+			if err := b.sp.Update(func(s *state.State) {
+				s.BackendName = "Memory"
+				s.BackendVersion = version.Get().Version
+			}); err != nil {
+				return nil, false, lazyerrors.Error(err)
+			}
+		}
+
+		colls = map[string]*collectionData{}
+		b.s.dbs[dbName] = colls
+	}
+
+	if c := colls[collectionName]; c != nil {
+		return c, false, nil
+	}
+
+	c := newCollectionData()
+	colls[collectionName] = c
+
+	return c, true, nil
+}
+
+// collectionGet returns collection data, or nil if database or collection does not exist.
+//
+// It does not hold the lock.
+func (b *backend) collectionGet(dbName, collectionName string) *collectionData {
+	return b.s.dbs[dbName][collectionName]
+}
+
+// check interfaces
+var (
+	_ backends.Backend = (*backend)(nil)
+)
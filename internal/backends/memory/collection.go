@@ -0,0 +1,448 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// collection implements backends.Collection interface.
+type collection struct {
+	b      *backend
+	dbName string
+	name   string
+}
+
+// newCollection creates a new Collection.
+func newCollection(b *backend, dbName, name string) backends.Collection {
+	return backends.CollectionContract(&collection{
+		b:      b,
+		dbName: dbName,
+		name:   name,
+	})
+}
+
+// Query implements backends.Collection interface.
+func (c *collection) Query(ctx context.Context, params *backends.QueryParams) (*backends.QueryResult, error) {
+	if params == nil {
+		params = new(backends.QueryParams)
+	}
+
+	c.b.s.rw.RLock()
+	defer c.b.s.rw.RUnlock()
+
+	coll := c.b.collectionGet(c.dbName, c.name)
+	if coll == nil {
+		return &backends.QueryResult{
+			Iter: newQueryIterator(ctx, nil),
+		}, nil
+	}
+
+	var records []*record
+
+	if id, ok := idFilter(params.Filter); ok {
+		if r := coll.ids[idKey(id)]; r != nil {
+			records = []*record{r}
+		}
+	} else {
+		records = slices.Clone(coll.records)
+	}
+
+	if params.Sort != nil {
+		switch params.Sort.Key {
+		case backends.DefaultIndexSortKey:
+			slices.SortFunc(records, func(a, b *record) int { return cmp.Compare(a.id, b.id) })
+			fallthrough
+		case backends.NaturalSortKey:
+			if params.Sort.Descending {
+				slices.Reverse(records)
+			}
+		}
+	}
+
+	if params.Limit != 0 && int64(len(records)) > params.Limit {
+		records = records[:params.Limit]
+	}
+
+	return &backends.QueryResult{
+		Iter: newQueryIterator(ctx, records),
+	}, nil
+}
+
+// InsertAll implements backends.Collection interface.
+func (c *collection) InsertAll(ctx context.Context, params *backends.InsertAllParams) (*backends.InsertAllResult, error) {
+	records := make([]*record, len(params.Docs))
+
+	for i, doc := range params.Docs {
+		doc = doc.DeepCopy()
+		doc.Freeze()
+
+		r, err := newRecord(doc)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		records[i] = r
+	}
+
+	c.b.s.rw.Lock()
+	defer c.b.s.rw.Unlock()
+
+	coll, _, err := c.b.collectionCreate(c.dbName, c.name)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if !coll.checkUnique(records, nil) {
+		return nil, backends.NewError(
+			backends.ErrorCodeInsertDuplicateID,
+			lazyerrors.Errorf("duplicate key in %s.%s", c.dbName, c.name),
+		)
+	}
+
+	for _, r := range records {
+		coll.add(r)
+	}
+
+	coll.records = append(coll.records, records...)
+
+	return new(backends.InsertAllResult), nil
+}
+
+// UpdateAll implements backends.Collection interface.
+func (c *collection) UpdateAll(ctx context.Context, params *backends.UpdateAllParams) (*backends.UpdateAllResult, error) {
+	var res backends.UpdateAllResult
+
+	records := make([]*record, len(params.Docs))
+
+	for i, doc := range params.Docs {
+		doc = doc.DeepCopy()
+		doc.Freeze()
+
+		r, err := newRecord(doc)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		records[i] = r
+	}
+
+	c.b.s.rw.Lock()
+	defer c.b.s.rw.Unlock()
+
+	coll := c.b.collectionGet(c.dbName, c.name)
+	if coll == nil {
+		return &res, nil
+	}
+
+	// only existing documents are updated
+	updated := make([]*record, 0, len(records))
+	skip := make(map[string]struct{}, len(records))
+
+	for _, r := range records {
+		if coll.ids[r.id] == nil {
+			continue
+		}
+
+		updated = append(updated, r)
+		skip[r.id] = struct{}{}
+	}
+
+	if !coll.checkUnique(updated, skip) {
+		return nil, lazyerrors.Errorf("duplicate key in %s.%s", c.dbName, c.name)
+	}
+
+	for _, r := range updated {
+		old := coll.ids[r.id]
+
+		// keep the natural order and record ID
+		r.doc.SetRecordID(old.doc.RecordID())
+
+		i := slices.Index(coll.records, old)
+		coll.records[i] = r
+
+		coll.remove(old)
+		coll.add(r)
+
+		res.Updated++
+	}
+
+	return &res, nil
+}
+
+// DeleteAll implements backends.Collection interface.
+func (c *collection) DeleteAll(ctx context.Context, params *backends.DeleteAllParams) (*backends.DeleteAllResult, error) {
+	c.b.s.rw.Lock()
+	defer c.b.s.rw.Unlock()
+
+	coll := c.b.collectionGet(c.dbName, c.name)
+	if coll == nil {
+		return &backends.DeleteAllResult{Deleted: 0}, nil
+	}
+
+	var deleted int32
+
+	if params.IDs != nil {
+		for _, id := range params.IDs {
+			r := coll.ids[idKey(id)]
+			if r == nil {
+				continue
+			}
+
+			coll.remove(r)
+			deleted++
+		}
+
+		coll.records = slices.DeleteFunc(coll.records, func(r *record) bool {
+			return coll.ids[r.id] != r
+		})
+
+		return &backends.DeleteAllResult{Deleted: deleted}, nil
+	}
+
+	coll.records = slices.DeleteFunc(coll.records, func(r *record) bool {
+		if !slices.Contains(params.RecordIDs, r.doc.RecordID()) {
+			return false
+		}
+
+		coll.remove(r)
+		deleted++
+
+		return true
+	})
+
+	return &backends.DeleteAllResult{Deleted: deleted}, nil
+}
+
+// Explain implements backends.Collection interface.
+func (c *collection) Explain(ctx context.Context, params *backends.ExplainParams) (*backends.ExplainResult, error) {
+	if params == nil {
+		params = new(backends.ExplainParams)
+	}
+
+	c.b.s.rw.RLock()
+	defer c.b.s.rw.RUnlock()
+
+	if coll := c.b.collectionGet(c.dbName, c.name); coll == nil {
+		return &backends.ExplainResult{
+			QueryPlanner: must.NotFail(types.NewDocument()),
+		}, nil
+	}
+
+	_, queryPushdown := idFilter(params.Filter)
+
+	plan := "COLLSCAN"
+	if queryPushdown {
+		plan = "IDHACK"
+	}
+
+	return &backends.ExplainResult{
+		QueryPlanner:  must.NotFail(types.NewDocument("Plan", plan)),
+		QueryPushdown: queryPushdown,
+		SortPushdown:  params.Sort != nil && params.Sort.Key == backends.NaturalSortKey,
+		LimitPushdown: params.Limit != 0,
+	}, nil
+}
+
+// Stats implements backends.Collection interface.
+func (c *collection) Stats(ctx context.Context, params *backends.CollectionStatsParams) (*backends.CollectionStatsResult, error) {
+	c.b.s.rw.RLock()
+	defer c.b.s.rw.RUnlock()
+
+	coll := c.b.collectionGet(c.dbName, c.name)
+	if coll == nil {
+		return nil, backends.NewError(
+			backends.ErrorCodeCollectionDoesNotExist,
+			lazyerrors.Errorf("no ns %s.%s", c.dbName, c.name),
+		)
+	}
+
+	res := &backends.CollectionStatsResult{
+		CountDocuments: int64(len(coll.records)),
+		SizeCollection: coll.size(),
+		IndexSizes:     coll.indexSizes(),
+	}
+
+	for _, s := range res.IndexSizes {
+		res.SizeIndexes += s.Size
+	}
+
+	res.SizeTotal = res.SizeCollection + res.SizeIndexes
+
+	return res, nil
+}
+
+// Compact implements backends.Collection interface.
+func (c *collection) Compact(ctx context.Context, params *backends.CompactParams) (*backends.CompactResult, error) {
+	c.b.s.rw.RLock()
+	defer c.b.s.rw.RUnlock()
+
+	if _, ok := c.b.s.dbs[c.dbName]; !ok {
+		return nil, backends.NewError(
+			backends.ErrorCodeDatabaseDoesNotExist,
+			lazyerrors.Errorf("no ns %s.%s", c.dbName, c.name),
+		)
+	}
+
+	if coll := c.b.collectionGet(c.dbName, c.name); coll == nil {
+		return nil, backends.NewError(
+			backends.ErrorCodeCollectionDoesNotExist,
+			lazyerrors.Errorf("no ns %s.%s", c.dbName, c.name),
+		)
+	}
+
+	// there is nothing to compact
+	return new(backends.CompactResult), nil
+}
+
+// ListIndexes implements backends.Collection interface.
+func (c *collection) ListIndexes(ctx context.Context, params *backends.ListIndexesParams) (*backends.ListIndexesResult, error) {
+	c.b.s.rw.RLock()
+	defer c.b.s.rw.RUnlock()
+
+	coll := c.b.collectionGet(c.dbName, c.name)
+	if coll == nil {
+		return nil, backends.NewError(
+			backends.ErrorCodeCollectionDoesNotExist,
+			lazyerrors.Errorf("no ns %s.%s", c.dbName, c.name),
+		)
+	}
+
+	res := backends.ListIndexesResult{
+		Indexes: make([]backends.IndexInfo, len(coll.indexes)),
+	}
+
+	for i, index := range coll.indexes {
+		res.Indexes[i] = index
+		res.Indexes[i].Key = slices.Clone(index.Key)
+	}
+
+	return &res, nil
+}
+
+// CreateIndexes implements backends.Collection interface.
+func (c *collection) CreateIndexes(ctx context.Context, params *backends.CreateIndexesParams) (*backends.CreateIndexesResult, error) { //nolint:lll // for readability
+	c.b.s.rw.Lock()
+	defer c.b.s.rw.Unlock()
+
+	coll, _, err := c.b.collectionCreate(c.dbName, c.name)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	var created []backends.IndexInfo
+	unique := map[string]map[string]*record{}
+
+	for _, index := range params.Indexes {
+		if slices.ContainsFunc(coll.indexes, func(i backends.IndexInfo) bool { return index.Name == i.Name }) {
+			continue
+		}
+
+		index.Key = slices.Clone(index.Key)
+
+		if index.Unique {
+			keys := make(map[string]*record, len(coll.records))
+
+			for i, r := range coll.records {
+				if err = context.Cause(ctx); err != nil {
+					return nil, lazyerrors.Error(err)
+				}
+
+				if params.Progress != nil {
+					params.Progress(int64(i), int64(len(coll.records)))
+				}
+
+				key, ok := indexKey(r.doc, &index)
+				if !ok {
+					continue
+				}
+
+				if _, ok = keys[key]; ok {
+					return nil, lazyerrors.Errorf("duplicate key for unique index %q in %s.%s", index.Name, c.dbName, c.name)
+				}
+
+				keys[key] = r
+			}
+
+			unique[index.Name] = keys
+		}
+
+		created = append(created, index)
+	}
+
+	if params.Progress != nil {
+		params.Progress(int64(len(coll.records)), int64(len(coll.records)))
+	}
+
+	for name, keys := range unique {
+		coll.unique[name] = keys
+	}
+
+	coll.indexes = append(coll.indexes, created...)
+	slices.SortFunc(coll.indexes, func(a, b backends.IndexInfo) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	return new(backends.CreateIndexesResult), nil
+}
+
+// DropIndexes implements backends.Collection interface.
+func (c *collection) DropIndexes(ctx context.Context, params *backends.DropIndexesParams) (*backends.DropIndexesResult, error) {
+	c.b.s.rw.Lock()
+	defer c.b.s.rw.Unlock()
+
+	coll := c.b.collectionGet(c.dbName, c.name)
+	if coll == nil {
+		return new(backends.DropIndexesResult), nil
+	}
+
+	coll.indexes = slices.DeleteFunc(coll.indexes, func(i backends.IndexInfo) bool {
+		return slices.Contains(params.Indexes, i.Name)
+	})
+
+	for _, name := range params.Indexes {
+		delete(coll.unique, name)
+	}
+
+	return new(backends.DropIndexesResult), nil
+}
+
+// idFilter returns _id value if the filter selects a single document by string or ObjectID _id,
+// the same way other backends push it down.
+func idFilter(filter *types.Document) (any, bool) {
+	if filter.Len() != 1 {
+		return nil, false
+	}
+
+	v, _ := filter.Get("_id")
+	switch v.(type) {
+	case string, types.ObjectID:
+		return v, true
+	default:
+		return nil, false
+	}
+}
+
+// check interfaces
+var (
+	_ backends.Collection = (*collection)(nil)
+)
@@ -0,0 +1,192 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/util/state"
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+)
+
+func TestCollection(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Ctx(t)
+
+	sp, err := state.NewProvider("")
+	require.NoError(t, err)
+
+	b, err := NewBackend(&NewBackendParams{L: testutil.Logger(t), P: sp})
+	require.NoError(t, err)
+	t.Cleanup(b.Close)
+
+	db, err := b.Database(testutil.DatabaseName(t))
+	require.NoError(t, err)
+
+	c, err := db.Collection(testutil.CollectionName(t))
+	require.NoError(t, err)
+
+	query := func(t *testing.T, params *backends.QueryParams) []*types.Document {
+		t.Helper()
+
+		res, err := c.Query(ctx, params)
+		require.NoError(t, err)
+
+		docs, err := iterator.ConsumeValues(res.Iter)
+		require.NoError(t, err)
+
+		return docs
+	}
+
+	_, err = c.InsertAll(ctx, &backends.InsertAllParams{Docs: []*types.Document{
+		must.NotFail(types.NewDocument("_id", "b", "v", int32(1))),
+		must.NotFail(types.NewDocument("_id", "a", "v", int32(2))),
+	}})
+	require.NoError(t, err)
+
+	t.Run("NaturalOrder", func(t *testing.T) {
+		docs := query(t, &backends.QueryParams{Sort: &backends.SortField{Key: backends.NaturalSortKey, Descending: true}})
+		require.Len(t, docs, 2)
+		assert.Equal(t, "a", must.NotFail(docs[0].Get("_id")))
+		assert.Equal(t, "b", must.NotFail(docs[1].Get("_id")))
+	})
+
+	t.Run("DeepCopy", func(t *testing.T) {
+		docs := query(t, &backends.QueryParams{Filter: must.NotFail(types.NewDocument("_id", "b"))})
+		require.Len(t, docs, 1)
+
+		docs[0].Set("v", int32(42))
+
+		docs = query(t, &backends.QueryParams{Filter: must.NotFail(types.NewDocument("_id", "b"))})
+		require.Len(t, docs, 1)
+		assert.Equal(t, int32(1), must.NotFail(docs[0].Get("v")))
+	})
+
+	t.Run("DuplicateID", func(t *testing.T) {
+		_, err = c.InsertAll(ctx, &backends.InsertAllParams{Docs: []*types.Document{
+			must.NotFail(types.NewDocument("_id", "c")),
+			must.NotFail(types.NewDocument("_id", "a")),
+		}})
+		assert.True(t, backends.ErrorCodeIs(err, backends.ErrorCodeInsertDuplicateID), "err = %v", err)
+
+		// the whole batch is rolled back
+		assert.Len(t, query(t, nil), 2)
+	})
+
+	t.Run("UniqueIndex", func(t *testing.T) {
+		_, err = c.CreateIndexes(ctx, &backends.CreateIndexesParams{Indexes: []backends.IndexInfo{{
+			Name:   "v_1",
+			Key:    []backends.IndexKeyPair{{Field: "v"}},
+			Unique: true,
+		}}})
+		require.NoError(t, err)
+
+		_, err = c.InsertAll(ctx, &backends.InsertAllParams{Docs: []*types.Document{
+			must.NotFail(types.NewDocument("_id", "c", "v", int32(1))),
+		}})
+		assert.True(t, backends.ErrorCodeIs(err, backends.ErrorCodeInsertDuplicateID), "err = %v", err)
+
+		// documents without indexed fields are not constrained
+		_, err = c.InsertAll(ctx, &backends.InsertAllParams{Docs: []*types.Document{
+			must.NotFail(types.NewDocument("_id", "d")),
+			must.NotFail(types.NewDocument("_id", "e")),
+		}})
+		require.NoError(t, err)
+
+		res, err := c.UpdateAll(ctx, &backends.UpdateAllParams{Docs: []*types.Document{
+			must.NotFail(types.NewDocument("_id", "d", "v", int32(2))),
+		}})
+		assert.Error(t, err)
+		assert.Nil(t, res)
+
+		res, err = c.UpdateAll(ctx, &backends.UpdateAllParams{Docs: []*types.Document{
+			must.NotFail(types.NewDocument("_id", "d", "v", int32(3))),
+		}})
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), res.Updated)
+
+		_, err = c.CreateIndexes(ctx, &backends.CreateIndexesParams{Indexes: []backends.IndexInfo{{
+			Name:   "missing_1",
+			Key:    []backends.IndexKeyPair{{Field: "missing"}},
+			Unique: true,
+		}}})
+		require.NoError(t, err)
+
+		list, err := c.ListIndexes(ctx, nil)
+		require.NoError(t, err)
+		require.Len(t, list.Indexes, 3)
+		assert.Equal(t, backends.DefaultIndexName, list.Indexes[0].Name)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		res, err := c.DeleteAll(ctx, &backends.DeleteAllParams{IDs: []any{"a", "a", "z"}})
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), res.Deleted)
+
+		docs := query(t, nil)
+		require.Len(t, docs, 3)
+		assert.Equal(t, "b", must.NotFail(docs[0].Get("_id")))
+	})
+
+	t.Run("UniqueIndexKeys", func(t *testing.T) {
+		insert := func(t *testing.T, id string, v int32) error {
+			t.Helper()
+
+			_, err := c.InsertAll(ctx, &backends.InsertAllParams{Docs: []*types.Document{
+				must.NotFail(types.NewDocument("_id", id, "v", v)),
+			}})
+
+			return err
+		}
+
+		// updated document releases the old key
+		res, err := c.UpdateAll(ctx, &backends.UpdateAllParams{Docs: []*types.Document{
+			must.NotFail(types.NewDocument("_id", "d", "v", int32(4))),
+		}})
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), res.Updated)
+
+		require.NoError(t, insert(t, "f", 3))
+		assert.Error(t, insert(t, "g", 4))
+
+		// deleted document releases its key
+		_, err = c.DeleteAll(ctx, &backends.DeleteAllParams{IDs: []any{"f"}})
+		require.NoError(t, err)
+		require.NoError(t, insert(t, "g", 3))
+
+		// documents could swap keys
+		res, err = c.UpdateAll(ctx, &backends.UpdateAllParams{Docs: []*types.Document{
+			must.NotFail(types.NewDocument("_id", "d", "v", int32(3))),
+			must.NotFail(types.NewDocument("_id", "g", "v", int32(4))),
+		}})
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), res.Updated)
+		assert.Error(t, insert(t, "h", 3))
+		assert.Error(t, insert(t, "h", 4))
+
+		// dropped index does not constrain documents
+		_, err = c.DropIndexes(ctx, &backends.DropIndexesParams{Indexes: []string{"v_1"}})
+		require.NoError(t, err)
+		require.NoError(t, insert(t, "h", 4))
+	})
+}
@@ -0,0 +1,183 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// database implements backends.Database interface.
+type database struct {
+	b    *backend
+	name string
+}
+
+// newDatabase creates a new Database.
+func newDatabase(b *backend, name string) backends.Database {
+	return backends.DatabaseContract(&database{
+		b:    b,
+		name: name,
+	})
+}
+
+// Collection implements backends.Database interface.
+func (db *database) Collection(name string) (backends.Collection, error) {
+	return newCollection(db.b, db.name, name), nil
+}
+
+// ListCollections implements backends.Database interface.
+//
+//nolint:lll // for readability
+func (db *database) ListCollections(ctx context.Context, params *backends.ListCollectionsParams) (*backends.ListCollectionsResult, error) {
+	db.b.s.rw.RLock()
+	defer db.b.s.rw.RUnlock()
+
+	colls := db.b.s.dbs[db.name]
+
+	res := make([]backends.CollectionInfo, 0, len(colls))
+	for name, c := range colls {
+		res = append(res, backends.CollectionInfo{
+			Name:      name,
+			UUID:      c.uuid,
+			Temp:      c.temp,
+			TempOwner: c.tempOwner,
+		})
+	}
+
+	slices.SortFunc(res, func(a, b backends.CollectionInfo) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	return &backends.ListCollectionsResult{
+		Collections: res,
+	}, nil
+}
+
+// CreateCollection implements backends.Database interface.
+func (db *database) CreateCollection(ctx context.Context, params *backends.CreateCollectionParams) error {
+	db.b.s.rw.Lock()
+	defer db.b.s.rw.Unlock()
+
+	c, created, err := db.b.collectionCreate(db.name, params.Name)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	if !created {
+		return backends.NewError(backends.ErrorCodeCollectionAlreadyExists, nil)
+	}
+
+	c.temp = params.Temp
+	c.tempOwner = params.TempOwner
+
+	return nil
+}
+
+// DropCollection implements backends.Database interface.
+func (db *database) DropCollection(ctx context.Context, params *backends.DropCollectionParams) error {
+	db.b.s.rw.Lock()
+	defer db.b.s.rw.Unlock()
+
+	if c := db.b.collectionGet(db.name, params.Name); c == nil || (params.UUID != "" && c.uuid != params.UUID) {
+		return backends.NewError(backends.ErrorCodeCollectionDoesNotExist, nil)
+	}
+
+	delete(db.b.s.dbs[db.name], params.Name)
+
+	return nil
+}
+
+// RenameCollection implements backends.Database interface.
+func (db *database) RenameCollection(ctx context.Context, params *backends.RenameCollectionParams) error {
+	db.b.s.rw.Lock()
+	defer db.b.s.rw.Unlock()
+
+	c := db.b.collectionGet(db.name, params.OldName)
+	if c == nil {
+		return backends.NewError(
+			backends.ErrorCodeCollectionDoesNotExist,
+			lazyerrors.Errorf("old database %q or collection %q does not exist", db.name, params.OldName),
+		)
+	}
+
+	if db.b.collectionGet(db.name, params.NewName) != nil && !params.DropTarget {
+		return backends.NewError(
+			backends.ErrorCodeCollectionAlreadyExists,
+			lazyerrors.Errorf("new database %q and collection %q already exists", db.name, params.NewName),
+		)
+	}
+
+	c.temp = false
+	c.tempOwner = ""
+
+	colls := db.b.s.dbs[db.name]
+	colls[params.NewName] = c
+	delete(colls, params.OldName)
+
+	return nil
+}
+
+// Stats implements backends.Database interface.
+func (db *database) Stats(ctx context.Context, params *backends.DatabaseStatsParams) (*backends.DatabaseStatsResult, error) {
+	db.b.s.rw.RLock()
+	defer db.b.s.rw.RUnlock()
+
+	colls, ok := db.b.s.dbs[db.name]
+	if !ok {
+		return nil, backends.NewError(backends.ErrorCodeDatabaseDoesNotExist, lazyerrors.Errorf("no database %s", db.name))
+	}
+
+	var res backends.DatabaseStatsResult
+
+	for _, c := range colls {
+		res.CountDocuments += int64(len(c.records))
+		res.SizeCollections += c.size()
+
+		for _, s := range c.indexSizes() {
+			res.SizeIndexes += s.Size
+		}
+	}
+
+	res.SizeTotal = res.SizeCollections + res.SizeIndexes
+
+	return &res, nil
+}
+
+// ValidateMetadata implements backends.Database interface.
+//
+// Collections and indexes are stored together with their metadata,
+// so they can't disagree and there is nothing to repair.
+//
+//nolint:lll // for readability
+func (db *database) ValidateMetadata(ctx context.Context, params *backends.ValidateMetadataParams) (*backends.ValidateMetadataResult, error) {
+	db.b.s.rw.RLock()
+	defer db.b.s.rw.RUnlock()
+
+	if _, ok := db.b.s.dbs[db.name]; !ok {
+		return nil, backends.NewError(backends.ErrorCodeDatabaseDoesNotExist, lazyerrors.Errorf("no database %s", db.name))
+	}
+
+	return new(backends.ValidateMetadataResult), nil
+}
+
+// check interfaces
+var (
+	_ backends.Database = (*database)(nil)
+)
@@ -0,0 +1,236 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package memory provides in-memory backend.
+//
+// All data is kept in the process memory and lost when the backend is closed.
+// The backend is intended for tests of applications embedding FerretDB
+// that do not want to depend on SQLite files or PostgreSQL server.
+//
+// # Design principles
+//
+//  1. All data is protected by a single lock. That is simple and fast enough for tests.
+//  2. Stored documents are frozen and never modified; queries return their deep copies.
+//  3. Indexes are not used for queries; only unique constraints are enforced
+//     with maps of index keys. Like SQLite, documents without some of the indexed fields are not constrained.
+package memory
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/handlers/sjson"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// storage represents all databases of the backend.
+type storage struct {
+	rw  sync.RWMutex
+	dbs map[string]map[string]*collectionData // database name -> collection name -> data
+}
+
+// collectionData represents a single collection.
+type collectionData struct {
+	records   []*record                     // in natural order
+	ids       map[string]*record            // _id key -> record; also used for the default index
+	unique    map[string]map[string]*record // other unique index name -> index key -> record
+	indexes   []backends.IndexInfo          // sorted by name
+	uuid      string
+	temp      bool
+	tempOwner string
+}
+
+// record represents a single stored document.
+type record struct {
+	doc  *types.Document // frozen
+	id   string
+	size int64
+}
+
+// newCollectionData returns a new empty collection with the default index.
+func newCollectionData() *collectionData {
+	return &collectionData{
+		ids:    map[string]*record{},
+		unique: map[string]map[string]*record{},
+		uuid:   uuid.NewString(),
+		indexes: []backends.IndexInfo{{
+			Name:   backends.DefaultIndexName,
+			Key:    []backends.IndexKeyPair{{Field: "_id"}},
+			Unique: true,
+		}},
+	}
+}
+
+// newRecord returns a new record for the given valid document with _id field.
+func newRecord(doc *types.Document) (*record, error) {
+	b, err := sjson.Marshal(doc)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return &record{
+		doc:  doc,
+		id:   idKey(must.NotFail(doc.Get("_id"))),
+		size: int64(len(b)),
+	}, nil
+}
+
+// idKey returns a key for the given _id value.
+//
+// Like other backends, it uses sjson representation, so values of different types are never equal.
+func idKey(id any) string {
+	return string(must.NotFail(sjson.MarshalSingleValue(id)))
+}
+
+// indexKey returns a key of the given document for the given index.
+//
+// It returns false if document does not have some of the indexed fields.
+func indexKey(doc *types.Document, index *backends.IndexInfo) (string, bool) {
+	parts := make([]string, len(index.Key))
+
+	for i, k := range index.Key {
+		path, err := types.NewPathFromString(k.Field)
+		if err != nil {
+			return "", false
+		}
+
+		v, err := doc.GetByPath(path)
+		if err != nil {
+			return "", false
+		}
+
+		parts[i] = idKey(v)
+	}
+
+	return strings.Join(parts, "\x00"), true
+}
+
+// uniqueKeys returns index keys map for the given unique index.
+func (c *collectionData) uniqueKeys(index *backends.IndexInfo) map[string]*record {
+	if index.Name == backends.DefaultIndexName {
+		return c.ids
+	}
+
+	return c.unique[index.Name]
+}
+
+// checkUnique returns false if the given records violate unique indexes of the collection.
+//
+// Existing records with _id keys in the skip set are ignored; that is used for updates.
+func (c *collectionData) checkUnique(recs []*record, skip map[string]struct{}) bool {
+	for i := range c.indexes {
+		index := &c.indexes[i]
+		if !index.Unique {
+			continue
+		}
+
+		keys := c.uniqueKeys(index)
+		seen := make(map[string]struct{}, len(recs))
+
+		for _, r := range recs {
+			key, ok := indexKey(r.doc, index)
+			if !ok {
+				continue
+			}
+
+			if _, ok = seen[key]; ok {
+				return false
+			}
+
+			seen[key] = struct{}{}
+
+			if existing := keys[key]; existing != nil {
+				if _, ok = skip[existing.id]; !ok {
+					return false
+				}
+			}
+		}
+	}
+
+	return true
+}
+
+// add adds the given record to _id and unique index keys maps.
+//
+// It does not add the record to the list of records.
+func (c *collectionData) add(r *record) {
+	c.ids[r.id] = r
+
+	for i := range c.indexes {
+		index := &c.indexes[i]
+		if !index.Unique || index.Name == backends.DefaultIndexName {
+			continue
+		}
+
+		if key, ok := indexKey(r.doc, index); ok {
+			c.unique[index.Name][key] = r
+		}
+	}
+}
+
+// remove removes the given record from _id and unique index keys maps.
+//
+// Keys that already point to other records are kept.
+// It does not remove the record from the list of records.
+func (c *collectionData) remove(r *record) {
+	if c.ids[r.id] == r {
+		delete(c.ids, r.id)
+	}
+
+	for i := range c.indexes {
+		index := &c.indexes[i]
+		if !index.Unique || index.Name == backends.DefaultIndexName {
+			continue
+		}
+
+		keys := c.unique[index.Name]
+
+		if key, ok := indexKey(r.doc, index); ok && keys[key] == r {
+			delete(keys, key)
+		}
+	}
+}
+
+// size returns the total estimated size of all documents in the collection.
+func (c *collectionData) size() int64 {
+	var res int64
+	for _, r := range c.records {
+		res += r.size
+	}
+
+	return res
+}
+
+// indexSizes returns the estimated sizes of all indexes of the collection.
+func (c *collectionData) indexSizes() []backends.IndexSize {
+	res := make([]backends.IndexSize, len(c.indexes))
+
+	for i := range c.indexes {
+		index := &c.indexes[i]
+		res[i].Name = index.Name
+
+		for _, r := range c.records {
+			if key, ok := indexKey(r.doc, index); ok {
+				res[i].Size += int64(len(key))
+			}
+		}
+	}
+
+	return res
+}
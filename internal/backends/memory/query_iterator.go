@@ -0,0 +1,108 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/observability"
+	"github.com/FerretDB/FerretDB/internal/util/resource"
+)
+
+// queryIterator implements iterator.Interface to return documents from the snapshot of records.
+type queryIterator struct {
+	// the order of fields is weird to make the struct smaller due to alignment
+
+	ctx     context.Context
+	records []*record // protected by m
+	token   *resource.Token
+	m       sync.Mutex
+}
+
+// newQueryIterator returns a new queryIterator for the given records.
+//
+// Iterator returns deep copies of stored documents, so the caller can modify them.
+// Nil records are possible and return already done iterator.
+// It still should be Close'd.
+func newQueryIterator(ctx context.Context, records []*record) types.DocumentsIterator {
+	iter := &queryIterator{
+		ctx:     ctx,
+		records: records,
+		token:   resource.NewToken(),
+	}
+	resource.Track(iter, iter.token)
+
+	return iter
+}
+
+// Next implements iterator.Interface.
+func (iter *queryIterator) Next() (struct{}, *types.Document, error) {
+	defer observability.FuncCall(iter.ctx)()
+
+	iter.m.Lock()
+	defer iter.m.Unlock()
+
+	var unused struct{}
+
+	// ignore context error, if any, if iterator is already closed
+	if iter.records == nil {
+		return unused, nil, iterator.ErrIteratorDone
+	}
+
+	if err := context.Cause(iter.ctx); err != nil {
+		iter.close()
+		return unused, nil, lazyerrors.Error(err)
+	}
+
+	if len(iter.records) == 0 {
+		iter.close()
+		return unused, nil, iterator.ErrIteratorDone
+	}
+
+	r := iter.records[0]
+	iter.records = iter.records[1:]
+
+	return unused, r.doc.DeepCopy(), nil
+}
+
+// Close implements iterator.Interface.
+func (iter *queryIterator) Close() {
+	defer observability.FuncCall(iter.ctx)()
+
+	iter.m.Lock()
+	defer iter.m.Unlock()
+
+	iter.close()
+}
+
+// close closes iterator without holding mutex.
+//
+// This should be called only when the caller already holds the mutex.
+func (iter *queryIterator) close() {
+	defer observability.FuncCall(iter.ctx)()
+
+	iter.records = nil
+
+	resource.Untrack(iter, iter.token)
+}
+
+// check interfaces
+var (
+	_ types.DocumentsIterator = (*queryIterator)(nil)
+)
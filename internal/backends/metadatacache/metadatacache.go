@@ -0,0 +1,81 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metadatacache provides a versioned cache of collections metadata
+// for backends that keep metadata in the registry.
+package metadatacache
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/exp/maps"
+)
+
+// Snapshot maps database names to collection names to collection metadata.
+//
+// It is immutable; it must not be modified by callers.
+type Snapshot[C any] map[string]map[string]*C
+
+// Cache is an immutable snapshot of collections metadata shared by all connections.
+//
+// It allows hot paths (like inserts) to get collection metadata without taking the registry lock
+// and copying metadata on every operation.
+// Collection values in the registry should never be modified in place (they should be replaced by modified copies),
+// so snapshot could share them with the registry.
+//
+// The zero value is ready to use.
+type Cache[C any] struct {
+	// version is incremented on every change of metadata; snapshot is nil if invalidated.
+	version  atomic.Uint64
+	snapshot atomic.Pointer[Snapshot[C]]
+}
+
+// Get returns the current snapshot, building a new one from colls if it was invalidated.
+//
+// It acquires rw's read lock if snapshot should be built, so it is safe for concurrent use.
+// colls is called with the lock held.
+func (c *Cache[C]) Get(rw *sync.RWMutex, colls func() map[string]map[string]*C) Snapshot[C] {
+	if s := c.snapshot.Load(); s != nil {
+		return *s
+	}
+
+	// hold the lock until the snapshot is stored, so it could not be invalidated in the meantime
+	rw.RLock()
+	defer rw.RUnlock()
+
+	src := colls()
+	s := make(Snapshot[C], len(src))
+
+	for dbName, dbColls := range src {
+		s[dbName] = maps.Clone(dbColls)
+	}
+
+	c.snapshot.Store(&s)
+
+	return s
+}
+
+// Invalidate increments metadata version and drops the current snapshot.
+//
+// It should be called after every change of metadata (DDL) while holding rw's write lock.
+func (c *Cache[C]) Invalidate() {
+	c.version.Add(1)
+	c.snapshot.Store(nil)
+}
+
+// Version returns the current metadata version.
+func (c *Cache[C]) Version() uint64 {
+	return c.version.Load()
+}
@@ -0,0 +1,66 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadatacache
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache(t *testing.T) {
+	t.Parallel()
+
+	type coll struct {
+		name string
+	}
+
+	var rw sync.RWMutex
+	colls := map[string]map[string]*coll{
+		"db": {"foo": {name: "foo"}},
+	}
+	get := func() map[string]map[string]*coll { return colls }
+
+	var c Cache[coll]
+
+	s := c.Get(&rw, get)
+	require.Len(t, s["db"], 1)
+	foo := s["db"]["foo"]
+	assert.Same(t, colls["db"]["foo"], foo, "collections should be shared with the registry")
+
+	// changes without invalidation are not visible
+	rw.Lock()
+	colls["db"]["bar"] = &coll{name: "bar"}
+	rw.Unlock()
+
+	assert.Len(t, c.Get(&rw, get)["db"], 1)
+
+	version := c.Version()
+
+	rw.Lock()
+	c.Invalidate()
+	rw.Unlock()
+
+	assert.Greater(t, c.Version(), version)
+
+	updated := c.Get(&rw, get)
+	assert.Len(t, updated["db"], 2)
+	assert.Same(t, foo, updated["db"]["foo"])
+
+	// previous snapshot is not modified
+	assert.Len(t, s["db"], 1)
+}
@@ -67,7 +67,7 @@ func (c *collection) Query(ctx context.Context, params *backends.QueryParams) (*
 		}, nil
 	}
 
-	meta, err := c.r.CollectionGet(ctx, c.dbName, c.name)
+	meta, err := c.r.CollectionGetCached(ctx, c.dbName, c.name)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
@@ -82,7 +82,7 @@ func (c *collection) Query(ctx context.Context, params *backends.QueryParams) (*
 
 	var placeholder metadata.Placeholder
 
-	where, args, err := prepareWhereClause(&placeholder, params.Filter)
+	where, args, err := prepareWhereClause(&placeholder, params.Filter, meta.Indexes)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
@@ -90,6 +90,10 @@ func (c *collection) Query(ctx context.Context, params *backends.QueryParams) (*
 	q += where
 
 	if params.Sort != nil {
+		if params.Sort.Key == backends.NaturalSortKey && !meta.RecordIDs {
+			return nil, naturalOrderNotSupported(c.dbName, c.name)
+		}
+
 		var sort string
 		var sortArgs []any
 
@@ -128,7 +132,7 @@ func (c *collection) InsertAll(ctx context.Context, params *backends.InsertAllPa
 		return nil, lazyerrors.Error(err)
 	}
 
-	meta, err := c.r.CollectionGet(ctx, c.dbName, c.name)
+	meta, err := c.r.CollectionGetCached(ctx, c.dbName, c.name)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
@@ -183,7 +187,7 @@ func (c *collection) UpdateAll(ctx context.Context, params *backends.UpdateAllPa
 		return &res, nil
 	}
 
-	meta, err := c.r.CollectionGet(ctx, c.dbName, c.name)
+	meta, err := c.r.CollectionGetCached(ctx, c.dbName, c.name)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
@@ -239,7 +243,7 @@ func (c *collection) DeleteAll(ctx context.Context, params *backends.DeleteAllPa
 		return &backends.DeleteAllResult{Deleted: 0}, nil
 	}
 
-	meta, err := c.r.CollectionGet(ctx, c.dbName, c.name)
+	meta, err := c.r.CollectionGetCached(ctx, c.dbName, c.name)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
@@ -291,7 +295,7 @@ func (c *collection) Explain(ctx context.Context, params *backends.ExplainParams
 		}, nil
 	}
 
-	meta, err := c.r.CollectionGet(ctx, c.dbName, c.name)
+	meta, err := c.r.CollectionGetCached(ctx, c.dbName, c.name)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
@@ -308,14 +312,20 @@ func (c *collection) Explain(ctx context.Context, params *backends.ExplainParams
 
 	var placeholder metadata.Placeholder
 
-	where, args, err := prepareWhereClause(&placeholder, params.Filter)
+	where, args, err := prepareWhereClause(&placeholder, params.Filter, meta.Indexes)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
 
 	res.QueryPushdown = where != ""
 
+	q += where
+
 	if params.Sort != nil {
+		if params.Sort.Key == backends.NaturalSortKey && !meta.RecordIDs {
+			return nil, naturalOrderNotSupported(c.dbName, c.name)
+		}
+
 		var sort string
 		var sortArgs []any
 
@@ -330,8 +340,6 @@ func (c *collection) Explain(ctx context.Context, params *backends.ExplainParams
 		res.SortPushdown = sort != ""
 	}
 
-	q += where
-
 	if params.Limit != 0 {
 		q += fmt.Sprintf(` LIMIT %s`, placeholder.Next())
 		args = append(args, params.Limit)
@@ -515,15 +523,17 @@ func (c *collection) ListIndexes(ctx context.Context, params *backends.ListIndex
 
 	for i, index := range coll.Indexes {
 		res.Indexes[i] = backends.IndexInfo{
-			Name:   index.Name,
-			Unique: index.Unique,
-			Key:    make([]backends.IndexKeyPair, len(index.Key)),
+			Name:               index.Name,
+			Unique:             index.Unique,
+			Key:                make([]backends.IndexKeyPair, len(index.Key)),
+			ExpireAfterSeconds: index.ExpireAfterSeconds,
 		}
 
 		for j, key := range index.Key {
 			res.Indexes[i].Key[j] = backends.IndexKeyPair{
 				Field:      key.Field,
 				Descending: key.Descending,
+				Expression: key.Expression,
 			}
 		}
 	}
@@ -540,20 +550,22 @@ func (c *collection) CreateIndexes(ctx context.Context, params *backends.CreateI
 	indexes := make([]metadata.IndexInfo, len(params.Indexes))
 	for i, index := range params.Indexes {
 		indexes[i] = metadata.IndexInfo{
-			Name:   index.Name,
-			Key:    make([]metadata.IndexKeyPair, len(index.Key)),
-			Unique: index.Unique,
+			Name:               index.Name,
+			Key:                make([]metadata.IndexKeyPair, len(index.Key)),
+			Unique:             index.Unique,
+			ExpireAfterSeconds: index.ExpireAfterSeconds,
 		}
 
 		for j, key := range index.Key {
 			indexes[i].Key[j] = metadata.IndexKeyPair{
 				Field:      key.Field,
 				Descending: key.Descending,
+				Expression: key.Expression,
 			}
 		}
 	}
 
-	err := c.r.IndexesCreate(ctx, c.dbName, c.name, indexes)
+	err := c.r.IndexesCreate(ctx, c.dbName, c.name, indexes, params.Progress)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
@@ -571,6 +583,15 @@ func (c *collection) DropIndexes(ctx context.Context, params *backends.DropIndex
 	return new(backends.DropIndexesResult), nil
 }
 
+// naturalOrderNotSupported returns an error for the natural order of collections
+// created by older versions without record IDs.
+func naturalOrderNotSupported(dbName, collectionName string) error {
+	return backends.NewError(
+		backends.ErrorCodeNaturalOrderNotSupported,
+		lazyerrors.Errorf("collection %s.%s has no record IDs", dbName, collectionName),
+	)
+}
+
 // check interfaces
 var (
 	_ backends.Collection = (*collection)(nil)
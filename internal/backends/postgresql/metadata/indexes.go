@@ -16,8 +16,11 @@ package metadata
 
 import (
 	"errors"
+	"fmt"
 	"slices"
+	"strings"
 
+	"github.com/FerretDB/FerretDB/internal/backends"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/iterator"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
@@ -29,16 +32,53 @@ type Indexes []IndexInfo
 
 // IndexInfo represents information about a single index.
 type IndexInfo struct {
-	Name    string
-	PgIndex string
-	Key     []IndexKeyPair
-	Unique  bool
+	Name               string
+	PgIndex            string
+	Key                []IndexKeyPair
+	Unique             bool
+	ExpireAfterSeconds *int32
 }
 
 // IndexKeyPair consists of a field name and a sort order that are part of the index.
 type IndexKeyPair struct {
 	Field      string
 	Descending bool
+	Expression backends.IndexKeyExpression
+}
+
+// Computed returns true if the index has at least one computed key.
+//
+// Such indexes are GIN indexes built on the results of SQL/JSON path queries (see [ComputedColumn]),
+// so they also cover values inside arrays.
+func (index *IndexInfo) Computed() bool {
+	return slices.ContainsFunc(index.Key, func(key IndexKeyPair) bool { return key.Expression != "" })
+}
+
+// ComputedColumn returns the SQL expression used for the given key of the computed index.
+//
+// The expression returns a JSON array of all values under the key's field;
+// lax mode unwraps arrays on the path the same way dot notation does.
+// Query predicates should use exactly the same expression for the index to be used.
+func ComputedColumn(key IndexKeyPair) string {
+	parts := strings.Split(key.Field, ".")
+	for i, part := range parts {
+		part = strings.ReplaceAll(part, `\`, `\\`)
+		parts[i] = `"` + strings.ReplaceAll(part, `"`, `\"`) + `"`
+	}
+
+	// It's important to sanitize key.Field data here, as it's a user-provided value.
+	path := quoteString("lax $." + strings.Join(parts, ".") + "[*]")
+	column := fmt.Sprintf("jsonb_path_query_array(%s, %s::jsonpath)", DefaultColumn, path)
+
+	switch key.Expression {
+	case "":
+		return "(" + column + ")"
+	case backends.IndexKeyExpressionToLower:
+		// lowercasing JSON text keeps it valid as all escape sequences are case-insensitive
+		return "(lower(" + column + "::text)::jsonb)"
+	default:
+		panic(fmt.Sprintf("unexpected index key expression %q", key.Expression))
+	}
 }
 
 // deepCopy returns a deep copy.
@@ -52,6 +92,11 @@ func (indexes Indexes) deepCopy() Indexes {
 			Key:     slices.Clone(index.Key),
 			Unique:  index.Unique,
 		}
+
+		if index.ExpireAfterSeconds != nil {
+			expireAfterSeconds := *index.ExpireAfterSeconds
+			res[i].ExpireAfterSeconds = &expireAfterSeconds
+		}
 	}
 
 	return res
@@ -70,15 +115,26 @@ func (indexes Indexes) marshal() *types.Array {
 				order = int32(-1)
 			}
 
+			if pair.Expression != "" {
+				key.Set(pair.Field, must.NotFail(types.NewDocument(string(pair.Expression), order)))
+				continue
+			}
+
 			key.Set(pair.Field, order)
 		}
 
-		res.Append(must.NotFail(types.NewDocument(
+		doc := must.NotFail(types.NewDocument(
 			"pgindex", index.PgIndex,
 			"name", index.Name,
 			"key", key,
 			"unique", index.Unique,
-		)))
+		))
+
+		if index.ExpireAfterSeconds != nil {
+			doc.Set("expireAfterSeconds", *index.ExpireAfterSeconds)
+		}
+
+		res.Append(doc)
 	}
 
 	return res
@@ -109,14 +165,23 @@ func (s *Indexes) unmarshal(a *types.Array) error {
 		key := make([]IndexKeyPair, keyDoc.Len())
 
 		for j, f := range fields {
+			var expression backends.IndexKeyExpression
+
+			order := orders[j]
+			if doc, ok := order.(*types.Document); ok {
+				expression = backends.IndexKeyExpression(doc.Command())
+				order = must.NotFail(doc.Get(doc.Command()))
+			}
+
 			descending := false
-			if orders[j].(int32) == -1 {
+			if order.(int32) == -1 {
 				descending = true
 			}
 
 			key[j] = IndexKeyPair{
 				Field:      f,
 				Descending: descending,
+				Expression: expression,
 			}
 		}
 
@@ -130,6 +195,11 @@ func (s *Indexes) unmarshal(a *types.Array) error {
 			Key:     key,
 			Unique:  unique,
 		}
+
+		if v, _ = index.Get("expireAfterSeconds"); v != nil {
+			expireAfterSeconds := v.(int32)
+			res[i].ExpireAfterSeconds = &expireAfterSeconds
+		}
 	}
 
 	*s = res
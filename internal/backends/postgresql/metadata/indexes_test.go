@@ -0,0 +1,94 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+)
+
+func TestIndexesMarshal(t *testing.T) {
+	t.Parallel()
+
+	expireAfterSeconds := int32(10)
+
+	indexes := Indexes{
+		{
+			Name:    "_id_",
+			PgIndex: "test__id__idx",
+			Key:     []IndexKeyPair{{Field: "_id"}},
+			Unique:  true,
+		},
+		{
+			Name:    "email_lower",
+			PgIndex: "test_email_lower_idx",
+			Key: []IndexKeyPair{
+				{Field: "email", Descending: true, Expression: backends.IndexKeyExpressionToLower},
+				{Field: "items.sku"},
+			},
+		},
+		{
+			Name:               "ttl",
+			PgIndex:            "test_ttl_idx",
+			Key:                []IndexKeyPair{{Field: "date"}},
+			ExpireAfterSeconds: &expireAfterSeconds,
+		},
+	}
+
+	var actual Indexes
+	require.NoError(t, actual.unmarshal(indexes.marshal()))
+	assert.Equal(t, indexes, actual)
+}
+
+func TestIndexCreateQuery(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		index    IndexInfo
+		expected string
+	}{
+		"Plain": {
+			index: IndexInfo{
+				PgIndex: "test_v_idx",
+				Key:     []IndexKeyPair{{Field: "v.foo", Descending: true}},
+				Unique:  true,
+			},
+			expected: `CREATE UNIQUE INDEX "test_v_idx" ON "db"."test" (((_jsonb->'v' -> 'foo')) DESC)`,
+		},
+		"Computed": {
+			index: IndexInfo{
+				PgIndex: "test_email_idx",
+				Key: []IndexKeyPair{
+					{Field: "email", Descending: true, Expression: backends.IndexKeyExpressionToLower},
+					{Field: `items.it's "sku"`},
+				},
+			},
+			expected: `CREATE INDEX "test_email_idx" ON "db"."test" USING gin (` +
+				`(lower(jsonb_path_query_array(_jsonb, 'lax $."email"[*]'::jsonpath)::text)::jsonb), ` +
+				`(jsonb_path_query_array(_jsonb, 'lax $."items"."it''s \"sku\""[*]'::jsonpath)))`,
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, indexCreateQuery("db", "test", tc.index, false))
+		})
+	}
+}
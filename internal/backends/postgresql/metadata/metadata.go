@@ -19,6 +19,7 @@ import (
 	"database/sql"
 	"database/sql/driver"
 
+	"github.com/FerretDB/FerretDB/internal/backends"
 	"github.com/FerretDB/FerretDB/internal/handlers/sjson"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
@@ -31,6 +32,9 @@ const (
 
 	// IDColumn is a PostgreSQL path expression for _id field.
 	IDColumn = DefaultColumn + "->'_id'"
+
+	// RecordIDColumn is a column name for record IDs that define the natural order of documents.
+	RecordIDColumn = backends.ReservedPrefix + "record_id"
 )
 
 // Collection represents collection metadata.
@@ -40,7 +44,15 @@ const (
 type Collection struct {
 	Name      string
 	TableName string
+	UUID      string // not set for collections created by older versions
 	Indexes   Indexes
+
+	// RecordIDs is false for tables created without RecordIDColumn by older versions
+	// until that column is added by the metadata repair.
+	RecordIDs bool
+
+	Temp      bool
+	TempOwner string // FerretDB instance that created the temporary collection
 }
 
 // deepCopy returns a deep copy.
@@ -52,7 +64,11 @@ func (c *Collection) deepCopy() *Collection {
 	return &Collection{
 		Name:      c.Name,
 		TableName: c.TableName,
+		UUID:      c.UUID,
 		Indexes:   c.Indexes.deepCopy(),
+		RecordIDs: c.RecordIDs,
+		Temp:      c.Temp,
+		TempOwner: c.TempOwner,
 	}
 }
 
@@ -99,7 +115,11 @@ func (c *Collection) marshal() *types.Document {
 	return must.NotFail(types.NewDocument(
 		"_id", c.Name,
 		"table", c.TableName,
+		"uuid", c.UUID,
 		"indexes", c.Indexes.marshal(),
+		"recordIDs", c.RecordIDs,
+		"temp", c.Temp,
+		"tempOwner", c.TempOwner,
 	))
 }
 
@@ -130,6 +150,19 @@ func (c *Collection) unmarshal(doc *types.Document) error {
 		return lazyerrors.Error(err)
 	}
 
+	// it is not set for collections created by older versions
+	v, _ = doc.Get("recordIDs")
+	c.RecordIDs, _ = v.(bool)
+
+	v, _ = doc.Get("uuid")
+	c.UUID, _ = v.(string)
+
+	v, _ = doc.Get("temp")
+	c.Temp, _ = v.(bool)
+
+	v, _ = doc.Get("tempOwner")
+	c.TempOwner, _ = v.(string)
+
 	return nil
 }
 
@@ -16,6 +16,7 @@ package metadata
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"hash/fnv"
 	"regexp"
@@ -23,7 +24,9 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/prometheus/client_golang/prometheus"
@@ -31,6 +34,7 @@ import (
 	"golang.org/x/exp/maps"
 
 	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/backends/metadatacache"
 	"github.com/FerretDB/FerretDB/internal/backends/postgresql/metadata/pool"
 	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
 	"github.com/FerretDB/FerretDB/internal/handlers/sjson"
@@ -82,6 +86,13 @@ type Registry struct {
 	// TODO https://github.com/FerretDB/FerretDB/issues/2755
 	rw    sync.RWMutex
 	colls map[string]map[string]*Collection // database name -> collection name -> collection
+
+	// cache is a snapshot of colls; it is invalidated on every change of colls.
+	cache metadatacache.Cache[Collection]
+
+	// PostgreSQL names of indexes that are being built, in "database name.index name" form.
+	// They are not a part of collections metadata yet, but their names are taken.
+	building map[string]struct{}
 }
 
 // NewRegistry creates a registry for PostgreSQL databases with a given base URI.
@@ -92,8 +103,9 @@ func NewRegistry(u string, l *zap.Logger, sp *state.Provider) (*Registry, error)
 	}
 
 	r := &Registry{
-		p: p,
-		l: l,
+		p:        p,
+		l:        l,
+		building: map[string]struct{}{},
 	}
 
 	return r, nil
@@ -143,6 +155,8 @@ func (r *Registry) getPool(ctx context.Context) (*pgxpool.Pool, error) {
 		}
 	}
 
+	r.cache.Invalidate()
+
 	return p, nil
 }
 
@@ -236,6 +250,26 @@ func (r *Registry) initCollections(ctx context.Context, dbName string, p *pgxpoo
 	return nil
 }
 
+// recordIDsAdd adds record IDs column to the existing table if it does not have one.
+//
+// That rewrites the whole table while holding an exclusive lock on it,
+// so it is done only on explicit request (see [Registry.DatabaseValidate]).
+// Existing rows get record IDs in their physical order, that is the best approximation of the insertion order;
+// new rows get larger record IDs.
+func recordIDsAdd(ctx context.Context, p *pgxpool.Pool, dbName, tableName string) error {
+	q := fmt.Sprintf(
+		`ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s bigint GENERATED ALWAYS AS IDENTITY`,
+		pgx.Identifier{dbName, tableName}.Sanitize(),
+		RecordIDColumn,
+	)
+
+	if _, err := p.Exec(ctx, q); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
 // DatabaseList returns a sorted list of existing databases.
 //
 // If the user is not authenticated, it returns error.
@@ -267,11 +301,7 @@ func (r *Registry) DatabaseGetExisting(ctx context.Context, dbName string) (*pgx
 		return nil, lazyerrors.Error(err)
 	}
 
-	r.rw.RLock()
-	defer r.rw.RUnlock()
-
-	db := r.colls[dbName]
-	if db == nil {
+	if r.cached()[dbName] == nil {
 		return nil, nil
 	}
 
@@ -356,6 +386,7 @@ func (r *Registry) databaseGetOrCreate(ctx context.Context, p *pgxpool.Pool, dbN
 	}
 
 	r.colls[dbName] = map[string]*Collection{}
+	r.cache.Invalidate()
 
 	return p, nil
 }
@@ -404,6 +435,7 @@ func (r *Registry) databaseDrop(ctx context.Context, p *pgxpool.Pool, dbName str
 	}
 
 	delete(r.colls, dbName)
+	r.cache.Invalidate()
 
 	return true, nil
 }
@@ -453,10 +485,35 @@ func (r *Registry) CollectionCreate(ctx context.Context, dbName, collectionName
 		return false, lazyerrors.Error(err)
 	}
 
+	// fast path for existing collections, without taking the write lock
+	if r.cached()[dbName][collectionName] != nil {
+		return false, nil
+	}
+
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	return r.collectionCreate(ctx, p, dbName, collectionName, "")
+}
+
+// CollectionCreateTemp creates a collection like CollectionCreate,
+// but marks it as temporary in the metadata, owned by the given FerretDB instance.
+//
+// If the user is not authenticated, it returns error.
+func (r *Registry) CollectionCreateTemp(ctx context.Context, dbName, collectionName, owner string) (bool, error) {
+	defer observability.FuncCall(ctx)()
+
+	must.NotBeZero(owner)
+
+	p, err := r.getPool(ctx)
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
 	r.rw.Lock()
 	defer r.rw.Unlock()
 
-	return r.collectionCreate(ctx, p, dbName, collectionName)
+	return r.collectionCreate(ctx, p, dbName, collectionName, owner)
 }
 
 // collectionCreate creates a collection in the database.
@@ -465,8 +522,10 @@ func (r *Registry) CollectionCreate(ctx context.Context, dbName, collectionName
 // Returned boolean value indicates whether the collection was created.
 // If collection already exists, (false, nil) is returned.
 //
+// If tempOwner is not empty, the collection is marked as temporary.
+//
 // It does not hold the lock.
-func (r *Registry) collectionCreate(ctx context.Context, p *pgxpool.Pool, dbName, collectionName string) (bool, error) {
+func (r *Registry) collectionCreate(ctx context.Context, p *pgxpool.Pool, dbName, collectionName, tempOwner string) (bool, error) { //nolint:lll // for readability
 	defer observability.FuncCall(ctx)()
 
 	_, err := r.databaseGetOrCreate(ctx, p, dbName)
@@ -507,12 +566,17 @@ func (r *Registry) collectionCreate(ctx context.Context, p *pgxpool.Pool, dbName
 	c := &Collection{
 		Name:      collectionName,
 		TableName: tableName,
+		UUID:      uuid.NewString(),
+		RecordIDs: true,
+		Temp:      tempOwner != "",
+		TempOwner: tempOwner,
 	}
 
 	q := fmt.Sprintf(
-		`CREATE TABLE %s (%s jsonb)`,
+		`CREATE TABLE %s (%s jsonb, %s bigint GENERATED ALWAYS AS IDENTITY)`,
 		pgx.Identifier{dbName, tableName}.Sanitize(),
 		DefaultColumn,
+		RecordIDColumn,
 	)
 	if _, err = p.Exec(ctx, q); err != nil {
 		return false, lazyerrors.Error(err)
@@ -534,6 +598,7 @@ func (r *Registry) collectionCreate(ctx context.Context, p *pgxpool.Pool, dbName
 		r.colls[dbName] = map[string]*Collection{}
 	}
 	r.colls[dbName][collectionName] = c
+	r.cache.Invalidate()
 
 	err = r.indexesCreate(ctx, p, dbName, collectionName, []IndexInfo{{
 		Name:   "_id_",
@@ -567,6 +632,27 @@ func (r *Registry) CollectionGet(ctx context.Context, dbName, collectionName str
 	return r.collectionGet(dbName, collectionName), nil
 }
 
+// cached returns the current metadata snapshot shared by all connections.
+func (r *Registry) cached() metadatacache.Snapshot[Collection] {
+	return r.cache.Get(&r.rw, func() map[string]map[string]*Collection { return r.colls })
+}
+
+// CollectionGetCached returns collection metadata from the cache shared by all connections.
+// It is faster than CollectionGet, but the returned value must not be modified by a caller.
+//
+// If database or collection does not exist, nil is returned.
+//
+// If the user is not authenticated, it returns error.
+func (r *Registry) CollectionGetCached(ctx context.Context, dbName, collectionName string) (*Collection, error) {
+	defer observability.FuncCall(ctx)()
+
+	if _, err := r.getPool(ctx); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return r.cached()[dbName][collectionName], nil
+}
+
 // collectionGet returns a copy of collection metadata.
 // It can be safely modified by a caller.
 //
@@ -602,6 +688,30 @@ func (r *Registry) CollectionDrop(ctx context.Context, dbName, collectionName st
 	return r.collectionDrop(ctx, p, dbName, collectionName)
 }
 
+// CollectionDropUUID drops a collection like CollectionDrop,
+// but only if it has the given UUID.
+//
+// If the collection has a different UUID, (false, nil) is returned.
+//
+// If the user is not authenticated, it returns error.
+func (r *Registry) CollectionDropUUID(ctx context.Context, dbName, collectionName, uuid string) (bool, error) {
+	defer observability.FuncCall(ctx)()
+
+	p, err := r.getPool(ctx)
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	if c := r.collectionGet(dbName, collectionName); c == nil || c.UUID != uuid {
+		return false, nil
+	}
+
+	return r.collectionDrop(ctx, p, dbName, collectionName)
+}
+
 // collectionDrop drops a collection in the database.
 //
 // Returned boolean value indicates whether the collection was dropped.
@@ -648,6 +758,7 @@ func (r *Registry) collectionDrop(ctx context.Context, p *pgxpool.Pool, dbName,
 	}
 
 	delete(r.colls[dbName], collectionName)
+	r.cache.Invalidate()
 
 	return true, nil
 }
@@ -655,6 +766,7 @@ func (r *Registry) collectionDrop(ctx context.Context, p *pgxpool.Pool, dbName,
 // CollectionRename renames a collection in the database.
 //
 // The collection name is update, but original table name is kept.
+// Renamed collection is no longer temporary.
 //
 // Returned boolean value indicates whether the collection was renamed.
 // If database or collection did not exist, (false, nil) is returned.
@@ -682,6 +794,8 @@ func (r *Registry) CollectionRename(ctx context.Context, dbName, oldCollectionNa
 	}
 
 	c.Name = newCollectionName
+	c.Temp = false
+	c.TempOwner = ""
 
 	b, err := sjson.Marshal(c.marshal())
 	if err != nil {
@@ -706,6 +820,103 @@ func (r *Registry) CollectionRename(ctx context.Context, dbName, oldCollectionNa
 
 	r.colls[dbName][newCollectionName] = c
 	delete(r.colls[dbName], oldCollectionName)
+	r.cache.Invalidate()
+
+	return true, nil
+}
+
+// CollectionReplace renames a collection in the database like CollectionRename,
+// dropping the existing collection with the new name in the same transaction.
+//
+// Returned boolean value indicates whether the collection was renamed.
+// If database or old collection did not exist, (false, nil) is returned.
+//
+// If the user is not authenticated, it returns error.
+func (r *Registry) CollectionReplace(ctx context.Context, dbName, oldCollectionName, newCollectionName string) (bool, error) {
+	defer observability.FuncCall(ctx)()
+
+	p, err := r.getPool(ctx)
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	if r.colls[dbName] == nil {
+		return false, nil
+	}
+
+	old := r.collectionGet(dbName, oldCollectionName)
+	if old == nil {
+		return false, nil
+	}
+
+	c := old.deepCopy()
+	c.Name = newCollectionName
+	c.Temp = false
+	c.TempOwner = ""
+
+	b, err := sjson.Marshal(c.marshal())
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	arg, err := sjson.MarshalSingleValue(oldCollectionName)
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	target := r.collectionGet(dbName, newCollectionName)
+
+	err = pool.InTransaction(ctx, p, func(tx pgx.Tx) error {
+		if target != nil {
+			targetArg, err := sjson.MarshalSingleValue(target.Name)
+			if err != nil {
+				return lazyerrors.Error(err)
+			}
+
+			q := fmt.Sprintf(
+				`DELETE FROM %s WHERE %s IN ($1)`,
+				pgx.Identifier{dbName, metadataTableName}.Sanitize(),
+				IDColumn,
+			)
+
+			if _, err = tx.Exec(ctx, q, targetArg); err != nil {
+				return lazyerrors.Error(err)
+			}
+
+			// TODO https://github.com/FerretDB/FerretDB/issues/811
+			q = fmt.Sprintf(
+				`DROP TABLE %s CASCADE`,
+				pgx.Identifier{dbName, target.TableName}.Sanitize(),
+			)
+
+			if _, err = tx.Exec(ctx, q); err != nil {
+				return lazyerrors.Error(err)
+			}
+		}
+
+		q := fmt.Sprintf(
+			`UPDATE %s SET %s = $1 WHERE %s = $2`,
+			pgx.Identifier{dbName, metadataTableName}.Sanitize(),
+			DefaultColumn,
+			IDColumn,
+		)
+
+		if _, err := tx.Exec(ctx, q, string(b), arg); err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	r.colls[dbName][newCollectionName] = c
+	delete(r.colls[dbName], oldCollectionName)
+	r.cache.Invalidate()
 
 	return true, nil
 }
@@ -714,8 +925,14 @@ func (r *Registry) CollectionRename(ctx context.Context, dbName, oldCollectionNa
 //
 // Existing indexes with given names are ignored.
 //
+// New indexes are built with CREATE INDEX CONCURRENTLY without holding the lock,
+// so long index builds do not block other operations.
+// For new and empty collections, plain CREATE INDEX is used, as it is much faster and blocks writes only briefly.
+// If progress is not nil, it is periodically called with the number of processed and total blocks or tuples.
+// If the build fails or the context is canceled, indexes built by this call are dropped.
+//
 // If the user is not authenticated, it returns error.
-func (r *Registry) IndexesCreate(ctx context.Context, dbName, collectionName string, indexes []IndexInfo) error {
+func (r *Registry) IndexesCreate(ctx context.Context, dbName, collectionName string, indexes []IndexInfo, progress func(done, total int64)) error { //nolint:lll // for readability
 	defer observability.FuncCall(ctx)()
 
 	p, err := r.getPool(ctx)
@@ -723,21 +940,150 @@ func (r *Registry) IndexesCreate(ctx context.Context, dbName, collectionName str
 		return lazyerrors.Error(err)
 	}
 
+	tableName, created, toBuild, err := r.indexesPrepare(ctx, p, dbName, collectionName, indexes)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	defer r.indexesRelease(dbName, toBuild)
+
+	concurrently := !created
+
+	if concurrently && len(toBuild) > 0 {
+		q := fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s)`, pgx.Identifier{dbName, tableName}.Sanitize())
+
+		var exists bool
+		if err = p.QueryRow(ctx, q).Scan(&exists); err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		concurrently = exists
+	}
+
+	// indexes should be dropped even if the context is canceled
+	dropCtx := context.WithoutCancel(ctx)
+
+	built := make([]IndexInfo, 0, len(toBuild))
+
+	for _, index := range toBuild {
+		// failed CREATE INDEX CONCURRENTLY leaves an invalid index that should be dropped too
+		built = append(built, index)
+
+		if err = indexBuild(ctx, p, dbName, tableName, index, concurrently, progress); err != nil {
+			r.pgIndexesDrop(dropCtx, p, dbName, built)
+			return lazyerrors.Error(err)
+		}
+	}
+
+	toDrop, err := r.indexesCommit(ctx, p, dbName, collectionName, tableName, built)
+	r.pgIndexesDrop(dropCtx, p, dbName, toDrop)
+
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// indexesPrepare creates the collection if needed and returns its table name,
+// true if the collection was created, and indexes that should be built.
+//
+// PostgreSQL index names of returned indexes are reserved until [indexesRelease] is called.
+//
+// It holds the lock.
+func (r *Registry) indexesPrepare(ctx context.Context, p *pgxpool.Pool, dbName, collectionName string, indexes []IndexInfo) (string, bool, []IndexInfo, error) { //nolint:lll // for readability
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	created, err := r.collectionCreate(ctx, p, dbName, collectionName, "")
+	if err != nil {
+		return "", false, nil, lazyerrors.Error(err)
+	}
+
+	c := r.collectionGet(dbName, collectionName)
+	if c == nil {
+		panic("collection does not exist")
+	}
+
+	toBuild := r.indexesNew(dbName, c, indexes)
+
+	for _, index := range toBuild {
+		r.building[dbName+"."+index.PgIndex] = struct{}{}
+	}
+
+	return c.TableName, created, toBuild, nil
+}
+
+// indexesRelease releases PostgreSQL index names reserved by [indexesPrepare].
+//
+// It holds the lock.
+func (r *Registry) indexesRelease(dbName string, indexes []IndexInfo) {
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	for _, index := range indexes {
+		delete(r.building, dbName+"."+index.PgIndex)
+	}
+}
+
+// indexesCommit adds built indexes to the collection metadata.
+//
+// Indexes are added to the collection that owns the given table, even if it was renamed while indexes were built.
+//
+// It returns indexes that were built but should be dropped:
+// ones that were concurrently created by another call, or all of them if metadata can't be updated.
+//
+// It holds the lock.
+func (r *Registry) indexesCommit(ctx context.Context, p *pgxpool.Pool, dbName, collectionName, tableName string, built []IndexInfo) ([]IndexInfo, error) { //nolint:lll // for readability
 	r.rw.Lock()
 	defer r.rw.Unlock()
 
-	return r.indexesCreate(ctx, p, dbName, collectionName, indexes)
+	var c *Collection
+
+	for _, coll := range r.colls[dbName] {
+		if coll.TableName == tableName {
+			// cached snapshots share collections, so they should not be modified in place
+			c = coll.deepCopy()
+			break
+		}
+	}
+
+	if c == nil {
+		// the collection was dropped (and maybe created again with a new table);
+		// built indexes were dropped together with the old table
+		return nil, lazyerrors.Errorf("collection %s.%s was dropped while indexes were built", dbName, collectionName)
+	}
+
+	var duplicates []IndexInfo
+
+	for _, index := range built {
+		if slices.ContainsFunc(c.Indexes, func(i IndexInfo) bool { return index.Name == i.Name }) {
+			duplicates = append(duplicates, index)
+			continue
+		}
+
+		c.Indexes = append(c.Indexes, index)
+	}
+
+	if err := r.collectionUpdate(ctx, p, dbName, c); err != nil {
+		return built, lazyerrors.Error(err)
+	}
+
+	return duplicates, nil
 }
 
 // indexesCreate creates indexes in the collection.
 //
 // Existing indexes with given names are ignored.
 //
+// Unlike [IndexesCreate], it builds indexes with a plain CREATE INDEX statement;
+// that's fast enough for new and empty collections.
+//
 // It does not hold the lock.
 func (r *Registry) indexesCreate(ctx context.Context, p *pgxpool.Pool, dbName, collectionName string, indexes []IndexInfo) error {
 	defer observability.FuncCall(ctx)()
 
-	_, err := r.collectionCreate(ctx, p, dbName, collectionName)
+	_, err := r.collectionCreate(ctx, p, dbName, collectionName, "")
 	if err != nil {
 		return lazyerrors.Error(err)
 	}
@@ -752,20 +1098,47 @@ func (r *Registry) indexesCreate(ctx context.Context, p *pgxpool.Pool, dbName, c
 		panic("collection does not exist")
 	}
 
-	allIndexes := make(map[string]string, len(db))   // to check if the index already exists
-	allPgIndexes := make(map[string]string, len(db)) // to ensure there are no indexes with the same name in the pg schema
+	created := make([]string, 0, len(indexes))
+
+	for _, index := range r.indexesNew(dbName, c, indexes) {
+		q := indexCreateQuery(dbName, c.TableName, index, false)
+
+		if _, err = p.Exec(ctx, q); err != nil {
+			_ = r.indexesDrop(ctx, p, dbName, collectionName, created)
+			return lazyerrors.Error(err)
+		}
+
+		created = append(created, index.Name)
+		c.Indexes = append(c.Indexes, index)
+	}
+
+	return r.collectionUpdate(ctx, p, dbName, c)
+}
+
+// indexesNew returns indexes that do not exist in the collection yet
+// with assigned PostgreSQL index names that are unique in the database.
+//
+// It does not hold the lock.
+func (r *Registry) indexesNew(dbName string, c *Collection, indexes []IndexInfo) []IndexInfo {
+	db := r.colls[dbName]
+
+	allIndexes := make(map[string]struct{}, len(c.Indexes)) // to check if the index already exists
+	allPgIndexes := make(map[string]struct{}, len(db))      // to ensure there are no indexes with the same name in the pg schema
+
+	for _, index := range c.Indexes {
+		allIndexes[index.Name] = struct{}{}
+	}
 
 	for _, coll := range db {
 		for _, index := range coll.Indexes {
-			allIndexes[index.Name] = coll.Name
-			allPgIndexes[index.PgIndex] = coll.Name
+			allPgIndexes[index.PgIndex] = struct{}{}
 		}
 	}
 
-	created := make([]string, 0, len(indexes))
+	res := make([]IndexInfo, 0, len(indexes))
 
 	for _, index := range indexes {
-		if coll, ok := allIndexes[index.Name]; ok && coll == collectionName {
+		if _, ok := allIndexes[index.Name]; ok {
 			continue
 		}
 
@@ -793,9 +1166,11 @@ func (r *Registry) indexesCreate(ctx context.Context, p *pgxpool.Pool, dbName, c
 			pgIndexName = fmt.Sprintf("%s_%s%s", tableNamePart, indexNamePart, suffixHash)
 
 			// indexes must be unique across the whole database, so we check for duplicates for all collections
+			// and for indexes that are being built
 			_, duplicate := allPgIndexes[pgIndexName]
+			_, building := r.building[dbName+"."+pgIndexName]
 
-			if !duplicate {
+			if !duplicate && !building {
 				break
 			}
 
@@ -804,56 +1179,165 @@ func (r *Registry) indexesCreate(ctx context.Context, p *pgxpool.Pool, dbName, c
 
 		index.PgIndex = pgIndexName
 
-		q := "CREATE "
+		res = append(res, index)
+		allIndexes[index.Name] = struct{}{}
+		allPgIndexes[index.PgIndex] = struct{}{}
+	}
 
-		if index.Unique {
-			q += "UNIQUE "
-		}
+	return res
+}
 
-		q += "INDEX %s ON %s (%s)"
+// indexCreateQuery returns CREATE INDEX query for the given index.
+func indexCreateQuery(dbName, tableName string, index IndexInfo, concurrently bool) string {
+	q := "CREATE "
 
-		columns := make([]string, len(index.Key))
+	if index.Unique {
+		q += "UNIQUE "
+	}
 
-		for i, key := range index.Key {
-			// if the field is nested (e.g. foo.bar), it needs to be translated to the correct json path (foo -> bar)
-			fs := strings.Split(key.Field, ".")
-			transformedParts := make([]string, len(fs))
+	q += "INDEX "
 
-			for j, f := range fs {
-				// It's important to sanitize field.Field data here, as it's a user-provided value.
-				transformedParts[j] = quoteString(f)
-			}
+	if concurrently {
+		q += "CONCURRENTLY "
+	}
 
-			columns[i] = fmt.Sprintf("((%s->%s))", DefaultColumn, strings.Join(transformedParts, " -> "))
-			if key.Descending {
-				columns[i] += " DESC"
-			}
-		}
+	q += "%s ON %s "
 
-		q = fmt.Sprintf(
-			q,
-			pgx.Identifier{index.PgIndex}.Sanitize(),
-			pgx.Identifier{dbName, c.TableName}.Sanitize(),
-			strings.Join(columns, ", "),
-		)
+	columns := make([]string, len(index.Key))
 
-		if _, err = p.Exec(ctx, q); err != nil {
-			_ = r.indexesDrop(ctx, p, dbName, collectionName, created)
-			return lazyerrors.Error(err)
+	if index.Computed() {
+		q += "USING gin "
+	}
+
+	q += "(%s)"
+
+	for i, key := range index.Key {
+		// GIN indexes are not ordered, so sort order is ignored
+		if index.Computed() {
+			columns[i] = ComputedColumn(key)
+			continue
 		}
 
-		created = append(created, index.Name)
-		c.Indexes = append(c.Indexes, index)
-		allIndexes[index.Name] = collectionName
-		allPgIndexes[index.PgIndex] = collectionName
-	}
+		// if the field is nested (e.g. foo.bar), it needs to be translated to the correct json path (foo -> bar)
+		fs := strings.Split(key.Field, ".")
+		transformedParts := make([]string, len(fs))
 
-	b, err := sjson.Marshal(c.marshal())
-	if err != nil {
-		return lazyerrors.Error(err)
+		for j, f := range fs {
+			// It's important to sanitize field.Field data here, as it's a user-provided value.
+			transformedParts[j] = quoteString(f)
+		}
+
+		columns[i] = fmt.Sprintf("((%s->%s))", DefaultColumn, strings.Join(transformedParts, " -> "))
+		if key.Descending {
+			columns[i] += " DESC"
+		}
 	}
 
-	arg, err := sjson.MarshalSingleValue(collectionName)
+	return fmt.Sprintf(
+		q,
+		pgx.Identifier{index.PgIndex}.Sanitize(),
+		pgx.Identifier{dbName, tableName}.Sanitize(),
+		strings.Join(columns, ", "),
+	)
+}
+
+// indexBuild builds a single index with CREATE INDEX, concurrently if requested.
+//
+// If progress is not nil, it is periodically called with the progress of the build.
+func indexBuild(ctx context.Context, p *pgxpool.Pool, dbName, tableName string, index IndexInfo, concurrently bool, progress func(done, total int64)) error { //nolint:lll // for readability
+	defer observability.FuncCall(ctx)()
+
+	// use a dedicated connection to know its backend PID
+	conn, err := p.Acquire(ctx)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	defer conn.Release()
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+
+	if progress != nil {
+		pid := conn.Conn().PgConn().PID()
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			indexBuildProgress(ctx, p, pid, done, progress)
+		}()
+	}
+
+	_, err = conn.Exec(ctx, indexCreateQuery(dbName, tableName, index, concurrently))
+
+	close(done)
+	wg.Wait()
+
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// indexBuildProgress polls pg_stat_progress_create_index view
+// for the index build progress of the given backend process until done is closed.
+func indexBuildProgress(ctx context.Context, p *pgxpool.Pool, pid uint32, done <-chan struct{}, progress func(done, total int64)) { //nolint:lll // for readability
+	q := `SELECT blocks_done, blocks_total, tuples_done, tuples_total FROM pg_stat_progress_create_index WHERE pid = $1`
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		var blocksDone, blocksTotal, tuplesDone, tuplesTotal int64
+
+		// there is no row while the build waits for other transactions, so just try again later
+		if err := p.QueryRow(ctx, q, int64(pid)).Scan(&blocksDone, &blocksTotal, &tuplesDone, &tuplesTotal); err != nil {
+			continue
+		}
+
+		if blocksTotal > 0 {
+			progress(blocksDone, blocksTotal)
+			continue
+		}
+
+		progress(tuplesDone, tuplesTotal)
+	}
+}
+
+// pgIndexesDrop drops given PostgreSQL indexes, if they exist, with DROP INDEX CONCURRENTLY.
+//
+// Errors are logged, not returned, as it is used for cleanup.
+//
+// It does not hold the lock.
+func (r *Registry) pgIndexesDrop(ctx context.Context, p *pgxpool.Pool, dbName string, indexes []IndexInfo) {
+	for _, index := range indexes {
+		q := fmt.Sprintf("DROP INDEX CONCURRENTLY IF EXISTS %s", pgx.Identifier{dbName, index.PgIndex}.Sanitize())
+		if _, err := p.Exec(ctx, q); err != nil {
+			r.l.Warn("Failed to drop index", zap.String("index", index.PgIndex), zap.Error(err))
+		}
+	}
+}
+
+// collectionUpdate saves collection metadata.
+//
+// It does not hold the lock.
+func (r *Registry) collectionUpdate(ctx context.Context, p *pgxpool.Pool, dbName string, c *Collection) error {
+	b, err := sjson.Marshal(c.marshal())
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	arg, err := sjson.MarshalSingleValue(c.Name)
 	if err != nil {
 		return lazyerrors.Error(err)
 	}
@@ -869,7 +1353,8 @@ func (r *Registry) indexesCreate(ctx context.Context, p *pgxpool.Pool, dbName, c
 		return lazyerrors.Error(err)
 	}
 
-	r.colls[dbName][collectionName] = c
+	r.colls[dbName][c.Name] = c
+	r.cache.Invalidate()
 
 	return nil
 }
@@ -924,32 +1409,613 @@ func (r *Registry) indexesDrop(ctx context.Context, p *pgxpool.Pool, dbName, col
 		c.Indexes = slices.Delete(c.Indexes, i, i+1)
 	}
 
-	b, err := sjson.Marshal(c.marshal())
+	return r.collectionUpdate(ctx, p, dbName, c)
+}
+
+// DatabaseValidate cross-checks collections metadata of the database against existing tables and indexes.
+//
+// If repair is true, found problems are repaired:
+//   - metadata of collections without tables is removed;
+//   - tables without metadata are registered as collections with the same name and the default index;
+//   - tables of collections created by older versions get record IDs column (that rewrites them);
+//   - metadata of indexes without PostgreSQL indexes is removed, except the default index that is rebuilt.
+//
+// PostgreSQL indexes without metadata are only reported; they could be created manually.
+//
+// Indexes that are being built are not reported.
+//
+// Repairs that add columns or build indexes are done without holding the lock,
+// so other collections stay available.
+//
+// If database does not exist, (nil, nil) is returned.
+//
+// If the user is not authenticated, it returns error.
+func (r *Registry) DatabaseValidate(ctx context.Context, dbName string, repair bool) ([]backends.MetadataProblem, error) {
+	defer observability.FuncCall(ctx)()
+
+	p, err := r.getPool(ctx)
 	if err != nil {
-		return lazyerrors.Error(err)
+		return nil, lazyerrors.Error(err)
+	}
+
+	res, repairs, err := r.databaseValidate(ctx, p, dbName, repair)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	for _, tr := range repairs {
+		if tr.recordIDs {
+			err = r.recordIDsRepair(ctx, p, dbName, tr.table)
+		} else {
+			err = r.defaultIndexRepair(ctx, p, dbName, tr.table, tr.unregister)
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		res[tr.problem].Repaired = true
+	}
+
+	return res, nil
+}
+
+// tableRepair represents a repair that adds a column to the table or builds an index.
+//
+// Such repairs could take a long time, so they are done without holding the lock.
+type tableRepair struct {
+	problem    int    // index of the repaired problem
+	table      string // the table of the collection
+	recordIDs  bool   // add record IDs column; otherwise, build the default index
+	unregister bool   // remove collection metadata if the default index can't be built
+}
+
+// databaseValidate cross-checks metadata like [DatabaseValidate] and repairs found problems if requested,
+// except ones that are returned as table repairs.
+//
+// It holds the lock.
+func (r *Registry) databaseValidate(ctx context.Context, p *pgxpool.Pool, dbName string, repair bool) ([]backends.MetadataProblem, []tableRepair, error) { //nolint:lll // for readability
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	if r.colls[dbName] == nil {
+		return nil, nil, nil
+	}
+
+	res, tables, repairs, err := r.tablesValidate(ctx, p, dbName, repair)
+	if err != nil {
+		return nil, nil, lazyerrors.Error(err)
+	}
+
+	problems, indexRepairs, err := r.indexesValidate(ctx, p, dbName, tables, repair)
+	if err != nil {
+		return nil, nil, lazyerrors.Error(err)
+	}
+
+	for _, tr := range indexRepairs {
+		tr.problem += len(res)
+		repairs = append(repairs, tr)
+	}
+
+	return append(res, problems...), repairs, nil
+}
+
+// tablesValidate cross-checks collections metadata of the database against existing tables
+// and repairs found problems if requested, except ones that are returned as table repairs.
+//
+// It also returns names of existing tables of registered collections.
+//
+// It does not hold the lock.
+func (r *Registry) tablesValidate(ctx context.Context, p *pgxpool.Pool, dbName string, repair bool) ([]backends.MetadataProblem, map[string]struct{}, []tableRepair, error) { //nolint:lll // for readability
+	defer observability.FuncCall(ctx)()
+
+	// tables that look like FerretDB tables: table name -> whether it has record IDs column
+	q := strings.TrimSpace(`
+		SELECT table_name, bool_or(column_name = $3)
+		FROM information_schema.columns
+		WHERE table_schema = $1 AND table_name IN (
+			SELECT table_name
+			FROM information_schema.tables
+			WHERE table_schema = $1 AND table_type = 'BASE TABLE'
+		)
+		GROUP BY table_name
+		HAVING bool_or(column_name = $2 AND data_type = 'jsonb')
+		ORDER BY table_name
+	`)
+
+	rows, err := p.Query(ctx, q, dbName, DefaultColumn, RecordIDColumn)
+	if err != nil {
+		return nil, nil, nil, lazyerrors.Error(err)
+	}
+	defer rows.Close()
+
+	tables := map[string]bool{}
+	var tableNames []string
+
+	for rows.Next() {
+		var tableName string
+		var recordIDs bool
+
+		if err = rows.Scan(&tableName, &recordIDs); err != nil {
+			return nil, nil, nil, lazyerrors.Error(err)
+		}
+
+		if strings.HasPrefix(tableName, backends.ReservedPrefix) {
+			continue
+		}
+
+		tables[tableName] = recordIDs
+		tableNames = append(tableNames, tableName)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, nil, nil, lazyerrors.Error(err)
+	}
+
+	var res []backends.MetadataProblem
+	var repairs []tableRepair
+
+	collectionNames := maps.Keys(r.colls[dbName])
+	sort.Strings(collectionNames)
+
+	registered := make(map[string]struct{}, len(collectionNames))
+
+	for _, collectionName := range collectionNames {
+		c := r.colls[dbName][collectionName]
+
+		if _, ok := tables[c.TableName]; ok {
+			registered[c.TableName] = struct{}{}
+			continue
+		}
+
+		problem := backends.MetadataProblem{
+			Type:       backends.MetadataProblemMissingTable,
+			Collection: c.Name,
+			Table:      c.TableName,
+			Action:     "remove collection metadata",
+		}
+
+		if repair {
+			if err = r.collectionMetadataDelete(ctx, p, dbName, c.Name); err != nil {
+				return nil, nil, nil, lazyerrors.Error(err)
+			}
+
+			problem.Repaired = true
+		}
+
+		res = append(res, problem)
+	}
+
+	for _, tableName := range tableNames {
+		if _, ok := registered[tableName]; ok {
+			continue
+		}
+
+		problem := backends.MetadataProblem{
+			Type:  backends.MetadataProblemOrphanTable,
+			Table: tableName,
+		}
+
+		// another collection may already use that name for a different table
+		if r.colls[dbName][tableName] == nil {
+			problem.Action = "register collection " + tableName
+		}
+
+		if repair && problem.Action != "" {
+			var indexed bool
+			if indexed, err = r.collectionRegister(ctx, p, dbName, tableName, tables[tableName]); err != nil {
+				return nil, nil, nil, lazyerrors.Error(err)
+			}
+
+			registered[tableName] = struct{}{}
+
+			problem.Collection = tableName
+			problem.Repaired = indexed
+
+			if !indexed {
+				repairs = append(repairs, tableRepair{problem: len(res), table: tableName, unregister: true})
+			}
+		}
+
+		res = append(res, problem)
+	}
+
+	// collections registered by the repair are checked too
+	collectionNames = maps.Keys(r.colls[dbName])
+	sort.Strings(collectionNames)
+
+	for _, collectionName := range collectionNames {
+		c := r.colls[dbName][collectionName]
+
+		if _, ok := registered[c.TableName]; !ok || c.RecordIDs {
+			continue
+		}
+
+		problem := backends.MetadataProblem{
+			Type:       backends.MetadataProblemMissingRecordIDs,
+			Collection: c.Name,
+			Table:      c.TableName,
+			Action:     "add record IDs column",
+		}
+
+		if repair {
+			repairs = append(repairs, tableRepair{problem: len(res), table: c.TableName, recordIDs: true})
+		}
+
+		res = append(res, problem)
+	}
+
+	return res, registered, repairs, nil
+}
+
+// indexesValidate cross-checks indexes metadata of collections with given existing tables
+// against existing PostgreSQL indexes and repairs found problems if requested,
+// except ones that are returned as table repairs.
+//
+// It does not hold the lock.
+func (r *Registry) indexesValidate(ctx context.Context, p *pgxpool.Pool, dbName string, existing map[string]struct{}, repair bool) ([]backends.MetadataProblem, []tableRepair, error) { //nolint:lll // for readability
+	defer observability.FuncCall(ctx)()
+
+	q := `SELECT indexname, tablename FROM pg_indexes WHERE schemaname = $1 ORDER BY indexname`
+
+	rows, err := p.Query(ctx, q, dbName)
+	if err != nil {
+		return nil, nil, lazyerrors.Error(err)
+	}
+	defer rows.Close()
+
+	pgIndexes := map[string]string{} // PostgreSQL index name -> table name
+	var pgIndexNames []string
+
+	for rows.Next() {
+		var indexName, tableName string
+		if err = rows.Scan(&indexName, &tableName); err != nil {
+			return nil, nil, lazyerrors.Error(err)
+		}
+
+		pgIndexes[indexName] = tableName
+		pgIndexNames = append(pgIndexNames, indexName)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, nil, lazyerrors.Error(err)
 	}
 
+	var res []backends.MetadataProblem
+	var repairs []tableRepair
+
+	collectionNames := maps.Keys(r.colls[dbName])
+	sort.Strings(collectionNames)
+
+	tables := make(map[string]string, len(collectionNames))       // table name -> collection name
+	registered := make(map[string]struct{}, len(collectionNames)) // registered PostgreSQL index names
+
+	for _, collectionName := range collectionNames {
+		c := r.collectionGet(dbName, collectionName)
+		if _, ok := existing[c.TableName]; !ok {
+			continue
+		}
+
+		tables[c.TableName] = c.Name
+
+		for _, index := range c.Indexes {
+			if pgIndexes[index.PgIndex] == c.TableName {
+				registered[index.PgIndex] = struct{}{}
+				continue
+			}
+
+			problem := backends.MetadataProblem{
+				Type:       backends.MetadataProblemMissingIndex,
+				Collection: c.Name,
+				Table:      c.TableName,
+				Index:      index.Name,
+				Action:     "remove index metadata",
+			}
+
+			if index.Name == backends.DefaultIndexName {
+				problem.Action = "rebuild index"
+			}
+
+			if repair {
+				if err = r.indexMetadataDelete(ctx, p, dbName, c.Name, index.Name); err != nil {
+					return nil, nil, lazyerrors.Error(err)
+				}
+
+				problem.Repaired = index.Name != backends.DefaultIndexName
+
+				if !problem.Repaired {
+					repairs = append(repairs, tableRepair{problem: len(res), table: c.TableName})
+				}
+			}
+
+			res = append(res, problem)
+		}
+	}
+
+	for _, indexName := range pgIndexNames {
+		collectionName, ok := tables[pgIndexes[indexName]]
+		if !ok {
+			continue
+		}
+
+		if _, ok = registered[indexName]; ok {
+			continue
+		}
+
+		if _, ok = r.building[dbName+"."+indexName]; ok {
+			continue
+		}
+
+		problem := backends.MetadataProblem{
+			Type:       backends.MetadataProblemOrphanIndex,
+			Collection: collectionName,
+			Table:      pgIndexes[indexName],
+			Index:      indexName,
+		}
+
+		// it may be added manually, so it is reported, but never dropped
+		res = append(res, problem)
+	}
+
+	return res, repairs, nil
+}
+
+// collectionMetadataDelete removes collection metadata without dropping the table.
+//
+// It does not hold the lock.
+func (r *Registry) collectionMetadataDelete(ctx context.Context, p *pgxpool.Pool, dbName, collectionName string) error {
 	arg, err := sjson.MarshalSingleValue(collectionName)
 	if err != nil {
 		return lazyerrors.Error(err)
 	}
 
 	q := fmt.Sprintf(
-		`UPDATE %s SET %s = $1 WHERE %s = $2`,
+		`DELETE FROM %s WHERE %s IN ($1)`,
 		pgx.Identifier{dbName, metadataTableName}.Sanitize(),
-		DefaultColumn,
 		IDColumn,
 	)
 
-	if _, err := p.Exec(ctx, q, string(b), arg); err != nil {
+	if _, err = p.Exec(ctx, q, arg); err != nil {
 		return lazyerrors.Error(err)
 	}
 
-	r.colls[dbName][collectionName] = c
+	delete(r.colls[dbName], collectionName)
+	r.cache.Invalidate()
 
 	return nil
 }
 
+// collectionRegister adds metadata for the existing table as a collection with the same name.
+//
+// Existing indexes of the table are kept as is.
+// If one of them is a unique index with the name of the default index, it is registered as the default index,
+// and true is returned; otherwise, the default index should be built by the caller.
+//
+// It does not hold the lock.
+func (r *Registry) collectionRegister(ctx context.Context, p *pgxpool.Pool, dbName, tableName string, recordIDs bool) (bool, error) { //nolint:lll // for readability
+	c := &Collection{
+		Name:      tableName,
+		TableName: tableName,
+		UUID:      uuid.NewString(),
+		RecordIDs: recordIDs,
+	}
+
+	q := fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES ($1)`,
+		pgx.Identifier{dbName, metadataTableName}.Sanitize(),
+		DefaultColumn,
+	)
+	if _, err := p.Exec(ctx, q, c); err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	r.colls[dbName][c.Name] = c
+	r.cache.Invalidate()
+
+	// the default index could be restored together with the table
+	index := r.indexesNew(dbName, c, []IndexInfo{defaultIndex()})[0]
+
+	q = strings.TrimSpace(`
+		SELECT indexdef LIKE 'CREATE UNIQUE INDEX %'
+		FROM pg_indexes
+		WHERE schemaname = $1 AND tablename = $2 AND indexname = $3
+	`)
+
+	var unique bool
+
+	err := p.QueryRow(ctx, q, dbName, tableName, index.PgIndex).Scan(&unique)
+
+	switch {
+	case err == nil && unique:
+		c = r.collectionGet(dbName, c.Name)
+		c.Indexes = append(c.Indexes, index)
+
+		if err = r.collectionUpdate(ctx, p, dbName, c); err == nil {
+			return true, nil
+		}
+
+	case err == nil, errors.Is(err, pgx.ErrNoRows):
+		return false, nil
+	}
+
+	_ = r.collectionMetadataDelete(ctx, p, dbName, c.Name)
+
+	return false, lazyerrors.Error(err)
+}
+
+// defaultIndex returns the default index specification.
+func defaultIndex() IndexInfo {
+	return IndexInfo{
+		Name:   backends.DefaultIndexName,
+		Key:    []IndexKeyPair{{Field: "_id"}},
+		Unique: true,
+	}
+}
+
+// collectionByTable returns a copy of the collection that owns the given table, or nil.
+//
+// It does not hold the lock.
+func (r *Registry) collectionByTable(dbName, tableName string) *Collection {
+	for _, c := range r.colls[dbName] {
+		if c.TableName == tableName {
+			return c.deepCopy()
+		}
+	}
+
+	return nil
+}
+
+// indexMetadataDelete removes metadata of the index that does not exist.
+//
+// It does not hold the lock.
+func (r *Registry) indexMetadataDelete(ctx context.Context, p *pgxpool.Pool, dbName, collectionName, indexName string) error {
+	c := r.collectionGet(dbName, collectionName)
+
+	c.Indexes = slices.DeleteFunc(c.Indexes, func(i IndexInfo) bool { return i.Name == indexName })
+
+	return r.collectionUpdate(ctx, p, dbName, c)
+}
+
+// defaultIndexRepair builds the default index of the collection that owns the given table
+// like [IndexesCreate], but also avoids names of existing PostgreSQL indexes that are not registered in metadata.
+//
+// If the index can't be built (for example, because of duplicate _id values) and unregister is true,
+// collection metadata is removed.
+//
+// It holds the lock only while reserving names and updating metadata.
+func (r *Registry) defaultIndexRepair(ctx context.Context, p *pgxpool.Pool, dbName, tableName string, unregister bool) error {
+	collectionName, toBuild, reserved, err := r.defaultIndexPrepare(ctx, p, dbName, tableName)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	defer func() {
+		r.rw.Lock()
+		defer r.rw.Unlock()
+
+		for _, name := range reserved {
+			delete(r.building, name)
+		}
+	}()
+
+	// indexes should be dropped even if the context is canceled
+	dropCtx := context.WithoutCancel(ctx)
+
+	if len(toBuild) == 0 {
+		return nil
+	}
+
+	for _, index := range toBuild {
+		if err = indexBuild(ctx, p, dbName, tableName, index, true, nil); err != nil {
+			break
+		}
+	}
+
+	if err != nil {
+		// failed CREATE INDEX CONCURRENTLY leaves an invalid index that should be dropped too
+		r.pgIndexesDrop(dropCtx, p, dbName, toBuild)
+	} else {
+		var toDrop []IndexInfo
+		toDrop, err = r.indexesCommit(ctx, p, dbName, collectionName, tableName, toBuild)
+		r.pgIndexesDrop(dropCtx, p, dbName, toDrop)
+	}
+
+	if err == nil {
+		return nil
+	}
+
+	if unregister {
+		r.rw.Lock()
+		defer r.rw.Unlock()
+
+		if c := r.collectionByTable(dbName, tableName); c != nil {
+			_ = r.collectionMetadataDelete(ctx, p, dbName, c.Name)
+		}
+	}
+
+	return lazyerrors.Error(err)
+}
+
+// defaultIndexPrepare returns the name of the collection that owns the given table
+// and its default index that should be built, if any.
+//
+// Names of existing PostgreSQL indexes and the returned index are reserved like for indexes that are being built;
+// returned reserved names should be released by the caller.
+//
+// It holds the lock.
+func (r *Registry) defaultIndexPrepare(ctx context.Context, p *pgxpool.Pool, dbName, tableName string) (string, []IndexInfo, []string, error) { //nolint:lll // for readability
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	c := r.collectionByTable(dbName, tableName)
+	if c == nil {
+		// the collection was dropped concurrently
+		return "", nil, nil, nil
+	}
+
+	rows, err := p.Query(ctx, `SELECT indexname FROM pg_indexes WHERE schemaname = $1`, dbName)
+	if err != nil {
+		return "", nil, nil, lazyerrors.Error(err)
+	}
+	defer rows.Close()
+
+	var reserved []string
+
+	for rows.Next() {
+		var indexName string
+		if err = rows.Scan(&indexName); err != nil {
+			return "", nil, nil, lazyerrors.Error(err)
+		}
+
+		// registered indexes are ignored anyway
+		name := dbName + "." + indexName
+		if _, ok := r.building[name]; !ok {
+			reserved = append(reserved, name)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return "", nil, nil, lazyerrors.Error(err)
+	}
+
+	for _, name := range reserved {
+		r.building[name] = struct{}{}
+	}
+
+	toBuild := r.indexesNew(dbName, c, []IndexInfo{defaultIndex()})
+
+	for _, index := range toBuild {
+		name := dbName + "." + index.PgIndex
+		r.building[name] = struct{}{}
+		reserved = append(reserved, name)
+	}
+
+	return c.Name, toBuild, reserved, nil
+}
+
+// recordIDsRepair adds record IDs column to the given table of the collection created by older versions
+// and marks the collection that owns that table as having record IDs.
+//
+// It holds the lock only while updating metadata.
+func (r *Registry) recordIDsRepair(ctx context.Context, p *pgxpool.Pool, dbName, tableName string) error {
+	if err := recordIDsAdd(ctx, p, dbName, tableName); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	c := r.collectionByTable(dbName, tableName)
+	if c == nil {
+		// the collection was dropped concurrently
+		return nil
+	}
+
+	c.RecordIDs = true
+
+	return r.collectionUpdate(ctx, p, dbName, c)
+}
+
 // quoteString returns a string that is safe to use in SQL queries.
 //
 // Deprecated: Warning! Avoid using this function unless there is no other way.
@@ -984,6 +2050,16 @@ func (r *Registry) Collect(ch chan<- prometheus.Metric) {
 		float64(len(r.colls)),
 	)
 
+	ch <- prometheus.MustNewConstMetric(
+		prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "version"),
+			"The current version of metadata; it is incremented on every change.",
+			nil, nil,
+		),
+		prometheus.CounterValue,
+		float64(r.cache.Version()),
+	)
+
 	for db, colls := range r.colls {
 		ch <- prometheus.MustNewConstMetric(
 			prometheus.NewDesc(
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"slices"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 
@@ -27,6 +28,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/FerretDB/FerretDB/internal/backends"
 	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
 	"github.com/FerretDB/FerretDB/internal/util/state"
 	"github.com/FerretDB/FerretDB/internal/util/testutil"
@@ -401,6 +403,7 @@ func TestRenameCollection(t *testing.T) {
 			Name:      newCollectionName,
 			TableName: oldCollection.TableName,
 			Indexes:   oldCollection.Indexes,
+			RecordIDs: oldCollection.RecordIDs,
 		}
 
 		actual, err := r.CollectionGet(ctx, dbName, newCollectionName)
@@ -488,7 +491,7 @@ func TestIndexesCreateDrop(t *testing.T) {
 		}},
 	}}
 
-	err := r.IndexesCreate(ctx, dbName, collectionName, toCreate)
+	err := r.IndexesCreate(ctx, dbName, collectionName, toCreate, nil)
 	require.NoError(t, err)
 
 	collection, err := r.CollectionGet(ctx, dbName, collectionName)
@@ -653,6 +656,259 @@ func TestIndexesCreateDrop(t *testing.T) {
 	})
 }
 
+func TestIndexesCreateFailed(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping in -short mode")
+	}
+
+	t.Parallel()
+
+	connInfo := conninfo.New()
+	ctx := conninfo.Ctx(testutil.Ctx(t), connInfo)
+
+	r, db, dbName := createDatabase(t, ctx)
+	collectionName := testutil.CollectionName(t)
+
+	created, err := r.CollectionCreate(ctx, dbName, collectionName)
+	require.NoError(t, err)
+	require.True(t, created)
+
+	collection, err := r.CollectionGet(ctx, dbName, collectionName)
+	require.NoError(t, err)
+
+	q := fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES ($1), ($1)`,
+		pgx.Identifier{dbName, collection.TableName}.Sanitize(),
+		DefaultColumn,
+	)
+	_, err = db.Exec(ctx, q, `{"foo": 1}`)
+	require.NoError(t, err)
+
+	err = r.IndexesCreate(ctx, dbName, collectionName, []IndexInfo{{
+		Name:   "index_unique",
+		Key:    []IndexKeyPair{{Field: "foo"}},
+		Unique: true,
+	}}, nil)
+	require.Error(t, err)
+
+	// invalid index left by CREATE INDEX CONCURRENTLY should be dropped
+	q = "SELECT count(indexdef) FROM pg_indexes WHERE schemaname = $1 AND tablename = $2"
+	row := db.QueryRow(ctx, q, dbName, collection.TableName)
+
+	var count int
+	require.NoError(t, row.Scan(&count))
+	require.Equal(t, 1, count) // only default index should be left
+
+	collection, err = r.CollectionGet(ctx, dbName, collectionName)
+	require.NoError(t, err)
+	require.Len(t, collection.Indexes, 1)
+	assert.Equal(t, "_id_", collection.Indexes[0].Name)
+}
+
+func TestIndexesCommitRecreated(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping in -short mode")
+	}
+
+	t.Parallel()
+
+	connInfo := conninfo.New()
+	ctx := conninfo.Ctx(testutil.Ctx(t), connInfo)
+
+	r, db, dbName := createDatabase(t, ctx)
+	collectionName := testutil.CollectionName(t)
+
+	created, err := r.CollectionCreate(ctx, dbName, collectionName)
+	require.NoError(t, err)
+	require.True(t, created)
+
+	collection, err := r.CollectionGet(ctx, dbName, collectionName)
+	require.NoError(t, err)
+
+	oldTableName := collection.TableName
+
+	// the collection is dropped and created again while indexes are built for the old table
+	dropped, err := r.CollectionDrop(ctx, dbName, collectionName)
+	require.NoError(t, err)
+	require.True(t, dropped)
+
+	created, err = r.CollectionCreate(ctx, dbName, collectionName)
+	require.NoError(t, err)
+	require.True(t, created)
+
+	built := []IndexInfo{{
+		Name:    "index_foo",
+		PgIndex: "index_foo_idx",
+		Key:     []IndexKeyPair{{Field: "foo"}},
+	}}
+
+	toDrop, err := r.indexesCommit(ctx, db, dbName, collectionName, oldTableName, built)
+	require.Error(t, err)
+	assert.Empty(t, toDrop)
+
+	collection, err = r.CollectionGet(ctx, dbName, collectionName)
+	require.NoError(t, err)
+	require.Len(t, collection.Indexes, 1)
+	assert.Equal(t, "_id_", collection.Indexes[0].Name)
+}
+
+func TestIndexesCreateCachedReads(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping in -short mode")
+	}
+
+	t.Parallel()
+
+	connInfo := conninfo.New()
+	ctx := conninfo.Ctx(testutil.Ctx(t), connInfo)
+
+	r, db, dbName := createDatabase(t, ctx)
+	collectionName := testutil.CollectionName(t)
+
+	created, err := r.CollectionCreate(ctx, dbName, collectionName)
+	require.NoError(t, err)
+	require.True(t, created)
+
+	collection, err := r.CollectionGet(ctx, dbName, collectionName)
+	require.NoError(t, err)
+
+	// indexes of non-empty tables are built concurrently and committed separately
+	q := fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES ($1)`,
+		pgx.Identifier{dbName, collection.TableName}.Sanitize(),
+		DefaultColumn,
+	)
+	_, err = db.Exec(ctx, q, `{"_id": 1}`)
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+
+				c, err := r.CollectionGetCached(ctx, dbName, collectionName)
+				if !assert.NoError(t, err) {
+					return
+				}
+
+				for _, index := range c.Indexes {
+					_ = index.Name
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < 10; i++ {
+		err = r.IndexesCreate(ctx, dbName, collectionName, []IndexInfo{{
+			Name: fmt.Sprintf("index_%d", i),
+			Key:  []IndexKeyPair{{Field: fmt.Sprintf("f%d", i)}},
+		}}, nil)
+		require.NoError(t, err)
+	}
+
+	close(done)
+	wg.Wait()
+
+	collection, err = r.CollectionGet(ctx, dbName, collectionName)
+	require.NoError(t, err)
+	assert.Len(t, collection.Indexes, 11)
+}
+
+func TestRecordIDsRepair(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping in -short mode")
+	}
+
+	t.Parallel()
+
+	connInfo := conninfo.New()
+	ctx := conninfo.Ctx(testutil.Ctx(t), connInfo)
+
+	r, db, dbName := createDatabase(t, ctx)
+	collectionName := testutil.CollectionName(t)
+
+	created, err := r.CollectionCreate(ctx, dbName, collectionName)
+	require.NoError(t, err)
+	require.True(t, created)
+
+	collection, err := r.CollectionGet(ctx, dbName, collectionName)
+	require.NoError(t, err)
+	require.True(t, collection.RecordIDs)
+
+	// make the collection look like one created by an older version
+	q := fmt.Sprintf(`ALTER TABLE %s DROP COLUMN %s`, pgx.Identifier{dbName, collection.TableName}.Sanitize(), RecordIDColumn)
+	_, err = db.Exec(ctx, q)
+	require.NoError(t, err)
+
+	q = fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES ('{"_id": 1}'), ('{"_id": 2}')`,
+		pgx.Identifier{dbName, collection.TableName}.Sanitize(), DefaultColumn,
+	)
+	_, err = db.Exec(ctx, q)
+	require.NoError(t, err)
+
+	collection.RecordIDs = false
+
+	r.rw.Lock()
+	err = r.collectionUpdate(ctx, db, dbName, collection)
+	r.rw.Unlock()
+	require.NoError(t, err)
+
+	// reload metadata to check that tables are not migrated implicitly
+	r.rw.Lock()
+	err = r.initCollections(ctx, dbName, db)
+	r.rw.Unlock()
+	require.NoError(t, err)
+
+	collection, err = r.CollectionGet(ctx, dbName, collectionName)
+	require.NoError(t, err)
+	require.False(t, collection.RecordIDs)
+
+	expected := []backends.MetadataProblem{{
+		Type:       backends.MetadataProblemMissingRecordIDs,
+		Collection: collectionName,
+		Table:      collection.TableName,
+		Action:     "add record IDs column",
+	}}
+
+	problems, err := r.DatabaseValidate(ctx, dbName, false)
+	require.NoError(t, err)
+	require.Equal(t, expected, problems)
+
+	expected[0].Repaired = true
+
+	problems, err = r.DatabaseValidate(ctx, dbName, true)
+	require.NoError(t, err)
+	require.Equal(t, expected, problems)
+
+	collection, err = r.CollectionGet(ctx, dbName, collectionName)
+	require.NoError(t, err)
+	assert.True(t, collection.RecordIDs)
+
+	q = fmt.Sprintf(
+		`SELECT %s FROM %s ORDER BY %s`,
+		RecordIDColumn, pgx.Identifier{dbName, collection.TableName}.Sanitize(), RecordIDColumn,
+	)
+	rows, err := db.Query(ctx, q)
+	require.NoError(t, err)
+
+	recordIDs, err := pgx.CollectRows(rows, pgx.RowTo[int64])
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2}, recordIDs)
+}
+
 func TestLongIndexNames(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping in -short mode")
@@ -708,7 +964,7 @@ func TestLongIndexNames(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
 
-			err := r.IndexesCreate(ctx, dbName, tc.collectionName, batch1)
+			err := r.IndexesCreate(ctx, dbName, tc.collectionName, batch1, nil)
 			require.NoError(t, err)
 
 			collection, err := r.CollectionGet(ctx, dbName, tc.collectionName)
@@ -728,7 +984,7 @@ func TestLongIndexNames(t *testing.T) {
 				}
 			}
 
-			err = r.IndexesCreate(ctx, dbName, tc.collectionName, batch2)
+			err = r.IndexesCreate(ctx, dbName, tc.collectionName, batch2, nil)
 			require.NoError(t, err)
 
 			// Force DBs and collection initialization to check that indexes metadata is stored correctly in the database.
@@ -756,3 +1012,175 @@ func TestLongIndexNames(t *testing.T) {
 		})
 	}
 }
+
+func TestDatabaseValidate(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping in -short mode")
+	}
+
+	t.Parallel()
+
+	connInfo := conninfo.New()
+	ctx := conninfo.Ctx(testutil.Ctx(t), connInfo)
+
+	r, db, dbName := createDatabase(t, ctx)
+
+	problems, err := r.DatabaseValidate(ctx, dbName, true)
+	require.NoError(t, err)
+	require.Empty(t, problems)
+
+	_, err = r.CollectionCreate(ctx, dbName, "missing")
+	require.NoError(t, err)
+
+	err = r.IndexesCreate(ctx, dbName, "indexes", []IndexInfo{{
+		Name: "index_missing",
+		Key:  []IndexKeyPair{{Field: "foo"}},
+	}}, nil)
+	require.NoError(t, err)
+
+	missing, err := r.CollectionGet(ctx, dbName, "missing")
+	require.NoError(t, err)
+
+	indexes, err := r.CollectionGet(ctx, dbName, "indexes")
+	require.NoError(t, err)
+	require.Len(t, indexes.Indexes, 2)
+
+	// make metadata disagree with tables and indexes
+	for _, q := range []string{
+		fmt.Sprintf(`DROP TABLE %s`, pgx.Identifier{dbName, missing.TableName}.Sanitize()),
+		fmt.Sprintf(`DROP INDEX %s`, pgx.Identifier{dbName, indexes.Indexes[0].PgIndex}.Sanitize()),
+		fmt.Sprintf(`DROP INDEX %s`, pgx.Identifier{dbName, indexes.Indexes[1].PgIndex}.Sanitize()),
+		fmt.Sprintf(
+			`CREATE INDEX orphan_index ON %s ((%s->'bar'))`,
+			pgx.Identifier{dbName, indexes.TableName}.Sanitize(), DefaultColumn,
+		),
+		fmt.Sprintf(`CREATE TABLE %s (%s jsonb)`, pgx.Identifier{dbName, "orphan"}.Sanitize(), DefaultColumn),
+		fmt.Sprintf(
+			`CREATE UNIQUE INDEX orphan__id__67399184_idx ON %s ((%s->'_id'))`,
+			pgx.Identifier{dbName, "orphan"}.Sanitize(), DefaultColumn,
+		),
+		fmt.Sprintf(
+			`CREATE INDEX orphan_manual ON %s ((%s->'foo'))`,
+			pgx.Identifier{dbName, "orphan"}.Sanitize(), DefaultColumn,
+		),
+	} {
+		_, err = db.Exec(ctx, q)
+		require.NoError(t, err)
+	}
+
+	orphanIndex := backends.MetadataProblem{
+		Type:       backends.MetadataProblemOrphanIndex,
+		Collection: "indexes",
+		Table:      indexes.TableName,
+		Index:      "orphan_index",
+	}
+
+	orphanManual := backends.MetadataProblem{
+		Type:       backends.MetadataProblemOrphanIndex,
+		Collection: "orphan",
+		Table:      "orphan",
+		Index:      "orphan_manual",
+	}
+
+	expected := []backends.MetadataProblem{{
+		Type:       backends.MetadataProblemMissingTable,
+		Collection: "missing",
+		Table:      missing.TableName,
+		Action:     "remove collection metadata",
+	}, {
+		Type:   backends.MetadataProblemOrphanTable,
+		Table:  "orphan",
+		Action: "register collection orphan",
+	}, {
+		Type:       backends.MetadataProblemMissingIndex,
+		Collection: "indexes",
+		Table:      indexes.TableName,
+		Index:      "_id_",
+		Action:     "rebuild index",
+	}, {
+		Type:       backends.MetadataProblemMissingIndex,
+		Collection: "indexes",
+		Table:      indexes.TableName,
+		Index:      "index_missing",
+		Action:     "remove index metadata",
+	}, orphanIndex}
+
+	problems, err = r.DatabaseValidate(ctx, dbName, false)
+	require.NoError(t, err)
+	require.Equal(t, expected, problems)
+
+	for i := range expected[:4] {
+		expected[i].Repaired = true
+	}
+	expected[1].Collection = "orphan"
+
+	// record IDs column is added to the registered table
+	expected = slices.Insert(expected, 2, backends.MetadataProblem{
+		Type:       backends.MetadataProblemMissingRecordIDs,
+		Collection: "orphan",
+		Table:      "orphan",
+		Action:     "add record IDs column",
+		Repaired:   true,
+	})
+
+	// indexes of the registered table are checked too, but orphan indexes are never dropped
+	expected = append(expected, orphanManual)
+
+	problems, err = r.DatabaseValidate(ctx, dbName, true)
+	require.NoError(t, err)
+	require.Equal(t, expected, problems)
+
+	problems, err = r.DatabaseValidate(ctx, dbName, true)
+	require.NoError(t, err)
+	require.Equal(t, []backends.MetadataProblem{orphanIndex, orphanManual}, problems)
+
+	// reload metadata to check that repairs were saved
+	r.rw.Lock()
+	err = r.initCollections(ctx, dbName, db)
+	r.rw.Unlock()
+	require.NoError(t, err)
+
+	list, err := r.CollectionList(ctx, dbName)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+
+	for i, name := range []string{"indexes", "orphan"} {
+		require.Equal(t, name, list[i].Name)
+		require.Len(t, list[i].Indexes, 1)
+		assert.Equal(t, "_id_", list[i].Indexes[0].Name)
+	}
+
+	// the existing default index was registered
+	assert.Equal(t, "orphan", list[1].TableName)
+	assert.Equal(t, "orphan__id__67399184_idx", list[1].Indexes[0].PgIndex)
+	assert.True(t, list[1].RecordIDs)
+
+	t.Run("RegisterFailed", func(t *testing.T) {
+		for _, q := range []string{
+			fmt.Sprintf(`CREATE TABLE %s (%s jsonb)`, pgx.Identifier{dbName, "dups"}.Sanitize(), DefaultColumn),
+			fmt.Sprintf(
+				`CREATE INDEX dups_manual ON %s ((%s->'foo'))`,
+				pgx.Identifier{dbName, "dups"}.Sanitize(), DefaultColumn,
+			),
+			fmt.Sprintf(
+				`INSERT INTO %s (%s) VALUES ('{"_id": 1}'), ('{"_id": 1}')`,
+				pgx.Identifier{dbName, "dups"}.Sanitize(), DefaultColumn,
+			),
+		} {
+			_, err = db.Exec(ctx, q)
+			require.NoError(t, err)
+		}
+
+		_, err = r.DatabaseValidate(ctx, dbName, true)
+		require.Error(t, err)
+
+		c, err := r.CollectionGet(ctx, dbName, "dups")
+		require.NoError(t, err)
+		require.Nil(t, c)
+
+		var count int
+		q := `SELECT count(*) FROM pg_indexes WHERE schemaname = $1 AND indexname = 'dups_manual'`
+		require.NoError(t, db.QueryRow(ctx, q, dbName).Scan(&count))
+		require.Equal(t, 1, count)
+	})
+}
@@ -19,9 +19,11 @@ import (
 	"fmt"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/jackc/pgx/v5"
 
+	"github.com/FerretDB/FerretDB/internal/backends"
 	"github.com/FerretDB/FerretDB/internal/backends/postgresql/metadata"
 	"github.com/FerretDB/FerretDB/internal/handlers/sjson"
 	"github.com/FerretDB/FerretDB/internal/types"
@@ -42,7 +44,9 @@ func prepareSelectClause(db, table string) string {
 }
 
 // prepareWhereClause adds WHERE clause with given filters to the query and returns the query and arguments.
-func prepareWhereClause(p *metadata.Placeholder, sqlFilters *types.Document) (string, []any, error) {
+//
+// Given collection indexes are used to add filters matching computed indexes; they could be nil.
+func prepareWhereClause(p *metadata.Placeholder, sqlFilters *types.Document, indexes metadata.Indexes) (string, []any, error) { //nolint:lll // for readability
 	var filters []string
 	var args []any
 
@@ -70,6 +74,12 @@ func prepareWhereClause(p *metadata.Placeholder, sqlFilters *types.Document) (st
 
 		path, err := types.NewPathFromString(rootKey)
 
+		// computed indexes cover dot notation too, so that is checked first
+		if f, a := filterComputed(p, indexes, rootKey, rootVal); len(f) > 0 {
+			filters = append(filters, f...)
+			args = append(args, a...)
+		}
+
 		var pe *types.PathError
 
 		switch {
@@ -182,19 +192,167 @@ func prepareWhereClause(p *metadata.Placeholder, sqlFilters *types.Document) (st
 }
 
 // prepareOrderByClause adds ORDER BY clause with given sort document and returns the query and arguments.
+//
+// The natural order uses record IDs.
 func prepareOrderByClause(p *metadata.Placeholder, key string, descending bool) (string, []any, error) {
+	sqlOrder := "ASC"
+
+	if descending {
+		sqlOrder = "DESC"
+	}
+
+	if key == backends.NaturalSortKey {
+		return fmt.Sprintf(" ORDER BY %s %s", metadata.RecordIDColumn, sqlOrder), nil, nil
+	}
+
+	if key == backends.DefaultIndexSortKey {
+		// the same expression as in the default index
+		return fmt.Sprintf(" ORDER BY %s %s", metadata.IDColumn, sqlOrder), nil, nil
+	}
+
 	// Skip sorting dot notation
 	if strings.ContainsRune(key, '.') {
 		return "", nil, nil
 	}
 
-	sqlOrder := "ASC"
+	return fmt.Sprintf(" ORDER BY %s->%s %s", metadata.DefaultColumn, p.Next(), sqlOrder), []any{key}, nil
+}
 
-	if descending {
-		sqlOrder = "DESC"
+// filterComputed returns SQL filters with arguments that select documents
+// where the value under k may be equal to v, using the same expressions as computed indexes
+// on that field. That allows PostgreSQL to choose those indexes.
+//
+// The filters are not exact, so they should be combined with other filters or applied by the caller.
+// Only strings are supported for now; v could be an operator document with `$eq`.
+// Case-insensitive regular expressions that match the whole ASCII string literally (like `^foo@example\.com$`)
+// are supported too; they use only computed keys with `$toLower` expression.
+func filterComputed(p *metadata.Placeholder, indexes metadata.Indexes, k string, v any) (filters []string, args []any) {
+	s, caseInsensitive, ok := computedValue(v)
+	if !ok {
+		return
 	}
 
-	return fmt.Sprintf(" ORDER BY %s->%s %s", metadata.DefaultColumn, p.Next(), sqlOrder), []any{key}, nil
+	// JSON array with a single element is contained in array of values returned by the column expression
+	arg := "[" + string(must.NotFail(sjson.MarshalSingleValue(s))) + "]"
+
+	for _, index := range indexes {
+		if !index.Computed() {
+			continue
+		}
+
+		for _, key := range index.Key {
+			if key.Field != k {
+				continue
+			}
+
+			switch key.Expression {
+			case "":
+				if caseInsensitive {
+					continue
+				}
+
+				filters = append(filters, fmt.Sprintf(`%s @> %s`, metadata.ComputedColumn(key), p.Next()))
+			case backends.IndexKeyExpressionToLower:
+				filters = append(filters, fmt.Sprintf(`%s @> lower(%s)::jsonb`, metadata.ComputedColumn(key), p.Next()))
+			default:
+				panic(fmt.Sprintf("unexpected index key expression %q", key.Expression))
+			}
+
+			args = append(args, arg)
+		}
+	}
+
+	return
+}
+
+// computedValue returns the string value that the field should contain to match the given filter value,
+// and true if it is compared case-insensitively.
+//
+// It returns false if the filter value is not supported by filterComputed.
+func computedValue(v any) (string, bool, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, false, true
+
+	case types.Regex:
+		return regexLiteral(v.Pattern, v.Options)
+
+	case *types.Document:
+		if eq, _ := v.Get("$eq"); eq != nil {
+			s, ok := eq.(string)
+			return s, false, ok
+		}
+
+		re, _ := v.Get("$regex")
+
+		switch re := re.(type) {
+		case string:
+			options, _ := v.Get("$options")
+			o, _ := options.(string)
+
+			return regexLiteral(re, o)
+
+		case types.Regex:
+			if v.Has("$options") {
+				return "", false, false
+			}
+
+			return regexLiteral(re.Pattern, re.Options)
+		}
+	}
+
+	return "", false, false
+}
+
+// regexLiteral returns the ASCII string that is matched as a whole by the given case-insensitive
+// regular expression without any special characters except anchors (like `^foo@example\.com$`).
+//
+// It returns false for other regular expressions.
+func regexLiteral(pattern, options string) (string, bool, bool) {
+	if options != "i" {
+		return "", false, false
+	}
+
+	body, ok := strings.CutPrefix(pattern, "^")
+	if !ok {
+		return "", false, false
+	}
+
+	if body, ok = strings.CutSuffix(body, "$"); !ok {
+		return "", false, false
+	}
+
+	var res strings.Builder
+
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+
+		switch {
+		case c >= utf8.RuneSelf:
+			return "", false, false
+
+		case c == '\\':
+			// only escaped punctuation is literal; sequences like `\d` and `\b` are not
+			if i++; i == len(body) || !isASCIIPunct(body[i]) {
+				return "", false, false
+			}
+
+			c = body[i]
+
+		case strings.IndexByte(`.[]{}()*+?|^$`, c) >= 0:
+			return "", false, false
+		}
+
+		res.WriteByte(c)
+	}
+
+	return res.String(), true, true
+}
+
+// isASCIIPunct returns true if c is an ASCII punctuation character.
+func isASCIIPunct(c byte) bool {
+	return c > ' ' && c < 0x7f &&
+		!('0' <= c && c <= '9') && !('a' <= c && c <= 'z') && !('A' <= c && c <= 'Z')
 }
 
 // filterEqual returns the proper SQL filter with arguments that filters documents
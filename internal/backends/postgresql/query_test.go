@@ -22,6 +22,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/FerretDB/FerretDB/internal/backends"
 	"github.com/FerretDB/FerretDB/internal/backends/postgresql/metadata"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/must"
@@ -36,8 +37,25 @@ func TestPrepareWhereClause(t *testing.T) {
 	whereGt := " WHERE _jsonb->$1 > $2"
 	whereNotEq := ` WHERE NOT ( _jsonb ? $1 AND _jsonb->$1 @> $2 AND _jsonb->'$s'->'p'->$1->'t' = `
 
+	// computed indexes used in tests
+	lowerEmail := metadata.Indexes{{
+		Name: "email_lower",
+		Key:  []metadata.IndexKeyPair{{Field: "email", Expression: backends.IndexKeyExpressionToLower}},
+	}}
+	itemsSKU := metadata.Indexes{{
+		Name: "items_sku",
+		Key: []metadata.IndexKeyPair{
+			{Field: "items.sku"},
+			{Field: "items.name", Expression: backends.IndexKeyExpressionToLower},
+		},
+	}}
+
+	whereLowerEmail := ` WHERE (lower(jsonb_path_query_array(_jsonb, 'lax $."email"[*]'::jsonpath)::text)::jsonb) @> lower($1)::jsonb`
+	whereItemsSKU := ` WHERE (jsonb_path_query_array(_jsonb, 'lax $."items"."sku"[*]'::jsonpath)) @> $1`
+
 	for name, tc := range map[string]struct {
 		filter   *types.Document
+		indexes  metadata.Indexes
 		expected string
 		skip     string
 		args     []any // if empty, check is disabled
@@ -228,6 +246,73 @@ func TestPrepareWhereClause(t *testing.T) {
 			expected: whereNotEq + `'"objectId"' )`,
 		},
 
+		"ComputedLower": {
+			filter:   must.NotFail(types.NewDocument("email", "Foo@Example.com")),
+			indexes:  lowerEmail,
+			expected: whereLowerEmail + ` AND _jsonb->$2 @> $3`,
+			args:     []any{`["Foo@Example.com"]`, `email`, `"Foo@Example.com"`},
+		},
+		"ComputedLowerEq": {
+			filter: must.NotFail(types.NewDocument(
+				"email", must.NotFail(types.NewDocument("$eq", "Foo@Example.com")),
+			)),
+			indexes:  lowerEmail,
+			expected: whereLowerEmail + ` AND _jsonb->$2 @> $3`,
+			args:     []any{`["Foo@Example.com"]`, `email`, `"Foo@Example.com"`},
+		},
+		"ComputedLowerRegex": {
+			filter: must.NotFail(types.NewDocument(
+				"email", must.NotFail(types.NewDocument("$regex", `^foo@example\.com$`, "$options", "i")),
+			)),
+			indexes:  lowerEmail,
+			expected: whereLowerEmail,
+			args:     []any{`["foo@example.com"]`},
+		},
+		"ComputedLowerRegexValue": {
+			filter:   must.NotFail(types.NewDocument("email", types.Regex{Pattern: `^Foo@Example\.com$`, Options: "i"})),
+			indexes:  lowerEmail,
+			expected: whereLowerEmail,
+			args:     []any{`["Foo@Example.com"]`},
+		},
+		"ComputedLowerRegexCaseSensitive": {
+			filter: must.NotFail(types.NewDocument(
+				"email", must.NotFail(types.NewDocument("$regex", `^foo@example\.com$`)),
+			)),
+			indexes: lowerEmail,
+		},
+		"ComputedLowerRegexNotAnchored": {
+			filter:  must.NotFail(types.NewDocument("email", types.Regex{Pattern: `foo@example\.com`, Options: "i"})),
+			indexes: lowerEmail,
+		},
+		"ComputedLowerRegexPattern": {
+			filter:  must.NotFail(types.NewDocument("email", types.Regex{Pattern: `^foo.*$`, Options: "i"})),
+			indexes: lowerEmail,
+		},
+		"ComputedLowerRegexClass": {
+			filter:  must.NotFail(types.NewDocument("email", types.Regex{Pattern: `^foo\d$`, Options: "i"})),
+			indexes: lowerEmail,
+		},
+		"ComputedRegexNotLower": {
+			filter:  must.NotFail(types.NewDocument("items.sku", types.Regex{Pattern: `^foo$`, Options: "i"})),
+			indexes: itemsSKU,
+		},
+		"ComputedLowerInt": {
+			filter:   must.NotFail(types.NewDocument("email", int32(42))),
+			indexes:  lowerEmail,
+			expected: whereContain,
+			args:     []any{`email`, int32(42)},
+		},
+		"ComputedDotNotation": {
+			filter:   must.NotFail(types.NewDocument("items.sku", "foo")),
+			indexes:  itemsSKU,
+			expected: whereItemsSKU,
+			args:     []any{`["foo"]`},
+		},
+		"ComputedOtherField": {
+			filter:  must.NotFail(types.NewDocument("items.size", "foo")),
+			indexes: itemsSKU,
+		},
+
 		"Comment": {
 			filter: must.NotFail(types.NewDocument("$comment", "I'm comment")),
 		},
@@ -240,7 +325,7 @@ func TestPrepareWhereClause(t *testing.T) {
 				t.Skip(tc.skip)
 			}
 
-			actual, args, err := prepareWhereClause(new(metadata.Placeholder), tc.filter)
+			actual, args, err := prepareWhereClause(new(metadata.Placeholder), tc.filter, tc.indexes)
 			require.NoError(t, err)
 
 			assert.Equal(t, tc.expected, actual)
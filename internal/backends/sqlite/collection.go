@@ -58,7 +58,7 @@ func (c *collection) Query(ctx context.Context, params *backends.QueryParams) (*
 		}, nil
 	}
 
-	meta := c.r.CollectionGet(ctx, c.dbName, c.name)
+	meta := c.r.CollectionGetCached(ctx, c.dbName, c.name)
 	if meta == nil {
 		return &backends.QueryResult{
 			Iter: newQueryIterator(ctx, nil),
@@ -87,6 +87,8 @@ func (c *collection) Query(ctx context.Context, params *backends.QueryParams) (*
 
 	q := fmt.Sprintf(`SELECT %s FROM %q`+whereClause, metadata.DefaultColumn, meta.TableName)
 
+	q += prepareOrderByClause(params.Sort)
+
 	if params.Limit != 0 {
 		q += ` LIMIT ?`
 		args = append(args, params.Limit)
@@ -109,7 +111,7 @@ func (c *collection) InsertAll(ctx context.Context, params *backends.InsertAllPa
 	}
 
 	db := c.r.DatabaseGetExisting(ctx, c.dbName)
-	meta := c.r.CollectionGet(ctx, c.dbName, c.name)
+	meta := c.r.CollectionGetCached(ctx, c.dbName, c.name)
 
 	err := db.InTransaction(ctx, func(tx *fsql.Tx) error {
 		for _, doc := range params.Docs {
@@ -151,7 +153,7 @@ func (c *collection) UpdateAll(ctx context.Context, params *backends.UpdateAllPa
 		return &res, nil
 	}
 
-	meta := c.r.CollectionGet(ctx, c.dbName, c.name)
+	meta := c.r.CollectionGetCached(ctx, c.dbName, c.name)
 	if meta == nil {
 		return &res, nil
 	}
@@ -199,7 +201,7 @@ func (c *collection) DeleteAll(ctx context.Context, params *backends.DeleteAllPa
 		return &backends.DeleteAllResult{Deleted: 0}, nil
 	}
 
-	meta := c.r.CollectionGet(ctx, c.dbName, c.name)
+	meta := c.r.CollectionGetCached(ctx, c.dbName, c.name)
 	if meta == nil {
 		return &backends.DeleteAllResult{Deleted: 0}, nil
 	}
@@ -241,7 +243,7 @@ func (c *collection) Explain(ctx context.Context, params *backends.ExplainParams
 		}, nil
 	}
 
-	meta := c.r.CollectionGet(ctx, c.dbName, c.name)
+	meta := c.r.CollectionGetCached(ctx, c.dbName, c.name)
 	if meta == nil {
 		return &backends.ExplainResult{
 			QueryPlanner: must.NotFail(types.NewDocument()),
@@ -272,6 +274,9 @@ func (c *collection) Explain(ctx context.Context, params *backends.ExplainParams
 
 	q := fmt.Sprintf(`EXPLAIN QUERY PLAN SELECT %s FROM %q`+whereClause, metadata.DefaultColumn, meta.TableName)
 
+	orderByClause := prepareOrderByClause(params.Sort)
+	q += orderByClause
+
 	var limitPushdown bool
 
 	if params.Limit != 0 {
@@ -312,6 +317,7 @@ func (c *collection) Explain(ctx context.Context, params *backends.ExplainParams
 	return &backends.ExplainResult{
 		QueryPlanner:  must.NotFail(types.NewDocument("Plan", queryPlan)),
 		QueryPushdown: queryPushdown,
+		SortPushdown:  orderByClause != "",
 		LimitPushdown: limitPushdown,
 	}, nil
 }
@@ -456,15 +462,17 @@ func (c *collection) ListIndexes(ctx context.Context, params *backends.ListIndex
 
 	for i, index := range coll.Settings.Indexes {
 		res.Indexes[i] = backends.IndexInfo{
-			Name:   index.Name,
-			Unique: index.Unique,
-			Key:    make([]backends.IndexKeyPair, len(index.Key)),
+			Name:               index.Name,
+			Unique:             index.Unique,
+			Key:                make([]backends.IndexKeyPair, len(index.Key)),
+			ExpireAfterSeconds: index.ExpireAfterSeconds,
 		}
 
 		for j, key := range index.Key {
 			res.Indexes[i].Key[j] = backends.IndexKeyPair{
 				Field:      key.Field,
 				Descending: key.Descending,
+				Expression: key.Expression,
 			}
 		}
 	}
@@ -481,15 +489,17 @@ func (c *collection) CreateIndexes(ctx context.Context, params *backends.CreateI
 	indexes := make([]metadata.IndexInfo, len(params.Indexes))
 	for i, index := range params.Indexes {
 		indexes[i] = metadata.IndexInfo{
-			Name:   index.Name,
-			Key:    make([]metadata.IndexKeyPair, len(index.Key)),
-			Unique: index.Unique,
+			Name:               index.Name,
+			Key:                make([]metadata.IndexKeyPair, len(index.Key)),
+			Unique:             index.Unique,
+			ExpireAfterSeconds: index.ExpireAfterSeconds,
 		}
 
 		for j, key := range index.Key {
 			indexes[i].Key[j] = metadata.IndexKeyPair{
 				Field:      key.Field,
 				Descending: key.Descending,
+				Expression: key.Expression,
 			}
 		}
 	}
@@ -512,6 +522,35 @@ func (c *collection) DropIndexes(ctx context.Context, params *backends.DropIndex
 	return new(backends.DropIndexesResult), nil
 }
 
+// prepareOrderByClause returns ORDER BY clause for the given sort field.
+//
+// Only the natural order and the order of the default index are supported.
+// For the natural order, a new row gets rowid larger than rowids of all existing rows,
+// and rowid does not change on update.
+func prepareOrderByClause(sort *backends.SortField) string {
+	if sort == nil {
+		return ""
+	}
+
+	var q string
+
+	switch sort.Key {
+	case backends.NaturalSortKey:
+		q = ` ORDER BY rowid`
+	case backends.DefaultIndexSortKey:
+		// the same expression as in the default index
+		q = ` ORDER BY ` + metadata.IDColumn
+	default:
+		return ""
+	}
+
+	if sort.Descending {
+		q += ` DESC`
+	}
+
+	return q
+}
+
 // check interfaces
 var (
 	_ backends.Collection = (*collection)(nil)
@@ -53,7 +53,10 @@ func (db *database) ListCollections(ctx context.Context, params *backends.ListCo
 	res := make([]backends.CollectionInfo, len(list))
 	for i, c := range list {
 		res[i] = backends.CollectionInfo{
-			Name: c.Name,
+			Name:      c.Name,
+			UUID:      c.Settings.UUID,
+			Temp:      c.Settings.Temp,
+			TempOwner: c.Settings.TempOwner,
 		}
 	}
 
@@ -64,7 +67,15 @@ func (db *database) ListCollections(ctx context.Context, params *backends.ListCo
 
 // CreateCollection implements backends.Database interface.
 func (db *database) CreateCollection(ctx context.Context, params *backends.CreateCollectionParams) error {
-	created, err := db.r.CollectionCreate(ctx, db.name, params.Name)
+	var created bool
+	var err error
+
+	if params.Temp {
+		created, err = db.r.CollectionCreateTemp(ctx, db.name, params.Name, params.TempOwner)
+	} else {
+		created, err = db.r.CollectionCreate(ctx, db.name, params.Name)
+	}
+
 	if err != nil {
 		return lazyerrors.Error(err)
 	}
@@ -78,7 +89,15 @@ func (db *database) CreateCollection(ctx context.Context, params *backends.Creat
 
 // DropCollection implements backends.Database interface.
 func (db *database) DropCollection(ctx context.Context, params *backends.DropCollectionParams) error {
-	dropped, err := db.r.CollectionDrop(ctx, db.name, params.Name)
+	var dropped bool
+	var err error
+
+	if params.UUID != "" {
+		dropped, err = db.r.CollectionDropUUID(ctx, db.name, params.Name, params.UUID)
+	} else {
+		dropped, err = db.r.CollectionDrop(ctx, db.name, params.Name)
+	}
+
 	if err != nil {
 		return lazyerrors.Error(err)
 	}
@@ -99,14 +118,19 @@ func (db *database) RenameCollection(ctx context.Context, params *backends.Renam
 		)
 	}
 
-	if c := db.r.CollectionGet(ctx, db.name, params.NewName); c != nil {
+	if c := db.r.CollectionGet(ctx, db.name, params.NewName); c != nil && !params.DropTarget {
 		return backends.NewError(
 			backends.ErrorCodeCollectionAlreadyExists,
 			lazyerrors.Errorf("new database %q and collection %q already exists", db.name, params.NewName),
 		)
 	}
 
-	renamed, err := db.r.CollectionRename(ctx, db.name, params.OldName, params.NewName)
+	rename := db.r.CollectionRename
+	if params.DropTarget {
+		rename = db.r.CollectionReplace
+	}
+
+	renamed, err := rename(ctx, db.name, params.OldName, params.NewName)
 	if err != nil {
 		return lazyerrors.Error(err)
 	}
@@ -160,6 +184,24 @@ func (db *database) Stats(ctx context.Context, params *backends.DatabaseStatsPar
 	}, nil
 }
 
+// ValidateMetadata implements backends.Database interface.
+//
+//nolint:lll // for readability
+func (db *database) ValidateMetadata(ctx context.Context, params *backends.ValidateMetadataParams) (*backends.ValidateMetadataResult, error) {
+	if db.r.DatabaseGetExisting(ctx, db.name) == nil {
+		return nil, backends.NewError(backends.ErrorCodeDatabaseDoesNotExist, lazyerrors.Errorf("no database %s", db.name))
+	}
+
+	problems, err := db.r.DatabaseValidate(ctx, db.name, params.Repair)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return &backends.ValidateMetadataResult{
+		Problems: problems,
+	}, nil
+}
+
 // check interfaces
 var (
 	_ backends.Database = (*database)(nil)
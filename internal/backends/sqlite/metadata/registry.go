@@ -16,6 +16,8 @@ package metadata
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"hash/fnv"
 	"slices"
@@ -23,11 +25,13 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/google/uuid"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 	"golang.org/x/exp/maps"
 
 	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/backends/metadatacache"
 	"github.com/FerretDB/FerretDB/internal/backends/sqlite/metadata/pool"
 	"github.com/FerretDB/FerretDB/internal/util/fsql"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
@@ -65,6 +69,9 @@ type Registry struct {
 	// TODO https://github.com/FerretDB/FerretDB/issues/2755
 	rw    sync.RWMutex
 	colls map[string]map[string]*Collection // database name -> collection name -> collection
+
+	// cache is a snapshot of colls; it is invalidated on every change of colls.
+	cache metadatacache.Cache[Collection]
 }
 
 // NewRegistry creates a registry for SQLite databases in the directory specified by SQLite URI.
@@ -121,6 +128,7 @@ func (r *Registry) initCollections(ctx context.Context, dbName string, db *fsql.
 	}
 
 	r.colls[dbName] = colls
+	r.cache.Invalidate()
 
 	return nil
 }
@@ -201,6 +209,7 @@ func (r *Registry) databaseDrop(ctx context.Context, dbName string) bool {
 	defer observability.FuncCall(ctx)()
 
 	delete(r.colls, dbName)
+	r.cache.Invalidate()
 
 	return r.p.Drop(ctx, dbName)
 }
@@ -237,10 +246,28 @@ func (r *Registry) CollectionList(ctx context.Context, dbName string) ([]*Collec
 func (r *Registry) CollectionCreate(ctx context.Context, dbName, collectionName string) (bool, error) {
 	defer observability.FuncCall(ctx)()
 
+	// fast path for existing collections, without taking the write lock
+	if r.cached()[dbName][collectionName] != nil {
+		return false, nil
+	}
+
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	return r.collectionCreate(ctx, dbName, collectionName, "")
+}
+
+// CollectionCreateTemp creates a collection like CollectionCreate,
+// but marks it as temporary in the metadata, owned by the given FerretDB instance.
+func (r *Registry) CollectionCreateTemp(ctx context.Context, dbName, collectionName, owner string) (bool, error) {
+	defer observability.FuncCall(ctx)()
+
+	must.NotBeZero(owner)
+
 	r.rw.Lock()
 	defer r.rw.Unlock()
 
-	return r.collectionCreate(ctx, dbName, collectionName)
+	return r.collectionCreate(ctx, dbName, collectionName, owner)
 }
 
 // collectionCreate creates a collection in the database.
@@ -249,8 +276,10 @@ func (r *Registry) CollectionCreate(ctx context.Context, dbName, collectionName
 // Returned boolean value indicates whether the collection was created.
 // If collection already exists, (false, nil) is returned.
 //
+// If tempOwner is not empty, the collection is marked as temporary.
+//
 // It does not hold the lock.
-func (r *Registry) collectionCreate(ctx context.Context, dbName, collectionName string) (bool, error) {
+func (r *Registry) collectionCreate(ctx context.Context, dbName, collectionName, tempOwner string) (bool, error) {
 	defer observability.FuncCall(ctx)()
 
 	db, err := r.databaseGetOrCreate(ctx, dbName)
@@ -289,8 +318,14 @@ func (r *Registry) collectionCreate(ctx context.Context, dbName, collectionName
 		return false, lazyerrors.Error(err)
 	}
 
+	settings := Settings{
+		UUID:      uuid.NewString(),
+		Temp:      tempOwner != "",
+		TempOwner: tempOwner,
+	}
+
 	q = fmt.Sprintf("INSERT INTO %q (name, table_name, settings) VALUES (?, ?, ?)", metadataTableName)
-	if _, err = db.ExecContext(ctx, q, collectionName, tableName, "{}"); err != nil {
+	if _, err = db.ExecContext(ctx, q, collectionName, tableName, settings); err != nil {
 		_, _ = db.ExecContext(ctx, fmt.Sprintf("DROP TABLE %q", tableName))
 		return false, lazyerrors.Error(err)
 	}
@@ -301,7 +336,9 @@ func (r *Registry) collectionCreate(ctx context.Context, dbName, collectionName
 	r.colls[dbName][collectionName] = &Collection{
 		Name:      collectionName,
 		TableName: tableName,
+		Settings:  settings,
 	}
+	r.cache.Invalidate()
 
 	err = r.indexesCreate(ctx, dbName, collectionName, []IndexInfo{{
 		Name:   backends.DefaultIndexName,
@@ -329,6 +366,21 @@ func (r *Registry) CollectionGet(ctx context.Context, dbName, collectionName str
 	return r.collectionGet(dbName, collectionName)
 }
 
+// cached returns the current metadata snapshot shared by all connections.
+func (r *Registry) cached() metadatacache.Snapshot[Collection] {
+	return r.cache.Get(&r.rw, func() map[string]map[string]*Collection { return r.colls })
+}
+
+// CollectionGetCached returns collection metadata from the cache shared by all connections.
+// It is faster than CollectionGet, but the returned value must not be modified by a caller.
+//
+// If database or collection does not exist, nil is returned.
+func (r *Registry) CollectionGetCached(ctx context.Context, dbName, collectionName string) *Collection {
+	defer observability.FuncCall(ctx)()
+
+	return r.cached()[dbName][collectionName]
+}
+
 // collectionGet returns a copy of collection metadata.
 // It can be safely modified by a caller.
 //
@@ -357,6 +409,23 @@ func (r *Registry) CollectionDrop(ctx context.Context, dbName, collectionName st
 	return r.collectionDrop(ctx, dbName, collectionName)
 }
 
+// CollectionDropUUID drops a collection like CollectionDrop,
+// but only if it has the given UUID.
+//
+// If the collection has a different UUID, (false, nil) is returned.
+func (r *Registry) CollectionDropUUID(ctx context.Context, dbName, collectionName, uuid string) (bool, error) {
+	defer observability.FuncCall(ctx)()
+
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	if c := r.collectionGet(dbName, collectionName); c == nil || c.Settings.UUID != uuid {
+		return false, nil
+	}
+
+	return r.collectionDrop(ctx, dbName, collectionName)
+}
+
 // collectionDrop drops a collection in the database.
 //
 // Returned boolean value indicates whether the collection was dropped.
@@ -387,6 +456,7 @@ func (r *Registry) collectionDrop(ctx context.Context, dbName, collectionName st
 	}
 
 	delete(r.colls[dbName], collectionName)
+	r.cache.Invalidate()
 
 	return true, nil
 }
@@ -394,6 +464,7 @@ func (r *Registry) collectionDrop(ctx context.Context, dbName, collectionName st
 // CollectionRename renames a collection in the database.
 //
 // The collection name is update, but original table name is kept.
+// Renamed collection is no longer temporary.
 //
 // Returned boolean value indicates whether the collection was renamed.
 // If database or collection did not exist, (false, nil) is returned.
@@ -413,14 +484,80 @@ func (r *Registry) CollectionRename(ctx context.Context, dbName, oldCollectionNa
 		return false, nil
 	}
 
-	q := fmt.Sprintf(`UPDATE %q SET name = ? WHERE table_name = ?`, metadataTableName)
-	if _, err := db.ExecContext(ctx, q, newCollectionName, c.TableName); err != nil {
+	settings := c.Settings.deepCopy()
+	settings.Temp = false
+	settings.TempOwner = ""
+
+	q := fmt.Sprintf(`UPDATE %q SET name = ?, settings = ? WHERE table_name = ?`, metadataTableName)
+	if _, err := db.ExecContext(ctx, q, newCollectionName, settings, c.TableName); err != nil {
 		return false, lazyerrors.Error(err)
 	}
 
 	c.Name = newCollectionName
+	c.Settings = settings
 	r.colls[dbName][newCollectionName] = c
 	delete(r.colls[dbName], oldCollectionName)
+	r.cache.Invalidate()
+
+	return true, nil
+}
+
+// CollectionReplace renames a collection in the database like CollectionRename,
+// dropping the existing collection with the new name in the same transaction.
+//
+// Returned boolean value indicates whether the collection was renamed.
+// If database or old collection did not exist, (false, nil) is returned.
+func (r *Registry) CollectionReplace(ctx context.Context, dbName, oldCollectionName, newCollectionName string) (bool, error) {
+	defer observability.FuncCall(ctx)()
+
+	db := r.DatabaseGetExisting(ctx, dbName)
+	if db == nil {
+		return false, nil
+	}
+
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	c := r.collectionGet(dbName, oldCollectionName)
+	if c == nil {
+		return false, nil
+	}
+
+	settings := c.Settings.deepCopy()
+	settings.Temp = false
+	settings.TempOwner = ""
+
+	target := r.collectionGet(dbName, newCollectionName)
+
+	err := db.InTransaction(ctx, func(tx *fsql.Tx) error {
+		if target != nil {
+			q := fmt.Sprintf("DELETE FROM %q WHERE name = ?", metadataTableName)
+			if _, err := tx.ExecContext(ctx, q, newCollectionName); err != nil {
+				return lazyerrors.Error(err)
+			}
+
+			q = fmt.Sprintf("DROP TABLE %q", target.TableName)
+			if _, err := tx.ExecContext(ctx, q); err != nil {
+				return lazyerrors.Error(err)
+			}
+		}
+
+		q := fmt.Sprintf(`UPDATE %q SET name = ?, settings = ? WHERE table_name = ?`, metadataTableName)
+		if _, err := tx.ExecContext(ctx, q, newCollectionName, settings, c.TableName); err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	c.Name = newCollectionName
+	c.Settings = settings
+	r.colls[dbName][newCollectionName] = c
+	delete(r.colls[dbName], oldCollectionName)
+	r.cache.Invalidate()
 
 	return true, nil
 }
@@ -445,7 +582,7 @@ func (r *Registry) IndexesCreate(ctx context.Context, dbName, collectionName str
 func (r *Registry) indexesCreate(ctx context.Context, dbName, collectionName string, indexes []IndexInfo) error {
 	defer observability.FuncCall(ctx)()
 
-	_, err := r.collectionCreate(ctx, dbName, collectionName)
+	_, err := r.collectionCreate(ctx, dbName, collectionName, "")
 	if err != nil {
 		return lazyerrors.Error(err)
 	}
@@ -479,7 +616,12 @@ func (r *Registry) indexesCreate(ctx context.Context, dbName, collectionName str
 
 		columns := make([]string, len(index.Key))
 		for i, key := range index.Key {
+			if key.Expression != "" {
+				return lazyerrors.Errorf("computed index key %s is not supported", key.Expression)
+			}
+
 			columns[i] = fmt.Sprintf("%s->'$.%s'", DefaultColumn, key.Field)
+
 			if key.Descending {
 				columns[i] += " DESC"
 			}
@@ -495,13 +637,14 @@ func (r *Registry) indexesCreate(ctx context.Context, dbName, collectionName str
 		c.Settings.Indexes = append(c.Settings.Indexes, index)
 	}
 
-	q := fmt.Sprintf("UPDATE %q SET settings = ?", metadataTableName)
-	if _, err := db.ExecContext(ctx, q, c.Settings); err != nil {
+	q := fmt.Sprintf("UPDATE %q SET settings = ? WHERE name = ?", metadataTableName)
+	if _, err := db.ExecContext(ctx, q, c.Settings, collectionName); err != nil {
 		_ = r.indexesDrop(ctx, dbName, collectionName, created)
 		return lazyerrors.Error(err)
 	}
 
 	r.colls[dbName][collectionName] = c
+	r.cache.Invalidate()
 
 	return nil
 }
@@ -554,16 +697,374 @@ func (r *Registry) indexesDrop(ctx context.Context, dbName, collectionName strin
 		c.Settings.Indexes = slices.Delete(c.Settings.Indexes, i, i+1)
 	}
 
-	q := fmt.Sprintf("UPDATE %q SET settings = ?", metadataTableName)
-	if _, err := db.ExecContext(ctx, q, c.Settings); err != nil {
+	q := fmt.Sprintf("UPDATE %q SET settings = ? WHERE name = ?", metadataTableName)
+	if _, err := db.ExecContext(ctx, q, c.Settings, collectionName); err != nil {
 		return lazyerrors.Error(err)
 	}
 
 	r.colls[dbName][collectionName] = c
+	r.cache.Invalidate()
+
+	return nil
+}
+
+// DatabaseValidate cross-checks collections metadata of the database against existing tables and indexes.
+//
+// If repair is true, found problems are repaired:
+//   - metadata of collections without tables is removed;
+//   - tables without metadata are registered as collections with the same name and the default index;
+//   - metadata of indexes without SQLite indexes is removed, except the default index that is rebuilt.
+//
+// SQLite indexes without metadata are only reported; they could be created manually.
+//
+// If database does not exist, (nil, nil) is returned.
+func (r *Registry) DatabaseValidate(ctx context.Context, dbName string, repair bool) ([]backends.MetadataProblem, error) {
+	defer observability.FuncCall(ctx)()
+
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	db := r.DatabaseGetExisting(ctx, dbName)
+	if db == nil {
+		return nil, nil
+	}
+
+	res, tables, err := r.tablesValidate(ctx, db, dbName, repair)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	problems, err := r.indexesValidate(ctx, db, dbName, tables, repair)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return append(res, problems...), nil
+}
+
+// tablesValidate cross-checks collections metadata of the database against existing tables
+// and repairs found problems if requested.
+//
+// It also returns names of existing tables of registered collections.
+//
+// It does not hold the lock.
+func (r *Registry) tablesValidate(ctx context.Context, db *fsql.DB, dbName string, repair bool) ([]backends.MetadataProblem, map[string]struct{}, error) { //nolint:lll // for readability
+	defer observability.FuncCall(ctx)()
+
+	// tables that look like FerretDB tables
+	q := strings.TrimSpace(`
+		SELECT m.name
+		FROM sqlite_master AS m
+		WHERE m.type = 'table' AND EXISTS (SELECT 1 FROM pragma_table_info(m.name) WHERE name = ?)
+		ORDER BY m.name
+	`)
+
+	rows, err := db.QueryContext(ctx, q, DefaultColumn)
+	if err != nil {
+		return nil, nil, lazyerrors.Error(err)
+	}
+	defer rows.Close()
+
+	tables := map[string]struct{}{}
+	var tableNames []string
+
+	for rows.Next() {
+		var tableName string
+		if err = rows.Scan(&tableName); err != nil {
+			return nil, nil, lazyerrors.Error(err)
+		}
+
+		if strings.HasPrefix(tableName, reservedTablePrefix) || strings.HasPrefix(tableName, backends.ReservedPrefix) {
+			continue
+		}
+
+		tables[tableName] = struct{}{}
+		tableNames = append(tableNames, tableName)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, nil, lazyerrors.Error(err)
+	}
+
+	var res []backends.MetadataProblem
+
+	collectionNames := maps.Keys(r.colls[dbName])
+	sort.Strings(collectionNames)
+
+	registered := make(map[string]struct{}, len(collectionNames))
+
+	for _, collectionName := range collectionNames {
+		c := r.colls[dbName][collectionName]
+
+		if _, ok := tables[c.TableName]; ok {
+			registered[c.TableName] = struct{}{}
+			continue
+		}
+
+		problem := backends.MetadataProblem{
+			Type:       backends.MetadataProblemMissingTable,
+			Collection: c.Name,
+			Table:      c.TableName,
+			Action:     "remove collection metadata",
+		}
+
+		if repair {
+			if err = r.collectionMetadataDelete(ctx, db, dbName, c.Name); err != nil {
+				return nil, nil, lazyerrors.Error(err)
+			}
+
+			problem.Repaired = true
+		}
+
+		res = append(res, problem)
+	}
+
+	for _, tableName := range tableNames {
+		if _, ok := registered[tableName]; ok {
+			continue
+		}
+
+		problem := backends.MetadataProblem{
+			Type:  backends.MetadataProblemOrphanTable,
+			Table: tableName,
+		}
+
+		// another collection may already use that name for a different table
+		if r.colls[dbName][tableName] == nil {
+			problem.Action = "register collection " + tableName
+		}
+
+		if repair && problem.Action != "" {
+			if err = r.collectionRegister(ctx, db, dbName, tableName); err != nil {
+				return nil, nil, lazyerrors.Error(err)
+			}
+
+			registered[tableName] = struct{}{}
+
+			problem.Collection = tableName
+			problem.Repaired = true
+		}
+
+		res = append(res, problem)
+	}
+
+	return res, registered, nil
+}
+
+// indexesValidate cross-checks indexes metadata of collections with given existing tables
+// against existing SQLite indexes and repairs found problems if requested.
+//
+// It does not hold the lock.
+func (r *Registry) indexesValidate(ctx context.Context, db *fsql.DB, dbName string, existing map[string]struct{}, repair bool) ([]backends.MetadataProblem, error) { //nolint:lll // for readability
+	defer observability.FuncCall(ctx)()
+
+	// automatic indexes have no SQL
+	q := `SELECT name, tbl_name FROM sqlite_master WHERE type = 'index' AND sql IS NOT NULL ORDER BY name`
+
+	rows, err := db.QueryContext(ctx, q)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+	defer rows.Close()
+
+	sqliteIndexes := map[string]string{} // SQLite index name -> table name
+	var sqliteIndexNames []string
+
+	for rows.Next() {
+		var indexName, tableName string
+		if err = rows.Scan(&indexName, &tableName); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		sqliteIndexes[indexName] = tableName
+		sqliteIndexNames = append(sqliteIndexNames, indexName)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	var res []backends.MetadataProblem
+
+	collectionNames := maps.Keys(r.colls[dbName])
+	sort.Strings(collectionNames)
+
+	tables := make(map[string]string, len(collectionNames))       // table name -> collection name
+	registered := make(map[string]struct{}, len(collectionNames)) // registered SQLite index names
+
+	for _, collectionName := range collectionNames {
+		c := r.collectionGet(dbName, collectionName)
+		if _, ok := existing[c.TableName]; !ok {
+			continue
+		}
+
+		tables[c.TableName] = c.Name
+
+		for _, index := range c.Settings.Indexes {
+			sqliteIndex := c.TableName + "_" + index.Name
+
+			if sqliteIndexes[sqliteIndex] == c.TableName {
+				registered[sqliteIndex] = struct{}{}
+				continue
+			}
+
+			problem := backends.MetadataProblem{
+				Type:       backends.MetadataProblemMissingIndex,
+				Collection: c.Name,
+				Table:      c.TableName,
+				Index:      index.Name,
+				Action:     "remove index metadata",
+			}
+
+			if index.Name == backends.DefaultIndexName {
+				problem.Action = "rebuild index"
+			}
+
+			if repair {
+				if err = r.indexRepair(ctx, db, dbName, c.Name, index); err != nil {
+					return nil, lazyerrors.Error(err)
+				}
+
+				problem.Repaired = true
+			}
+
+			res = append(res, problem)
+		}
+	}
+
+	for _, indexName := range sqliteIndexNames {
+		collectionName, ok := tables[sqliteIndexes[indexName]]
+		if !ok {
+			continue
+		}
+
+		if _, ok = registered[indexName]; ok {
+			continue
+		}
+
+		problem := backends.MetadataProblem{
+			Type:       backends.MetadataProblemOrphanIndex,
+			Collection: collectionName,
+			Table:      sqliteIndexes[indexName],
+			Index:      indexName,
+		}
+
+		// it may be added manually, so it is reported, but never dropped
+		res = append(res, problem)
+	}
+
+	return res, nil
+}
+
+// collectionMetadataDelete removes collection metadata without dropping the table.
+//
+// It does not hold the lock.
+func (r *Registry) collectionMetadataDelete(ctx context.Context, db *fsql.DB, dbName, collectionName string) error {
+	q := fmt.Sprintf("DELETE FROM %q WHERE name = ?", metadataTableName)
+	if _, err := db.ExecContext(ctx, q, collectionName); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	delete(r.colls[dbName], collectionName)
+	r.cache.Invalidate()
 
 	return nil
 }
 
+// collectionRegister adds metadata for the existing table as a collection with the same name.
+//
+// Existing indexes of the table are kept as is.
+// If one of them is a unique index with the name of the default index, it is registered as the default index;
+// otherwise, the default index is created.
+//
+// It does not hold the lock.
+func (r *Registry) collectionRegister(ctx context.Context, db *fsql.DB, dbName, tableName string) error {
+	defaultIndex := IndexInfo{
+		Name:   backends.DefaultIndexName,
+		Key:    []IndexKeyPair{{Field: "_id"}},
+		Unique: true,
+	}
+
+	// the default index could be restored together with the table
+	indexName := tableName + "_" + defaultIndex.Name
+	q := `SELECT tbl_name, sql FROM sqlite_master WHERE type = 'index' AND name = ?`
+
+	var indexTable, indexSQL string
+
+	err := db.QueryRowContext(ctx, q, indexName).Scan(&indexTable, &indexSQL)
+
+	var adopt bool
+
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		return lazyerrors.Error(err)
+	case indexTable != tableName || !strings.HasPrefix(indexSQL, "CREATE UNIQUE INDEX "):
+		return lazyerrors.Errorf("index %q already exists and can't be used as the default index", indexName)
+	default:
+		adopt = true
+	}
+
+	c := &Collection{
+		Name:      tableName,
+		TableName: tableName,
+		Settings: Settings{
+			UUID: uuid.NewString(),
+		},
+	}
+
+	if adopt {
+		c.Settings.Indexes = []IndexInfo{defaultIndex}
+	}
+
+	q = fmt.Sprintf("INSERT INTO %q (name, table_name, settings) VALUES (?, ?, ?)", metadataTableName)
+	if _, err = db.ExecContext(ctx, q, c.Name, c.TableName, c.Settings); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	if r.colls[dbName] == nil {
+		r.colls[dbName] = map[string]*Collection{}
+	}
+	r.colls[dbName][c.Name] = c
+	r.cache.Invalidate()
+
+	if adopt {
+		return nil
+	}
+
+	if err = r.indexesCreate(ctx, dbName, c.Name, []IndexInfo{defaultIndex}); err != nil {
+		// for example, because of duplicate _id values
+		_ = r.collectionMetadataDelete(ctx, db, dbName, c.Name)
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// indexRepair removes metadata of the index that does not exist.
+// The default index is rebuilt instead.
+//
+// It does not hold the lock.
+func (r *Registry) indexRepair(ctx context.Context, db *fsql.DB, dbName, collectionName string, index IndexInfo) error {
+	c := r.collectionGet(dbName, collectionName)
+
+	c.Settings.Indexes = slices.DeleteFunc(c.Settings.Indexes, func(i IndexInfo) bool { return i.Name == index.Name })
+
+	q := fmt.Sprintf("UPDATE %q SET settings = ? WHERE name = ?", metadataTableName)
+	if _, err := db.ExecContext(ctx, q, c.Settings, collectionName); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	r.colls[dbName][collectionName] = c
+	r.cache.Invalidate()
+
+	if index.Name != backends.DefaultIndexName {
+		return nil
+	}
+
+	return r.indexesCreate(ctx, dbName, collectionName, []IndexInfo{index})
+}
+
 // Describe implements prometheus.Collector.
 func (r *Registry) Describe(ch chan<- *prometheus.Desc) {
 	prometheus.DescribeByCollect(r, ch)
@@ -586,6 +1087,16 @@ func (r *Registry) Collect(ch chan<- prometheus.Metric) {
 		float64(len(r.colls)),
 	)
 
+	ch <- prometheus.MustNewConstMetric(
+		prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "version"),
+			"The current version of metadata; it is incremented on every change.",
+			nil, nil,
+		),
+		prometheus.CounterValue,
+		float64(r.cache.Version()),
+	)
+
 	for db, colls := range r.colls {
 		ch <- prometheus.MustNewConstMetric(
 			prometheus.NewDesc(
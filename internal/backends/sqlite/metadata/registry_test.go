@@ -20,8 +20,10 @@ import (
 	"sync/atomic"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/FerretDB/FerretDB/internal/backends"
 	"github.com/FerretDB/FerretDB/internal/util/fsql"
 	"github.com/FerretDB/FerretDB/internal/util/state"
 	"github.com/FerretDB/FerretDB/internal/util/testutil"
@@ -94,6 +96,65 @@ func TestCreateDrop(t *testing.T) {
 	testCollection(t, ctx, r, db, dbName, collectionName)
 }
 
+func TestCollectionReplace(t *testing.T) {
+	t.Parallel()
+	ctx := testutil.Ctx(t)
+
+	sp, err := state.NewProvider("")
+	require.NoError(t, err)
+
+	r, err := NewRegistry("file:"+t.TempDir()+"/", testutil.Logger(t), sp)
+	require.NoError(t, err)
+	t.Cleanup(r.Close)
+
+	dbName := testutil.DatabaseName(t)
+
+	db, err := r.DatabaseGetOrCreate(ctx, dbName)
+	require.NoError(t, err)
+
+	created, err := r.CollectionCreate(ctx, dbName, "target")
+	require.NoError(t, err)
+	require.True(t, created)
+
+	created, err = r.CollectionCreateTemp(ctx, dbName, "source", "owner")
+	require.NoError(t, err)
+	require.True(t, created)
+
+	target := r.CollectionGet(ctx, dbName, "target")
+	source := r.CollectionGet(ctx, dbName, "source")
+	require.Equal(t, "owner", source.Settings.TempOwner)
+
+	replaced, err := r.CollectionReplace(ctx, dbName, "source", "target")
+	require.NoError(t, err)
+	require.True(t, replaced)
+
+	assert.Nil(t, r.CollectionGet(ctx, dbName, "source"))
+
+	c := r.CollectionGet(ctx, dbName, "target")
+	require.NotNil(t, c)
+	assert.Equal(t, source.TableName, c.TableName)
+	assert.Equal(t, source.Settings.UUID, c.Settings.UUID)
+	assert.False(t, c.Settings.Temp)
+	assert.Empty(t, c.Settings.TempOwner)
+
+	var count int
+	q := "SELECT count(*) FROM sqlite_schema WHERE type = 'table' AND name = ?"
+	require.NoError(t, db.QueryRowContext(ctx, q, target.TableName).Scan(&count))
+	assert.Zero(t, count)
+
+	// metadata is persisted
+	require.NoError(t, r.initCollections(ctx, dbName, db))
+
+	c = r.CollectionGet(ctx, dbName, "target")
+	require.NotNil(t, c)
+	assert.Equal(t, source.TableName, c.TableName)
+	assert.False(t, c.Settings.Temp)
+
+	replaced, err = r.CollectionReplace(ctx, dbName, "source", "target")
+	require.NoError(t, err)
+	require.False(t, replaced)
+}
+
 func TestCreateDropStress(t *testing.T) {
 	// Otherwise, the test might fail with "database schema has changed".
 	// That error code is SQLITE_SCHEMA (17).
@@ -434,3 +495,248 @@ func TestIndexesCreateDrop(t *testing.T) {
 		require.Equal(t, 1, len(collection.Settings.Indexes))
 	})
 }
+
+func TestIndexesSettingsPerCollection(t *testing.T) {
+	t.Parallel()
+	ctx := testutil.Ctx(t)
+
+	sp, err := state.NewProvider("")
+	require.NoError(t, err)
+
+	r, err := NewRegistry("file:"+t.TempDir()+"/", testutil.Logger(t), sp)
+	require.NoError(t, err)
+	t.Cleanup(r.Close)
+
+	dbName := testutil.DatabaseName(t)
+
+	db, err := r.DatabaseGetOrCreate(ctx, dbName)
+	require.NoError(t, err)
+
+	_, err = r.CollectionCreate(ctx, dbName, "other")
+	require.NoError(t, err)
+
+	err = r.IndexesCreate(ctx, dbName, "indexes", []IndexInfo{{
+		Name: "index_foo",
+		Key:  []IndexKeyPair{{Field: "foo"}},
+	}})
+	require.NoError(t, err)
+
+	// reload metadata to check that settings of other collections were not overwritten
+	err = r.initCollections(ctx, dbName, db)
+	require.NoError(t, err)
+
+	require.Len(t, r.CollectionGet(ctx, dbName, "indexes").Settings.Indexes, 2)
+	require.Len(t, r.CollectionGet(ctx, dbName, "other").Settings.Indexes, 1)
+
+	err = r.IndexesDrop(ctx, dbName, "other", []string{"_id_"})
+	require.NoError(t, err)
+
+	err = r.initCollections(ctx, dbName, db)
+	require.NoError(t, err)
+
+	require.Len(t, r.CollectionGet(ctx, dbName, "indexes").Settings.Indexes, 2)
+	require.Empty(t, r.CollectionGet(ctx, dbName, "other").Settings.Indexes)
+}
+
+func TestDatabaseValidate(t *testing.T) {
+	t.Parallel()
+	ctx := testutil.Ctx(t)
+
+	sp, err := state.NewProvider("")
+	require.NoError(t, err)
+
+	r, err := NewRegistry("file:"+t.TempDir()+"/", testutil.Logger(t), sp)
+	require.NoError(t, err)
+	t.Cleanup(r.Close)
+
+	dbName := testutil.DatabaseName(t)
+
+	db, err := r.DatabaseGetOrCreate(ctx, dbName)
+	require.NoError(t, err)
+	require.NotNil(t, db)
+
+	problems, err := r.DatabaseValidate(ctx, dbName, true)
+	require.NoError(t, err)
+	require.Empty(t, problems)
+
+	_, err = r.CollectionCreate(ctx, dbName, "missing")
+	require.NoError(t, err)
+
+	err = r.IndexesCreate(ctx, dbName, "indexes", []IndexInfo{{
+		Name: "index_missing",
+		Key:  []IndexKeyPair{{Field: "foo"}},
+	}})
+	require.NoError(t, err)
+
+	missing := r.CollectionGet(ctx, dbName, "missing")
+	indexes := r.CollectionGet(ctx, dbName, "indexes")
+
+	// make metadata disagree with tables and indexes
+	for _, q := range []string{
+		fmt.Sprintf(`DROP TABLE %q`, missing.TableName),
+		fmt.Sprintf(`DROP INDEX %q`, indexes.TableName+"__id_"),
+		fmt.Sprintf(`DROP INDEX %q`, indexes.TableName+"_index_missing"),
+		fmt.Sprintf(`CREATE INDEX "orphan_index" ON %q (%s->'$.bar')`, indexes.TableName, DefaultColumn),
+		fmt.Sprintf(`CREATE TABLE "orphan" (%s TEXT NOT NULL) STRICT`, DefaultColumn),
+		fmt.Sprintf(`CREATE UNIQUE INDEX "orphan__id_" ON "orphan" (%s->'$._id')`, DefaultColumn),
+		fmt.Sprintf(`CREATE INDEX "orphan_manual" ON "orphan" (%s->'$.foo')`, DefaultColumn),
+	} {
+		_, err = db.ExecContext(ctx, q)
+		require.NoError(t, err)
+	}
+
+	orphanIndex := backends.MetadataProblem{
+		Type:       backends.MetadataProblemOrphanIndex,
+		Collection: "indexes",
+		Table:      indexes.TableName,
+		Index:      "orphan_index",
+	}
+
+	orphanManual := backends.MetadataProblem{
+		Type:       backends.MetadataProblemOrphanIndex,
+		Collection: "orphan",
+		Table:      "orphan",
+		Index:      "orphan_manual",
+	}
+
+	expected := []backends.MetadataProblem{{
+		Type:       backends.MetadataProblemMissingTable,
+		Collection: "missing",
+		Table:      missing.TableName,
+		Action:     "remove collection metadata",
+	}, {
+		Type:   backends.MetadataProblemOrphanTable,
+		Table:  "orphan",
+		Action: "register collection orphan",
+	}, {
+		Type:       backends.MetadataProblemMissingIndex,
+		Collection: "indexes",
+		Table:      indexes.TableName,
+		Index:      "_id_",
+		Action:     "rebuild index",
+	}, {
+		Type:       backends.MetadataProblemMissingIndex,
+		Collection: "indexes",
+		Table:      indexes.TableName,
+		Index:      "index_missing",
+		Action:     "remove index metadata",
+	}, orphanIndex}
+
+	problems, err = r.DatabaseValidate(ctx, dbName, false)
+	require.NoError(t, err)
+	require.Equal(t, expected, problems)
+
+	for i := range expected[:4] {
+		expected[i].Repaired = true
+	}
+	expected[1].Collection = "orphan"
+
+	// indexes of the registered table are checked too, but orphan indexes are never dropped
+	expected = append(expected, orphanManual)
+
+	problems, err = r.DatabaseValidate(ctx, dbName, true)
+	require.NoError(t, err)
+	require.Equal(t, expected, problems)
+
+	problems, err = r.DatabaseValidate(ctx, dbName, true)
+	require.NoError(t, err)
+	require.Equal(t, []backends.MetadataProblem{orphanIndex, orphanManual}, problems)
+
+	// reload metadata to check that repairs were saved
+	err = r.initCollections(ctx, dbName, db)
+	require.NoError(t, err)
+
+	list, err := r.CollectionList(ctx, dbName)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+
+	defaultIndexes := []IndexInfo{{
+		Name:   "_id_",
+		Key:    []IndexKeyPair{{Field: "_id"}},
+		Unique: true,
+	}}
+
+	require.Equal(t, "indexes", list[0].Name)
+	require.Equal(t, defaultIndexes, list[0].Settings.Indexes)
+
+	// the existing default index was registered
+	require.Equal(t, "orphan", list[1].Name)
+	require.Equal(t, "orphan", list[1].TableName)
+	require.Equal(t, defaultIndexes, list[1].Settings.Indexes)
+
+	t.Run("RegisterFailed", func(t *testing.T) {
+		for _, q := range []string{
+			fmt.Sprintf(`CREATE TABLE "dups" (%s TEXT NOT NULL) STRICT`, DefaultColumn),
+			fmt.Sprintf(`CREATE INDEX "dups_manual" ON "dups" (%s->'$.foo')`, DefaultColumn),
+			fmt.Sprintf(`INSERT INTO "dups" (%s) VALUES ('{"_id": 1}'), ('{"_id": 1}')`, DefaultColumn),
+		} {
+			_, err = db.ExecContext(ctx, q)
+			require.NoError(t, err)
+		}
+
+		_, err = r.DatabaseValidate(ctx, dbName, true)
+		require.Error(t, err)
+
+		require.Nil(t, r.CollectionGet(ctx, dbName, "dups"))
+
+		var count int
+		q := `SELECT count(*) FROM sqlite_master WHERE type = 'index' AND name = 'dups_manual'`
+		require.NoError(t, db.QueryRowContext(ctx, q).Scan(&count))
+		require.Equal(t, 1, count)
+	})
+}
+
+func TestCache(t *testing.T) {
+	t.Parallel()
+	ctx := testutil.Ctx(t)
+
+	sp, err := state.NewProvider("")
+	require.NoError(t, err)
+
+	r, err := NewRegistry("file:"+t.TempDir()+"/", testutil.Logger(t), sp)
+	require.NoError(t, err)
+	t.Cleanup(r.Close)
+
+	dbName := testutil.DatabaseName(t)
+	collectionName := testutil.CollectionName(t)
+
+	require.Nil(t, r.CollectionGetCached(ctx, dbName, collectionName))
+
+	version := r.cache.Version()
+
+	created, err := r.CollectionCreate(ctx, dbName, collectionName)
+	require.NoError(t, err)
+	require.True(t, created)
+	require.Greater(t, r.cache.Version(), version)
+
+	c := r.CollectionGetCached(ctx, dbName, collectionName)
+	require.NotNil(t, c)
+	require.Equal(t, r.CollectionGet(ctx, dbName, collectionName), c)
+
+	// the same snapshot is returned without copying until the next change
+	version = r.cache.Version()
+	require.Same(t, c, r.CollectionGetCached(ctx, dbName, collectionName))
+
+	created, err = r.CollectionCreate(ctx, dbName, collectionName)
+	require.NoError(t, err)
+	require.False(t, created)
+	require.Equal(t, version, r.cache.Version())
+	require.Same(t, c, r.CollectionGetCached(ctx, dbName, collectionName))
+
+	err = r.IndexesCreate(ctx, dbName, collectionName, []IndexInfo{{
+		Name: "index",
+		Key:  []IndexKeyPair{{Field: "foo"}},
+	}})
+	require.NoError(t, err)
+	require.Greater(t, r.cache.Version(), version)
+
+	updated := r.CollectionGetCached(ctx, dbName, collectionName)
+	require.NotSame(t, c, updated)
+	require.Len(t, updated.Settings.Indexes, 2)
+	require.Len(t, c.Settings.Indexes, 1, "previous snapshot should not be modified")
+
+	dropped, err := r.CollectionDrop(ctx, dbName, collectionName)
+	require.NoError(t, err)
+	require.True(t, dropped)
+	require.Nil(t, r.CollectionGetCached(ctx, dbName, collectionName))
+}
@@ -20,25 +20,33 @@ import (
 	"encoding/json"
 	"slices"
 
+	"github.com/FerretDB/FerretDB/internal/backends"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
 )
 
 // Settings represents collection settings.
 type Settings struct {
 	Indexes []IndexInfo `json:"indexes"`
+	UUID    string      `json:"uuid,omitempty"` // not set for collections created by older versions
+	Temp    bool        `json:"temp,omitempty"`
+
+	// TempOwner identifies the FerretDB instance that created the temporary collection.
+	TempOwner string `json:"tempOwner,omitempty"`
 }
 
 // IndexInfo represents information about a single index.
 type IndexInfo struct {
-	Name   string         `json:"name"`
-	Key    []IndexKeyPair `json:"key"`
-	Unique bool           `json:"unique"`
+	Name               string         `json:"name"`
+	Key                []IndexKeyPair `json:"key"`
+	Unique             bool           `json:"unique"`
+	ExpireAfterSeconds *int32         `json:"expireAfterSeconds,omitempty"`
 }
 
 // IndexKeyPair consists of a field name and a sort order that are part of the index.
 type IndexKeyPair struct {
-	Field      string `json:"field"`
-	Descending bool   `json:"descending"`
+	Field      string                      `json:"field"`
+	Descending bool                        `json:"descending"`
+	Expression backends.IndexKeyExpression `json:"expression,omitempty"`
 }
 
 // deepCopy returns a deep copy.
@@ -51,10 +59,18 @@ func (s Settings) deepCopy() Settings {
 			Key:    slices.Clone(index.Key),
 			Unique: index.Unique,
 		}
+
+		if index.ExpireAfterSeconds != nil {
+			expireAfterSeconds := *index.ExpireAfterSeconds
+			indexes[i].ExpireAfterSeconds = &expireAfterSeconds
+		}
 	}
 
 	return Settings{
-		Indexes: indexes,
+		Indexes:   indexes,
+		UUID:      s.UUID,
+		Temp:      s.Temp,
+		TempOwner: s.TempOwner,
 	}
 }
 
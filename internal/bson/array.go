@@ -89,25 +89,24 @@ func (a arrayType) MarshalBinary() ([]byte, error) {
 	ta := types.Array(a)
 	l := ta.Len()
 
-	fields := make([]field, l)
+	keys := make([]string, l)
+	values := make([]any, l)
+
 	for i := 0; i < l; i++ {
-		key := strconv.Itoa(i)
 		value, err := ta.Get(i)
 		if err != nil {
 			return nil, lazyerrors.Error(err)
 		}
 
-		fields[i] = field{key: key, value: value}
-	}
-
-	doc := Document{
-		fields: fields,
+		keys[i] = strconv.Itoa(i)
+		values[i] = value
 	}
 
-	b, err := doc.MarshalBinary()
+	b, err := appendDocument(nil, keys, values)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
+
 	return b, nil
 }
 
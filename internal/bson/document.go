@@ -16,15 +16,14 @@ package bson
 
 import (
 	"bufio"
-	"bytes"
 	"encoding/binary"
 	"fmt"
-	"io"
+	"math"
+	"strconv"
 	"time"
 
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
-	"github.com/FerretDB/FerretDB/internal/util/must"
 )
 
 const (
@@ -138,167 +137,14 @@ func (doc *Document) ReadFrom(r *bufio.Reader) error {
 // It also takes the nesting value, and checks if the
 // document doesn't exceed the max nesting allowed.
 func (doc *Document) readNested(r *bufio.Reader, nesting int) error {
-	if nesting > maxNesting {
-		return fmt.Errorf("bson.Document.readNested: document has exceeded the max supported nesting: %d", maxNesting)
-	}
-
-	var l int32
-	if err := binary.Read(r, binary.LittleEndian, &l); err != nil {
-		return lazyerrors.Errorf("bson.Document.ReadFrom (binary.Read): %w", err)
-	}
-	if l < minDocumentLen || l > types.MaxDocumentLen {
-		return lazyerrors.Errorf("bson.Document.ReadFrom: invalid length %d", l)
-	}
-
-	// make buffer
-	b := make([]byte, l)
-
-	binary.LittleEndian.PutUint32(b, uint32(l))
-
-	// read e_list and terminating zero
-	n, err := io.ReadFull(r, b[4:])
+	raw, err := ReadRawDocument(r)
 	if err != nil {
-		return lazyerrors.Errorf("bson.Document.ReadFrom (io.ReadFull, expected %d, read %d): %w", len(b), n, err)
+		return lazyerrors.Errorf("bson.Document.ReadFrom: %w", err)
 	}
 
-	bufr := bufio.NewReader(bytes.NewReader(b[4:]))
-
-	fields := make([]field, 0, 8)
-	for {
-		t, err := bufr.ReadByte()
-		if err != nil {
-			return lazyerrors.Errorf("bson.Document.ReadFrom (ReadByte): %w", err)
-		}
-
-		if t == 0 {
-			// documented ended
-			if _, err := bufr.Peek(1); err != io.EOF {
-				return lazyerrors.Errorf("unexpected end of the document: %w", err)
-			}
-			break
-		}
-
-		var ename CString
-		if err := ename.ReadFrom(bufr); err != nil {
-			return lazyerrors.Errorf("bson.Document.ReadFrom (ename.ReadFrom): %w", err)
-		}
-
-		key := string(ename)
-
-		switch tag(t) {
-		case tagDocument:
-			var v Document
-			if err := v.readNested(bufr, nesting+1); err != nil {
-				return lazyerrors.Errorf("bson.Document.ReadFrom (embedded document): %w", err)
-			}
-
-			value, err := types.ConvertDocument(&v)
-			if err != nil {
-				return lazyerrors.Errorf("bson.Document.ReadFrom (embedded document): %w", err)
-			}
-
-			fields = append(fields, field{key: key, value: value})
-
-		case tagArray:
-			var v arrayType
-			if err := v.readNested(bufr, nesting+1); err != nil {
-				return lazyerrors.Errorf("bson.Document.ReadFrom (Array): %w", err)
-			}
-			a := types.Array(v)
-			fields = append(fields, field{key: key, value: &a})
-
-		case tagDouble:
-			var v doubleType
-			if err := v.ReadFrom(bufr); err != nil {
-				return lazyerrors.Errorf("bson.Document.ReadFrom (Double): %w", err)
-			}
-
-			fields = append(fields, field{key: key, value: float64(v)})
-
-		case tagString:
-			var v stringType
-			if err := v.ReadFrom(bufr); err != nil {
-				return lazyerrors.Errorf("bson.Document.ReadFrom (String): %w", err)
-			}
-
-			fields = append(fields, field{key: key, value: string(v)})
-
-		case tagBinary:
-			var v binaryType
-			if err := v.ReadFrom(bufr); err != nil {
-				return lazyerrors.Errorf("bson.Document.ReadFrom (Binary): %w", err)
-			}
-
-			fields = append(fields, field{key: key, value: types.Binary(v)})
-
-		case tagUndefined:
-			return lazyerrors.Errorf("bson.Document.ReadFrom: unhandled element type `Undefined (value) Deprecated`")
-
-		case tagObjectID:
-			var v objectIDType
-			if err := v.ReadFrom(bufr); err != nil {
-				return lazyerrors.Errorf("bson.Document.ReadFrom (ObjectID): %w", err)
-			}
-
-			fields = append(fields, field{key: key, value: types.ObjectID(v)})
-
-		case tagBool:
-			var v boolType
-			if err := v.ReadFrom(bufr); err != nil {
-				return lazyerrors.Errorf("bson.Document.ReadFrom (Bool): %w", err)
-			}
-
-			fields = append(fields, field{key: key, value: bool(v)})
-
-		case tagDateTime:
-			var v dateTimeType
-			if err := v.ReadFrom(bufr); err != nil {
-				return lazyerrors.Errorf("bson.Document.ReadFrom (DateTime): %w", err)
-			}
-
-			fields = append(fields, field{key: key, value: time.Time(v)})
-
-		case tagNull:
-			// skip calling ReadFrom that does nothing
-			fields = append(fields, field{key: key, value: types.Null})
-
-		case tagRegex:
-			var v regexType
-			if err := v.ReadFrom(bufr); err != nil {
-				return lazyerrors.Errorf("bson.Document.ReadFrom (Regex): %w", err)
-			}
-
-			fields = append(fields, field{key: key, value: types.Regex(v)})
-
-		case tagInt32:
-			var v int32Type
-			if err := v.ReadFrom(bufr); err != nil {
-				return lazyerrors.Errorf("bson.Document.ReadFrom (Int32): %w", err)
-			}
-
-			fields = append(fields, field{key: key, value: int32(v)})
-
-		case tagTimestamp:
-			var v timestampType
-			if err := v.ReadFrom(bufr); err != nil {
-				return lazyerrors.Errorf("bson.Document.ReadFrom (Timestamp): %w", err)
-			}
-
-			fields = append(fields, field{key: key, value: types.Timestamp(v)})
-
-		case tagInt64:
-			var v int64Type
-			if err := v.ReadFrom(bufr); err != nil {
-				return lazyerrors.Errorf("bson.Document.ReadFrom (Int64): %w", err)
-			}
-
-			fields = append(fields, field{key: key, value: int64(v)})
-
-		case tagDBPointer, tagDecimal, tagJavaScript, tagJavaScriptScope, tagMaxKey, tagMinKey, tagSymbol:
-			return lazyerrors.Errorf("bson.Document.ReadFrom: unhandled element type %#02x (%s)", t, tag(t))
-		default:
-			return lazyerrors.Errorf("bson.Document.ReadFrom: unhandled element type %#02x (%s)", t, tag(t))
-		}
+	fields, err := raw.decode(nesting)
+	if err != nil {
+		return lazyerrors.Errorf("bson.Document.ReadFrom: %w", err)
 	}
 
 	*doc = Document{fields: fields}
@@ -322,9 +168,6 @@ func (doc Document) WriteTo(w *bufio.Writer) error {
 
 // MarshalBinary implements bsontype interface.
 func (doc Document) MarshalBinary() ([]byte, error) {
-	var elist bytes.Buffer
-	bufw := bufio.NewWriter(&elist)
-
 	keys := doc.Keys()
 	values := doc.Values()
 
@@ -332,150 +175,138 @@ func (doc Document) MarshalBinary() ([]byte, error) {
 		panic(fmt.Sprintf("document must have the same number of keys and values (keys: %d, values: %d)", len(keys), len(values)))
 	}
 
-	for i, elK := range keys {
-		ename := CString(elK)
-		elV := values[i]
-
-		switch elV := elV.(type) {
-		case *types.Document:
-			bufw.WriteByte(byte(tagDocument))
-			if err := ename.WriteTo(bufw); err != nil {
-				return nil, lazyerrors.Error(err)
-			}
-
-			doc, err := ConvertDocument(elV)
-			if err != nil {
-				return nil, lazyerrors.Error(err)
-			}
-
-			if err := doc.WriteTo(bufw); err != nil {
-				return nil, lazyerrors.Error(err)
-			}
-
-		case *types.Array:
-			bufw.WriteByte(byte(tagArray))
-			if err := ename.WriteTo(bufw); err != nil {
-				return nil, lazyerrors.Error(err)
-			}
-			if err := arrayType(*elV).WriteTo(bufw); err != nil {
-				return nil, lazyerrors.Error(err)
-			}
-
-		case float64:
-			bufw.WriteByte(byte(tagDouble))
-			if err := ename.WriteTo(bufw); err != nil {
-				return nil, lazyerrors.Error(err)
-			}
-			if err := doubleType(elV).WriteTo(bufw); err != nil {
-				return nil, lazyerrors.Error(err)
-			}
-
-		case string:
-			bufw.WriteByte(byte(tagString))
-			if err := ename.WriteTo(bufw); err != nil {
-				return nil, lazyerrors.Error(err)
-			}
-			if err := stringType(elV).WriteTo(bufw); err != nil {
-				return nil, lazyerrors.Error(err)
-			}
-
-		case types.Binary:
-			bufw.WriteByte(byte(tagBinary))
-			if err := ename.WriteTo(bufw); err != nil {
-				return nil, lazyerrors.Error(err)
-			}
-			if err := binaryType(elV).WriteTo(bufw); err != nil {
-				return nil, lazyerrors.Error(err)
-			}
-
-		case types.ObjectID:
-			bufw.WriteByte(byte(tagObjectID))
-			if err := ename.WriteTo(bufw); err != nil {
-				return nil, lazyerrors.Error(err)
-			}
-			if err := objectIDType(elV).WriteTo(bufw); err != nil {
-				return nil, lazyerrors.Error(err)
-			}
-
-		case bool:
-			bufw.WriteByte(byte(tagBool))
-			if err := ename.WriteTo(bufw); err != nil {
-				return nil, lazyerrors.Error(err)
-			}
-			if err := boolType(elV).WriteTo(bufw); err != nil {
-				return nil, lazyerrors.Error(err)
-			}
-
-		case time.Time:
-			bufw.WriteByte(byte(tagDateTime))
-			if err := ename.WriteTo(bufw); err != nil {
-				return nil, lazyerrors.Error(err)
-			}
-			if err := dateTimeType(elV).WriteTo(bufw); err != nil {
-				return nil, lazyerrors.Error(err)
-			}
-
-		case types.NullType:
-			bufw.WriteByte(byte(tagNull))
-			if err := ename.WriteTo(bufw); err != nil {
-				return nil, lazyerrors.Error(err)
-			}
-			// skip calling WriteTo that does nothing
-
-		case types.Regex:
-			bufw.WriteByte(byte(tagRegex))
-			if err := ename.WriteTo(bufw); err != nil {
-				return nil, lazyerrors.Error(err)
-			}
-			if err := regexType(elV).WriteTo(bufw); err != nil {
-				return nil, lazyerrors.Error(err)
-			}
-
-		case int32:
-			bufw.WriteByte(byte(tagInt32))
-			if err := ename.WriteTo(bufw); err != nil {
-				return nil, lazyerrors.Error(err)
-			}
-			if err := int32Type(elV).WriteTo(bufw); err != nil {
-				return nil, lazyerrors.Error(err)
-			}
-
-		case types.Timestamp:
-			bufw.WriteByte(byte(tagTimestamp))
-			if err := ename.WriteTo(bufw); err != nil {
-				return nil, lazyerrors.Error(err)
-			}
-			if err := timestampType(elV).WriteTo(bufw); err != nil {
-				return nil, lazyerrors.Error(err)
-			}
-
-		case int64:
-			bufw.WriteByte(byte(tagInt64))
-			if err := ename.WriteTo(bufw); err != nil {
-				return nil, lazyerrors.Error(err)
-			}
-			if err := int64Type(elV).WriteTo(bufw); err != nil {
-				return nil, lazyerrors.Error(err)
-			}
-
-		default:
-			return nil, lazyerrors.Errorf("bson.Document.MarshalBinary: unhandled element type %T", elV)
+	b, err := appendDocument(nil, keys, values)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return b, nil
+}
+
+// appendDocument appends the encoded document with given keys and values to b and returns the extended buffer.
+//
+// It encodes nested documents and arrays directly into the same buffer.
+func appendDocument(b []byte, keys []string, values []any) ([]byte, error) {
+	start := len(b)
+
+	// length placeholder
+	b = append(b, 0, 0, 0, 0)
+
+	for i, key := range keys {
+		var err error
+		if b, err = appendElement(b, key, values[i]); err != nil {
+			return nil, lazyerrors.Error(err)
 		}
 	}
 
-	if err := bufw.Flush(); err != nil {
-		return nil, lazyerrors.Error(err)
+	b = append(b, 0)
+
+	binary.LittleEndian.PutUint32(b[start:], uint32(len(b)-start))
+
+	return b, nil
+}
+
+// appendElement appends the encoded element with the given key and value to b and returns the extended buffer.
+func appendElement(b []byte, key string, value any) ([]byte, error) {
+	var t tag
+
+	switch value.(type) {
+	case *types.Document:
+		t = tagDocument
+	case *types.Array:
+		t = tagArray
+	case float64:
+		t = tagDouble
+	case string:
+		t = tagString
+	case types.Binary:
+		t = tagBinary
+	case types.ObjectID:
+		t = tagObjectID
+	case bool:
+		t = tagBool
+	case time.Time:
+		t = tagDateTime
+	case types.NullType:
+		t = tagNull
+	case types.Regex:
+		t = tagRegex
+	case int32:
+		t = tagInt32
+	case types.Timestamp:
+		t = tagTimestamp
+	case int64:
+		t = tagInt64
+	default:
+		return nil, lazyerrors.Errorf("bson.Document.MarshalBinary: unhandled element type %T", value)
 	}
 
-	var res bytes.Buffer
-	l := int32(elist.Len() + 5)
-	binary.Write(&res, binary.LittleEndian, l)
-	must.NotFail(elist.WriteTo(&res))
-	res.WriteByte(0)
-	if int32(res.Len()) != l {
-		panic(fmt.Sprintf("got %d, expected %d", res.Len(), l))
+	b = append(b, byte(t))
+	b = append(b, key...)
+	b = append(b, 0)
+
+	switch v := value.(type) {
+	case *types.Document:
+		return appendDocument(b, v.Keys(), v.Values())
+
+	case *types.Array:
+		l := v.Len()
+		keys := make([]string, l)
+		values := make([]any, l)
+
+		for i := 0; i < l; i++ {
+			keys[i] = strconv.Itoa(i)
+			values[i], _ = v.Get(i)
+		}
+
+		return appendDocument(b, keys, values)
+
+	case float64:
+		b = binary.LittleEndian.AppendUint64(b, math.Float64bits(v))
+
+	case string:
+		b = binary.LittleEndian.AppendUint32(b, uint32(len(v)+1))
+		b = append(b, v...)
+		b = append(b, 0)
+
+	case types.Binary:
+		b = binary.LittleEndian.AppendUint32(b, uint32(len(v.B)))
+		b = append(b, byte(v.Subtype))
+		b = append(b, v.B...)
+
+	case types.ObjectID:
+		b = append(b, v[:]...)
+
+	case bool:
+		if v {
+			b = append(b, 1)
+		} else {
+			b = append(b, 0)
+		}
+
+	case time.Time:
+		b = binary.LittleEndian.AppendUint64(b, uint64(v.UnixMilli()))
+
+	case types.NullType:
+		// nothing
+
+	case types.Regex:
+		b = append(b, v.Pattern...)
+		b = append(b, 0)
+		b = append(b, v.Options...)
+		b = append(b, 0)
+
+	case int32:
+		b = binary.LittleEndian.AppendUint32(b, uint32(v))
+
+	case types.Timestamp:
+		b = binary.LittleEndian.AppendUint64(b, uint64(v))
+
+	case int64:
+		b = binary.LittleEndian.AppendUint64(b, uint64(v))
 	}
-	return res.Bytes(), nil
+
+	return b, nil
 }
 
 // check interfaces
@@ -0,0 +1,347 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bson
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// RawDocument represents a single BSON document in the binary encoded form.
+//
+// It is always decoded as a whole by Convert, directly from the underlying bytes,
+// without intermediate readers, buffers, or copies of nested documents.
+// It is used only for reading documents from the wire, such as OP_MSG sections;
+// handlers and backends still work with fully decoded *types.Document values.
+type RawDocument []byte
+
+// ReadRawDocument reads a single BSON document from the reader without decoding it.
+//
+// Only the document length is validated.
+func ReadRawDocument(r *bufio.Reader) (RawDocument, error) {
+	var lb [4]byte
+	if _, err := io.ReadFull(r, lb[:]); err != nil {
+		return nil, lazyerrors.Errorf("bson.ReadRawDocument (io.ReadFull): %w", err)
+	}
+
+	l := int32(binary.LittleEndian.Uint32(lb[:]))
+	if l < minDocumentLen || l > types.MaxDocumentLen {
+		return nil, lazyerrors.Errorf("bson.ReadRawDocument: invalid length %d", l)
+	}
+
+	b := make([]byte, l)
+	copy(b, lb[:])
+
+	if n, err := io.ReadFull(r, b[4:]); err != nil {
+		return nil, lazyerrors.Errorf("bson.ReadRawDocument (io.ReadFull, expected %d, read %d): %w", len(b), n, err)
+	}
+
+	return b, nil
+}
+
+// CutRawDocument slices a single BSON document from the beginning of b without copying or decoding it.
+// It returns that document and the rest of b.
+//
+// Only the document length is validated.
+func CutRawDocument(b []byte) (RawDocument, []byte, error) {
+	l, err := documentLen(b)
+	if err != nil {
+		return nil, nil, lazyerrors.Error(err)
+	}
+
+	return RawDocument(b[:l:l]), b[l:], nil
+}
+
+// Convert decodes the whole document.
+//
+// Binary values reference the memory of raw, so it should not be modified after that.
+func (raw RawDocument) Convert() (*types.Document, error) {
+	fields, err := raw.decode(0)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	doc, err := types.ConvertDocument(&Document{fields: fields})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return doc, nil
+}
+
+// elist validates document's length and terminating zero, and returns document's elements list.
+func (raw RawDocument) elist() ([]byte, error) {
+	l, err := documentLen(raw)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if l != len(raw) {
+		return nil, lazyerrors.Errorf("bson.RawDocument: length %d, expected %d", len(raw), l)
+	}
+
+	if raw[l-1] != 0 {
+		return nil, lazyerrors.Errorf("bson.RawDocument: unexpected terminating byte %#02x", raw[l-1])
+	}
+
+	return raw[4 : l-1], nil
+}
+
+// decode decodes all document's fields.
+func (raw RawDocument) decode(nesting int) ([]field, error) {
+	if nesting > maxNesting {
+		return nil, lazyerrors.Errorf("bson.RawDocument.decode: document has exceeded the max supported nesting: %d", maxNesting)
+	}
+
+	elist, err := raw.elist()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	fields := make([]field, 0, 8)
+
+	for len(elist) > 0 {
+		var e element
+		if e, elist, err = nextElement(elist); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		v, err := decodeValue(e.t, e.value, nesting+1)
+		if err != nil {
+			return nil, lazyerrors.Errorf("bson.RawDocument.decode (%s): %w", e.t, err)
+		}
+
+		fields = append(fields, field{key: string(e.key), value: v})
+	}
+
+	return fields, nil
+}
+
+// element represents a single encoded element of the document.
+type element struct {
+	key   []byte
+	value []byte
+	t     tag
+}
+
+// nextElement slices the first element from the given elements list and returns it and the rest of the list.
+func nextElement(elist []byte) (element, []byte, error) {
+	var e element
+
+	e.t = tag(elist[0])
+	if e.t == 0 {
+		return e, nil, lazyerrors.New("unexpected end of the document")
+	}
+
+	key, rest, ok := bytes.Cut(elist[1:], []byte{0})
+	if !ok {
+		return e, nil, lazyerrors.Errorf("bson.nextElement: %w", io.ErrUnexpectedEOF)
+	}
+
+	e.key = key
+
+	l, err := valueLen(e.t, rest)
+	if err != nil {
+		return e, nil, lazyerrors.Error(err)
+	}
+
+	e.value = rest[:l:l]
+
+	return e, rest[l:], nil
+}
+
+// documentLen returns the length of the encoded document or array at the beginning of b.
+func documentLen(b []byte) (int, error) {
+	if len(b) < 4 {
+		return 0, lazyerrors.Errorf("bson.documentLen: %w", io.ErrUnexpectedEOF)
+	}
+
+	l := int32(binary.LittleEndian.Uint32(b))
+	if l < minDocumentLen || l > types.MaxDocumentLen {
+		return 0, lazyerrors.Errorf("bson.documentLen: invalid length %d", l)
+	}
+
+	if int(l) > len(b) {
+		return 0, lazyerrors.Errorf("bson.documentLen (expected %d, got %d): %w", l, len(b), io.ErrUnexpectedEOF)
+	}
+
+	return int(l), nil
+}
+
+// valueLen returns the length of the encoded value of the given type at the beginning of b.
+func valueLen(t tag, b []byte) (int, error) {
+	var l int
+
+	switch t {
+	case tagDocument, tagArray:
+		return documentLen(b)
+
+	case tagString:
+		if len(b) < 4 {
+			return 0, lazyerrors.Errorf("bson.valueLen (String): %w", io.ErrUnexpectedEOF)
+		}
+
+		sl := int32(binary.LittleEndian.Uint32(b))
+		if sl <= 0 {
+			return 0, lazyerrors.Errorf("bson.valueLen (String): invalid length %d", sl)
+		}
+
+		l = 4 + int(sl)
+
+	case tagBinary:
+		if len(b) < 4 {
+			return 0, lazyerrors.Errorf("bson.valueLen (Binary): %w", io.ErrUnexpectedEOF)
+		}
+
+		bl := int32(binary.LittleEndian.Uint32(b))
+		if bl < 0 {
+			return 0, lazyerrors.Errorf("bson.valueLen (Binary): invalid length %d", bl)
+		}
+
+		l = 5 + int(bl)
+
+	case tagRegex:
+		pattern := bytes.IndexByte(b, 0)
+		if pattern < 0 {
+			return 0, lazyerrors.Errorf("bson.valueLen (Regex): %w", io.ErrUnexpectedEOF)
+		}
+
+		options := bytes.IndexByte(b[pattern+1:], 0)
+		if options < 0 {
+			return 0, lazyerrors.Errorf("bson.valueLen (Regex): %w", io.ErrUnexpectedEOF)
+		}
+
+		l = pattern + options + 2
+
+	case tagDouble, tagDateTime, tagTimestamp, tagInt64:
+		l = 8
+	case tagInt32:
+		l = 4
+	case tagObjectID:
+		l = 12
+	case tagBool:
+		l = 1
+	case tagNull:
+		l = 0
+
+	case tagUndefined:
+		return 0, lazyerrors.Errorf("bson.valueLen: unhandled element type `Undefined (value) Deprecated`")
+	default:
+		return 0, lazyerrors.Errorf("bson.valueLen: unhandled element type %#02x (%s)", byte(t), t)
+	}
+
+	if l > len(b) {
+		return 0, lazyerrors.Errorf("bson.valueLen (%s, expected %d, got %d): %w", t, l, len(b), io.ErrUnexpectedEOF)
+	}
+
+	return l, nil
+}
+
+// decodeValue decodes the given encoded value of the given type.
+// The length of b is already checked by valueLen.
+func decodeValue(t tag, b []byte, nesting int) (any, error) {
+	switch t {
+	case tagDocument:
+		fields, err := RawDocument(b).decode(nesting)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		return types.ConvertDocument(&Document{fields: fields})
+
+	case tagArray:
+		fields, err := RawDocument(b).decode(nesting)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		a := types.MakeArray(len(fields))
+
+		for i, f := range fields {
+			if f.key != strconv.Itoa(i) {
+				return nil, lazyerrors.Errorf("key %d is %q", i, f.key)
+			}
+
+			a.Append(f.value)
+		}
+
+		return a, nil
+
+	case tagDouble:
+		return math.Float64frombits(binary.LittleEndian.Uint64(b)), nil
+
+	case tagString:
+		if b[len(b)-1] != 0 {
+			return nil, lazyerrors.Errorf("unexpected terminating byte %#02x", b[len(b)-1])
+		}
+
+		return string(b[4 : len(b)-1]), nil
+
+	case tagBinary:
+		return types.Binary{
+			Subtype: types.BinarySubtype(b[4]),
+			B:       b[5:],
+		}, nil
+
+	case tagObjectID:
+		return types.ObjectID(b), nil
+
+	case tagBool:
+		switch b[0] {
+		case 0:
+			return false, nil
+		case 1:
+			return true, nil
+		default:
+			return nil, lazyerrors.Errorf("bson.decodeValue (Bool): unexpected byte %#02x", b[0])
+		}
+
+	case tagDateTime:
+		// Use .UTC()
+		// TODO https://github.com/FerretDB/FerretDB/issues/43
+		return time.UnixMilli(int64(binary.LittleEndian.Uint64(b))), nil
+
+	case tagNull:
+		return types.Null, nil
+
+	case tagRegex:
+		pattern, options, _ := bytes.Cut(b[:len(b)-1], []byte{0})
+
+		return types.Regex{
+			Pattern: string(pattern),
+			Options: string(options),
+		}, nil
+
+	case tagInt32:
+		return int32(binary.LittleEndian.Uint32(b)), nil
+
+	case tagTimestamp:
+		return types.Timestamp(binary.LittleEndian.Uint64(b)), nil
+
+	case tagInt64:
+		return int64(binary.LittleEndian.Uint64(b)), nil
+
+	default:
+		return nil, lazyerrors.Errorf("bson.decodeValue: unhandled element type %#02x (%s)", byte(t), t)
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bson
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+func TestRawDocument(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range documentTestCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			raw, rest, err := CutRawDocument(tc.b)
+			if tc.bErr != "" {
+				require.Error(t, err)
+				require.Equal(t, tc.bErr, lastErr(err).Error())
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Empty(t, rest)
+
+			expected, err := types.ConvertDocument(tc.v.(*Document))
+			require.NoError(t, err)
+
+			actual, err := raw.Convert()
+			require.NoError(t, err)
+			assert.Equal(t, expected, actual)
+		})
+	}
+}
+
+func TestCutRawDocument(t *testing.T) {
+	t.Parallel()
+
+	b := append(append([]byte{}, handshake3.b...), handshake1.b...)
+
+	raw, rest, err := CutRawDocument(b)
+	require.NoError(t, err)
+	assert.Equal(t, RawDocument(handshake3.b), raw)
+	assert.Equal(t, handshake1.b, rest)
+
+	raw, rest, err = CutRawDocument(rest)
+	require.NoError(t, err)
+	assert.Equal(t, RawDocument(handshake1.b), raw)
+	assert.Empty(t, rest)
+
+	_, _, err = CutRawDocument(handshake1.b[:len(handshake1.b)-1])
+	require.Error(t, err)
+
+	// elements must not go beyond the document
+	truncated := append([]byte{}, handshake3.b...)
+	truncated[0]--
+	_, err = RawDocument(truncated[:len(truncated)-1]).Convert()
+	require.Error(t, err)
+}
+
+func BenchmarkRawDocumentConvert(b *testing.B) {
+	raw := RawDocument(handshake4.b)
+
+	var err error
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(raw)))
+
+	for i := 0; i < b.N; i++ {
+		_, err = raw.Convert()
+	}
+
+	b.StopTimer()
+
+	require.NoError(b, err)
+}
@@ -36,6 +36,8 @@ import (
 
 	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
 	"github.com/FerretDB/FerretDB/internal/clientconn/connmetrics"
+	"github.com/FerretDB/FerretDB/internal/clientconn/scheduler"
+	"github.com/FerretDB/FerretDB/internal/clientconn/session"
 	"github.com/FerretDB/FerretDB/internal/handlers"
 	"github.com/FerretDB/FerretDB/internal/handlers/commoncommands"
 	"github.com/FerretDB/FerretDB/internal/handlers/commonerrors"
@@ -78,8 +80,12 @@ type conn struct {
 	l              *zap.SugaredLogger
 	h              handlers.Interface
 	m              *connmetrics.ConnMetrics
+	connRegistry   *conninfo.Registry
+	sessions       *session.Registry
+	sched          *scheduler.Conn
 	proxy          *proxy.Router
 	lastRequestID  atomic.Int32
+	requireAuth    bool
 	testRecordsDir string // if empty, no records are created
 }
 
@@ -90,7 +96,11 @@ type newConnOpts struct {
 	l              *zap.Logger
 	handler        handlers.Interface
 	connMetrics    *connmetrics.ConnMetrics
+	connRegistry   *conninfo.Registry
+	sessions       *session.Registry    // if nil, sessions are not tracked
+	scheduler      *scheduler.Scheduler // if nil, operations are not scheduled
 	proxyAddr      string
+	requireAuth    bool   // if true, commands of unauthenticated clients are rejected
 	testRecordsDir string // if empty, no records are created
 }
 
@@ -102,6 +112,9 @@ func newConn(opts *newConnOpts) (*conn, error) {
 	if opts.handler == nil {
 		panic("handler required")
 	}
+	if opts.connRegistry == nil {
+		panic("connRegistry required")
+	}
 
 	var p *proxy.Router
 	if opts.mode != NormalMode {
@@ -111,13 +124,22 @@ func newConn(opts *newConnOpts) (*conn, error) {
 		}
 	}
 
+	var sched *scheduler.Conn
+	if opts.scheduler != nil {
+		sched = opts.scheduler.NewConn()
+	}
+
 	return &conn{
 		netConn:        opts.netConn,
 		mode:           opts.mode,
 		l:              opts.l.Sugar(),
 		h:              opts.handler,
 		m:              opts.connMetrics,
+		connRegistry:   opts.connRegistry,
+		sessions:       opts.sessions,
+		sched:          sched,
 		proxy:          p,
+		requireAuth:    opts.requireAuth,
 		testRecordsDir: opts.testRecordsDir,
 	}, nil
 }
@@ -139,6 +161,11 @@ func (c *conn) run(ctx context.Context) (err error) {
 		connInfo.PeerAddr = c.netConn.RemoteAddr().String()
 	}
 
+	connInfo.LocalAddr = c.netConn.LocalAddr().String()
+
+	c.connRegistry.Add(connInfo)
+	defer c.connRegistry.Remove(connInfo)
+
 	ctx = conninfo.Ctx(ctx, connInfo)
 
 	done := make(chan struct{})
@@ -417,6 +444,12 @@ func (c *conn) route(ctx context.Context, reqHeader *wire.MsgHeader, reqBody wir
 
 		resHeader.OpCode = wire.OpCodeMsg
 
+		if err == nil && c.sessions != nil {
+			if id, ok := session.GetID(document); ok {
+				c.sessions.Touch(id)
+			}
+		}
+
 		if err == nil {
 			// do not store typed nil in interface, it makes it non-nil
 
@@ -552,12 +585,59 @@ func (c *conn) route(ctx context.Context, reqHeader *wire.MsgHeader, reqBody wir
 	return
 }
 
+// scheduled returns true if the given command should wait for its turn in the operation scheduler.
+//
+// getMore commands with maxTimeMS (sent by drivers only for awaitData cursors) could wait for new documents
+// for a long time, so they do not take a slot.
+func scheduled(command string, msg *wire.OpMsg) bool {
+	cmd := commoncommands.Commands[command]
+	if cmd.Anonymous || cmd.Unscheduled {
+		return false
+	}
+
+	if command != "getMore" {
+		return true
+	}
+
+	doc, err := msg.Document()
+	if err != nil {
+		return true
+	}
+
+	return !doc.Has("maxTimeMS")
+}
+
 // handleOpMsg processes OP_MSG request.
 //
 // The passed context is canceled when the client disconnects.
 func (c *conn) handleOpMsg(ctx context.Context, msg *wire.OpMsg, command string) (*wire.OpMsg, error) {
 	if cmd, ok := commoncommands.Commands[command]; ok {
+		if c.requireAuth && !cmd.Anonymous {
+			if !conninfo.Get(ctx).Verified() {
+				errMsg := fmt.Sprintf("Command %s requires authentication", command)
+				return nil, commonerrors.NewCommandErrorMsg(commonerrors.ErrUnauthorized, errMsg)
+			}
+		}
+
 		if cmd.Handler != nil {
+			if c.sched != nil && scheduled(command, msg) {
+				release, err := c.sched.Acquire(ctx)
+				if errors.Is(err, scheduler.ErrBusy) {
+					errMsg := fmt.Sprintf("Server is busy, %s; retry later", err)
+
+					// drivers retry writes only with that label
+					return nil, commonerrors.NewCommandErrorMsgWithLabels(
+						commonerrors.ErrExceededTimeLimit, errMsg, "RetryableWriteError",
+					)
+				}
+
+				if err != nil {
+					return nil, lazyerrors.Error(err)
+				}
+
+				defer release()
+			}
+
 			defer observability.FuncCall(ctx)()
 
 			defer pprof.SetGoroutineLabels(ctx)
@@ -0,0 +1,112 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientconn
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/internal/clientconn/scheduler"
+	"github.com/FerretDB/FerretDB/internal/handlers/commonerrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/wire"
+)
+
+func TestConnRequireAuth(t *testing.T) {
+	t.Parallel()
+
+	c := &conn{requireAuth: true}
+	connInfo := conninfo.New()
+	ctx := conninfo.Ctx(context.Background(), connInfo)
+
+	var msg wire.OpMsg
+	must.NoError(msg.SetSections(wire.OpMsgSection{
+		Documents: []*types.Document{must.NotFail(types.NewDocument("find", "test", "$db", "test"))},
+	}))
+
+	res, err := c.handleOpMsg(ctx, &msg, "find")
+	assert.Nil(t, res)
+
+	expected := commonerrors.NewCommandErrorMsg(commonerrors.ErrUnauthorized, "Command find requires authentication")
+	require.Equal(t, expected, err)
+
+	// credentials that were not verified by the backend are not enough
+	connInfo.SetAuth("user", "password")
+
+	res, err = c.handleOpMsg(ctx, &msg, "find")
+	assert.Nil(t, res)
+	require.Equal(t, expected, err)
+}
+
+func TestConnSchedulerBusy(t *testing.T) {
+	t.Parallel()
+
+	s := scheduler.New(1, 0)
+	c := &conn{sched: s.NewConn()}
+	ctx := conninfo.Ctx(context.Background(), conninfo.New())
+
+	release, err := s.NewConn().Acquire(ctx)
+	require.NoError(t, err)
+
+	defer release()
+
+	var msg wire.OpMsg
+	must.NoError(msg.SetSections(wire.OpMsgSection{
+		Documents: []*types.Document{must.NotFail(types.NewDocument("find", "test", "$db", "test"))},
+	}))
+
+	res, err := c.handleOpMsg(ctx, &msg, "find")
+	assert.Nil(t, res)
+
+	expected := commonerrors.NewCommandErrorMsgWithLabels(
+		commonerrors.ErrExceededTimeLimit,
+		"Server is busy, too many operations are queued; retry later",
+		"RetryableWriteError",
+	)
+	require.Equal(t, expected, err)
+
+	var ce *commonerrors.CommandError
+	require.ErrorAs(t, err, &ce)
+
+	labels := must.NotFail(ce.Document().Get("errorLabels"))
+	assert.Equal(t, must.NotFail(types.NewArray("RetryableWriteError")), labels)
+}
+
+func TestConnScheduled(t *testing.T) {
+	t.Parallel()
+
+	getMore := func(pairs ...any) *wire.OpMsg {
+		var msg wire.OpMsg
+		must.NoError(msg.SetSections(wire.OpMsgSection{
+			Documents: []*types.Document{must.NotFail(types.NewDocument(pairs...))},
+		}))
+
+		return &msg
+	}
+
+	// awaitData getMore does not take a slot while waiting for new documents
+	msg := getMore("getMore", int64(1), "collection", "test", "maxTimeMS", int32(1000), "$db", "test")
+	assert.False(t, scheduled("getMore", msg))
+
+	msg = getMore("getMore", int64(1), "collection", "test", "$db", "test")
+	assert.True(t, scheduled("getMore", msg))
+
+	assert.False(t, scheduled("hello", msg))
+}
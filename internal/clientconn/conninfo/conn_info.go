@@ -18,6 +18,9 @@ package conninfo
 import (
 	"context"
 	"sync"
+	"time"
+
+	"github.com/FerretDB/FerretDB/internal/types"
 )
 
 // contextKey is a named unexported type for the safe use of context.WithValue.
@@ -28,17 +31,24 @@ var connInfoKey = contextKey{}
 
 // ConnInfo represents connection info.
 type ConnInfo struct {
-	PeerAddr string
-
-	rw           sync.RWMutex
-	username     string
-	password     string
-	metadataRecv bool
+	ID        int32 // set by Registry.Add, zero if connection is not registered
+	PeerAddr  string
+	LocalAddr string
+	Connected time.Time
+
+	rw             sync.RWMutex
+	username       string
+	password       string
+	verified       bool
+	metadataRecv   bool
+	clientMetadata *types.Document
 }
 
 // New returns a new ConnInfo.
 func New() *ConnInfo {
-	return new(ConnInfo)
+	return &ConnInfo{
+		Connected: time.Now(),
+	}
 }
 
 // Auth returns stored username and password.
@@ -50,12 +60,31 @@ func (connInfo *ConnInfo) Auth() (username, password string) {
 }
 
 // SetAuth stores username and password.
+//
+// Stored credentials are not verified until SetVerified is called.
 func (connInfo *ConnInfo) SetAuth(username, password string) {
 	connInfo.rw.Lock()
 	defer connInfo.rw.Unlock()
 
 	connInfo.username = username
 	connInfo.password = password
+	connInfo.verified = false
+}
+
+// Verified returns true if stored credentials were verified by the backend.
+func (connInfo *ConnInfo) Verified() bool {
+	connInfo.rw.RLock()
+	defer connInfo.rw.RUnlock()
+
+	return connInfo.verified
+}
+
+// SetVerified marks stored credentials as verified by the backend.
+func (connInfo *ConnInfo) SetVerified() {
+	connInfo.rw.Lock()
+	defer connInfo.rw.Unlock()
+
+	connInfo.verified = true
 }
 
 // MetadataRecv returns whatever client metadata was received already.
@@ -74,6 +103,23 @@ func (connInfo *ConnInfo) SetMetadataRecv() {
 	connInfo.metadataRecv = true
 }
 
+// ClientMetadata returns client metadata document sent in the handshake, or nil.
+func (connInfo *ConnInfo) ClientMetadata() *types.Document {
+	connInfo.rw.RLock()
+	defer connInfo.rw.RUnlock()
+
+	return connInfo.clientMetadata
+}
+
+// SetClientMetadata stores client metadata document sent in the handshake.
+// The caller should not modify it after that.
+func (connInfo *ConnInfo) SetClientMetadata(md *types.Document) {
+	connInfo.rw.Lock()
+	defer connInfo.rw.Unlock()
+
+	connInfo.clientMetadata = md
+}
+
 // Ctx returns a derived context with the given ConnInfo.
 func Ctx(ctx context.Context, connInfo *ConnInfo) context.Context {
 	return context.WithValue(ctx, connInfoKey, connInfo)
@@ -0,0 +1,190 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conninfo
+
+import (
+	"cmp"
+	"encoding/json"
+	"math"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// Registry stores information about all client connections.
+//
+// It implements http.Handler that returns the information in JSON format for the debug handler.
+type Registry struct {
+	rw     sync.RWMutex
+	conns  map[int32]*ConnInfo
+	lastID int32
+}
+
+// NewRegistry returns a new registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		conns: map[int32]*ConnInfo{},
+	}
+}
+
+// Add assigns a new ID to the given connection info and stores it until Remove is called.
+//
+// IDs are positive int32 values, as clients expect for `connectionId`;
+// after the last one, they start from 1 again, skipping IDs of connections that are still open.
+func (r *Registry) Add(connInfo *ConnInfo) {
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	for {
+		if r.lastID == math.MaxInt32 {
+			r.lastID = 0
+		}
+
+		r.lastID++
+
+		if _, ok := r.conns[r.lastID]; !ok {
+			break
+		}
+	}
+
+	connInfo.ID = r.lastID
+	r.conns[connInfo.ID] = connInfo
+}
+
+// Remove removes the given connection info from the registry.
+func (r *Registry) Remove(connInfo *ConnInfo) {
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	delete(r.conns, connInfo.ID)
+}
+
+// All returns all registered connections ordered by ID.
+func (r *Registry) All() []*ConnInfo {
+	r.rw.RLock()
+	defer r.rw.RUnlock()
+
+	res := make([]*ConnInfo, 0, len(r.conns))
+	for _, connInfo := range r.conns {
+		res = append(res, connInfo)
+	}
+
+	slices.SortFunc(res, func(a, b *ConnInfo) int { return cmp.Compare(a.ID, b.ID) })
+
+	return res
+}
+
+// Client represents the client application and driver extracted from the client metadata.
+type Client struct {
+	Application   string `json:"application,omitempty"`
+	DriverName    string `json:"driverName,omitempty"`
+	DriverVersion string `json:"driverVersion,omitempty"`
+}
+
+// Client returns the client application and driver for that connection.
+// All fields are empty if client metadata was not received.
+func (connInfo *ConnInfo) Client() Client {
+	md := connInfo.ClientMetadata()
+	if md == nil {
+		return Client{}
+	}
+
+	get := func(path ...string) string {
+		v, _ := md.GetByPath(types.NewStaticPath(path...))
+		s, _ := v.(string)
+		return s
+	}
+
+	return Client{
+		Application:   get("application", "name"),
+		DriverName:    get("driver", "name"),
+		DriverVersion: get("driver", "version"),
+	}
+}
+
+// ServeHTTP implements http.Handler.
+//
+// It returns all connections and the number of connections for each client.
+func (r *Registry) ServeHTTP(rw http.ResponseWriter, _ *http.Request) {
+	type connection struct {
+		ID        int32     `json:"id"`
+		PeerAddr  string    `json:"peerAddr,omitempty"`
+		LocalAddr string    `json:"localAddr,omitempty"`
+		Connected time.Time `json:"connected"`
+		Username  string    `json:"username,omitempty"`
+		Client
+	}
+
+	type client struct {
+		Client
+		Connections int `json:"connections"`
+	}
+
+	res := struct {
+		Connections []connection `json:"connections"`
+		Clients     []client     `json:"clients"`
+	}{
+		Connections: []connection{},
+		Clients:     []client{},
+	}
+
+	counts := map[Client]int{}
+
+	for _, connInfo := range r.All() {
+		username, _ := connInfo.Auth()
+		c := connInfo.Client()
+
+		res.Connections = append(res.Connections, connection{
+			ID:        connInfo.ID,
+			PeerAddr:  connInfo.PeerAddr,
+			LocalAddr: connInfo.LocalAddr,
+			Connected: connInfo.Connected,
+			Username:  username,
+			Client:    c,
+		})
+
+		counts[c]++
+	}
+
+	for c, n := range counts {
+		res.Clients = append(res.Clients, client{Client: c, Connections: n})
+	}
+
+	// the most active clients first
+	slices.SortFunc(res.Clients, func(a, b client) int {
+		if c := cmp.Compare(b.Connections, a.Connections); c != 0 {
+			return c
+		}
+
+		return cmp.Compare(a.Application+a.DriverName+a.DriverVersion, b.Application+b.DriverName+b.DriverVersion)
+	})
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	enc := json.NewEncoder(rw)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(res); err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// check interfaces
+var (
+	_ http.Handler = (*Registry)(nil)
+)
@@ -0,0 +1,102 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conninfo
+
+import (
+	"encoding/json"
+	"math"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry()
+
+	metadata := func(app, version string) *types.Document {
+		return must.NotFail(types.NewDocument(
+			"application", must.NotFail(types.NewDocument("name", app)),
+			"driver", must.NotFail(types.NewDocument("name", "nodejs", "version", version)),
+		))
+	}
+
+	conns := make([]*ConnInfo, 4)
+	for i := range conns {
+		conns[i] = New()
+		r.Add(conns[i])
+	}
+
+	conns[0].SetClientMetadata(metadata("app1", "6.0.0"))
+	conns[1].SetClientMetadata(metadata("app2", "5.9.0"))
+	conns[2].SetClientMetadata(metadata("app2", "5.9.0"))
+
+	r.Remove(conns[3])
+
+	assert.Equal(t, conns[:3], r.All())
+	assert.Equal(t, Client{Application: "app2", DriverName: "nodejs", DriverVersion: "5.9.0"}, conns[1].Client())
+	assert.Equal(t, Client{}, conns[3].Client())
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/debug/connections", nil))
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var res struct {
+		Connections []struct {
+			ID int32 `json:"id"`
+		} `json:"connections"`
+		Clients []struct {
+			Client
+			Connections int `json:"connections"`
+		} `json:"clients"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+
+	require.Len(t, res.Connections, 3)
+	assert.Equal(t, conns[2].ID, res.Connections[2].ID)
+
+	require.Len(t, res.Clients, 2)
+	assert.Equal(t, "app2", res.Clients[0].Application)
+	assert.Equal(t, 2, res.Clients[0].Connections)
+	assert.Equal(t, "app1", res.Clients[1].Application)
+	assert.Equal(t, 1, res.Clients[1].Connections)
+}
+
+func TestRegistryIDWrap(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry()
+
+	first := New()
+	r.Add(first)
+	require.Equal(t, int32(1), first.ID)
+
+	r.lastID = math.MaxInt32 - 1
+
+	last := New()
+	r.Add(last)
+	assert.Equal(t, int32(math.MaxInt32), last.ID)
+
+	// IDs of open connections are skipped
+	wrapped := New()
+	r.Add(wrapped)
+	assert.Equal(t, int32(2), wrapped.ID)
+}
@@ -17,9 +17,11 @@ package cursor
 
 import (
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
 	"github.com/FerretDB/FerretDB/internal/util/resource"
 )
 
@@ -49,7 +51,11 @@ type Cursor struct {
 	Collection string
 	Username   string
 	ID         int64
+	lastUsed   atomic.Int64 // Unix time in nanoseconds
+	nextM      sync.Mutex   // serializes Next calls
+	m          sync.Mutex   // protects iter and iterating
 	closeOnce  sync.Once
+	iterating  bool // iter.Next is running
 }
 
 // newCursor creates a new cursor.
@@ -66,21 +72,68 @@ func newCursor(id int64, db, collection, username string, iter types.DocumentsIt
 		token:      resource.NewToken(),
 	}
 
+	c.lastUsed.Store(c.created.UnixNano())
+
 	resource.Track(c, c.token)
 
 	return c
 }
 
 // Next implements types.DocumentsIterator interface.
+//
+// The underlying iterator is called without holding the lock,
+// so a slow iteration does not block closing the cursor.
 func (c *Cursor) Next() (struct{}, *types.Document, error) {
-	return c.iter.Next()
+	c.nextM.Lock()
+	defer c.nextM.Unlock()
+
+	c.lastUsed.Store(time.Now().UnixNano())
+
+	c.m.Lock()
+	iter := c.iter
+	c.iterating = iter != nil
+	c.m.Unlock()
+
+	if iter == nil {
+		return struct{}{}, nil, iterator.ErrIteratorDone
+	}
+
+	_, doc, err := iter.Next()
+
+	c.lastUsed.Store(time.Now().UnixNano())
+
+	c.m.Lock()
+	c.iterating = false
+	closed := c.iter == nil
+	c.m.Unlock()
+
+	// the cursor was closed during iteration and left the iterator for us
+	if closed {
+		iter.Close()
+	}
+
+	return struct{}{}, doc, err
+}
+
+// LastUsed returns the time when the cursor was created or last iterated.
+func (c *Cursor) LastUsed() time.Time {
+	return time.Unix(0, c.lastUsed.Load())
 }
 
 // Close implements types.DocumentsIterator interface.
+//
+// If Next is running, the underlying iterator is closed when it returns.
 func (c *Cursor) Close() {
 	c.closeOnce.Do(func() {
-		c.iter.Close()
+		c.m.Lock()
+		iter := c.iter
 		c.iter = nil
+		iterating := c.iterating
+		c.m.Unlock()
+
+		if !iterating {
+			iter.Close()
+		}
 
 		c.r.delete(c)
 
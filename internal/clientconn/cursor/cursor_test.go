@@ -14,8 +14,115 @@
 
 package cursor
 
-import "testing"
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+)
 
 func TestDummy(t *testing.T) {
 	// we need at least one test per package to correctly calculate coverage
 }
+
+func TestCloseIdle(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Ctx(t)
+
+	r := NewRegistry(testutil.Logger(t))
+	t.Cleanup(r.Close)
+
+	newIter := func() types.DocumentsIterator {
+		return iterator.Values(iterator.ForSlice([]*types.Document{
+			must.NotFail(types.NewDocument("v", int32(1))),
+			must.NotFail(types.NewDocument("v", int32(2))),
+		}))
+	}
+
+	idle := r.NewCursor(ctx, &NewParams{Iter: newIter(), DB: "db", Collection: "idle"})
+	used := r.NewCursor(ctx, &NewParams{Iter: newIter(), DB: "db", Collection: "used"})
+
+	time.Sleep(100 * time.Millisecond)
+
+	_, _, err := used.Next()
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, r.CloseIdle(50*time.Millisecond))
+	assert.Nil(t, r.Get(idle.ID))
+	assert.Equal(t, used, r.Get(used.ID))
+
+	_, _, err = idle.Next()
+	require.ErrorIs(t, err, iterator.ErrIteratorDone)
+
+	used.Close()
+	assert.Empty(t, r.All())
+}
+
+// blockingIterator returns a single document when released and records whether it was closed.
+type blockingIterator struct {
+	started chan struct{}
+	release chan struct{}
+	closed  atomic.Bool
+}
+
+// Next implements types.DocumentsIterator interface.
+func (iter *blockingIterator) Next() (struct{}, *types.Document, error) {
+	close(iter.started)
+	<-iter.release
+
+	return struct{}{}, must.NotFail(types.NewDocument("v", int32(1))), nil
+}
+
+// Close implements types.DocumentsIterator interface.
+func (iter *blockingIterator) Close() {
+	iter.closed.Store(true)
+}
+
+func TestCloseDuringNext(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Ctx(t)
+
+	r := NewRegistry(testutil.Logger(t))
+	t.Cleanup(r.Close)
+
+	iter := &blockingIterator{
+		started: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+
+	c := r.NewCursor(ctx, &NewParams{Iter: iter, DB: "db", Collection: "coll"})
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		_, doc, err := c.Next()
+		assert.NoError(t, err)
+		assert.NotNil(t, doc)
+	}()
+
+	<-iter.started
+
+	// should not wait for Next
+	c.Close()
+	assert.Nil(t, r.Get(c.ID))
+	assert.False(t, iter.closed.Load())
+
+	close(iter.release)
+	<-done
+
+	assert.True(t, iter.closed.Load())
+
+	_, _, err := c.Next()
+	require.ErrorIs(t, err, iterator.ErrIteratorDone)
+}
@@ -171,6 +171,29 @@ func (r *Registry) All() []*Cursor {
 	return maps.Values(r.m)
 }
 
+// CloseIdle closes cursors that were not used for longer than the given timeout.
+// It returns the number of closed cursors.
+func (r *Registry) CloseIdle(timeout time.Duration) int {
+	var idle []*Cursor
+
+	r.rw.RLock()
+
+	for _, c := range r.m {
+		if time.Since(c.LastUsed()) > timeout {
+			idle = append(idle, c)
+		}
+	}
+
+	r.rw.RUnlock()
+
+	for _, c := range idle {
+		r.l.Debug("Closing idle cursor", zap.Int64("id", c.ID), zap.Time("last_used", c.LastUsed()))
+		c.Close()
+	}
+
+	return len(idle)
+}
+
 // This method should be called only from cursor.Close().
 func (r *Registry) delete(c *Cursor) {
 	r.rw.Lock()
@@ -24,13 +24,17 @@ import (
 	"net"
 	"os"
 	"runtime/pprof"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
 	"github.com/FerretDB/FerretDB/internal/clientconn/connmetrics"
+	"github.com/FerretDB/FerretDB/internal/clientconn/scheduler"
+	"github.com/FerretDB/FerretDB/internal/clientconn/session"
 	"github.com/FerretDB/FerretDB/internal/handlers"
 	"github.com/FerretDB/FerretDB/internal/util/ctxutil"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
@@ -42,13 +46,14 @@ import (
 type Listener struct {
 	*NewListenerOpts
 
-	tcpListener  net.Listener
-	unixListener net.Listener
-	tlsListener  net.Listener
+	listeners      []*listener
+	listenersReady chan struct{}
+}
 
-	tcpListenerReady  chan struct{}
-	unixListenerReady chan struct{}
-	tlsListenerReady  chan struct{}
+// listener represents a single network listener with its configuration.
+type listener struct {
+	net.Listener
+	config *ListenerConfig
 }
 
 // NewListenerOpts represents listener configuration.
@@ -60,9 +65,15 @@ type NewListenerOpts struct {
 	TLSKeyFile  string
 	TLSCAFile   string
 
+	// Listeners are configured in addition to TCP, Unix and TLS listeners above.
+	Listeners []*ListenerConfig
+
 	ProxyAddr      string
 	Mode           Mode
 	Metrics        *connmetrics.ListenerMetrics
+	ConnRegistry   *conninfo.Registry   // if nil, a new registry is created
+	Sessions       *session.Registry    // if nil, sessions are not tracked
+	Scheduler      *scheduler.Scheduler // if nil, operations are not scheduled
 	Handler        handlers.Interface
 	Logger         *zap.Logger
 	TestRecordsDir string // if empty, no records are created
@@ -70,12 +81,39 @@ type NewListenerOpts struct {
 
 // NewListener returns a new listener, configured by the NewListenerOpts argument.
 func NewListener(opts *NewListenerOpts) *Listener {
+	if opts.ConnRegistry == nil {
+		opts.ConnRegistry = conninfo.NewRegistry()
+	}
+
 	return &Listener{
-		NewListenerOpts:   opts,
-		tcpListenerReady:  make(chan struct{}),
-		unixListenerReady: make(chan struct{}),
-		tlsListenerReady:  make(chan struct{}),
+		NewListenerOpts: opts,
+		listenersReady:  make(chan struct{}),
+	}
+}
+
+// configs returns configurations of all listeners.
+func (l *Listener) configs() []*ListenerConfig {
+	var res []*ListenerConfig
+
+	if l.TCP != "" {
+		res = append(res, &ListenerConfig{Network: NetworkTCP, Addr: l.TCP})
 	}
+
+	if l.Unix != "" {
+		res = append(res, &ListenerConfig{Network: NetworkUnix, Addr: l.Unix})
+	}
+
+	if l.TLS != "" {
+		res = append(res, &ListenerConfig{
+			Network:     NetworkTLS,
+			Addr:        l.TLS,
+			TLSCertFile: l.TLSCertFile,
+			TLSKeyFile:  l.TLSKeyFile,
+			TLSCAFile:   l.TLSCAFile,
+		})
+	}
+
+	return append(res, l.Listeners...)
 }
 
 // Run runs the listener until ctx is canceled or some unrecoverable error occurs.
@@ -86,43 +124,22 @@ func (l *Listener) Run(ctx context.Context) error {
 
 	logger := l.Logger.Named("listener")
 
-	if l.TCP != "" {
-		var err error
-		if l.tcpListener, err = net.Listen("tcp", l.TCP); err != nil {
-			return err
-		}
-
-		close(l.tcpListenerReady)
-
-		logger.Sugar().Infof("Listening on TCP %s ...", l.TCPAddr())
-	}
+	for _, config := range l.configs() {
+		nl, err := config.listen()
+		if err != nil {
+			for _, ln := range l.listeners {
+				ln.Close()
+			}
 
-	if l.Unix != "" {
-		var err error
-		if l.unixListener, err = net.Listen("unix", l.Unix); err != nil {
 			return err
 		}
 
-		close(l.unixListenerReady)
+		l.listeners = append(l.listeners, &listener{Listener: nl, config: config})
 
-		logger.Sugar().Infof("Listening on Unix %s ...", l.UnixAddr())
+		logger.Sugar().Infof("Listening on %s %s ...", strings.ToUpper(string(config.Network)), nl.Addr())
 	}
 
-	if l.TLS != "" {
-		var err error
-		if l.tlsListener, err = setupTLSListener(&setupTLSListenerOpts{
-			addr:     l.TLS,
-			certFile: l.TLSCertFile,
-			keyFile:  l.TLSKeyFile,
-			caFile:   l.TLSCAFile,
-		}); err != nil {
-			return err
-		}
-
-		close(l.tlsListenerReady)
-
-		logger.Sugar().Infof("Listening on TLS %s ...", l.TLSAddr())
-	}
+	close(l.listenersReady)
 
 	var wg sync.WaitGroup
 
@@ -132,55 +149,23 @@ func (l *Listener) Run(ctx context.Context) error {
 
 		<-ctx.Done()
 
-		if l.tcpListener != nil {
-			l.tcpListener.Close()
-		}
-
-		if l.unixListener != nil {
-			l.unixListener.Close()
-		}
-
-		if l.tlsListener != nil {
-			l.tlsListener.Close()
+		for _, ln := range l.listeners {
+			ln.Close()
 		}
 	}()
 
-	if l.TCP != "" {
-		wg.Add(1)
-
-		go func() {
-			defer func() {
-				logger.Sugar().Infof("%s stopped.", l.TCPAddr())
-				wg.Done()
-			}()
-
-			acceptLoop(ctx, l.tcpListener, &wg, l, logger)
-		}()
-	}
-
-	if l.Unix != "" {
-		wg.Add(1)
-
-		go func() {
-			defer func() {
-				logger.Sugar().Infof("%s stopped.", l.UnixAddr())
-				wg.Done()
-			}()
-
-			acceptLoop(ctx, l.unixListener, &wg, l, logger)
-		}()
-	}
+	for _, ln := range l.listeners {
+		ln := ln
 
-	if l.TLS != "" {
 		wg.Add(1)
 
 		go func() {
 			defer func() {
-				logger.Sugar().Infof("%s stopped.", l.tlsListener.Addr())
+				logger.Sugar().Infof("%s stopped.", ln.Addr())
 				wg.Done()
 			}()
 
-			acceptLoop(ctx, l.tlsListener, &wg, l, logger)
+			acceptLoop(ctx, ln, &wg, l, logger)
 		}()
 	}
 
@@ -247,10 +232,10 @@ func setupTLSListener(opts *setupTLSListenerOpts) (net.Listener, error) {
 }
 
 // acceptLoop runs listener's connection accepting loop until context is canceled.
-func acceptLoop(ctx context.Context, listener net.Listener, wg *sync.WaitGroup, l *Listener, logger *zap.Logger) {
+func acceptLoop(ctx context.Context, ln *listener, wg *sync.WaitGroup, l *Listener, logger *zap.Logger) {
 	var retry int64
 	for {
-		netConn, err := listener.Accept()
+		netConn, err := ln.Accept()
 		if err != nil {
 			// Run closed listener on context cancellation
 			if context.Cause(ctx) != nil {
@@ -307,7 +292,11 @@ func acceptLoop(ctx context.Context, listener net.Listener, wg *sync.WaitGroup,
 				l:              l.Logger.Named("// " + connID + " "), // derive from the original unnamed logger
 				handler:        l.Handler,
 				connMetrics:    l.Metrics.ConnMetrics, // share between all conns
+				connRegistry:   l.ConnRegistry,
+				sessions:       l.Sessions,
+				scheduler:      l.Scheduler,
 				proxyAddr:      l.ProxyAddr,
+				requireAuth:    ln.config.RequireAuth,
 				testRecordsDir: l.TestRecordsDir,
 			}
 
@@ -330,36 +319,66 @@ func acceptLoop(ctx context.Context, listener net.Listener, wg *sync.WaitGroup,
 	}
 }
 
-// TCPAddr returns TCP listener's address.
+// TCPAddr returns the first TCP listener's address.
 // It can be used to determine an actually used port, if it was zero.
 func (l *Listener) TCPAddr() net.Addr {
-	<-l.tcpListenerReady
-	return l.tcpListener.Addr()
+	return l.addr(NetworkTCP)
 }
 
-// UnixAddr returns Unix domain socket listener's address.
+// UnixAddr returns the first Unix domain socket listener's address.
 func (l *Listener) UnixAddr() net.Addr {
-	<-l.unixListenerReady
-	return l.unixListener.Addr()
+	return l.addr(NetworkUnix)
 }
 
-// TLSAddr returns TLS listener's address.
+// TLSAddr returns the first TLS listener's address.
 // It can be used to determine an actually used port, if it was zero.
 func (l *Listener) TLSAddr() net.Addr {
-	<-l.tlsListenerReady
-	return l.tlsListener.Addr()
+	return l.addr(NetworkTLS)
+}
+
+// Addrs returns addresses of all listeners in the order of their configuration.
+func (l *Listener) Addrs() []net.Addr {
+	<-l.listenersReady
+
+	res := make([]net.Addr, len(l.listeners))
+	for i, ln := range l.listeners {
+		res[i] = ln.Addr()
+	}
+
+	return res
+}
+
+// addr returns the address of the first listener with the given network, or nil.
+func (l *Listener) addr(network Network) net.Addr {
+	<-l.listenersReady
+
+	for _, ln := range l.listeners {
+		if ln.config.Network == network {
+			return ln.Addr()
+		}
+	}
+
+	return nil
 }
 
 // Describe implements prometheus.Collector.
 func (l *Listener) Describe(ch chan<- *prometheus.Desc) {
 	l.Metrics.Describe(ch)
 	l.Handler.Describe(ch)
+
+	if l.Scheduler != nil {
+		l.Scheduler.Describe(ch)
+	}
 }
 
 // Collect implements prometheus.Collector.
 func (l *Listener) Collect(ch chan<- prometheus.Metric) {
 	l.Metrics.Collect(ch)
 	l.Handler.Collect(ch)
+
+	if l.Scheduler != nil {
+		l.Scheduler.Collect(ch)
+	}
 }
 
 // check interfaces
@@ -0,0 +1,159 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientconn
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Network represents listener's network type.
+type Network string
+
+const (
+	// NetworkTCP is a plaintext TCP listener.
+	NetworkTCP Network = "tcp"
+
+	// NetworkUnix is a Unix domain socket listener.
+	NetworkUnix Network = "unix"
+
+	// NetworkTLS is a TLS over TCP listener.
+	NetworkTLS Network = "tls"
+)
+
+// ListenerConfig represents configuration of a single listener.
+type ListenerConfig struct {
+	Network Network
+	Addr    string // host:port for TCP and TLS, socket path for Unix
+
+	TLSCertFile string
+	TLSKeyFile  string
+	TLSCAFile   string // may be empty to skip client's certificate validation
+
+	// RequireAuth makes the listener reject commands of unauthenticated clients,
+	// except for those that are needed for the handshake and authentication.
+	RequireAuth bool
+}
+
+// ParseListenerConfig parses listener configuration from the URL like:
+//
+//	tcp://127.0.0.1:27017
+//	unix:///tmp/ferretdb.sock?require-auth=true
+//	tls://0.0.0.0:27018?tls-cert-file=cert.pem&tls-key-file=key.pem&tls-ca-file=ca.pem&require-auth=true
+func ParseListenerConfig(s string) (*ListenerConfig, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid listener %q: %w", s, err)
+	}
+
+	config := &ListenerConfig{
+		Network: Network(u.Scheme),
+	}
+
+	switch config.Network {
+	case NetworkTCP, NetworkTLS:
+		config.Addr = u.Host
+	case NetworkUnix:
+		config.Addr = u.Host + u.Path
+	default:
+		return nil, fmt.Errorf("invalid listener %q: unexpected network %q", s, u.Scheme)
+	}
+
+	if config.Addr == "" {
+		return nil, fmt.Errorf("invalid listener %q: address is empty", s)
+	}
+
+	for k, vs := range u.Query() {
+		v := vs[len(vs)-1]
+
+		switch k {
+		case "tls-cert-file":
+			config.TLSCertFile = v
+		case "tls-key-file":
+			config.TLSKeyFile = v
+		case "tls-ca-file":
+			config.TLSCAFile = v
+		case "require-auth":
+			if config.RequireAuth, err = strconv.ParseBool(v); err != nil {
+				return nil, fmt.Errorf("invalid listener %q: %s: %w", s, k, err)
+			}
+		default:
+			return nil, fmt.Errorf("invalid listener %q: unexpected parameter %q", s, k)
+		}
+
+		if strings.HasPrefix(k, "tls-") && config.Network != NetworkTLS {
+			return nil, fmt.Errorf("invalid listener %q: %s is only allowed for TLS listeners", s, k)
+		}
+	}
+
+	if config.Network == NetworkTLS && (config.TLSCertFile == "" || config.TLSKeyFile == "") {
+		return nil, fmt.Errorf("invalid listener %q: tls-cert-file and tls-key-file are required", s)
+	}
+
+	return config, nil
+}
+
+// String returns listener's configuration in the format accepted by ParseListenerConfig.
+func (config *ListenerConfig) String() string {
+	u := &url.URL{
+		Scheme: string(config.Network),
+		Host:   config.Addr,
+	}
+
+	if config.Network == NetworkUnix {
+		u.Host = ""
+		u.Path = config.Addr
+	}
+
+	q := url.Values{}
+
+	for k, v := range map[string]string{
+		"tls-cert-file": config.TLSCertFile,
+		"tls-key-file":  config.TLSKeyFile,
+		"tls-ca-file":   config.TLSCAFile,
+	} {
+		if v != "" {
+			q.Set(k, v)
+		}
+	}
+
+	if config.RequireAuth {
+		q.Set("require-auth", "true")
+	}
+
+	u.RawQuery = q.Encode()
+
+	return u.String()
+}
+
+// listen creates a new network listener for that configuration.
+func (config *ListenerConfig) listen() (net.Listener, error) {
+	switch config.Network {
+	case NetworkTCP, NetworkUnix:
+		return net.Listen(string(config.Network), config.Addr)
+	case NetworkTLS:
+		return setupTLSListener(&setupTLSListenerOpts{
+			addr:     config.Addr,
+			certFile: config.TLSCertFile,
+			keyFile:  config.TLSKeyFile,
+			caFile:   config.TLSCAFile,
+		})
+	default:
+		return nil, fmt.Errorf("unexpected network %q", config.Network)
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientconn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseListenerConfig(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		s        string
+		expected *ListenerConfig
+		err      string
+	}{
+		"TCP": {
+			s:        "tcp://127.0.0.1:27017",
+			expected: &ListenerConfig{Network: NetworkTCP, Addr: "127.0.0.1:27017"},
+		},
+		"Unix": {
+			s:        "unix:///tmp/ferretdb.sock?require-auth=true",
+			expected: &ListenerConfig{Network: NetworkUnix, Addr: "/tmp/ferretdb.sock", RequireAuth: true},
+		},
+		"TLS": {
+			s: "tls://0.0.0.0:27018?tls-cert-file=cert.pem&tls-key-file=key.pem&tls-ca-file=ca.pem&require-auth=1",
+			expected: &ListenerConfig{
+				Network:     NetworkTLS,
+				Addr:        "0.0.0.0:27018",
+				TLSCertFile: "cert.pem",
+				TLSKeyFile:  "key.pem",
+				TLSCAFile:   "ca.pem",
+				RequireAuth: true,
+			},
+		},
+		"UnknownNetwork": {
+			s:   "udp://127.0.0.1:27017",
+			err: `invalid listener "udp://127.0.0.1:27017": unexpected network "udp"`,
+		},
+		"EmptyAddr": {
+			s:   "tcp://",
+			err: `invalid listener "tcp://": address is empty`,
+		},
+		"UnknownParameter": {
+			s:   "tcp://127.0.0.1:27017?foo=bar",
+			err: `invalid listener "tcp://127.0.0.1:27017?foo=bar": unexpected parameter "foo"`,
+		},
+		"TLSParameterForTCP": {
+			s:   "tcp://127.0.0.1:27017?tls-cert-file=cert.pem",
+			err: `invalid listener "tcp://127.0.0.1:27017?tls-cert-file=cert.pem": tls-cert-file is only allowed for TLS listeners`,
+		},
+		"TLSWithoutKey": {
+			s:   "tls://127.0.0.1:27017?tls-cert-file=cert.pem",
+			err: `invalid listener "tls://127.0.0.1:27017?tls-cert-file=cert.pem": tls-cert-file and tls-key-file are required`,
+		},
+		"BadRequireAuth": {
+			s:   "tcp://127.0.0.1:27017?require-auth=maybe",
+			err: `invalid listener "tcp://127.0.0.1:27017?require-auth=maybe": require-auth: strconv.ParseBool: parsing "maybe": invalid syntax`,
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := ParseListenerConfig(tc.s)
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+
+			actual, err = ParseListenerConfig(actual.String())
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
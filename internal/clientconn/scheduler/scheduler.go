@@ -0,0 +1,314 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package scheduler provides a bounded scheduler of client operations.
+package scheduler
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Parts of Prometheus metric names.
+const (
+	namespace = "ferretdb"
+	subsystem = "scheduler"
+)
+
+// ErrBusy is returned when the operation can't run immediately and the queue is full.
+var ErrBusy = errors.New("too many operations are queued")
+
+// waiter represents a single queued operation.
+type waiter struct {
+	conn  *Conn
+	start time.Duration // start tag
+	seq   uint64        // for FIFO order of equal start tags
+	ready chan struct{} // closed when the operation is started
+	index int           // index in the queue, -1 when the operation is started
+}
+
+// waiters is a priority queue of operations ordered by start tags.
+type waiters []*waiter
+
+// Len implements heap.Interface.
+func (q waiters) Len() int { return len(q) }
+
+// Less implements heap.Interface.
+func (q waiters) Less(i, j int) bool {
+	if q[i].start != q[j].start {
+		return q[i].start < q[j].start
+	}
+
+	return q[i].seq < q[j].seq
+}
+
+// Swap implements heap.Interface.
+func (q waiters) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+// Push implements heap.Interface.
+func (q *waiters) Push(x any) {
+	w := x.(*waiter)
+	w.index = len(*q)
+	*q = append(*q, w)
+}
+
+// Pop implements heap.Interface.
+func (q *waiters) Pop() any {
+	old := *q
+	n := len(old)
+	w := old[n-1]
+	old[n-1] = nil
+	w.index = -1
+	*q = old[:n-1]
+
+	return w
+}
+
+// Scheduler limits the number of concurrently running operations of all client connections.
+//
+// Operations that can't run immediately wait in a bounded queue.
+// Waiting operations are started with start-time fair queuing:
+// each connection is charged for the execution time of its operations,
+// so operations of connections that used less time go first,
+// and a few connections running heavy operations can't starve others.
+//
+//nolint:vet // for readability
+type Scheduler struct {
+	maxRunning int
+	maxQueued  int
+
+	m       sync.Mutex
+	running int
+	vtime   time.Duration // virtual time: the largest start tag of started operations
+	seq     uint64
+	queue   waiters
+
+	runningGauge prometheus.Gauge
+	queuedGauge  prometheus.Gauge
+	rejected     prometheus.Counter
+	wait         prometheus.Histogram
+}
+
+// New creates a new scheduler that runs up to maxRunning operations concurrently
+// and queues up to maxQueued operations.
+func New(maxRunning, maxQueued int) *Scheduler {
+	if maxRunning <= 0 {
+		panic("maxRunning must be positive")
+	}
+
+	if maxQueued < 0 {
+		panic("maxQueued must not be negative")
+	}
+
+	return &Scheduler{
+		maxRunning: maxRunning,
+		maxQueued:  maxQueued,
+		runningGauge: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "running",
+				Help:      "Number of running operations.",
+			},
+		),
+		queuedGauge: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "queued",
+				Help:      "Number of operations waiting in the queue.",
+			},
+		),
+		rejected: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "rejected_total",
+				Help:      "Total number of operations rejected because the queue was full.",
+			},
+		),
+		wait: prometheus.NewHistogram(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "wait_seconds",
+				Help:      "Time operations spent in the queue in seconds.",
+				Buckets: []float64{
+					(1 * time.Millisecond).Seconds(),
+					(5 * time.Millisecond).Seconds(),
+					(10 * time.Millisecond).Seconds(),
+					(25 * time.Millisecond).Seconds(),
+					(50 * time.Millisecond).Seconds(),
+					(100 * time.Millisecond).Seconds(),
+					(250 * time.Millisecond).Seconds(),
+					(500 * time.Millisecond).Seconds(),
+					(1000 * time.Millisecond).Seconds(),
+					(2500 * time.Millisecond).Seconds(),
+					(5000 * time.Millisecond).Seconds(),
+					(10000 * time.Millisecond).Seconds(),
+				},
+			},
+		),
+	}
+}
+
+// Conn represents the scheduling state of a single client connection.
+type Conn struct {
+	s      *Scheduler
+	finish time.Duration // finish tag of the last operation; protected by s.m
+}
+
+// NewConn returns the scheduling state for a new client connection.
+func (s *Scheduler) NewConn() *Conn {
+	return &Conn{s: s}
+}
+
+// Stats returns the number of running and queued operations.
+func (s *Scheduler) Stats() (running, queued int) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	return s.running, s.queue.Len()
+}
+
+// Acquire waits until the operation of the connection can run.
+//
+// On success, it returns a function that must be called exactly once when the operation finishes.
+// It returns ErrBusy if the operation can't run immediately and the queue is full,
+// or context error if ctx is canceled while the operation is queued.
+func (c *Conn) Acquire(ctx context.Context) (func(), error) {
+	s := c.s
+
+	s.m.Lock()
+
+	w := &waiter{
+		conn:  c,
+		start: max(s.vtime, c.finish),
+		seq:   s.seq,
+		ready: make(chan struct{}),
+	}
+	s.seq++
+
+	if s.running < s.maxRunning && s.queue.Len() == 0 {
+		s.startLocked(w)
+		s.m.Unlock()
+
+		return s.release(w, time.Now()), nil
+	}
+
+	if s.queue.Len() >= s.maxQueued {
+		s.m.Unlock()
+		s.rejected.Inc()
+
+		return nil, ErrBusy
+	}
+
+	heap.Push(&s.queue, w)
+	s.queuedGauge.Set(float64(s.queue.Len()))
+	s.m.Unlock()
+
+	queued := time.Now()
+
+	select {
+	case <-w.ready:
+	case <-ctx.Done():
+		s.m.Lock()
+
+		if w.index >= 0 {
+			heap.Remove(&s.queue, w.index)
+			s.queuedGauge.Set(float64(s.queue.Len()))
+			s.m.Unlock()
+
+			return nil, context.Cause(ctx)
+		}
+
+		s.m.Unlock()
+
+		// operation was started concurrently; give its slot to others
+		s.release(w, time.Now())()
+
+		return nil, context.Cause(ctx)
+	}
+
+	now := time.Now()
+	s.wait.Observe(now.Sub(queued).Seconds())
+
+	return s.release(w, now), nil
+}
+
+// startLocked marks the operation as started.
+//
+// It should be called with the lock held.
+func (s *Scheduler) startLocked(w *waiter) {
+	s.running++
+	s.vtime = max(s.vtime, w.start)
+
+	// the connection is charged for the operation when it finishes
+	w.conn.finish = max(w.conn.finish, w.start)
+
+	s.runningGauge.Set(float64(s.running))
+	close(w.ready)
+}
+
+// release returns a function that finishes the operation started at the given time
+// and starts queued operations.
+func (s *Scheduler) release(w *waiter, started time.Time) func() {
+	return func() {
+		cost := time.Since(started)
+
+		s.m.Lock()
+		defer s.m.Unlock()
+
+		w.conn.finish = max(w.conn.finish, w.start+cost)
+		s.running--
+
+		for s.running < s.maxRunning && s.queue.Len() > 0 {
+			s.startLocked(heap.Pop(&s.queue).(*waiter))
+		}
+
+		s.runningGauge.Set(float64(s.running))
+		s.queuedGauge.Set(float64(s.queue.Len()))
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (s *Scheduler) Describe(ch chan<- *prometheus.Desc) {
+	s.runningGauge.Describe(ch)
+	s.queuedGauge.Describe(ch)
+	s.rejected.Describe(ch)
+	s.wait.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (s *Scheduler) Collect(ch chan<- prometheus.Metric) {
+	s.runningGauge.Collect(ch)
+	s.queuedGauge.Collect(ch)
+	s.rejected.Collect(ch)
+	s.wait.Collect(ch)
+}
+
+// check interfaces
+var (
+	_ heap.Interface       = (*waiters)(nil)
+	_ prometheus.Collector = (*Scheduler)(nil)
+)
@@ -0,0 +1,151 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// waitQueued waits until the given number of operations is queued.
+func waitQueued(t *testing.T, s *Scheduler, n int) {
+	t.Helper()
+
+	require.Eventually(t, func() bool {
+		_, queued := s.Stats()
+		return queued == n
+	}, time.Second, time.Millisecond)
+}
+
+func TestSchedulerBusy(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := New(1, 1)
+	c := s.NewConn()
+
+	release1, err := c.Acquire(ctx)
+	require.NoError(t, err)
+
+	started := make(chan struct{})
+
+	go func() {
+		release2, err := c.Acquire(ctx)
+		assert.NoError(t, err)
+		close(started)
+		release2()
+	}()
+
+	waitQueued(t, s, 1)
+
+	_, err = c.Acquire(ctx)
+	require.ErrorIs(t, err, ErrBusy)
+	assert.Equal(t, float64(1), testutil.ToFloat64(s.rejected))
+
+	running, queued := s.Stats()
+	assert.Equal(t, 1, running)
+	assert.Equal(t, 1, queued)
+
+	release1()
+	<-started
+
+	require.Eventually(t, func() bool {
+		running, queued = s.Stats()
+		return running == 0 && queued == 0
+	}, time.Second, time.Millisecond)
+}
+
+func TestSchedulerFairness(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := New(1, 10)
+	heavy, light, other := s.NewConn(), s.NewConn(), s.NewConn()
+
+	release, err := heavy.Acquire(ctx)
+	require.NoError(t, err)
+	time.Sleep(20 * time.Millisecond)
+	release()
+
+	release, err = other.Acquire(ctx)
+	require.NoError(t, err)
+
+	order := make(chan string, 2)
+
+	for _, tc := range []struct {
+		name string
+		conn *Conn
+	}{
+		{"heavy", heavy},
+		{"light", light},
+	} {
+		tc := tc
+
+		go func() {
+			release, err := tc.conn.Acquire(ctx)
+			assert.NoError(t, err)
+			order <- tc.name
+			release()
+		}()
+
+		// heavy connection is queued first
+		_, queued := s.Stats()
+		waitQueued(t, s, queued+1)
+	}
+
+	release()
+
+	assert.Equal(t, "light", <-order)
+	assert.Equal(t, "heavy", <-order)
+}
+
+func TestSchedulerCancel(t *testing.T) {
+	t.Parallel()
+
+	s := New(1, 1)
+	c := s.NewConn()
+
+	release, err := c.Acquire(context.Background())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+
+	go func() {
+		_, err := c.Acquire(ctx)
+		done <- err
+	}()
+
+	waitQueued(t, s, 1)
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+
+	_, queued := s.Stats()
+	assert.Equal(t, 0, queued)
+
+	release()
+
+	release, err = c.Acquire(context.Background())
+	require.NoError(t, err)
+	release()
+
+	running, _ := s.Stats()
+	assert.Equal(t, 0, running)
+}
@@ -0,0 +1,234 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package session provides a registry of logical sessions.
+package session
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// DefaultTimeout is the idle time after which logical session expires.
+//
+// It is advertised to clients as `logicalSessionTimeoutMinutes`.
+const DefaultTimeout = 30 * time.Minute
+
+// session represents a single logical session.
+type session struct {
+	lastUsed time.Time
+	hooks    []*hook
+}
+
+// hook is a function that is called when the session ends.
+//
+// Pointers are used to find hooks that should be unregistered.
+type hook struct {
+	f func()
+}
+
+// Registry stores logical sessions of all clients and runs their end hooks
+// when sessions are ended explicitly or expire.
+//
+//nolint:vet // for readability
+type Registry struct {
+	m        sync.Mutex
+	sessions map[uuid.UUID]*session
+
+	l *zap.Logger
+}
+
+// NewRegistry returns a new registry.
+func NewRegistry(l *zap.Logger) *Registry {
+	return &Registry{
+		sessions: map[uuid.UUID]*session{},
+		l:        l,
+	}
+}
+
+// get returns the session with the given ID, creating it if needed.
+//
+// It should be called with the lock held.
+func (r *Registry) get(id uuid.UUID) *session {
+	s := r.sessions[id]
+	if s == nil {
+		s = new(session)
+		r.sessions[id] = s
+	}
+
+	return s
+}
+
+// Touch marks sessions with the given IDs as used now, starting them if needed.
+func (r *Registry) Touch(ids ...uuid.UUID) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	now := time.Now()
+
+	for _, id := range ids {
+		r.get(id).lastUsed = now
+	}
+}
+
+// OnEnd registers a hook that is called once when the active session with the given ID ends.
+// It also marks the session as used now.
+//
+// If the session is not active (was never used or already ended), the hook is not registered,
+// and false is returned; the session is not started again.
+//
+// Returned function unregisters the hook.
+// It does nothing if the session already ended and the hook was called or is being called.
+func (r *Registry) OnEnd(id uuid.UUID, f func()) (stop func(), ok bool) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	s := r.sessions[id]
+	if s == nil {
+		return func() {}, false
+	}
+
+	s.lastUsed = time.Now()
+
+	h := &hook{f: f}
+	s.hooks = append(s.hooks, h)
+
+	return func() {
+		r.m.Lock()
+		defer r.m.Unlock()
+
+		s.hooks = slices.DeleteFunc(s.hooks, func(e *hook) bool { return e == h })
+	}, true
+}
+
+// Len returns the number of active sessions.
+func (r *Registry) Len() int {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	return len(r.sessions)
+}
+
+// End ends sessions with the given IDs and runs their hooks.
+// Unknown IDs are ignored.
+func (r *Registry) End(ids ...uuid.UUID) {
+	r.m.Lock()
+
+	var hooks []*hook
+
+	for _, id := range ids {
+		if s := r.sessions[id]; s != nil {
+			hooks = append(hooks, s.hooks...)
+			s.hooks = nil
+			delete(r.sessions, id)
+		}
+	}
+
+	r.m.Unlock()
+
+	runHooks(hooks)
+}
+
+// EndAll ends all sessions and runs their hooks.
+func (r *Registry) EndAll() {
+	r.m.Lock()
+
+	var hooks []*hook
+
+	for _, s := range r.sessions {
+		hooks = append(hooks, s.hooks...)
+		s.hooks = nil
+	}
+
+	clear(r.sessions)
+
+	r.m.Unlock()
+
+	runHooks(hooks)
+}
+
+// Expire ends sessions that were not used for longer than the given timeout
+// and returns the number of them.
+func (r *Registry) Expire(timeout time.Duration) int {
+	r.m.Lock()
+
+	var expired int
+	var hooks []*hook
+
+	for id, s := range r.sessions {
+		if time.Since(s.lastUsed) > timeout {
+			r.l.Debug("Expiring idle session", zap.Stringer("id", id), zap.Time("last_used", s.lastUsed))
+
+			expired++
+			hooks = append(hooks, s.hooks...)
+			s.hooks = nil
+			delete(r.sessions, id)
+		}
+	}
+
+	r.m.Unlock()
+
+	runHooks(hooks)
+
+	return expired
+}
+
+// runHooks runs hooks of ended sessions.
+// It should be called without the lock held, as hooks may be slow.
+func runHooks(hooks []*hook) {
+	for _, h := range hooks {
+		h.f()
+	}
+}
+
+// ParseID returns the logical session ID from the given `lsid` value
+// (a document with a single `id` field of UUID binary type).
+// It returns false if the value is malformed.
+func ParseID(lsid any) (uuid.UUID, bool) {
+	doc, ok := lsid.(*types.Document)
+	if !ok {
+		return uuid.Nil, false
+	}
+
+	v, _ := doc.Get("id")
+
+	b, ok := v.(types.Binary)
+	if !ok || b.Subtype != types.BinaryUUID {
+		return uuid.Nil, false
+	}
+
+	id, err := uuid.FromBytes(b.B)
+	if err != nil {
+		return uuid.Nil, false
+	}
+
+	return id, true
+}
+
+// GetID returns the logical session ID from the `lsid` field of the given command document.
+// It returns false if the field is absent or malformed.
+func GetID(document *types.Document) (uuid.UUID, bool) {
+	lsid, err := document.Get("lsid")
+	if err != nil {
+		return uuid.Nil, false
+	}
+
+	return ParseID(lsid)
+}
@@ -0,0 +1,130 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry(zaptest.NewLogger(t))
+
+	id1, id2, id3 := uuid.New(), uuid.New(), uuid.New()
+
+	var ended []uuid.UUID
+	for _, id := range []uuid.UUID{id1, id2, id3} {
+		id := id
+		r.Touch(id)
+
+		_, ok := r.OnEnd(id, func() { ended = append(ended, id) })
+		require.True(t, ok)
+	}
+
+	assert.Equal(t, 3, r.Len())
+
+	r.End(id1, uuid.New())
+	assert.Equal(t, []uuid.UUID{id1}, ended)
+	assert.Equal(t, 2, r.Len())
+
+	// hooks of ended sessions are not called again
+	r.End(id1)
+	assert.Equal(t, []uuid.UUID{id1}, ended)
+
+	assert.Equal(t, 0, r.Expire(time.Hour))
+
+	time.Sleep(10 * time.Millisecond)
+	r.Touch(id3)
+
+	assert.Equal(t, 1, r.Expire(5*time.Millisecond))
+	assert.Equal(t, []uuid.UUID{id1, id2}, ended)
+
+	r.EndAll()
+	assert.Equal(t, []uuid.UUID{id1, id2, id3}, ended)
+	assert.Equal(t, 0, r.Len())
+
+	// hooks are not registered for ended sessions, and sessions are not started again
+	_, ok := r.OnEnd(id1, func() { ended = append(ended, id1) })
+	assert.False(t, ok)
+	assert.Equal(t, 0, r.Len())
+
+	// unregistered hooks are not called
+	r.Touch(id1)
+	stop, _ := r.OnEnd(id1, func() { ended = append(ended, id1) })
+	r.OnEnd(id1, func() { ended = append(ended, id2) })
+	stop()
+
+	r.End(id1)
+	assert.Equal(t, []uuid.UUID{id1, id2, id3, id2}, ended)
+
+	// stopping hooks of ended sessions does nothing
+	stop()
+}
+
+func TestGetID(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.New()
+
+	for name, tc := range map[string]struct {
+		doc      *types.Document
+		expected uuid.UUID
+		ok       bool
+	}{
+		"Valid": {
+			doc: must.NotFail(types.NewDocument(
+				"find", "test",
+				"lsid", must.NotFail(types.NewDocument(
+					"id", types.Binary{Subtype: types.BinaryUUID, B: id[:]},
+				)),
+			)),
+			expected: id,
+			ok:       true,
+		},
+		"Missing": {
+			doc: must.NotFail(types.NewDocument("find", "test")),
+		},
+		"WrongSubtype": {
+			doc: must.NotFail(types.NewDocument(
+				"find", "test",
+				"lsid", must.NotFail(types.NewDocument(
+					"id", types.Binary{Subtype: types.BinaryGeneric, B: id[:]},
+				)),
+			)),
+		},
+		"WrongType": {
+			doc: must.NotFail(types.NewDocument("find", "test", "lsid", "session")),
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, ok := GetID(tc.doc)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
@@ -16,6 +16,7 @@ package common
 
 import (
 	"errors"
+	"strings"
 
 	"github.com/FerretDB/FerretDB/internal/handlers/common/aggregations/operators"
 	"github.com/FerretDB/FerretDB/internal/handlers/commonerrors"
@@ -26,15 +27,17 @@ import (
 )
 
 // AddFieldsIterator returns an iterator that adds a new field to the underlying iterator.
+// Given variables are available for aggregation expressions; they could be nil.
 // It will be added to the given closer.
 //
 // Next method returns the next document after adding the new field to the document.
 //
 // Close method closes the underlying iterator.
-func AddFieldsIterator(iter types.DocumentsIterator, closer *iterator.MultiCloser, newField *types.Document) types.DocumentsIterator { //nolint:lll // for readability
+func AddFieldsIterator(iter types.DocumentsIterator, closer *iterator.MultiCloser, newField *types.Document, vars operators.Variables) types.DocumentsIterator { //nolint:lll // for readability
 	res := &addFieldsIterator{
 		iter:     iter,
 		newField: newField,
+		vars:     vars,
 	}
 	closer.Add(res)
 
@@ -45,6 +48,7 @@ func AddFieldsIterator(iter types.DocumentsIterator, closer *iterator.MultiClose
 type addFieldsIterator struct {
 	iter     types.DocumentsIterator
 	newField *types.Document
+	vars     operators.Variables
 }
 
 // Next implements iterator.Interface. See addFieldsIterator for details.
@@ -70,10 +74,25 @@ func (iter *addFieldsIterator) Next() (struct{}, *types.Document, error) {
 				return unused, nil, err
 			}
 
-			val, err = op.Process(doc)
+			val, err = operators.ProcessOperator(op, doc, iter.vars)
 			if err = processAddFieldsError(err); err != nil {
 				return unused, nil, err
 			}
+
+		case string:
+			if !strings.HasPrefix(v, "$$") {
+				break
+			}
+
+			if val, err = operators.EvaluateVariable(doc, iter.vars, v); err != nil {
+				return unused, nil, err
+			}
+		}
+
+		// missing value (for example, `$$REMOVE`) removes the field
+		if val == nil {
+			doc.Remove(key)
+			continue
 		}
 
 		doc.Set(key, val)
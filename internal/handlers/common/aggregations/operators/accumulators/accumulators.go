@@ -25,6 +25,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/FerretDB/FerretDB/internal/handlers/common/aggregations/operators"
 	"github.com/FerretDB/FerretDB/internal/handlers/commonerrors"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/iterator"
@@ -32,8 +33,8 @@ import (
 )
 
 // newAccumulatorFunc is a type for a function that creates an accumulation operator.
-// It takes the arguments extracted from the accumulator document.
-type newAccumulatorFunc func(args ...any) (Accumulator, error)
+// It takes variables defined for the command and the arguments extracted from the accumulator document.
+type newAccumulatorFunc func(vars operators.Variables, args ...any) (Accumulator, error)
 
 // Accumulator is a common interface for aggregation accumulation operators.
 type Accumulator interface {
@@ -43,7 +44,8 @@ type Accumulator interface {
 }
 
 // NewAccumulator returns accumulator for provided value.
-func NewAccumulator(stage, key string, value any) (Accumulator, error) {
+// Given variables could be used by accumulator expressions.
+func NewAccumulator(stage, key string, value any, vars operators.Variables) (Accumulator, error) {
 	accumulation, ok := value.(*types.Document)
 	if !ok || accumulation.Len() == 0 {
 		return nil, commonerrors.NewCommandErrorMsgWithArgument(
@@ -97,7 +99,7 @@ func NewAccumulator(stage, key string, value any) (Accumulator, error) {
 		)
 	}
 
-	return newAccumulator(args...)
+	return newAccumulator(vars, args...)
 }
 
 // Accumulators maps all aggregation accumulators.
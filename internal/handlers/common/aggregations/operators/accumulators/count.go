@@ -17,6 +17,7 @@ package accumulators
 import (
 	"errors"
 
+	"github.com/FerretDB/FerretDB/internal/handlers/common/aggregations/operators"
 	"github.com/FerretDB/FerretDB/internal/handlers/commonerrors"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/iterator"
@@ -26,7 +27,7 @@ import (
 type count struct{}
 
 // newCount creates a new $count aggregation operator.
-func newCount(args ...any) (Accumulator, error) {
+func newCount(_ operators.Variables, args ...any) (Accumulator, error) {
 	if len(args) != 1 {
 		return nil, commonerrors.NewCommandErrorMsgWithArgument(
 			commonerrors.ErrStageGroupUnaryOperator,
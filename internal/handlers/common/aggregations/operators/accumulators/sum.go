@@ -16,6 +16,7 @@ package accumulators
 
 import (
 	"errors"
+	"strings"
 
 	"github.com/FerretDB/FerretDB/internal/handlers/common/aggregations"
 	"github.com/FerretDB/FerretDB/internal/handlers/common/aggregations/operators"
@@ -30,11 +31,15 @@ type sum struct {
 	expression *aggregations.Expression
 	operator   operators.Operator
 	number     any
+	variable   string              // variable expression like `$$value`
+	vars       operators.Variables // for operator and variable
 }
 
 // newSum creates a new $sum aggregation operator.
-func newSum(args ...any) (Accumulator, error) {
-	accumulator := new(sum)
+func newSum(vars operators.Variables, args ...any) (Accumulator, error) {
+	accumulator := &sum{
+		vars: vars,
+	}
 
 	if len(args) != 1 {
 		return nil, commonerrors.NewCommandErrorMsgWithArgument(
@@ -66,6 +71,17 @@ func newSum(args ...any) (Accumulator, error) {
 		case float64:
 			accumulator.number = arg
 		case string:
+			if strings.HasPrefix(arg, "$$") {
+				// check that variable is defined
+				if _, err := operators.EvaluateVariable(nil, vars, arg); err != nil {
+					return nil, err
+				}
+
+				accumulator.variable = arg
+
+				break
+			}
+
 			var err error
 			if accumulator.expression, err = aggregations.NewExpression(arg, nil); err != nil {
 				// $sum returns 0 on non-existent field.
@@ -99,7 +115,7 @@ func (s *sum) Accumulate(iter types.DocumentsIterator) (any, error) {
 
 		switch {
 		case s.operator != nil:
-			v, err := s.operator.Process(doc)
+			v, err := operators.ProcessOperator(s.operator, doc, s.vars)
 			if err != nil {
 				return nil, err
 			}
@@ -108,6 +124,19 @@ func (s *sum) Accumulate(iter types.DocumentsIterator) (any, error) {
 
 			continue
 
+		case s.variable != "":
+			v, err := operators.EvaluateVariable(doc, s.vars, s.variable)
+			if err != nil {
+				return nil, err
+			}
+
+			// sum values that exist
+			if v != nil {
+				numbers = append(numbers, v)
+			}
+
+			continue
+
 		case s.expression != nil:
 			value, err := s.expression.Evaluate(doc)
 
@@ -0,0 +1,571 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/FerretDB/FerretDB/internal/handlers/commonerrors"
+	"github.com/FerretDB/FerretDB/internal/handlers/commonparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// convertTargetTypes contains type codes that could be used as `to` argument of `$convert`.
+var convertTargetTypes = map[commonparams.TypeCode]struct{}{
+	commonparams.TypeCodeDouble:    {},
+	commonparams.TypeCodeString:    {},
+	commonparams.TypeCodeObject:    {},
+	commonparams.TypeCodeArray:     {},
+	commonparams.TypeCodeBinData:   {},
+	commonparams.TypeCodeObjectID:  {},
+	commonparams.TypeCodeBool:      {},
+	commonparams.TypeCodeDate:      {},
+	commonparams.TypeCodeNull:      {},
+	commonparams.TypeCodeRegex:     {},
+	commonparams.TypeCodeInt:       {},
+	commonparams.TypeCodeTimestamp: {},
+	commonparams.TypeCodeLong:      {},
+	commonparams.TypeCodeDecimal:   {},
+	commonparams.TypeCodeMinKey:    {},
+	commonparams.TypeCodeMaxKey:    {},
+}
+
+// convertDateLayouts contains layouts of strings that could be converted to date.
+// Strings without time zone are treated as UTC.
+var convertDateLayouts = []string{
+	"2006-01-02T15:04:05.999999999Z07:00",
+	"2006-01-02T15:04:05.999999999Z0700",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+// conversionError represents a failure to convert the value that could be handled by `onError`.
+type conversionError struct {
+	msg    string
+	reason string
+}
+
+// Error implements error interface.
+func (e *conversionError) Error() string {
+	msg := e.msg + " in $convert with no onError value"
+	if e.reason != "" {
+		msg += ": " + e.reason
+	}
+
+	return msg
+}
+
+// convert represents `$convert` operator and its shorthands like `$toInt`.
+type convert struct {
+	input   any
+	to      any
+	onError any
+	onNull  any
+
+	hasOnError bool
+	hasOnNull  bool
+}
+
+// newConvert returns `$convert` operator.
+func newConvert(args ...any) (Operator, error) {
+	if len(args) != 1 {
+		return nil, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrFailedToParse,
+			"$convert expects an object of named arguments but found: array",
+			"$convert",
+		)
+	}
+
+	params, ok := args[0].(*types.Document)
+	if !ok {
+		return nil, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrFailedToParse,
+			fmt.Sprintf(
+				"$convert expects an object of named arguments but found: %s",
+				commonparams.AliasFromType(args[0]),
+			),
+			"$convert",
+		)
+	}
+
+	var c convert
+
+	for _, k := range params.Keys() {
+		v := must.NotFail(params.Get(k))
+
+		switch k {
+		case "input":
+			c.input = v
+		case "to":
+			c.to = v
+		case "onError":
+			c.onError = v
+			c.hasOnError = true
+		case "onNull":
+			c.onNull = v
+			c.hasOnNull = true
+		default:
+			return nil, commonerrors.NewCommandErrorMsgWithArgument(
+				commonerrors.ErrFailedToParse,
+				fmt.Sprintf("$convert found an unknown argument: %s", k),
+				"$convert",
+			)
+		}
+	}
+
+	if !params.Has("input") {
+		return nil, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrFailedToParse,
+			"Missing 'input' parameter to $convert",
+			"$convert",
+		)
+	}
+
+	if !params.Has("to") {
+		return nil, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrFailedToParse,
+			"Missing 'to' parameter to $convert",
+			"$convert",
+		)
+	}
+
+	return &c, nil
+}
+
+// newConvertShorthand returns a function that creates
+// shorthand operator for `$convert` with the given target type (like `$toInt`).
+func newConvertShorthand(operator string, to commonparams.TypeCode) newOperatorFunc {
+	return func(args ...any) (Operator, error) {
+		if len(args) != 1 {
+			return nil, newOperatorError(
+				ErrArgsInvalidLen,
+				operator,
+				fmt.Sprintf("Expression %s takes exactly 1 arguments. %d were passed in.", operator, len(args)),
+			)
+		}
+
+		return &convert{
+			input: args[0],
+			to:    int32(to),
+		}, nil
+	}
+}
+
+// Process implements Operator interface.
+func (c *convert) Process(doc *types.Document) (any, error) {
+	return c.processVariables(doc, nil)
+}
+
+// processVariables implements variablesOperator interface.
+func (c *convert) processVariables(doc *types.Document, vars Variables) (any, error) {
+	to, err := evaluate(doc, vars, c.to)
+	if err != nil {
+		return nil, err
+	}
+
+	input, err := evaluate(doc, vars, c.input)
+	if err != nil {
+		return nil, err
+	}
+
+	var target commonparams.TypeCode
+
+	toIsNull := isNullish(to)
+	if !toIsNull {
+		if target, err = convertTargetType(to); err != nil {
+			return nil, err
+		}
+	}
+
+	if isNullish(input) {
+		if c.hasOnNull {
+			return c.evaluateFallback(doc, vars, c.onNull)
+		}
+
+		return types.Null, nil
+	}
+
+	if toIsNull {
+		return types.Null, nil
+	}
+
+	res, err := convertValue(input, target)
+	if err == nil {
+		return res, nil
+	}
+
+	var convErr *conversionError
+	if !errors.As(err, &convErr) {
+		return nil, err
+	}
+
+	if c.hasOnError {
+		return c.evaluateFallback(doc, vars, c.onError)
+	}
+
+	return nil, commonerrors.NewCommandErrorMsgWithArgument(
+		commonerrors.ErrConversionFailure,
+		convErr.Error(),
+		"$convert",
+	)
+}
+
+// evaluateFallback evaluates `onNull` or `onError` value.
+// Missing value is returned as null.
+func (c *convert) evaluateFallback(doc *types.Document, vars Variables, v any) (any, error) {
+	res, err := evaluate(doc, vars, v)
+	if err != nil {
+		return nil, err
+	}
+
+	if res == nil {
+		return types.Null, nil
+	}
+
+	return res, nil
+}
+
+// isNullish returns true if the value is null or missing.
+func isNullish(v any) bool {
+	return v == nil || v == types.Null
+}
+
+// convertTargetType returns type code for the evaluated `to` argument of `$convert`.
+func convertTargetType(to any) (commonparams.TypeCode, error) {
+	switch to := to.(type) {
+	case string:
+		for code := range convertTargetTypes {
+			if code.String() == to {
+				return code, nil
+			}
+		}
+
+		return 0, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrBadValue,
+			fmt.Sprintf("Unknown type name: %s", to),
+			"$convert",
+		)
+
+	case float64, int32, int64:
+		n, err := commonparams.GetWholeNumberParam(to)
+		if err != nil {
+			return 0, commonerrors.NewCommandErrorMsgWithArgument(
+				commonerrors.ErrFailedToParse,
+				fmt.Sprintf("In $convert, numeric 'to' argument is not an integer: %v", to),
+				"$convert",
+			)
+		}
+
+		code := commonparams.TypeCode(n)
+		if _, ok := convertTargetTypes[code]; !ok || n != int64(code) {
+			return 0, commonerrors.NewCommandErrorMsgWithArgument(
+				commonerrors.ErrFailedToParse,
+				fmt.Sprintf("In $convert, numeric value for 'to' does not correspond to a BSON type: %d", n),
+				"$convert",
+			)
+		}
+
+		return code, nil
+
+	default:
+		return 0, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrFailedToParse,
+			fmt.Sprintf(
+				"$convert's 'to' argument must be a string or number, but is %s",
+				commonparams.AliasFromType(to),
+			),
+			"$convert",
+		)
+	}
+}
+
+// convertValue converts non-null value to the given type.
+//
+// It returns *conversionError if conversion is not possible.
+// Decimal128 values are not supported, so conversion to decimal always fails that way
+// and could be handled by `onError`.
+func convertValue(v any, target commonparams.TypeCode) (any, error) {
+	switch target {
+	case commonparams.TypeCodeDouble:
+		return convertToDouble(v)
+	case commonparams.TypeCodeString:
+		return convertToString(v)
+	case commonparams.TypeCodeObjectID:
+		return convertToObjectID(v)
+	case commonparams.TypeCodeBool:
+		return convertToBool(v), nil
+	case commonparams.TypeCodeDate:
+		return convertToDate(v)
+	case commonparams.TypeCodeInt:
+		return convertToInt(v)
+	case commonparams.TypeCodeLong:
+		return convertToLong(v)
+	case commonparams.TypeCodeDecimal:
+		return nil, &conversionError{msg: "Conversion to decimal is not supported"}
+	default:
+		return nil, unsupportedConversion(v, target)
+	}
+}
+
+// unsupportedConversion returns *conversionError for conversion that is not supported.
+func unsupportedConversion(v any, target commonparams.TypeCode) error {
+	return &conversionError{
+		msg: fmt.Sprintf("Unsupported conversion from %s to %s", commonparams.AliasFromType(v), target),
+	}
+}
+
+// convertToDouble converts the value to double.
+func convertToDouble(v any) (any, error) {
+	switch v := v.(type) {
+	case float64:
+		return v, nil
+	case int32:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case bool:
+		if v {
+			return float64(1), nil
+		}
+
+		return float64(0), nil
+	case time.Time:
+		return float64(v.UnixMilli()), nil
+	case string:
+		// hexadecimal floats and underscores are not accepted
+		if !strings.ContainsAny(v, "xX_") {
+			if f, err := strconv.ParseFloat(v, 64); err == nil || errors.Is(err, strconv.ErrRange) {
+				return f, nil
+			}
+		}
+
+		return nil, &conversionError{msg: fmt.Sprintf("Failed to parse number '%s'", v)}
+	default:
+		return nil, unsupportedConversion(v, commonparams.TypeCodeDouble)
+	}
+}
+
+// convertToString converts the value to string.
+func convertToString(v any) (any, error) {
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case float64:
+		switch {
+		case math.IsInf(v, 1):
+			return "Infinity", nil
+		case math.IsInf(v, -1):
+			return "-Infinity", nil
+		case math.IsNaN(v):
+			return "NaN", nil
+		}
+
+		// the same as C's %g format used by MongoDB
+		return strconv.FormatFloat(v, 'g', 6, 64), nil
+	case int32:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case types.ObjectID:
+		return hex.EncodeToString(v[:]), nil
+	case time.Time:
+		return v.UTC().Format("2006-01-02T15:04:05.000Z"), nil
+	default:
+		return nil, unsupportedConversion(v, commonparams.TypeCodeString)
+	}
+}
+
+// convertToObjectID converts the value to ObjectID.
+func convertToObjectID(v any) (any, error) {
+	switch v := v.(type) {
+	case types.ObjectID:
+		return v, nil
+	case string:
+		if len(v) != 2*types.ObjectIDLen {
+			return nil, &conversionError{
+				msg: fmt.Sprintf("Failed to parse objectId '%s'", v),
+				reason: fmt.Sprintf(
+					"Invalid string length for parsing to OID, expected %d but found %d",
+					2*types.ObjectIDLen, len(v),
+				),
+			}
+		}
+
+		b, err := hex.DecodeString(v)
+		if err != nil {
+			return nil, &conversionError{
+				msg:    fmt.Sprintf("Failed to parse objectId '%s'", v),
+				reason: "Invalid character found in hex string",
+			}
+		}
+
+		return types.ObjectID(b), nil
+	default:
+		return nil, unsupportedConversion(v, commonparams.TypeCodeObjectID)
+	}
+}
+
+// convertToBool converts the value to bool.
+//
+// Numbers are converted to true if they are not zero, all other values are converted to true.
+func convertToBool(v any) any {
+	switch v := v.(type) {
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case int32:
+		return v != 0
+	case int64:
+		return v != 0
+	default:
+		return true
+	}
+}
+
+// convertToDate converts the value to date.
+func convertToDate(v any) (any, error) {
+	switch v := v.(type) {
+	case time.Time:
+		return v, nil
+	case float64:
+		ms, err := floatToInt(v, math.MinInt64, math.MaxInt64)
+		if err != nil {
+			return nil, err
+		}
+
+		return time.UnixMilli(ms).UTC(), nil
+	case int64:
+		return time.UnixMilli(v).UTC(), nil
+	case types.Timestamp:
+		return v.Time().UTC(), nil
+	case types.ObjectID:
+		return time.Unix(int64(binary.BigEndian.Uint32(v[:4])), 0).UTC(), nil
+	case string:
+		for _, layout := range convertDateLayouts {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t.UTC(), nil
+			}
+		}
+
+		return nil, &conversionError{msg: fmt.Sprintf("Error parsing date string '%s'", v)}
+	default:
+		return nil, unsupportedConversion(v, commonparams.TypeCodeDate)
+	}
+}
+
+// convertToInt converts the value to int.
+func convertToInt(v any) (any, error) {
+	switch v := v.(type) {
+	case int32:
+		return v, nil
+	case int64:
+		if v < math.MinInt32 || v > math.MaxInt32 {
+			return nil, &conversionError{msg: "Conversion would overflow target type", reason: strconv.FormatInt(v, 10)}
+		}
+
+		return int32(v), nil
+	case float64:
+		n, err := floatToInt(v, math.MinInt32, math.MaxInt32)
+		if err != nil {
+			return nil, err
+		}
+
+		return int32(n), nil
+	case bool:
+		if v {
+			return int32(1), nil
+		}
+
+		return int32(0), nil
+	case string:
+		n, err := strconv.ParseInt(v, 10, 32)
+		if err != nil {
+			return nil, &conversionError{msg: fmt.Sprintf("Failed to parse number '%s'", v)}
+		}
+
+		return int32(n), nil
+	default:
+		return nil, unsupportedConversion(v, commonparams.TypeCodeInt)
+	}
+}
+
+// convertToLong converts the value to long.
+func convertToLong(v any) (any, error) {
+	switch v := v.(type) {
+	case int64:
+		return v, nil
+	case int32:
+		return int64(v), nil
+	case float64:
+		return floatToInt(v, math.MinInt64, math.MaxInt64)
+	case bool:
+		if v {
+			return int64(1), nil
+		}
+
+		return int64(0), nil
+	case time.Time:
+		return v.UnixMilli(), nil
+	case string:
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, &conversionError{msg: fmt.Sprintf("Failed to parse number '%s'", v)}
+		}
+
+		return n, nil
+	default:
+		return nil, unsupportedConversion(v, commonparams.TypeCodeLong)
+	}
+}
+
+// floatToInt truncates the double value and checks that result is within the given range.
+func floatToInt(v float64, minValue, maxValue int64) (int64, error) {
+	switch {
+	case math.IsNaN(v):
+		return 0, &conversionError{msg: "Attempt to convert NaN value to integer type"}
+	case math.IsInf(v, 0):
+		return 0, &conversionError{msg: "Attempt to convert infinity value to integer type"}
+	}
+
+	t := math.Trunc(v)
+
+	// float64(math.MaxInt64) is rounded up to 2^63, so the upper bound is exclusive
+	if t < float64(minValue) || t >= float64(maxValue)+1 {
+		return 0, &conversionError{
+			msg:    "Conversion would overflow target type",
+			reason: strconv.FormatFloat(v, 'g', -1, 64),
+		}
+	}
+
+	return int64(t), nil
+}
+
+// check interfaces
+var (
+	_ variablesOperator = (*convert)(nil)
+)
@@ -0,0 +1,140 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/FerretDB/FerretDB/internal/handlers/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// evaluate returns the value of operator argument for the given document and variables.
+//
+// Operator documents are processed, field path and variable expressions are evaluated,
+// array elements and document fields are evaluated recursively.
+// Any other value is returned as is.
+//
+// If field path expression points to the missing field, nil is returned.
+// The document could be nil when operator is validated; in that case all field paths are missing.
+func evaluate(doc *types.Document, vars Variables, arg any) (any, error) {
+	switch arg := arg.(type) {
+	case *types.Document:
+		if IsOperator(arg) {
+			op, err := NewOperator(arg)
+			if err != nil {
+				var opErr OperatorError
+				if errors.As(err, &opErr) && opErr.Code() == ErrInvalidExpression {
+					opErr.code = ErrInvalidNestedExpression
+					return nil, opErr
+				}
+
+				return nil, err
+			}
+
+			return ProcessOperator(op, doc, vars)
+		}
+
+		res := new(types.Document)
+
+		iter := arg.Iterator()
+		defer iter.Close()
+
+		for {
+			k, v, err := iter.Next()
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				break
+			}
+
+			if err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			processed, err := evaluate(doc, vars, v)
+			if err != nil {
+				return nil, err
+			}
+
+			// missing values are not added to the document
+			if processed != nil {
+				res.Set(k, processed)
+			}
+		}
+
+		return res, nil
+
+	case *types.Array:
+		res := types.MakeArray(arg.Len())
+
+		iter := arg.Iterator()
+		defer iter.Close()
+
+		for {
+			_, v, err := iter.Next()
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				break
+			}
+
+			if err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			processed, err := evaluate(doc, vars, v)
+			if err != nil {
+				return nil, err
+			}
+
+			// missing values become null inside arrays
+			if processed == nil {
+				processed = types.Null
+			}
+
+			res.Append(processed)
+		}
+
+		return res, nil
+
+	case string:
+		if !strings.HasPrefix(arg, "$") {
+			return arg, nil
+		}
+
+		if strings.HasPrefix(arg, "$$") {
+			return EvaluateVariable(doc, vars, arg)
+		}
+
+		expression, err := aggregations.NewExpression(arg, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if doc == nil {
+			return nil, nil
+		}
+
+		value, err := expression.Evaluate(doc)
+		if err != nil {
+			return nil, nil
+		}
+
+		return value, nil
+
+	default:
+		return arg, nil
+	}
+}
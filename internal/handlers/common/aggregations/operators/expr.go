@@ -18,6 +18,7 @@ package operators
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/FerretDB/FerretDB/internal/handlers/common/aggregations"
 	"github.com/FerretDB/FerretDB/internal/handlers/commonerrors"
@@ -30,6 +31,7 @@ import (
 // expr represents $expr operator.
 type expr struct {
 	exprValue   any
+	vars        Variables
 	errArgument string
 }
 
@@ -37,11 +39,14 @@ type expr struct {
 // from query and $match aggregation stage. $expr operator is a top level operator and
 // cannot be used from nested expression.
 //
+// Given variables are available for aggregation expressions; they could be nil.
+//
 // It returns CommandError for invalid value of $expr operator.
-func NewExpr(exprValue *types.Document, errArgument string) (Operator, error) {
+func NewExpr(exprValue *types.Document, vars Variables, errArgument string) (Operator, error) {
 	v := must.NotFail(exprValue.Get("$expr"))
 	e := &expr{
 		exprValue:   v,
+		vars:        vars,
 		errArgument: errArgument,
 	}
 
@@ -70,7 +75,7 @@ func (e *expr) validateExpr(exprValue any) error {
 				return processExprOperatorErrors(err, e.errArgument)
 			}
 
-			_, err = op.Process(nil)
+			_, err = ProcessOperator(op, nil, e.vars)
 			if err != nil {
 				// TODO https://github.com/FerretDB/FerretDB/issues/3129
 				return processExprOperatorErrors(err, e.errArgument)
@@ -115,6 +120,14 @@ func (e *expr) validateExpr(exprValue any) error {
 			}
 		}
 	case string:
+		if strings.HasPrefix(exprValue, "$$") {
+			if _, err := EvaluateVariable(nil, e.vars, exprValue); err != nil {
+				return processExprOperatorErrors(err, e.errArgument)
+			}
+
+			return nil
+		}
+
 		_, err := aggregations.NewExpression(exprValue, nil)
 		var exprErr *aggregations.ExpressionError
 
@@ -145,7 +158,7 @@ func (e *expr) processExpr(exprValue any, doc *types.Document) (any, error) {
 				return nil, lazyerrors.Error(err)
 			}
 
-			v, err := op.Process(doc)
+			v, err := ProcessOperator(op, doc, e.vars)
 			if err != nil {
 				// Process does not return error for existing operators
 				return nil, lazyerrors.Error(err)
@@ -204,6 +217,21 @@ func (e *expr) processExpr(exprValue any, doc *types.Document) (any, error) {
 
 		return res, nil
 	case string:
+		if strings.HasPrefix(exprValue, "$$") {
+			v, err := EvaluateVariable(doc, e.vars, exprValue)
+			if err != nil {
+				// variable was validated in NewExpr
+				return nil, lazyerrors.Error(err)
+			}
+
+			// missing value is set to null
+			if v == nil {
+				return types.Null, nil
+			}
+
+			return v, nil
+		}
+
 		expression, err := aggregations.NewExpression(exprValue, nil)
 
 		var exprErr *aggregations.ExpressionError
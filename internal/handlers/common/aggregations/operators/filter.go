@@ -0,0 +1,211 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/FerretDB/FerretDB/internal/handlers/commonerrors"
+	"github.com/FerretDB/FerretDB/internal/handlers/commonparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// filter represents `$filter` operator.
+type filter struct {
+	input any
+	as    string
+	cond  any
+	limit any
+}
+
+// newFilter returns `$filter` operator.
+func newFilter(args ...any) (Operator, error) {
+	var params *types.Document
+
+	if len(args) == 1 {
+		params, _ = args[0].(*types.Document)
+	}
+
+	if params == nil {
+		return nil, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrFilterNotObject,
+			"$filter only supports an object as its argument",
+			"$filter",
+		)
+	}
+
+	f := &filter{
+		as: "this",
+	}
+
+	for _, k := range params.Keys() {
+		v := must.NotFail(params.Get(k))
+
+		switch k {
+		case "input":
+			f.input = v
+		case "as":
+			// non-string name is treated as empty
+			f.as, _ = v.(string)
+		case "cond":
+			f.cond = v
+		case "limit":
+			f.limit = v
+		default:
+			return nil, commonerrors.NewCommandErrorMsgWithArgument(
+				commonerrors.ErrFilterUnknownArgument,
+				fmt.Sprintf("Unrecognized parameter to $filter: %s", k),
+				"$filter",
+			)
+		}
+	}
+
+	if !params.Has("input") {
+		return nil, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrFilterMissingInput,
+			"Missing 'input' parameter to $filter",
+			"$filter",
+		)
+	}
+
+	if !params.Has("cond") {
+		return nil, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrFilterMissingCond,
+			"Missing 'cond' parameter to $filter",
+			"$filter",
+		)
+	}
+
+	if err := validateVariableName(f.as); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// Process implements Operator interface.
+func (f *filter) Process(doc *types.Document) (any, error) {
+	return f.processVariables(doc, nil)
+}
+
+// processVariables implements variablesOperator interface.
+//
+// The `cond` expression is evaluated for each element of the input array
+// with the variable named by `as` set to that element.
+func (f *filter) processVariables(doc *types.Document, vars Variables) (any, error) {
+	input, err := evaluate(doc, vars, f.input)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, err := f.getLimit(doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if isNullish(input) {
+		return types.Null, nil
+	}
+
+	arr, ok := input.(*types.Array)
+	if !ok {
+		return nil, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrFilterInputNotArray,
+			fmt.Sprintf("input to $filter must be an array not %s", commonparams.AliasFromType(input)),
+			"$filter",
+		)
+	}
+
+	res := types.MakeArray(0)
+
+	iter := arr.Iterator()
+	defer iter.Close()
+
+	for limit == 0 || res.Len() < limit {
+		_, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		cond, err := evaluate(doc, vars.with(f.as, v), f.cond)
+		if err != nil {
+			return nil, err
+		}
+
+		if isTruthy(cond) {
+			res.Append(v)
+		}
+	}
+
+	return res, nil
+}
+
+// getLimit evaluates `limit` argument.
+// It returns 0 if limit is not set, null or missing.
+func (f *filter) getLimit(doc *types.Document, vars Variables) (int, error) {
+	v, err := evaluate(doc, vars, f.limit)
+	if err != nil {
+		return 0, err
+	}
+
+	if isNullish(v) {
+		return 0, nil
+	}
+
+	var limit float64
+
+	switch v := v.(type) {
+	case float64:
+		limit = v
+	case int32:
+		limit = float64(v)
+	case int64:
+		limit = float64(v)
+	default:
+		limit = math.NaN()
+	}
+
+	if limit != math.Trunc(limit) || limit > math.MaxInt32 || limit < math.MinInt32 {
+		return 0, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrFilterLimitNotInt32,
+			fmt.Sprintf("$filter: limit must be represented as a 32-bit integral value: %s", types.FormatAnyValue(v)),
+			"$filter",
+		)
+	}
+
+	if limit <= 0 {
+		return 0, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrFilterLimitNotPositive,
+			fmt.Sprintf("$filter: limit must be greater than 0: %d", int64(limit)),
+			"$filter",
+		)
+	}
+
+	return int(limit), nil
+}
+
+// check interfaces
+var (
+	_ variablesOperator = (*filter)(nil)
+)
@@ -0,0 +1,160 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/FerretDB/FerretDB/internal/handlers/commonerrors"
+	"github.com/FerretDB/FerretDB/internal/handlers/commonparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// mapOp represents `$map` operator.
+type mapOp struct {
+	input any
+	as    string
+	in    any
+}
+
+// newMap returns `$map` operator.
+func newMap(args ...any) (Operator, error) {
+	var params *types.Document
+
+	if len(args) == 1 {
+		params, _ = args[0].(*types.Document)
+	}
+
+	if params == nil {
+		return nil, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrMapNotObject,
+			"$map only supports an object as its argument",
+			"$map",
+		)
+	}
+
+	m := &mapOp{
+		as: "this",
+	}
+
+	for _, k := range params.Keys() {
+		v := must.NotFail(params.Get(k))
+
+		switch k {
+		case "input":
+			m.input = v
+		case "as":
+			// non-string name is treated as empty
+			m.as, _ = v.(string)
+		case "in":
+			m.in = v
+		default:
+			return nil, commonerrors.NewCommandErrorMsgWithArgument(
+				commonerrors.ErrMapUnknownArgument,
+				fmt.Sprintf("Unrecognized parameter to $map: %s", k),
+				"$map",
+			)
+		}
+	}
+
+	if !params.Has("input") {
+		return nil, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrMapMissingInput,
+			"Missing 'input' parameter to $map",
+			"$map",
+		)
+	}
+
+	if !params.Has("in") {
+		return nil, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrMapMissingIn,
+			"Missing 'in' parameter to $map",
+			"$map",
+		)
+	}
+
+	if err := validateVariableName(m.as); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Process implements Operator interface.
+func (m *mapOp) Process(doc *types.Document) (any, error) {
+	return m.processVariables(doc, nil)
+}
+
+// processVariables implements variablesOperator interface.
+//
+// The `in` expression is evaluated for each element of the input array
+// with the variable named by `as` set to that element.
+func (m *mapOp) processVariables(doc *types.Document, vars Variables) (any, error) {
+	input, err := evaluate(doc, vars, m.input)
+	if err != nil {
+		return nil, err
+	}
+
+	if isNullish(input) {
+		return types.Null, nil
+	}
+
+	arr, ok := input.(*types.Array)
+	if !ok {
+		return nil, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrMapInputNotArray,
+			fmt.Sprintf("input to $map must be an array not %s", commonparams.AliasFromType(input)),
+			"$map",
+		)
+	}
+
+	res := types.MakeArray(arr.Len())
+
+	iter := arr.Iterator()
+	defer iter.Close()
+
+	for {
+		_, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		value, err := evaluate(doc, vars.with(m.as, v), m.in)
+		if err != nil {
+			return nil, err
+		}
+
+		if value == nil {
+			value = types.Null
+		}
+
+		res.Append(value)
+	}
+
+	return res, nil
+}
+
+// check interfaces
+var (
+	_ variablesOperator = (*mapOp)(nil)
+)
@@ -26,6 +26,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/FerretDB/FerretDB/internal/handlers/commonparams"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/iterator"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
@@ -139,8 +140,32 @@ func NewOperator(doc *types.Document) (Operator, error) {
 // Operators maps all standard aggregation operators.
 var Operators = map[string]newOperatorFunc{
 	// sorted alphabetically
-	"$sum":  newSum,
-	"$type": newType,
+	"$convert":      newConvert,
+	"$filter":       newFilter,
+	"$ltrim":        newTrimOperator("$ltrim", true, false),
+	"$map":          newMap,
+	"$reduce":       newReduce,
+	"$regexFind":    newRegexOperator("$regexFind", regexModeFind),
+	"$regexFindAll": newRegexOperator("$regexFindAll", regexModeFindAll),
+	"$regexMatch":   newRegexOperator("$regexMatch", regexModeMatch),
+	"$replaceAll":   newReplaceOperator("$replaceAll", true),
+	"$replaceOne":   newReplaceOperator("$replaceOne", false),
+	"$rtrim":        newTrimOperator("$rtrim", false, true),
+	"$sortArray":    newSortArray,
+	"$split":        newSplit,
+	"$substrCP":     newSubstrCP,
+	"$sum":          newSum,
+	"$toBool":       newConvertShorthand("$toBool", commonparams.TypeCodeBool),
+	"$toDate":       newConvertShorthand("$toDate", commonparams.TypeCodeDate),
+	"$toDecimal":    newConvertShorthand("$toDecimal", commonparams.TypeCodeDecimal),
+	"$toDouble":     newConvertShorthand("$toDouble", commonparams.TypeCodeDouble),
+	"$toInt":        newConvertShorthand("$toInt", commonparams.TypeCodeInt),
+	"$toLong":       newConvertShorthand("$toLong", commonparams.TypeCodeLong),
+	"$toObjectId":   newConvertShorthand("$toObjectId", commonparams.TypeCodeObjectID),
+	"$toString":     newConvertShorthand("$toString", commonparams.TypeCodeString),
+	"$trim":         newTrimOperator("$trim", true, true),
+	"$type":         newType,
+	"$zip":          newZip,
 	// please keep sorted alphabetically
 }
 
@@ -169,7 +194,6 @@ var unsupportedOperators = map[string]struct{}{
 	"$concat":           {},
 	"$concatArrays":     {},
 	"$cond":             {},
-	"$convert":          {},
 	"$cos":              {},
 	"$cosh":             {},
 	"$covariancePop":    {},
@@ -193,7 +217,6 @@ var unsupportedOperators = map[string]struct{}{
 	"$eq":               {},
 	"$exp":              {},
 	"$expMovingAvg":     {},
-	"$filter":           {},
 	"$floor":            {},
 	"$function":         {},
 	"$getField":         {},
@@ -220,8 +243,6 @@ var unsupportedOperators = map[string]struct{}{
 	"$log10":            {},
 	"$lt":               {},
 	"$lte":              {},
-	"$ltrim":            {},
-	"$map":              {},
 	"$max":              {},
 	"$meta":             {},
 	"$min":              {},
@@ -240,15 +261,8 @@ var unsupportedOperators = map[string]struct{}{
 	"$rand":             {},
 	"$range":            {},
 	"$rank":             {},
-	"$reduce":           {},
-	"$regexFind":        {},
-	"$regexFindAll":     {},
-	"$regexMatch":       {},
-	"$replaceOne":       {},
-	"$replaceAll":       {},
 	"$reverseArray":     {},
 	"$round":            {},
-	"$rtrim":            {},
 	"$sampleRate":       {},
 	"$second":           {},
 	"$setDifference":    {},
@@ -262,8 +276,6 @@ var unsupportedOperators = map[string]struct{}{
 	"$sin":              {},
 	"$sinh":             {},
 	"$slice":            {},
-	"$sortArray":        {},
-	"$split":            {},
 	"$sqrt":             {},
 	"$stdDevPop":        {},
 	"$stdDevSamp":       {},
@@ -272,28 +284,17 @@ var unsupportedOperators = map[string]struct{}{
 	"$strLenCP":         {},
 	"$substr":           {},
 	"$substrBytes":      {},
-	"$substrCP":         {},
 	"$subtract":         {},
 	"$switch":           {},
 	"$tan":              {},
 	"$tanh":             {},
-	"$toBool":           {},
-	"$toDate":           {},
-	"$toDecimal":        {},
-	"$toDouble":         {},
-	"$toInt":            {},
-	"$toLong":           {},
-	"$toObjectId":       {},
-	"$toString":         {},
 	"$toLower":          {},
 	"$toUpper":          {},
-	"$trim":             {},
 	"$trunc":            {},
 	"$tsIncrement":      {},
 	"$tsSecond":         {},
 	"$unsetField":       {},
 	"$week":             {},
 	"$year":             {},
-	"$zip":              {},
 	// please keep sorted alphabetically
 }
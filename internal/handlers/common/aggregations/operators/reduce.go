@@ -0,0 +1,154 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/FerretDB/FerretDB/internal/handlers/commonerrors"
+	"github.com/FerretDB/FerretDB/internal/handlers/commonparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// reduce represents `$reduce` operator.
+type reduce struct {
+	input        any
+	initialValue any
+	in           any
+}
+
+// newReduce returns `$reduce` operator.
+func newReduce(args ...any) (Operator, error) {
+	var params *types.Document
+
+	if len(args) == 1 {
+		params, _ = args[0].(*types.Document)
+	}
+
+	if params == nil {
+		return nil, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrReduceNotObject,
+			"$reduce only supports an object as its argument",
+			"$reduce",
+		)
+	}
+
+	r := new(reduce)
+
+	for _, k := range params.Keys() {
+		v := must.NotFail(params.Get(k))
+
+		switch k {
+		case "input":
+			r.input = v
+		case "initialValue":
+			r.initialValue = v
+		case "in":
+			r.in = v
+		default:
+			return nil, commonerrors.NewCommandErrorMsgWithArgument(
+				commonerrors.ErrReduceUnknownArgument,
+				fmt.Sprintf("$reduce found an unknown argument: %s", k),
+				"$reduce",
+			)
+		}
+	}
+
+	for _, p := range []struct {
+		name string
+		code commonerrors.ErrorCode
+	}{
+		{"input", commonerrors.ErrReduceMissingInput},
+		{"initialValue", commonerrors.ErrReduceMissingInitialValue},
+		{"in", commonerrors.ErrReduceMissingIn},
+	} {
+		if !params.Has(p.name) {
+			return nil, commonerrors.NewCommandErrorMsgWithArgument(
+				p.code,
+				fmt.Sprintf("$reduce requires '%s' to be specified", p.name),
+				"$reduce",
+			)
+		}
+	}
+
+	return r, nil
+}
+
+// Process implements Operator interface.
+func (r *reduce) Process(doc *types.Document) (any, error) {
+	return r.processVariables(doc, nil)
+}
+
+// processVariables implements variablesOperator interface.
+//
+// The `in` expression is evaluated for each element of the input array
+// with `$$this` set to that element and `$$value` set to the accumulated value.
+func (r *reduce) processVariables(doc *types.Document, vars Variables) (any, error) {
+	input, err := evaluate(doc, vars, r.input)
+	if err != nil {
+		return nil, err
+	}
+
+	if isNullish(input) {
+		return types.Null, nil
+	}
+
+	arr, ok := input.(*types.Array)
+	if !ok {
+		return nil, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrReduceInputNotArray,
+			fmt.Sprintf("input to $reduce must be an array not %s", commonparams.AliasFromType(input)),
+			"$reduce",
+		)
+	}
+
+	value, err := evaluate(doc, vars, r.initialValue)
+	if err != nil {
+		return nil, err
+	}
+
+	iter := arr.Iterator()
+	defer iter.Close()
+
+	for {
+		_, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if value, err = evaluate(doc, vars.with("value", value).with("this", v), r.in); err != nil {
+			return nil, err
+		}
+	}
+
+	if value == nil {
+		return types.Null, nil
+	}
+
+	return value, nil
+}
+
+// check interfaces
+var (
+	_ variablesOperator = (*reduce)(nil)
+)
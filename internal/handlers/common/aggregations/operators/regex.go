@@ -0,0 +1,303 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/FerretDB/FerretDB/internal/handlers/commonerrors"
+	"github.com/FerretDB/FerretDB/internal/handlers/commonparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// regexMode represents the kind of regex operator.
+type regexMode int
+
+const (
+	regexModeMatch   regexMode = iota // $regexMatch
+	regexModeFind                     // $regexFind
+	regexModeFindAll                  // $regexFindAll
+)
+
+// regexOp represents `$regexMatch`, `$regexFind` and `$regexFindAll` operators.
+type regexOp struct {
+	operator string
+	mode     regexMode
+
+	input   any
+	regex   any
+	options any
+}
+
+// newRegexOperator returns a function that creates regex operator with the given mode.
+func newRegexOperator(operator string, mode regexMode) newOperatorFunc {
+	return func(args ...any) (Operator, error) {
+		var params *types.Document
+
+		if len(args) == 1 {
+			params, _ = args[0].(*types.Document)
+		}
+
+		if params == nil {
+			found := commonparams.TypeCodeArray.String()
+			if len(args) == 1 {
+				found = commonparams.AliasFromType(args[0])
+			}
+
+			return nil, commonerrors.NewCommandErrorMsgWithArgument(
+				commonerrors.ErrRegexNotObject,
+				fmt.Sprintf("%s expects an object of named arguments but found: %s", operator, found),
+				operator,
+			)
+		}
+
+		op := &regexOp{
+			operator: operator,
+			mode:     mode,
+		}
+
+		for _, k := range params.Keys() {
+			v := must.NotFail(params.Get(k))
+
+			switch k {
+			case "input":
+				op.input = v
+			case "regex":
+				op.regex = v
+			case "options":
+				op.options = v
+			default:
+				return nil, commonerrors.NewCommandErrorMsgWithArgument(
+					commonerrors.ErrRegexUnknownArgument,
+					fmt.Sprintf("%s found an unknown argument: %s", operator, k),
+					operator,
+				)
+			}
+		}
+
+		if !params.Has("input") {
+			return nil, commonerrors.NewCommandErrorMsgWithArgument(
+				commonerrors.ErrRegexMissingInput,
+				fmt.Sprintf("%s requires 'input' parameter", operator),
+				operator,
+			)
+		}
+
+		if !params.Has("regex") {
+			return nil, commonerrors.NewCommandErrorMsgWithArgument(
+				commonerrors.ErrRegexMissingRegex,
+				fmt.Sprintf("%s requires 'regex' parameter", operator),
+				operator,
+			)
+		}
+
+		return op, nil
+	}
+}
+
+// Process implements Operator interface.
+func (r *regexOp) Process(doc *types.Document) (any, error) {
+	return r.processVariables(doc, nil)
+}
+
+// processVariables implements variablesOperator interface.
+func (r *regexOp) processVariables(doc *types.Document, vars Variables) (any, error) {
+	input, err := evaluate(doc, vars, r.input)
+	if err != nil {
+		return nil, err
+	}
+
+	re, err := r.compile(doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	var s string
+
+	switch input := input.(type) {
+	case string:
+		s = input
+	case nil, types.NullType:
+		return r.noMatch(), nil
+	default:
+		return nil, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrRegexInputNotString,
+			fmt.Sprintf("%s needs 'input' to be of type string", r.operator),
+			r.operator,
+		)
+	}
+
+	if re == nil {
+		return r.noMatch(), nil
+	}
+
+	switch r.mode {
+	case regexModeMatch:
+		return re.MatchString(s), nil
+
+	case regexModeFind:
+		loc := re.FindStringSubmatchIndex(s)
+		if loc == nil {
+			return types.Null, nil
+		}
+
+		return regexMatchDocument(s, loc), nil
+
+	case regexModeFindAll:
+		res := types.MakeArray(0)
+
+		for _, loc := range re.FindAllStringSubmatchIndex(s, -1) {
+			res.Append(regexMatchDocument(s, loc))
+		}
+
+		return res, nil
+
+	default:
+		panic(fmt.Sprintf("unexpected regex mode %d", r.mode))
+	}
+}
+
+// compile evaluates `regex` and `options` arguments and returns compiled regular expression.
+// It returns nil if regex is null or missing.
+func (r *regexOp) compile(doc *types.Document, vars Variables) (*regexp.Regexp, error) {
+	regexValue, err := evaluate(doc, vars, r.regex)
+	if err != nil {
+		return nil, err
+	}
+
+	optionsValue, err := evaluate(doc, vars, r.options)
+	if err != nil {
+		return nil, err
+	}
+
+	var options string
+
+	switch optionsValue := optionsValue.(type) {
+	case nil, types.NullType:
+	case string:
+		options = optionsValue
+	default:
+		return nil, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrRegexOptionsNotString,
+			fmt.Sprintf("%s needs 'options' to be of type string", r.operator),
+			r.operator,
+		)
+	}
+
+	var regex types.Regex
+
+	switch regexValue := regexValue.(type) {
+	case nil, types.NullType:
+		return nil, nil
+	case string:
+		regex = types.Regex{Pattern: regexValue, Options: options}
+	case types.Regex:
+		if regexValue.Options != "" && options != "" {
+			return nil, commonerrors.NewCommandErrorMsgWithArgument(
+				commonerrors.ErrRegexOptionsConflict,
+				fmt.Sprintf("%s found regex option(s) specified in both 'regex' and 'option' fields", r.operator),
+				r.operator,
+			)
+		}
+
+		regex = regexValue
+		if options != "" {
+			regex.Options = options
+		}
+	default:
+		return nil, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrRegexInvalidType,
+			fmt.Sprintf("%s needs 'regex' to be of type string or regex", r.operator),
+			r.operator,
+		)
+	}
+
+	for _, option := range regex.Options {
+		if !strings.ContainsRune("imsx", option) {
+			return nil, commonerrors.NewCommandErrorMsgWithArgument(
+				commonerrors.ErrBadRegexOption,
+				fmt.Sprintf("%s invalid flag in regex options: %c", r.operator, option),
+				r.operator,
+			)
+		}
+	}
+
+	re, err := regex.Compile()
+
+	switch {
+	case err == nil:
+		return re, nil
+	case errors.Is(err, types.ErrOptionNotImplemented):
+		return nil, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrNotImplemented,
+			`option 'x' not implemented`,
+			r.operator,
+		)
+	default:
+		return nil, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrRegexInvalid,
+			fmt.Sprintf("Invalid Regex in %s: %s", r.operator, err),
+			r.operator,
+		)
+	}
+}
+
+// noMatch returns the result of operator for null or missing input or regex.
+func (r *regexOp) noMatch() any {
+	switch r.mode {
+	case regexModeMatch:
+		return false
+	case regexModeFind:
+		return types.Null
+	case regexModeFindAll:
+		return types.MakeArray(0)
+	default:
+		panic(fmt.Sprintf("unexpected regex mode %d", r.mode))
+	}
+}
+
+// regexMatchDocument returns a document describing the match at the given location
+// as returned by regexp.FindStringSubmatchIndex.
+//
+// The index of the match is the number of code points before the match.
+// Captures that did not participate in the match are null.
+func regexMatchDocument(s string, loc []int) *types.Document {
+	captures := types.MakeArray(len(loc)/2 - 1)
+
+	for i := 2; i < len(loc); i += 2 {
+		if loc[i] < 0 {
+			captures.Append(types.Null)
+			continue
+		}
+
+		captures.Append(s[loc[i]:loc[i+1]])
+	}
+
+	return must.NotFail(types.NewDocument(
+		"match", s[loc[0]:loc[1]],
+		"idx", int32(utf8.RuneCountInString(s[:loc[0]])),
+		"captures", captures,
+	))
+}
+
+// check interfaces
+var (
+	_ variablesOperator = (*regexOp)(nil)
+)
@@ -0,0 +1,166 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/FerretDB/FerretDB/internal/handlers/commonerrors"
+	"github.com/FerretDB/FerretDB/internal/handlers/commonparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// replace represents `$replaceOne` and `$replaceAll` operators.
+type replace struct {
+	operator string
+	all      bool
+
+	input       any
+	find        any
+	replacement any
+}
+
+// newReplaceOperator returns a function that creates `$replaceOne` or `$replaceAll` operator.
+func newReplaceOperator(operator string, all bool) newOperatorFunc {
+	return func(args ...any) (Operator, error) {
+		var params *types.Document
+
+		if len(args) == 1 {
+			params, _ = args[0].(*types.Document)
+		}
+
+		if params == nil {
+			found := commonparams.TypeCodeArray.String()
+			if len(args) == 1 {
+				found = commonparams.AliasFromType(args[0])
+			}
+
+			return nil, commonerrors.NewCommandErrorMsgWithArgument(
+				commonerrors.ErrReplaceNotObject,
+				fmt.Sprintf("%s requires an object as an argument, found: %s", operator, found),
+				operator,
+			)
+		}
+
+		r := &replace{
+			operator: operator,
+			all:      all,
+		}
+
+		for _, k := range params.Keys() {
+			v := must.NotFail(params.Get(k))
+
+			switch k {
+			case "input":
+				r.input = v
+			case "find":
+				r.find = v
+			case "replacement":
+				r.replacement = v
+			default:
+				return nil, commonerrors.NewCommandErrorMsgWithArgument(
+					commonerrors.ErrReplaceUnknownArgument,
+					fmt.Sprintf("%s found an unknown argument: %s", operator, k),
+					operator,
+				)
+			}
+		}
+
+		for _, p := range []struct {
+			name string
+			code commonerrors.ErrorCode
+		}{
+			{"input", commonerrors.ErrReplaceMissingInput},
+			{"find", commonerrors.ErrReplaceMissingFind},
+			{"replacement", commonerrors.ErrReplaceMissingReplacement},
+		} {
+			if !params.Has(p.name) {
+				return nil, commonerrors.NewCommandErrorMsgWithArgument(
+					p.code,
+					fmt.Sprintf("%s requires '%s' to be specified", operator, p.name),
+					operator,
+				)
+			}
+		}
+
+		return r, nil
+	}
+}
+
+// Process implements Operator interface.
+func (r *replace) Process(doc *types.Document) (any, error) {
+	return r.processVariables(doc, nil)
+}
+
+// processVariables implements variablesOperator interface.
+func (r *replace) processVariables(doc *types.Document, vars Variables) (any, error) {
+	input, err := r.evaluateString(doc, vars, "input", r.input, commonerrors.ErrReplaceInputNotString)
+	if err != nil {
+		return nil, err
+	}
+
+	find, err := r.evaluateString(doc, vars, "find", r.find, commonerrors.ErrReplaceFindNotString)
+	if err != nil {
+		return nil, err
+	}
+
+	replacement, err := r.evaluateString(
+		doc, vars, "replacement", r.replacement, commonerrors.ErrReplaceReplacementNotString,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if input == nil || find == nil || replacement == nil {
+		return types.Null, nil
+	}
+
+	if r.all {
+		return strings.ReplaceAll(*input, *find, *replacement), nil
+	}
+
+	return strings.Replace(*input, *find, *replacement, 1), nil
+}
+
+// evaluateString evaluates the argument with the given name that should be a string.
+// It returns nil for null or missing value.
+func (r *replace) evaluateString(
+	doc *types.Document, vars Variables, name string, arg any, code commonerrors.ErrorCode,
+) (*string, error) {
+	v, err := evaluate(doc, vars, arg)
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := v.(type) {
+	case nil, types.NullType:
+		return nil, nil
+	case string:
+		return &v, nil
+	default:
+		return nil, commonerrors.NewCommandErrorMsgWithArgument(
+			code,
+			fmt.Sprintf("%s requires that '%s' be a string, found: %s", r.operator, name, types.FormatAnyValue(v)),
+			r.operator,
+		)
+	}
+}
+
+// check interfaces
+var (
+	_ variablesOperator = (*replace)(nil)
+)
@@ -0,0 +1,253 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/FerretDB/FerretDB/internal/handlers/commonerrors"
+	"github.com/FerretDB/FerretDB/internal/handlers/commonparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// sortArrayKey represents a single sort key of `$sortArray` operator.
+type sortArrayKey struct {
+	path  *types.Path // nil when array elements are compared as whole values
+	order types.SortType
+}
+
+// sortArray represents `$sortArray` operator.
+type sortArray struct {
+	input any
+	keys  []sortArrayKey
+}
+
+// newSortArray returns `$sortArray` operator.
+func newSortArray(args ...any) (Operator, error) {
+	var params *types.Document
+
+	if len(args) == 1 {
+		params, _ = args[0].(*types.Document)
+	}
+
+	if params == nil {
+		found := commonparams.TypeCodeArray.String()
+		if len(args) == 1 {
+			found = commonparams.AliasFromType(args[0])
+		}
+
+		return nil, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrSortArrayNotObject,
+			fmt.Sprintf("$sortArray requires an object as an argument, found: %s", found),
+			"$sortArray",
+		)
+	}
+
+	s := new(sortArray)
+
+	for _, k := range params.Keys() {
+		v := must.NotFail(params.Get(k))
+
+		switch k {
+		case "input":
+			s.input = v
+		case "sortBy":
+			keys, err := sortArrayKeys(v)
+			if err != nil {
+				return nil, err
+			}
+
+			s.keys = keys
+		default:
+			return nil, commonerrors.NewCommandErrorMsgWithArgument(
+				commonerrors.ErrSortArrayUnknownArgument,
+				fmt.Sprintf("$sortArray found an unknown argument: %s", k),
+				"$sortArray",
+			)
+		}
+	}
+
+	if !params.Has("input") {
+		return nil, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrSortArrayMissingInput,
+			"$sortArray requires 'input' to be specified",
+			"$sortArray",
+		)
+	}
+
+	if !params.Has("sortBy") {
+		return nil, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrSortArrayMissingSortBy,
+			"$sortArray requires 'sortBy' to be specified",
+			"$sortArray",
+		)
+	}
+
+	return s, nil
+}
+
+// sortArrayKeys parses `sortBy` argument of `$sortArray` operator.
+//
+// It is either a sort order which compares elements as whole values,
+// or a document of (possibly dotted) field paths to sort orders.
+func sortArrayKeys(sortBy any) ([]sortArrayKey, error) {
+	sortDoc, ok := sortBy.(*types.Document)
+	if !ok {
+		order, err := sortArrayOrder(sortBy)
+		if err != nil {
+			return nil, err
+		}
+
+		return []sortArrayKey{{order: order}}, nil
+	}
+
+	if sortDoc.Len() == 0 {
+		return nil, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrFailedToParse,
+			"$sortArray sort pattern must not be empty",
+			"$sortArray",
+		)
+	}
+
+	keys := make([]sortArrayKey, 0, sortDoc.Len())
+
+	for _, k := range sortDoc.Keys() {
+		path, err := types.NewPathFromString(k)
+		if err != nil {
+			return nil, commonerrors.NewCommandErrorMsgWithArgument(
+				commonerrors.ErrFailedToParse,
+				fmt.Sprintf("$sortArray has invalid sort path: %q", k),
+				"$sortArray",
+			)
+		}
+
+		order, err := sortArrayOrder(must.NotFail(sortDoc.Get(k)))
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, sortArrayKey{path: &path, order: order})
+	}
+
+	return keys, nil
+}
+
+// sortArrayOrder returns sort order for the given `$sortArray` sort value.
+func sortArrayOrder(v any) (types.SortType, error) {
+	order, err := commonparams.GetWholeNumberParam(v)
+	if err != nil {
+		return 0, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrSortBadOrder,
+			"$sort key ordering must be 1 (for ascending) or -1 (for descending)",
+			"$sortArray",
+		)
+	}
+
+	switch order {
+	case 1:
+		return types.Ascending, nil
+	case -1:
+		return types.Descending, nil
+	default:
+		return 0, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrSortBadOrder,
+			"$sort key ordering must be 1 (for ascending) or -1 (for descending)",
+			"$sortArray",
+		)
+	}
+}
+
+// Process implements Operator interface.
+func (s *sortArray) Process(doc *types.Document) (any, error) {
+	return s.processVariables(doc, nil)
+}
+
+// processVariables implements variablesOperator interface.
+func (s *sortArray) processVariables(doc *types.Document, vars Variables) (any, error) {
+	input, err := evaluate(doc, vars, s.input)
+	if err != nil {
+		return nil, err
+	}
+
+	if isNullish(input) {
+		return types.Null, nil
+	}
+
+	arr, ok := input.(*types.Array)
+	if !ok {
+		return nil, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrSortArrayInputNotArray,
+			fmt.Sprintf(
+				"The input argument to $sortArray must be an array, but was of type: %s",
+				commonparams.AliasFromType(input),
+			),
+			"$sortArray",
+		)
+	}
+
+	values := make([]any, arr.Len())
+	for i := range values {
+		values[i] = must.NotFail(arr.Get(i))
+	}
+
+	sort.SliceStable(values, func(i, j int) bool {
+		for _, key := range s.keys {
+			a, b := key.value(values[i]), key.value(values[j])
+
+			switch types.CompareOrderForSort(a, b, key.order) {
+			case types.Less:
+				return true
+			case types.Greater:
+				return false
+			}
+		}
+
+		return false
+	})
+
+	res := types.MakeArray(len(values))
+	for _, v := range values {
+		res.Append(v)
+	}
+
+	return res, nil
+}
+
+// value returns the value of array element used for comparison by that key.
+// Missing fields and non-document elements are compared as null.
+func (key sortArrayKey) value(v any) any {
+	if key.path == nil {
+		return v
+	}
+
+	d, ok := v.(*types.Document)
+	if !ok {
+		return types.Null
+	}
+
+	res, err := d.GetByPath(*key.path)
+	if err != nil {
+		return types.Null
+	}
+
+	return res
+}
+
+// check interfaces
+var (
+	_ variablesOperator = (*sortArray)(nil)
+)
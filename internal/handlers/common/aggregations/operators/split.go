@@ -0,0 +1,114 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/FerretDB/FerretDB/internal/handlers/commonerrors"
+	"github.com/FerretDB/FerretDB/internal/handlers/commonparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// split represents `$split` operator.
+type split struct {
+	input     any
+	separator any
+}
+
+// newSplit returns `$split` operator.
+func newSplit(args ...any) (Operator, error) {
+	if len(args) != 2 {
+		return nil, newOperatorError(
+			ErrArgsInvalidLen,
+			"$split",
+			fmt.Sprintf("Expression $split takes exactly 2 arguments. %d were passed in.", len(args)),
+		)
+	}
+
+	return &split{
+		input:     args[0],
+		separator: args[1],
+	}, nil
+}
+
+// Process implements Operator interface.
+func (s *split) Process(doc *types.Document) (any, error) {
+	return s.processVariables(doc, nil)
+}
+
+// processVariables implements variablesOperator interface.
+func (s *split) processVariables(doc *types.Document, vars Variables) (any, error) {
+	input, err := evaluate(doc, vars, s.input)
+	if err != nil {
+		return nil, err
+	}
+
+	separator, err := evaluate(doc, vars, s.separator)
+	if err != nil {
+		return nil, err
+	}
+
+	if isNullish(input) || isNullish(separator) {
+		return types.Null, nil
+	}
+
+	str, ok := input.(string)
+	if !ok {
+		return nil, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrSplitInputNotString,
+			fmt.Sprintf(
+				"$split requires an expression that evaluates to a string as a first argument, found: %s",
+				commonparams.AliasFromType(input),
+			),
+			"$split",
+		)
+	}
+
+	sep, ok := separator.(string)
+	if !ok {
+		return nil, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrSplitSeparatorNotString,
+			fmt.Sprintf(
+				"$split requires an expression that evaluates to a string as a second argument, found: %s",
+				commonparams.AliasFromType(separator),
+			),
+			"$split",
+		)
+	}
+
+	if sep == "" {
+		return nil, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrSplitEmptySeparator,
+			"$split requires a non-empty separator",
+			"$split",
+		)
+	}
+
+	parts := strings.Split(str, sep)
+
+	res := types.MakeArray(len(parts))
+	for _, p := range parts {
+		res.Append(p)
+	}
+
+	return res, nil
+}
+
+// check interfaces
+var (
+	_ variablesOperator = (*split)(nil)
+)
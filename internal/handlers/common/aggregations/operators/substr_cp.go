@@ -0,0 +1,179 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/FerretDB/FerretDB/internal/handlers/commonerrors"
+	"github.com/FerretDB/FerretDB/internal/handlers/commonparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// substrCP represents `$substrCP` operator.
+type substrCP struct {
+	input  any
+	start  any
+	length any
+}
+
+// newSubstrCP returns `$substrCP` operator.
+func newSubstrCP(args ...any) (Operator, error) {
+	if len(args) != 3 {
+		return nil, newOperatorError(
+			ErrArgsInvalidLen,
+			"$substrCP",
+			fmt.Sprintf("Expression $substrCP takes exactly 3 arguments. %d were passed in.", len(args)),
+		)
+	}
+
+	return &substrCP{
+		input:  args[0],
+		start:  args[1],
+		length: args[2],
+	}, nil
+}
+
+// Process implements Operator interface.
+func (s *substrCP) Process(doc *types.Document) (any, error) {
+	return s.processVariables(doc, nil)
+}
+
+// processVariables implements variablesOperator interface.
+func (s *substrCP) processVariables(doc *types.Document, vars Variables) (any, error) {
+	input, err := evaluate(doc, vars, s.input)
+	if err != nil {
+		return nil, err
+	}
+
+	str, err := coerceToString(input)
+	if err != nil {
+		return nil, err
+	}
+
+	startValue, err := evaluate(doc, vars, s.start)
+	if err != nil {
+		return nil, err
+	}
+
+	start, err := substrCPInt32(startValue, "starting index",
+		commonerrors.ErrSubstrStartNotNumber, commonerrors.ErrSubstrStartNotInt32,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	lengthValue, err := evaluate(doc, vars, s.length)
+	if err != nil {
+		return nil, err
+	}
+
+	length, err := substrCPInt32(lengthValue, "length",
+		commonerrors.ErrSubstrLengthNotNumber, commonerrors.ErrSubstrLengthNotInt32,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if start < 0 {
+		return nil, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrSubstrStartNegative,
+			fmt.Sprintf("$substrCP: starting index must be non-negative (got: %d)", start),
+			"$substrCP",
+		)
+	}
+
+	if length < 0 {
+		return nil, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrSubstrLengthNegative,
+			"$substrCP: length must be a nonnegative integer.",
+			"$substrCP",
+		)
+	}
+
+	runes := []rune(str)
+
+	if int(start) >= len(runes) {
+		return "", nil
+	}
+
+	end := min(int(start)+int(length), len(runes))
+
+	return string(runes[start:end]), nil
+}
+
+// substrCPInt32 returns the value of `$substrCP` numeric argument.
+func substrCPInt32(v any, name string, notNumberCode, notInt32Code commonerrors.ErrorCode) (int32, error) {
+	var f float64
+
+	switch v := v.(type) {
+	case int32:
+		return v, nil
+	case int64:
+		f = float64(v)
+	case float64:
+		f = v
+	default:
+		typeName := "missing"
+		if v != nil {
+			typeName = commonparams.AliasFromType(v)
+		}
+
+		return 0, commonerrors.NewCommandErrorMsgWithArgument(
+			notNumberCode,
+			fmt.Sprintf("$substrCP: %s must be a numeric type (is BSON type %s)", name, typeName),
+			"$substrCP",
+		)
+	}
+
+	if f != math.Trunc(f) || f < math.MinInt32 || f > math.MaxInt32 {
+		return 0, commonerrors.NewCommandErrorMsgWithArgument(
+			notInt32Code,
+			fmt.Sprintf("$substrCP: %s cannot be represented as a 32-bit integral value: %s", name, types.FormatAnyValue(v)),
+			"$substrCP",
+		)
+	}
+
+	return int32(f), nil
+}
+
+// coerceToString converts the value to string the same way as string operators do.
+// Null and missing values are converted to an empty string.
+func coerceToString(v any) (string, error) {
+	switch v := v.(type) {
+	case nil, types.NullType:
+		return "", nil
+	case string, float64, int32, int64, time.Time:
+		res, err := convertToString(v)
+		if err != nil {
+			return "", err
+		}
+
+		return res.(string), nil
+	default:
+		return "", commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrCannotConvertToString,
+			fmt.Sprintf("can't convert from BSON type %s to String", commonparams.AliasFromType(v)),
+			"$substrCP",
+		)
+	}
+}
+
+// check interfaces
+var (
+	_ variablesOperator = (*substrCP)(nil)
+)
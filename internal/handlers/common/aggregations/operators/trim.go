@@ -0,0 +1,160 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/FerretDB/FerretDB/internal/handlers/commonerrors"
+	"github.com/FerretDB/FerretDB/internal/handlers/commonparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// trimDefaultChars contains characters removed by `$trim` when `chars` is not specified.
+const trimDefaultChars = "\x00 \t\n\v\f\r\u00a0\u1680" +
+	"\u2000\u2001\u2002\u2003\u2004\u2005\u2006\u2007\u2008\u2009\u200a"
+
+// trim represents `$trim`, `$ltrim` and `$rtrim` operators.
+type trim struct {
+	operator string
+	left     bool
+	right    bool
+
+	input any
+	chars any
+}
+
+// newTrimOperator returns a function that creates trim operator
+// that removes characters from the left and/or right side of the string.
+func newTrimOperator(operator string, left, right bool) newOperatorFunc {
+	return func(args ...any) (Operator, error) {
+		var params *types.Document
+
+		if len(args) == 1 {
+			params, _ = args[0].(*types.Document)
+		}
+
+		if params == nil {
+			return nil, commonerrors.NewCommandErrorMsgWithArgument(
+				commonerrors.ErrTrimNotObject,
+				fmt.Sprintf("%s only supports an object as its argument", operator),
+				operator,
+			)
+		}
+
+		t := &trim{
+			operator: operator,
+			left:     left,
+			right:    right,
+		}
+
+		for _, k := range params.Keys() {
+			v := must.NotFail(params.Get(k))
+
+			switch k {
+			case "input":
+				t.input = v
+			case "chars":
+				t.chars = v
+			default:
+				return nil, commonerrors.NewCommandErrorMsgWithArgument(
+					commonerrors.ErrTrimUnknownField,
+					fmt.Sprintf("%s got unrecognized field: %s", operator, k),
+					operator,
+				)
+			}
+		}
+
+		if !params.Has("input") {
+			return nil, commonerrors.NewCommandErrorMsgWithArgument(
+				commonerrors.ErrTrimMissingInput,
+				fmt.Sprintf("%s requires an 'input' field", operator),
+				operator,
+			)
+		}
+
+		return t, nil
+	}
+}
+
+// Process implements Operator interface.
+func (t *trim) Process(doc *types.Document) (any, error) {
+	return t.processVariables(doc, nil)
+}
+
+// processVariables implements variablesOperator interface.
+func (t *trim) processVariables(doc *types.Document, vars Variables) (any, error) {
+	input, err := evaluate(doc, vars, t.input)
+	if err != nil {
+		return nil, err
+	}
+
+	if isNullish(input) {
+		return types.Null, nil
+	}
+
+	s, ok := input.(string)
+	if !ok {
+		return nil, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrTrimInputNotString,
+			fmt.Sprintf(
+				"%s requires its input to be a string, got %s (of type %s) instead.",
+				t.operator, types.FormatAnyValue(input), commonparams.AliasFromType(input),
+			),
+			t.operator,
+		)
+	}
+
+	cutset := trimDefaultChars
+
+	if t.chars != nil {
+		chars, err := evaluate(doc, vars, t.chars)
+		if err != nil {
+			return nil, err
+		}
+
+		if isNullish(chars) {
+			return types.Null, nil
+		}
+
+		if cutset, ok = chars.(string); !ok {
+			return nil, commonerrors.NewCommandErrorMsgWithArgument(
+				commonerrors.ErrTrimCharsNotString,
+				fmt.Sprintf(
+					"%s requires 'chars' to be a string, got %s (of type %s) instead.",
+					t.operator, types.FormatAnyValue(chars), commonparams.AliasFromType(chars),
+				),
+				t.operator,
+			)
+		}
+	}
+
+	if t.left {
+		s = strings.TrimLeft(s, cutset)
+	}
+
+	if t.right {
+		s = strings.TrimRight(s, cutset)
+	}
+
+	return s, nil
+}
+
+// check interfaces
+var (
+	_ variablesOperator = (*trim)(nil)
+)
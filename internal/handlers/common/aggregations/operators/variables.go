@@ -0,0 +1,212 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/FerretDB/FerretDB/internal/handlers/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handlers/commonerrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// Variables maps names of aggregation expression variables (without `$$` prefix) to their values.
+//
+// It contains user variables defined by the `let` command parameter and operators like `$map`,
+// and system variables with values fixed for the whole command like `$$NOW`.
+// Variables that depend on the processed document (`$$ROOT`, `$$CURRENT`) and `$$REMOVE`
+// are handled separately.
+//
+// Nil value is valid; it has no user variables defined.
+type Variables map[string]any
+
+// NewVariables returns variables for a single command with the given `let` parameter.
+// The let document could be nil.
+//
+// Variable values are evaluated once; they can't reference document fields,
+// but could reference system variables and variables defined before them.
+func NewVariables(let *types.Document) (Variables, error) {
+	vars := Variables{
+		"NOW": now(),
+	}
+
+	if let == nil {
+		return vars, nil
+	}
+
+	for _, name := range let.Keys() {
+		if err := validateVariableName(name); err != nil {
+			return nil, err
+		}
+
+		v, err := evaluate(nil, vars, must.NotFail(let.Get(name)))
+		if err != nil {
+			return nil, err
+		}
+
+		vars[name] = v
+	}
+
+	return vars, nil
+}
+
+// with returns a copy of variables with the given variable set.
+// Variable with the same name defined in the outer scope is shadowed.
+func (vars Variables) with(name string, value any) Variables {
+	res := make(Variables, len(vars)+1)
+
+	for k, v := range vars {
+		res[k] = v
+	}
+
+	res[name] = value
+
+	return res
+}
+
+// variablesOperator is implemented by operators that support aggregation expression variables.
+type variablesOperator interface {
+	Operator
+
+	// processVariables is the same as Process, but with the given variables defined.
+	processVariables(doc *types.Document, vars Variables) (any, error)
+}
+
+// ProcessOperator processes operator with the given variables defined if operator supports them.
+func ProcessOperator(op Operator, doc *types.Document, vars Variables) (any, error) {
+	if vo, ok := op.(variablesOperator); ok {
+		return vo.processVariables(doc, vars)
+	}
+
+	return op.Process(doc)
+}
+
+// EvaluateVariable returns the value of variable expression like `$$this`, `$$NOW` or `$$ROOT.field`
+// for the given document.
+//
+// If the variable is `$$REMOVE`, or the field path points to the missing field, nil is returned.
+// The document could be nil when expression is validated; in that case `$$ROOT` is missing.
+// CommandError is returned for undefined variables.
+func EvaluateVariable(doc *types.Document, vars Variables, expression string) (any, error) {
+	name, path, _ := strings.Cut(strings.TrimPrefix(expression, "$$"), ".")
+
+	// reuse validation of variable names
+	_, err := aggregations.NewExpression(expression, nil)
+
+	var exErr *aggregations.ExpressionError
+	if !errors.As(err, &exErr) || exErr.Code() != aggregations.ErrUndefinedVariable {
+		return nil, err
+	}
+
+	var value any
+
+	switch name {
+	case "ROOT", "CURRENT":
+		if doc != nil {
+			// copy to allow storing the value in the same document
+			value = doc.DeepCopy()
+		}
+
+	case "REMOVE":
+		return nil, nil
+
+	case "NOW":
+		var ok bool
+		if value, ok = vars[name]; !ok {
+			value = now()
+		}
+
+	default:
+		var ok bool
+		if value, ok = vars[name]; !ok {
+			return nil, commonerrors.NewCommandErrorMsgWithArgument(
+				commonerrors.ErrGroupUndefinedVariable,
+				fmt.Sprintf("Use of undefined variable: %s", name),
+				"$$"+name,
+			)
+		}
+	}
+
+	if path == "" || value == nil {
+		return value, nil
+	}
+
+	// evaluate the path on the document containing variable value,
+	// that handles values inside embedded arrays the same way as fields
+	expr, err := aggregations.NewExpression("$v."+path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := expr.Evaluate(must.NotFail(types.NewDocument("v", value)))
+	if err != nil {
+		return nil, nil
+	}
+
+	return res, nil
+}
+
+// now returns the value of `$$NOW` system variable.
+func now() time.Time {
+	// BSON dates have millisecond precision
+	return time.Now().UTC().Truncate(time.Millisecond)
+}
+
+// validateVariableName checks the name of user-defined variable (for example, set by `as` argument).
+func validateVariableName(name string) error {
+	if name == "" {
+		return commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrEmptyVariableName,
+			"empty variable names are not allowed",
+			name,
+		)
+	}
+
+	for i, r := range name {
+		if i == 0 {
+			if !unicode.IsLower(r) && r < unicode.MaxASCII {
+				return commonerrors.NewCommandErrorMsgWithArgument(
+					commonerrors.ErrVariableNameInvalidStart,
+					fmt.Sprintf("'%s' starts with an invalid character for a user variable name", name),
+					name,
+				)
+			}
+
+			continue
+		}
+
+		if r < unicode.MaxASCII && r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return commonerrors.NewCommandErrorMsgWithArgument(
+				commonerrors.ErrVariableNameInvalidChar,
+				fmt.Sprintf("'%s' contains an invalid character for a variable name: '%c'", name, r),
+				name,
+			)
+		}
+	}
+
+	return nil
+}
+
+// isTruthy returns true if the value is considered true by aggregation expressions.
+//
+// False, null, missing value and zero numbers are false, all other values are true.
+func isTruthy(v any) bool {
+	return !isNullish(v) && convertToBool(v).(bool)
+}
@@ -0,0 +1,210 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+
+	"github.com/FerretDB/FerretDB/internal/handlers/commonerrors"
+	"github.com/FerretDB/FerretDB/internal/handlers/commonparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// zip represents `$zip` operator.
+type zip struct {
+	inputs           *types.Array
+	defaults         *types.Array
+	useLongestLength bool
+}
+
+// newZip returns `$zip` operator.
+func newZip(args ...any) (Operator, error) {
+	var params *types.Document
+
+	if len(args) == 1 {
+		params, _ = args[0].(*types.Document)
+	}
+
+	if params == nil {
+		found := commonparams.TypeCodeArray.String()
+		if len(args) == 1 {
+			found = commonparams.AliasFromType(args[0])
+		}
+
+		return nil, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrZipNotObject,
+			fmt.Sprintf("$zip only supports an object as an argument, found %s", found),
+			"$zip",
+		)
+	}
+
+	z := new(zip)
+
+	for _, k := range params.Keys() {
+		v := must.NotFail(params.Get(k))
+
+		var ok bool
+
+		switch k {
+		case "inputs":
+			if z.inputs, ok = v.(*types.Array); !ok {
+				return nil, commonerrors.NewCommandErrorMsgWithArgument(
+					commonerrors.ErrZipInputsNotArray,
+					fmt.Sprintf("inputs must be an array of expressions, found %s", commonparams.AliasFromType(v)),
+					"$zip",
+				)
+			}
+		case "defaults":
+			if z.defaults, ok = v.(*types.Array); !ok {
+				return nil, commonerrors.NewCommandErrorMsgWithArgument(
+					commonerrors.ErrZipDefaultsNotArray,
+					fmt.Sprintf("defaults must be an array of expressions, found %s", commonparams.AliasFromType(v)),
+					"$zip",
+				)
+			}
+		case "useLongestLength":
+			if z.useLongestLength, ok = v.(bool); !ok {
+				return nil, commonerrors.NewCommandErrorMsgWithArgument(
+					commonerrors.ErrZipUseLongestLengthNotBool,
+					fmt.Sprintf("useLongestLength must be a bool, found %s", commonparams.AliasFromType(v)),
+					"$zip",
+				)
+			}
+		default:
+			return nil, commonerrors.NewCommandErrorMsgWithArgument(
+				commonerrors.ErrZipUnknownArgument,
+				fmt.Sprintf("$zip found an unknown argument: %s", k),
+				"$zip",
+			)
+		}
+	}
+
+	if z.inputs == nil {
+		return nil, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrZipMissingInputs,
+			"Missing 'inputs' parameter to $zip",
+			"$zip",
+		)
+	}
+
+	if z.defaults != nil {
+		if !z.useLongestLength {
+			return nil, commonerrors.NewCommandErrorMsgWithArgument(
+				commonerrors.ErrZipDefaultsWithoutLongest,
+				"cannot specify defaults unless useLongestLength is true",
+				"$zip",
+			)
+		}
+
+		if z.defaults.Len() != z.inputs.Len() {
+			return nil, commonerrors.NewCommandErrorMsgWithArgument(
+				commonerrors.ErrZipDefaultsLength,
+				"defaults and inputs must have the same length",
+				"$zip",
+			)
+		}
+	}
+
+	return z, nil
+}
+
+// Process implements Operator interface.
+func (z *zip) Process(doc *types.Document) (any, error) {
+	return z.processVariables(doc, nil)
+}
+
+// processVariables implements variablesOperator interface.
+func (z *zip) processVariables(doc *types.Document, vars Variables) (any, error) {
+	inputs := make([]*types.Array, z.inputs.Len())
+
+	var minLen, maxLen int
+
+	for i := range inputs {
+		v, err := evaluate(doc, vars, must.NotFail(z.inputs.Get(i)))
+		if err != nil {
+			return nil, err
+		}
+
+		if isNullish(v) {
+			return types.Null, nil
+		}
+
+		arr, ok := v.(*types.Array)
+		if !ok {
+			return nil, commonerrors.NewCommandErrorMsgWithArgument(
+				commonerrors.ErrZipInputNotArray,
+				fmt.Sprintf("$zip found a non-array expression in input: %s", types.FormatAnyValue(v)),
+				"$zip",
+			)
+		}
+
+		inputs[i] = arr
+
+		if i == 0 || arr.Len() < minLen {
+			minLen = arr.Len()
+		}
+
+		maxLen = max(maxLen, arr.Len())
+	}
+
+	defaults := make([]any, len(inputs))
+
+	for i := range defaults {
+		defaults[i] = types.Null
+
+		if z.defaults == nil {
+			continue
+		}
+
+		v, err := evaluate(doc, vars, must.NotFail(z.defaults.Get(i)))
+		if err != nil {
+			return nil, err
+		}
+
+		if v != nil {
+			defaults[i] = v
+		}
+	}
+
+	n := minLen
+	if z.useLongestLength {
+		n = maxLen
+	}
+
+	res := types.MakeArray(n)
+
+	for j := 0; j < n; j++ {
+		tuple := types.MakeArray(len(inputs))
+
+		for i, arr := range inputs {
+			if j < arr.Len() {
+				tuple.Append(must.NotFail(arr.Get(j)))
+				continue
+			}
+
+			tuple.Append(defaults[i])
+		}
+
+		res.Append(tuple)
+	}
+
+	return res, nil
+}
+
+// check interfaces
+var (
+	_ variablesOperator = (*zip)(nil)
+)
@@ -20,6 +20,7 @@ import (
 
 	"github.com/FerretDB/FerretDB/internal/handlers/common"
 	"github.com/FerretDB/FerretDB/internal/handlers/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handlers/common/aggregations/operators"
 	"github.com/FerretDB/FerretDB/internal/handlers/commonerrors"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/iterator"
@@ -31,10 +32,11 @@ import (
 //	{ $addFields: { <newField>: <expression>, ... } }
 type addFields struct {
 	newField *types.Document
+	vars     operators.Variables
 }
 
 // newAddFields validates stage document and creates a new $addFields stage.
-func newAddFields(stage *types.Document) (aggregations.Stage, error) {
+func newAddFields(stage *types.Document, vars operators.Variables) (aggregations.Stage, error) {
 	fields, err := stage.Get("$addFields")
 	if err != nil {
 		return nil, lazyerrors.Error(err)
@@ -57,14 +59,19 @@ func newAddFields(stage *types.Document) (aggregations.Stage, error) {
 		return nil, err
 	}
 
+	if err := validateVariables(fieldsDoc, vars); err != nil {
+		return nil, err
+	}
+
 	return &addFields{
 		newField: fieldsDoc,
+		vars:     vars,
 	}, nil
 }
 
 // Process implements Stage interface.
 func (s *addFields) Process(_ context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) { //nolint:lll // for readability
-	return common.AddFieldsIterator(iter, closer, s.newField), nil
+	return common.AddFieldsIterator(iter, closer, s.newField, s.vars), nil
 }
 
 // check interfaces
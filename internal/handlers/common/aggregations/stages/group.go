@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/FerretDB/FerretDB/internal/handlers/common"
@@ -45,6 +46,7 @@ import (
 // For each group of documents, accumulators are applied.
 type group struct {
 	groupExpression any
+	vars            operators.Variables
 	groupBy         []groupBy
 }
 
@@ -55,7 +57,7 @@ type groupBy struct {
 }
 
 // newGroup creates a new $group stage.
-func newGroup(stage *types.Document) (aggregations.Stage, error) {
+func newGroup(stage *types.Document, vars operators.Variables) (aggregations.Stage, error) {
 	fields, err := common.GetRequiredParam[*types.Document](stage, "$group")
 	if err != nil {
 		return nil, commonerrors.NewCommandErrorMsgWithArgument(
@@ -83,7 +85,7 @@ func newGroup(stage *types.Document) (aggregations.Stage, error) {
 		}
 
 		if field == "_id" {
-			if err = validateGroupKey(v); err != nil {
+			if err = validateGroupKey(v, vars); err != nil {
 				return nil, err
 			}
 
@@ -91,7 +93,7 @@ func newGroup(stage *types.Document) (aggregations.Stage, error) {
 			continue
 		}
 
-		accumulator, err := accumulators.NewAccumulator("$group", field, v)
+		accumulator, err := accumulators.NewAccumulator("$group", field, v, vars)
 		if err != nil {
 			return nil, processGroupStageError(err)
 		}
@@ -112,6 +114,7 @@ func newGroup(stage *types.Document) (aggregations.Stage, error) {
 
 	return &group{
 		groupExpression: groupKey,
+		vars:            vars,
 		groupBy:         groups,
 	}, nil
 }
@@ -161,7 +164,16 @@ func (g *group) Process(ctx context.Context, iter types.DocumentsIterator, close
 
 // validateGroupKey returns error on invalid group key.
 // If group key is a document, it recursively validates operator and expression.
-func validateGroupKey(groupKey any) error {
+// Used variables should be defined.
+func validateGroupKey(groupKey any, vars operators.Variables) error {
+	if s, ok := groupKey.(string); ok && strings.HasPrefix(s, "$$") {
+		if _, err := operators.EvaluateVariable(nil, vars, s); err != nil {
+			return processGroupStageError(err)
+		}
+
+		return nil
+	}
+
 	doc, ok := groupKey.(*types.Document)
 	if !ok {
 		return nil
@@ -173,7 +185,7 @@ func validateGroupKey(groupKey any) error {
 			return processGroupStageError(err)
 		}
 
-		_, err = op.Process(nil)
+		_, err = operators.ProcessOperator(op, nil, vars)
 		if err != nil {
 			// TODO https://github.com/FerretDB/FerretDB/issues/3129
 			return processGroupStageError(err)
@@ -206,8 +218,16 @@ func validateGroupKey(groupKey any) error {
 
 		switch v := v.(type) {
 		case *types.Document:
-			return validateGroupKey(v)
+			return validateGroupKey(v, vars)
 		case string:
+			if strings.HasPrefix(v, "$$") {
+				if _, err = operators.EvaluateVariable(nil, vars, v); err != nil {
+					return processGroupStageError(err)
+				}
+
+				continue
+			}
+
 			_, err := aggregations.NewExpression(v, nil)
 			var exprErr *aggregations.ExpressionError
 
@@ -241,7 +261,7 @@ func (g *group) groupDocuments(iter types.DocumentsIterator) ([]groupedDocuments
 
 		switch groupKey := g.groupExpression.(type) {
 		case *types.Document:
-			val, err := evaluateDocument(groupKey, doc, false)
+			val, err := evaluateDocument(groupKey, doc, false, g.vars)
 			if err != nil {
 				// operator and expression errors are validated in newGroup
 				return nil, lazyerrors.Error(err)
@@ -252,6 +272,23 @@ func (g *group) groupDocuments(iter types.DocumentsIterator) ([]groupedDocuments
 			types.Regex, int32, types.Timestamp, int64:
 			m.addOrAppend(groupKey, doc)
 		case string:
+			if strings.HasPrefix(groupKey, "$$") {
+				val, err := operators.EvaluateVariable(doc, g.vars, groupKey)
+				if err != nil {
+					// variables are validated in newGroup
+					return nil, lazyerrors.Error(err)
+				}
+
+				// $group treats missing values as nulls
+				if val == nil {
+					val = types.Null
+				}
+
+				m.addOrAppend(val, doc)
+
+				continue
+			}
+
 			expression, err := aggregations.NewExpression(groupKey, nil)
 			if err != nil {
 				var exprErr *aggregations.ExpressionError
@@ -282,8 +319,8 @@ func (g *group) groupDocuments(iter types.DocumentsIterator) ([]groupedDocuments
 	return m.docs, nil
 }
 
-// evaluateDocument recursively evaluates document's field expressions and operators.
-func evaluateDocument(expr, doc *types.Document, nestedField bool) (any, error) {
+// evaluateDocument recursively evaluates document's field expressions, variables and operators.
+func evaluateDocument(expr, doc *types.Document, nestedField bool, vars operators.Variables) (any, error) {
 	if operators.IsOperator(expr) {
 		op, err := operators.NewOperator(expr)
 		if err != nil {
@@ -291,7 +328,7 @@ func evaluateDocument(expr, doc *types.Document, nestedField bool) (any, error)
 			return nil, processGroupStageError(err)
 		}
 
-		v, err := op.Process(doc)
+		v, err := operators.ProcessOperator(op, doc, vars)
 		if err != nil {
 			// operator and expression errors are validated in newGroup
 			return nil, processGroupStageError(err)
@@ -317,13 +354,34 @@ func evaluateDocument(expr, doc *types.Document, nestedField bool) (any, error)
 
 		switch exprVal := exprVal.(type) {
 		case *types.Document:
-			v, err := evaluateDocument(exprVal, doc, true)
+			v, err := evaluateDocument(exprVal, doc, true, vars)
 			if err != nil {
 				return nil, lazyerrors.Error(err)
 			}
 
 			evaluatedDocument.Set(k, v)
 		case string:
+			if strings.HasPrefix(exprVal, "$$") {
+				v, err := operators.EvaluateVariable(doc, vars, exprVal)
+				if err != nil {
+					// variables are validated in newGroup
+					return nil, lazyerrors.Error(err)
+				}
+
+				// missing value (for example, `$$REMOVE`) is handled like non-existent path
+				if v == nil {
+					if expr.Len() == 1 && !nestedField {
+						evaluatedDocument.Set(k, types.Null)
+					}
+
+					continue
+				}
+
+				evaluatedDocument.Set(k, v)
+
+				continue
+			}
+
 			expression, err := aggregations.NewExpression(exprVal, nil)
 
 			var exprErr *aggregations.ExpressionError
@@ -19,6 +19,7 @@ import (
 
 	"github.com/FerretDB/FerretDB/internal/handlers/common"
 	"github.com/FerretDB/FerretDB/internal/handlers/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handlers/common/aggregations/operators"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/iterator"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
@@ -30,7 +31,7 @@ type limit struct {
 }
 
 // newLimit creates a new $limit stage.
-func newLimit(stage *types.Document) (aggregations.Stage, error) {
+func newLimit(stage *types.Document, _ operators.Variables) (aggregations.Stage, error) {
 	doc, err := stage.Get("$limit")
 	if err != nil {
 		return nil, lazyerrors.Error(err)
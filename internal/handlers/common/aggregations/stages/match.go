@@ -28,10 +28,11 @@ import (
 // match represents $match stage.
 type match struct {
 	filter *types.Document
+	vars   operators.Variables
 }
 
 // newMatch creates a new $match stage.
-func newMatch(stage *types.Document) (aggregations.Stage, error) {
+func newMatch(stage *types.Document, vars operators.Variables) (aggregations.Stage, error) {
 	filter, err := common.GetRequiredParam[*types.Document](stage, "$match")
 	if err != nil {
 		return nil, commonerrors.NewCommandErrorMsgWithArgument(
@@ -41,24 +42,25 @@ func newMatch(stage *types.Document) (aggregations.Stage, error) {
 		)
 	}
 
-	if err := validateMatch(filter); err != nil {
+	if err := validateMatch(filter, vars); err != nil {
 		return nil, err
 	}
 
 	return &match{
 		filter: filter,
+		vars:   vars,
 	}, nil
 }
 
 // Process implements Stage interface.
 func (m *match) Process(ctx context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) { //nolint:lll // for readability
-	return common.FilterIterator(iter, closer, m.filter), nil
+	return common.FilterIterator(iter, closer, m.filter, m.vars), nil
 }
 
 // validateMatch validates $expr field if any.
-func validateMatch(filter *types.Document) error {
+func validateMatch(filter *types.Document, vars operators.Variables) error {
 	if filter.Has("$expr") {
-		_, err := operators.NewExpr(filter, "$match (stage)")
+		_, err := operators.NewExpr(filter, vars, "$match (stage)")
 		if err != nil {
 			return err
 		}
@@ -19,6 +19,7 @@ import (
 
 	"github.com/FerretDB/FerretDB/internal/handlers/common"
 	"github.com/FerretDB/FerretDB/internal/handlers/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handlers/common/aggregations/operators"
 	"github.com/FerretDB/FerretDB/internal/handlers/common/aggregations/stages/projection"
 	"github.com/FerretDB/FerretDB/internal/handlers/commonerrors"
 	"github.com/FerretDB/FerretDB/internal/types"
@@ -36,10 +37,11 @@ import (
 type project struct {
 	projection *types.Document
 	inclusion  bool
+	vars       operators.Variables
 }
 
 // newProject validates projection document and creates a new $project stage.
-func newProject(stage *types.Document) (aggregations.Stage, error) {
+func newProject(stage *types.Document, vars operators.Variables) (aggregations.Stage, error) {
 	fields, err := common.GetRequiredParam[*types.Document](stage, "$project")
 	if err != nil {
 		return nil, commonerrors.NewCommandErrorMsgWithArgument(
@@ -49,7 +51,7 @@ func newProject(stage *types.Document) (aggregations.Stage, error) {
 		)
 	}
 
-	validated, inclusion, err := projection.ValidateProjection(fields)
+	validated, inclusion, err := projection.ValidateProjection(fields, vars)
 	if err != nil {
 		return nil, err
 	}
@@ -57,6 +59,7 @@ func newProject(stage *types.Document) (aggregations.Stage, error) {
 	return &project{
 		projection: validated,
 		inclusion:  inclusion,
+		vars:       vars,
 	}, nil
 }
 
@@ -64,7 +67,7 @@ func newProject(stage *types.Document) (aggregations.Stage, error) {
 //
 //nolint:lll // for readability
 func (p *project) Process(_ context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) {
-	return projection.ProjectionIterator(iter, closer, p.projection)
+	return projection.ProjectionIterator(iter, closer, p.projection, p.vars)
 }
 
 // check interfaces
@@ -45,8 +45,11 @@ import (
 //   - `ErrWrongPositionalOperatorLocation` when there are multiple `$`;
 //   - `ErrAggregatePositionalProject` when `$` is used in the suffix key;
 //   - `ErrAggregatePositionalProject` when positional projection contains empty path;
-//   - `ErrNotImplemented` when there is unimplemented projection operators and expressions.
-func ValidateProjection(projection *types.Document) (*types.Document, bool, error) {
+//   - `ErrNotImplemented` when there is unimplemented projection operators and expressions;
+//   - `ErrGroupUndefinedVariable` when undefined variable is used.
+//
+// Given variables are available for aggregation expressions; they could be nil.
+func ValidateProjection(projection *types.Document, vars operators.Variables) (*types.Document, bool, error) {
 	validated := types.MakeDocument(0)
 
 	if projection.Len() == 0 {
@@ -163,7 +166,14 @@ func ValidateProjection(projection *types.Document) (*types.Document, bool, erro
 				return nil, false, err
 			}
 
-			_, err = op.Process(must.NotFail(types.NewDocument("key", "value")))
+			_, err = operators.ProcessOperator(op, must.NotFail(types.NewDocument("key", "value")), vars)
+
+			// failure to convert the value of the dummy document is not a validation error
+			var cmdErr *commonerrors.CommandError
+			if errors.As(err, &cmdErr) && cmdErr.Code() == commonerrors.ErrConversionFailure {
+				err = nil
+			}
+
 			if err = processOperatorError(err); err != nil {
 				return nil, false, err
 			}
@@ -173,7 +183,17 @@ func ValidateProjection(projection *types.Document) (*types.Document, bool, erro
 
 			result = true
 
-		case *types.Array, string, types.Binary, types.ObjectID,
+		case string:
+			if strings.HasPrefix(value, "$$") {
+				if _, err = operators.EvaluateVariable(nil, vars, value); err != nil {
+					return nil, false, processOperatorError(err)
+				}
+			}
+
+			result = true
+
+			validated.Set(key, value)
+		case *types.Array, types.Binary, types.ObjectID,
 			time.Time, types.NullType, types.Regex, types.Timestamp: // all this types are treated as new fields value
 			result = true
 
@@ -237,7 +257,8 @@ func ValidateProjection(projection *types.Document) (*types.Document, bool, erro
 }
 
 // ProjectDocument applies projection to the copy of the document.
-func ProjectDocument(doc, projection *types.Document, inclusion bool) (*types.Document, error) {
+// Given variables are available for aggregation expressions; they could be nil.
+func ProjectDocument(doc, projection *types.Document, inclusion bool, vars operators.Variables) (*types.Document, error) {
 	projected, err := types.NewDocument("_id", must.NotFail(doc.Get("_id")))
 	if err != nil {
 		return nil, err
@@ -265,15 +286,29 @@ func ProjectDocument(doc, projection *types.Document, inclusion bool) (*types.Do
 				return nil, processOperatorError(err)
 			}
 
-			value, err = op.Process(doc)
+			value, err = operators.ProcessOperator(op, doc, vars)
 			if err != nil {
 				return nil, err
 			}
 
-			set = true
-			projected.Set("_id", value)
+			if value != nil {
+				set = true
+				projected.Set("_id", value)
+			}
 
-		case *types.Array, string, types.Binary, types.ObjectID,
+		case string:
+			var value any
+
+			if value, err = evaluateVariable(doc, vars, idValue); err != nil {
+				return nil, err
+			}
+
+			if value != nil {
+				set = true
+				projected.Set("_id", value)
+			}
+
+		case *types.Array, types.Binary, types.ObjectID,
 			time.Time, types.NullType, types.Regex, types.Timestamp: // all this types are treated as new fields value
 			projected.Set("_id", idValue)
 
@@ -291,7 +326,7 @@ func ProjectDocument(doc, projection *types.Document, inclusion bool) (*types.Do
 		}
 	}
 
-	projectedWithoutID, err := projectDocumentWithoutID(doc, projection, inclusion)
+	projectedWithoutID, err := projectDocumentWithoutID(doc, projection, inclusion, vars)
 	if err != nil {
 		// TODO https://github.com/FerretDB/FerretDB/issues/2633
 		return nil, err
@@ -306,7 +341,7 @@ func ProjectDocument(doc, projection *types.Document, inclusion bool) (*types.Do
 
 // projectDocumentWithoutID applies projection to the copy of the document and returns projected document.
 // It ignores _id field in the projection.
-func projectDocumentWithoutID(doc *types.Document, projection *types.Document, inclusion bool) (*types.Document, error) {
+func projectDocumentWithoutID(doc, projection *types.Document, inclusion bool, vars operators.Variables) (*types.Document, error) {
 	projectionWithoutID := projection.DeepCopy()
 	projectionWithoutID.Remove("_id")
 
@@ -353,14 +388,28 @@ func projectDocumentWithoutID(doc *types.Document, projection *types.Document, i
 				return nil, processOperatorError(err)
 			}
 
-			v, err = op.Process(doc)
+			v, err = operators.ProcessOperator(op, doc, vars)
 			if err != nil {
 				return nil, err
 			}
 
-			projected.Set(key, v)
+			// missing value (for example, `$$REMOVE`) is not projected
+			if v != nil {
+				projected.Set(key, v)
+			}
+
+		case string:
+			var v any
+
+			if v, err = evaluateVariable(doc, vars, value); err != nil {
+				return nil, err
+			}
+
+			if v != nil {
+				projected.Set(key, v)
+			}
 
-		case *types.Array, string, types.Binary, types.ObjectID,
+		case *types.Array, types.Binary, types.ObjectID,
 			time.Time, types.NullType, types.Regex, types.Timestamp: // all these types are treated as new fields value
 			projected.Set(key, value)
 
@@ -621,6 +670,17 @@ func setBySourceOrder(key string, val any, source, projected *types.Document) {
 	}
 }
 
+// evaluateVariable returns the value of projected string.
+// Variable expressions like `$$ROOT` are evaluated, other strings are returned as is.
+// Nil is returned for `$$REMOVE`.
+func evaluateVariable(doc *types.Document, vars operators.Variables, value string) (any, error) {
+	if !strings.HasPrefix(value, "$$") {
+		return value, nil
+	}
+
+	return operators.EvaluateVariable(doc, vars, value)
+}
+
 // processOperatorError takes internal error related to operator evaluation and
 // returns proper CommandError that can be returned by $project aggregation stage.
 //
@@ -20,6 +20,7 @@ import (
 
 	"github.com/FerretDB/FerretDB/internal/handlers/common"
 	"github.com/FerretDB/FerretDB/internal/handlers/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handlers/common/aggregations/operators"
 	"github.com/FerretDB/FerretDB/internal/handlers/commonerrors"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/iterator"
@@ -33,10 +34,11 @@ import (
 //	{ $set: { <newField>: <expression>, ... } }
 type set struct {
 	newField *types.Document
+	vars     operators.Variables
 }
 
 // newSet validates stage document and creates a new $set stage.
-func newSet(stage *types.Document) (aggregations.Stage, error) {
+func newSet(stage *types.Document, vars operators.Variables) (aggregations.Stage, error) {
 	fields, err := stage.Get("$set")
 	if err != nil {
 		return nil, lazyerrors.Error(err)
@@ -59,14 +61,19 @@ func newSet(stage *types.Document) (aggregations.Stage, error) {
 		return nil, err
 	}
 
+	if err := validateVariables(fieldsDoc, vars); err != nil {
+		return nil, err
+	}
+
 	return &set{
 		newField: fieldsDoc,
+		vars:     vars,
 	}, nil
 }
 
 // Process implements Stage interface.
 func (s *set) Process(_ context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) { //nolint:lll // for readability
-	return common.AddFieldsIterator(iter, closer, s.newField), nil
+	return common.AddFieldsIterator(iter, closer, s.newField, s.vars), nil
 }
 
 // check interfaces
@@ -19,6 +19,7 @@ import (
 
 	"github.com/FerretDB/FerretDB/internal/handlers/common"
 	"github.com/FerretDB/FerretDB/internal/handlers/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handlers/common/aggregations/operators"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/iterator"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
@@ -30,7 +31,7 @@ type skip struct {
 }
 
 // newSkip creates a new $skip stage.
-func newSkip(stage *types.Document) (aggregations.Stage, error) {
+func newSkip(stage *types.Document, _ operators.Variables) (aggregations.Stage, error) {
 	value, err := stage.Get("$skip")
 	if err != nil {
 		return nil, lazyerrors.Error(err)
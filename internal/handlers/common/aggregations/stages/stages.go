@@ -19,12 +19,14 @@ import (
 	"fmt"
 
 	"github.com/FerretDB/FerretDB/internal/handlers/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handlers/common/aggregations/operators"
 	"github.com/FerretDB/FerretDB/internal/handlers/commonerrors"
 	"github.com/FerretDB/FerretDB/internal/types"
 )
 
 // newStageFunc is a type for a function that creates a new aggregation stage.
-type newStageFunc func(stage *types.Document) (aggregations.Stage, error)
+// Given variables are available for aggregation expressions used by the stage.
+type newStageFunc func(stage *types.Document, vars operators.Variables) (aggregations.Stage, error)
 
 // Stages maps all supported aggregation Stages.
 var Stages = map[string]newStageFunc{
@@ -78,7 +80,8 @@ var unsupportedStages = map[string]struct{}{
 }
 
 // NewStage creates a new aggregation stage.
-func NewStage(stage *types.Document) (aggregations.Stage, error) {
+// Given variables are available for aggregation expressions; they could be nil.
+func NewStage(stage *types.Document, vars operators.Variables) (aggregations.Stage, error) {
 	if stage.Len() != 1 {
 		return nil, commonerrors.NewCommandErrorMsgWithArgument(
 			commonerrors.ErrStageInvalid,
@@ -97,7 +100,7 @@ func NewStage(stage *types.Document) (aggregations.Stage, error) {
 		panic(fmt.Sprintf("stage %q is in both `stages` and `unsupportedStages`", name))
 
 	case supported && !unsupported:
-		return f(stage)
+		return f(stage, vars)
 
 	case !supported && unsupported:
 		return nil, commonerrors.NewCommandErrorMsgWithArgument(
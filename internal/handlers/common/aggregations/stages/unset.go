@@ -21,6 +21,7 @@ import (
 	"strings"
 
 	"github.com/FerretDB/FerretDB/internal/handlers/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handlers/common/aggregations/operators"
 	"github.com/FerretDB/FerretDB/internal/handlers/common/aggregations/stages/projection"
 	"github.com/FerretDB/FerretDB/internal/handlers/commonerrors"
 	"github.com/FerretDB/FerretDB/internal/types"
@@ -39,7 +40,7 @@ type unset struct {
 }
 
 // newUnset validates unset document and creates a new $unset stage.
-func newUnset(stage *types.Document) (aggregations.Stage, error) {
+func newUnset(stage *types.Document, _ operators.Variables) (aggregations.Stage, error) {
 	fields := must.NotFail(stage.Get("$unset"))
 
 	// exclusion contains keys with `false` values to specify projection exclusion later.
@@ -141,7 +142,7 @@ func newUnset(stage *types.Document) (aggregations.Stage, error) {
 // Process implements Stage interface.
 func (u *unset) Process(_ context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) { //nolint:lll // for readability
 	// Use $project to unset fields, $unset is alias for $project exclusion.
-	return projection.ProjectionIterator(iter, closer, u.exclusion)
+	return projection.ProjectionIterator(iter, closer, u.exclusion, nil)
 }
 
 // validateUnsetField returns error on invalid field value.
@@ -21,6 +21,7 @@ import (
 
 	"github.com/FerretDB/FerretDB/internal/handlers/common"
 	"github.com/FerretDB/FerretDB/internal/handlers/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handlers/common/aggregations/operators"
 	"github.com/FerretDB/FerretDB/internal/handlers/commonerrors"
 	"github.com/FerretDB/FerretDB/internal/handlers/commonpath"
 	"github.com/FerretDB/FerretDB/internal/types"
@@ -35,7 +36,7 @@ type unwind struct {
 }
 
 // newUnwind creates a new $unwind stage.
-func newUnwind(stage *types.Document) (aggregations.Stage, error) {
+func newUnwind(stage *types.Document, _ operators.Variables) (aggregations.Stage, error) {
 	field, err := stage.Get("$unwind")
 	if err != nil {
 		return nil, err
@@ -19,10 +19,13 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/FerretDB/FerretDB/internal/handlers/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handlers/common/aggregations/operators"
 	"github.com/FerretDB/FerretDB/internal/handlers/commonerrors"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/iterator"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
 )
 
 // validateExpression recursively validates expressions in document and array
@@ -107,3 +110,40 @@ func validateFieldPath(stage string, fieldsDoc *types.Document) error {
 
 	return nil
 }
+
+// validateVariables validates variable expressions like `$$NOW` used as field values.
+// Command Errors:
+//   - ErrGroupUndefinedVariable
+//   - ErrFailedToParse
+func validateVariables(fieldsDoc *types.Document, vars operators.Variables) error {
+	for _, key := range fieldsDoc.Keys() {
+		v, ok := must.NotFail(fieldsDoc.Get(key)).(string)
+		if !ok || !strings.HasPrefix(v, "$$") {
+			continue
+		}
+
+		_, err := operators.EvaluateVariable(nil, vars, v)
+
+		var exErr *aggregations.ExpressionError
+		if !errors.As(err, &exErr) {
+			return err
+		}
+
+		switch exErr.Code() {
+		case aggregations.ErrEmptyVariable:
+			return commonerrors.NewCommandErrorMsgWithArgument(
+				commonerrors.ErrFailedToParse,
+				"empty variable names are not allowed",
+				key,
+			)
+		default:
+			return commonerrors.NewCommandErrorMsgWithArgument(
+				commonerrors.ErrFailedToParse,
+				fmt.Sprintf("'%s' starts with an invalid character for a user variable name", exErr.Name()),
+				key,
+			)
+		}
+	}
+
+	return nil
+}
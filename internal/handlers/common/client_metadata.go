@@ -17,13 +17,21 @@ package common
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
 	"github.com/FerretDB/FerretDB/internal/handlers/commonerrors"
 	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/wire"
 )
 
-// CheckClientMetadata checks if the message does not contain client metadata after it was received already.
+// maxClientMetadataSize is the maximum size of the client metadata document in bytes, as in MongoDB.
+const maxClientMetadataSize = 512
+
+// CheckClientMetadata checks if the message does not contain client metadata after it was received already,
+// and that the client metadata document is not too large.
+// If it is received for the first time, it is stored in the connection info.
 func CheckClientMetadata(ctx context.Context, doc *types.Document) error {
 	c, _ := doc.Get("client")
 	if c == nil {
@@ -38,7 +46,26 @@ func CheckClientMetadata(ctx context.Context, doc *types.Document) error {
 		)
 	}
 
+	md, _ := c.(*types.Document)
+	if md != nil {
+		size, err := wire.DocumentSize(md)
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		if size > maxClientMetadataSize {
+			return commonerrors.NewCommandErrorMsg(
+				commonerrors.ErrClientMetadataDocumentTooLarge,
+				fmt.Sprintf("The client metadata document must be less than or equal to %d bytes", maxClientMetadataSize),
+			)
+		}
+	}
+
 	connInfo.SetMetadataRecv()
 
+	if md != nil {
+		connInfo.SetClientMetadata(md.DeepCopy())
+	}
+
 	return nil
 }
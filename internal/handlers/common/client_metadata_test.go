@@ -17,6 +17,7 @@ package common
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -51,6 +52,19 @@ func TestCheckClientMetadata(t *testing.T) {
 
 	empty := must.NotFail(types.NewDocument())
 
+	large := must.NotFail(types.NewDocument(
+		"client", must.NotFail(types.NewDocument(
+			"application", must.NotFail(types.NewDocument(
+				"name", strings.Repeat("a", 512),
+			)),
+		)),
+	))
+
+	tooLarge := commonerrors.NewCommandErrorMsg(
+		commonerrors.ErrClientMetadataDocumentTooLarge,
+		"The client metadata document must be less than or equal to 512 bytes",
+	)
+
 	for name, tc := range map[string][]struct { //nolint:vet // used for test only
 		document *types.Document
 		err      error
@@ -74,6 +88,10 @@ func TestCheckClientMetadata(t *testing.T) {
 			{document: empty},
 			{document: empty},
 		},
+		"TooLarge": {
+			{document: large, err: tooLarge},
+			{document: metadata, recv: true},
+		},
 		"2xClientMetadataDocument": {
 			{document: metadata, recv: true},
 			{
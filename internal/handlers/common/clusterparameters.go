@@ -0,0 +1,300 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"golang.org/x/exp/maps"
+
+	"github.com/FerretDB/FerretDB/internal/handlers/commonerrors"
+	"github.com/FerretDB/FerretDB/internal/handlers/commonparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// Fixed database and collection names for storing cluster parameters.
+const (
+	ClusterParametersDatabase   = "config"
+	ClusterParametersCollection = "clusterParameters"
+)
+
+// clusterParameter represents a single cluster parameter.
+type clusterParameter struct {
+	// defaults returns parameter's fields with default values.
+	defaults func() *types.Document
+
+	// validate checks the value passed to setClusterParameter.
+	validate func(value *types.Document) error
+}
+
+// clusterParameters contains all supported cluster parameters.
+var clusterParameters = map[string]clusterParameter{
+	"changeStreamOptions": {
+		defaults: func() *types.Document {
+			return must.NotFail(types.NewDocument(
+				"preAndPostImages", must.NotFail(types.NewDocument(
+					"expireAfterSeconds", "off",
+				)),
+			))
+		},
+		validate: validateChangeStreamOptions,
+	},
+}
+
+// CheckClusterParameterDatabase returns an error if cluster parameters command is not run against admin database.
+func CheckClusterParameterDatabase(command, dbName string) error {
+	if dbName == "admin" {
+		return nil
+	}
+
+	return commonerrors.NewCommandErrorMsgWithArgument(
+		commonerrors.ErrUnauthorized,
+		fmt.Sprintf("%s may only be run against the admin database.", command),
+		command,
+	)
+}
+
+// GetClusterParameterNames returns names of cluster parameters requested by getClusterParameter command.
+//
+// The value could be a parameter name, an array of names, or "*" for all parameters.
+func GetClusterParameterNames(document *types.Document) ([]string, error) {
+	command := document.Command()
+
+	var names []string
+
+	switch v := must.NotFail(document.Get(command)).(type) {
+	case string:
+		if v == "*" {
+			names = maps.Keys(clusterParameters)
+			slices.Sort(names)
+
+			return names, nil
+		}
+
+		names = []string{v}
+
+	case *types.Array:
+		iter := v.Iterator()
+		defer iter.Close()
+
+		for {
+			_, name, err := iter.Next()
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				break
+			}
+
+			if err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			s, ok := name.(string)
+			if !ok {
+				return nil, commonerrors.NewCommandErrorMsgWithArgument(
+					commonerrors.ErrTypeMismatch,
+					fmt.Sprintf(
+						"BSON field '%s' is the wrong type '%s', expected type 'string'",
+						command, commonparams.AliasFromType(name),
+					),
+					command,
+				)
+			}
+
+			names = append(names, s)
+		}
+
+	default:
+		return nil, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrTypeMismatch,
+			fmt.Sprintf(
+				"BSON field '%s' is the wrong type '%s', expected types '[string, array]'",
+				command, commonparams.AliasFromType(v),
+			),
+			command,
+		)
+	}
+
+	for _, name := range names {
+		if _, ok := clusterParameters[name]; !ok {
+			return nil, commonerrors.NewCommandErrorMsgWithArgument(
+				commonerrors.ErrNoSuchKey,
+				fmt.Sprintf("Unknown Cluster Parameter %s", name),
+				command,
+			)
+		}
+	}
+
+	return names, nil
+}
+
+// DefaultClusterParameter returns a document for cluster parameter with the given name
+// that was never set with setClusterParameter.
+//
+// The name should be one of the names returned by GetClusterParameterNames.
+func DefaultClusterParameter(name string) *types.Document {
+	res := must.NotFail(types.NewDocument(
+		"_id", name,
+		"clusterParameterTime", types.Timestamp(0),
+	))
+
+	defaults := clusterParameters[name].defaults()
+
+	for _, k := range defaults.Keys() {
+		res.Set(k, must.NotFail(defaults.Get(k)))
+	}
+
+	return res
+}
+
+// GetSetClusterParameterParams validates setClusterParameter command
+// and returns the document that should be stored in ClusterParametersCollection.
+func GetSetClusterParameterParams(document *types.Document) (*types.Document, error) {
+	command := document.Command()
+
+	v := must.NotFail(document.Get(command))
+
+	param, ok := v.(*types.Document)
+	if !ok {
+		return nil, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrTypeMismatch,
+			fmt.Sprintf(
+				"BSON field '%s' is the wrong type '%s', expected type 'object'",
+				command, commonparams.AliasFromType(v),
+			),
+			command,
+		)
+	}
+
+	if param.Len() != 1 {
+		return nil, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrIllegalOperation,
+			"Exactly one cluster parameter should be set at a time",
+			command,
+		)
+	}
+
+	name := param.Keys()[0]
+
+	cp, ok := clusterParameters[name]
+	if !ok {
+		return nil, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrNoSuchKey,
+			fmt.Sprintf("Unknown Cluster Parameter %s", name),
+			command,
+		)
+	}
+
+	value, ok := must.NotFail(param.Get(name)).(*types.Document)
+	if !ok {
+		return nil, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrTypeMismatch,
+			fmt.Sprintf(
+				"BSON field '%s.%s' is the wrong type '%s', expected type 'object'",
+				command, name, commonparams.AliasFromType(must.NotFail(param.Get(name))),
+			),
+			command,
+		)
+	}
+
+	if err := cp.validate(value); err != nil {
+		return nil, err
+	}
+
+	res := must.NotFail(types.NewDocument(
+		"_id", name,
+		"clusterParameterTime", types.NextTimestamp(time.Now()),
+	))
+
+	for _, k := range value.Keys() {
+		res.Set(k, must.NotFail(value.Get(k)))
+	}
+
+	return res, nil
+}
+
+// validateChangeStreamOptions validates changeStreamOptions cluster parameter value.
+func validateChangeStreamOptions(value *types.Document) error {
+	for _, k := range value.Keys() {
+		if k != "preAndPostImages" {
+			return commonerrors.NewCommandErrorMsgWithArgument(
+				commonerrors.ErrFailedToParseInput,
+				fmt.Sprintf("BSON field 'changeStreamOptions.%s' is an unknown field.", k),
+				"setClusterParameter",
+			)
+		}
+	}
+
+	v, _ := value.Get("preAndPostImages")
+	if v == nil {
+		return nil
+	}
+
+	images, ok := v.(*types.Document)
+	if !ok {
+		return commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrTypeMismatch,
+			fmt.Sprintf(
+				"BSON field 'changeStreamOptions.preAndPostImages' is the wrong type '%s', expected type 'object'",
+				commonparams.AliasFromType(v),
+			),
+			"setClusterParameter",
+		)
+	}
+
+	for _, k := range images.Keys() {
+		if k != "expireAfterSeconds" {
+			return commonerrors.NewCommandErrorMsgWithArgument(
+				commonerrors.ErrFailedToParseInput,
+				fmt.Sprintf("BSON field 'changeStreamOptions.preAndPostImages.%s' is an unknown field.", k),
+				"setClusterParameter",
+			)
+		}
+	}
+
+	expire, _ := images.Get("expireAfterSeconds")
+
+	switch expire := expire.(type) {
+	case nil:
+		return nil
+
+	case string:
+		if expire == "off" {
+			return nil
+		}
+
+		return commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrBadValue,
+			fmt.Sprintf("Invalid value '%s' for expireAfterSeconds, expected 'off' or a positive number", expire),
+			"setClusterParameter",
+		)
+
+	default:
+		n, err := commonparams.GetWholeNumberParam(expire)
+		if err == nil && n > 0 {
+			return nil
+		}
+
+		return commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrBadValue,
+			"Invalid value for expireAfterSeconds, expected 'off' or a positive number",
+			"setClusterParameter",
+		)
+	}
+}
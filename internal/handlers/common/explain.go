@@ -36,6 +36,7 @@ type ExplainParams struct {
 	Sort   *types.Document `ferretdb:"sort,opt"`
 	Skip   int64           `ferretdb:"skip,opt"`
 	Limit  int64           `ferretdb:"limit,opt"`
+	Hint   any             `ferretdb:"hint,opt"`
 
 	StagesDocs []any           `ferretdb:"-"`
 	Aggregate  bool            `ferretdb:"-"`
@@ -89,6 +90,8 @@ func GetExplainParams(document *types.Document, l *zap.Logger) (*ExplainParams,
 		return nil, lazyerrors.Error(err)
 	}
 
+	hint, _ := explain.Get("hint")
+
 	var limit, skip int64
 
 	if limit, err = GetLimitParam(explain); err != nil {
@@ -138,6 +141,7 @@ func GetExplainParams(document *types.Document, l *zap.Logger) (*ExplainParams,
 		Collection: collection,
 		Filter:     filter,
 		Sort:       sort,
+		Hint:       hint,
 		Skip:       skip,
 		Limit:      limit,
 		StagesDocs: stagesDocs,
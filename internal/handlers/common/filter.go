@@ -33,9 +33,10 @@ import (
 )
 
 // FilterDocument returns true if given document satisfies given filter expression.
+// Given variables are available for $expr aggregation expressions; they could be nil.
 //
 // Passed arguments must not be modified.
-func FilterDocument(doc, filter *types.Document) (bool, error) {
+func FilterDocument(doc, filter *types.Document, vars operators.Variables) (bool, error) {
 	iter := filter.Iterator()
 	defer iter.Close()
 
@@ -50,7 +51,7 @@ func FilterDocument(doc, filter *types.Document) (bool, error) {
 		}
 
 		// top-level filters are ANDed together
-		matches, err := filterDocumentPair(doc, filterKey, filterValue)
+		matches, err := filterDocumentPair(doc, filterKey, filterValue, vars)
 		if err != nil {
 			return false, lazyerrors.Error(err)
 		}
@@ -96,7 +97,7 @@ func HasQueryOperator(filter *types.Document) (bool, error) {
 }
 
 // filterDocumentPair handles a single filter element key/value pair {filterKey: filterValue}.
-func filterDocumentPair(doc *types.Document, filterKey string, filterValue any) (bool, error) {
+func filterDocumentPair(doc *types.Document, filterKey string, filterValue any, vars operators.Variables) (bool, error) {
 	var vals []any
 	filterSuffix := filterKey
 
@@ -124,7 +125,7 @@ func filterDocumentPair(doc *types.Document, filterKey string, filterValue any)
 
 	if strings.HasPrefix(filterKey, "$") {
 		// {$operator: filterValue}
-		return filterOperator(doc, filterKey, filterValue)
+		return filterOperator(doc, filterKey, filterValue, vars)
 	}
 
 	switch filterValue := filterValue.(type) {
@@ -185,7 +186,7 @@ func filterDocumentPair(doc *types.Document, filterKey string, filterValue any)
 }
 
 // filterOperator handles a top-level operator filter {$operator: filterValue}.
-func filterOperator(doc *types.Document, operator string, filterValue any) (bool, error) {
+func filterOperator(doc *types.Document, operator string, filterValue any, vars operators.Variables) (bool, error) {
 	switch operator {
 	case "$and":
 		// {$and: [{expr1}, {expr2}, ...]}
@@ -220,7 +221,7 @@ func filterOperator(doc *types.Document, operator string, filterValue any) (bool
 		for i := 0; i < exprs.Len(); i++ {
 			expr := must.NotFail(exprs.Get(i)).(*types.Document)
 
-			matches, err := FilterDocument(doc, expr)
+			matches, err := FilterDocument(doc, expr, vars)
 			if err != nil {
 				return false, err
 			}
@@ -264,7 +265,7 @@ func filterOperator(doc *types.Document, operator string, filterValue any) (bool
 		for i := 0; i < exprs.Len(); i++ {
 			expr := must.NotFail(exprs.Get(i)).(*types.Document)
 
-			matches, err := FilterDocument(doc, expr)
+			matches, err := FilterDocument(doc, expr, vars)
 			if err != nil {
 				return false, err
 			}
@@ -308,7 +309,7 @@ func filterOperator(doc *types.Document, operator string, filterValue any) (bool
 		for i := 0; i < exprs.Len(); i++ {
 			expr := must.NotFail(exprs.Get(i)).(*types.Document)
 
-			matches, err := FilterDocument(doc, expr)
+			matches, err := FilterDocument(doc, expr, vars)
 			if err != nil {
 				return false, err
 			}
@@ -323,7 +324,7 @@ func filterOperator(doc *types.Document, operator string, filterValue any) (bool
 		return true, nil
 
 	case "$expr":
-		return filterExprOperator(doc, must.NotFail(types.NewDocument(operator, filterValue)))
+		return filterExprOperator(doc, must.NotFail(types.NewDocument(operator, filterValue)), vars)
 	default:
 		msg := fmt.Sprintf(
 			`unknown top level operator: %s. `+
@@ -341,9 +342,9 @@ func filterOperator(doc *types.Document, operator string, filterValue any) (bool
 // $expr is primary used by operators such as $gt and $cond which return boolean result.
 // However, if non-boolean result is returned from processing aggregation expression,
 // it returns false for null or zero value and true for all other values.
-func filterExprOperator(doc, filter *types.Document) (bool, error) {
+func filterExprOperator(doc, filter *types.Document, vars operators.Variables) (bool, error) {
 	// TODO https://github.com/FerretDB/FerretDB/issues/3170
-	op, err := operators.NewExpr(filter, "$expr")
+	op, err := operators.NewExpr(filter, vars, "$expr")
 	if err != nil {
 		return false, err
 	}
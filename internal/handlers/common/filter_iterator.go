@@ -15,21 +15,24 @@
 package common
 
 import (
+	"github.com/FerretDB/FerretDB/internal/handlers/common/aggregations/operators"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/iterator"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
 )
 
 // FilterIterator returns an iterator that filters out documents that don't match the filter.
+// Given variables are available for $expr aggregation expressions; they could be nil.
 // It will be added to the given closer.
 //
 // Next method returns the next document that matches the filter.
 //
 // Close method closes the underlying iterator.
-func FilterIterator(iter types.DocumentsIterator, closer *iterator.MultiCloser, filter *types.Document) types.DocumentsIterator {
+func FilterIterator(iter types.DocumentsIterator, closer *iterator.MultiCloser, filter *types.Document, vars operators.Variables) types.DocumentsIterator { //nolint:lll // for readability
 	res := &filterIterator{
 		iter:   iter,
 		filter: filter,
+		vars:   vars,
 	}
 	closer.Add(res)
 
@@ -40,6 +43,7 @@ func FilterIterator(iter types.DocumentsIterator, closer *iterator.MultiCloser,
 type filterIterator struct {
 	iter   types.DocumentsIterator
 	filter *types.Document
+	vars   operators.Variables
 }
 
 // Next implements iterator.Interface. See FilterIterator for details.
@@ -52,7 +56,7 @@ func (iter *filterIterator) Next() (struct{}, *types.Document, error) {
 			return unused, nil, lazyerrors.Error(err)
 		}
 
-		matches, err := FilterDocument(doc, iter.filter)
+		matches, err := FilterDocument(doc, iter.filter, iter.vars)
 		if err != nil {
 			return unused, nil, lazyerrors.Error(err)
 		}
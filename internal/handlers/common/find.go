@@ -39,15 +39,15 @@ type FindParams struct {
 	SingleBatch bool            `ferretdb:"singleBatch,opt"`
 	Comment     string          `ferretdb:"comment,opt"`
 	MaxTimeMS   int64           `ferretdb:"maxTimeMS,opt,wholePositiveNumber"`
+	Let         *types.Document `ferretdb:"let,opt"`
 
 	Collation *types.Document `ferretdb:"collation,unimplemented"`
-	Let       *types.Document `ferretdb:"let,unimplemented"`
 
 	AllowDiskUse bool            `ferretdb:"allowDiskUse,ignored"`
 	ReadConcern  *types.Document `ferretdb:"readConcern,ignored"`
 	Max          *types.Document `ferretdb:"max,ignored"`
 	Min          *types.Document `ferretdb:"min,ignored"`
-	Hint         any             `ferretdb:"hint,ignored"`
+	Hint         any             `ferretdb:"hint,opt"`
 	LSID         any             `ferretdb:"lsid,ignored"`
 
 	ReturnKey           bool `ferretdb:"returnKey,unimplemented-non-default"`
@@ -53,13 +53,13 @@ type FindAndModifyParams struct {
 	Upsert            bool            `ferretdb:"upsert,opt"`
 	ReturnNewDocument bool            `ferretdb:"new,opt,numericBool"`
 	MaxTimeMS         int64           `ferretdb:"maxTimeMS,opt,wholePositiveNumber"`
+	Let               *types.Document `ferretdb:"let,opt"`
 
 	Update      *types.Document `ferretdb:"-"`
 	Aggregation *types.Array    `ferretdb:"-"`
 
 	HasUpdateOperators bool `ferretdb:"-"`
 
-	Let          *types.Document `ferretdb:"let,unimplemented"`
 	Collation    *types.Document `ferretdb:"collation,unimplemented"`
 	Fields       *types.Document `ferretdb:"fields,unimplemented"`
 	ArrayFilters *types.Array    `ferretdb:"arrayFilters,unimplemented"`
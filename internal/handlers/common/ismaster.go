@@ -18,6 +18,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
 	"github.com/FerretDB/FerretDB/internal/util/must"
@@ -25,31 +26,39 @@ import (
 )
 
 // IsMaster is a common implementation of the isMaster command used by deprecated OP_QUERY message.
-func IsMaster(ctx context.Context, query *types.Document) (*wire.OpReply, error) {
+func IsMaster(ctx context.Context, query *types.Document, sessionTimeout time.Duration) (*wire.OpReply, error) {
 	if err := CheckClientMetadata(ctx, query); err != nil {
 		return nil, lazyerrors.Error(err)
 	}
 
 	return &wire.OpReply{
 		NumberReturned: 1,
-		Documents:      IsMasterDocuments(),
+		Documents:      IsMasterDocuments(ctx, sessionTimeout),
 	}, nil
 }
 
 // IsMasterDocuments returns isMaster's Documents field (identical for both OP_MSG and OP_QUERY).
-func IsMasterDocuments() []*types.Document {
-	return []*types.Document{must.NotFail(types.NewDocument(
+//
+// Logical sessions are advertised only if sessionTimeout is not zero.
+func IsMasterDocuments(ctx context.Context, sessionTimeout time.Duration) []*types.Document {
+	doc := must.NotFail(types.NewDocument(
 		"ismaster", true, // only lowercase
 		// topologyVersion
 		"maxBsonObjectSize", int32(types.MaxDocumentLen),
 		"maxMessageSizeBytes", int32(wire.MaxMsgLen),
 		"maxWriteBatchSize", int32(100000),
 		"localTime", time.Now(),
-		// logicalSessionTimeoutMinutes
-		"connectionId", int32(42),
-		"minWireVersion", MinWireVersion,
-		"maxWireVersion", MaxWireVersion,
-		"readOnly", false,
-		"ok", float64(1),
-	))}
+	))
+
+	if sessionTimeout != 0 {
+		doc.Set("logicalSessionTimeoutMinutes", int32(sessionTimeout/time.Minute))
+	}
+
+	doc.Set("connectionId", conninfo.Get(ctx).ID)
+	doc.Set("minWireVersion", MinWireVersion)
+	doc.Set("maxWireVersion", MaxWireVersion)
+	doc.Set("readOnly", false)
+	doc.Set("ok", float64(1))
+
+	return []*types.Document{doc}
 }
@@ -21,6 +21,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/FerretDB/FerretDB/internal/handlers/common/aggregations/operators"
 	"github.com/FerretDB/FerretDB/internal/handlers/commonerrors"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/iterator"
@@ -46,8 +47,9 @@ import (
 //   - `ErrBadPositionalProjection` when array or filter at positional projection path is empty;
 //   - `ErrBadPositionalProjection` when there is no filter field key for positional projection path;
 //   - `ErrElementMismatchPositionalProjection` when unexpected array was found on positional projection path;
-//   - `ErrNotImplemented` when there is unimplemented projection operators and expressions.
-func ValidateProjection(projection *types.Document) (*types.Document, bool, error) {
+//   - `ErrNotImplemented` when there is unimplemented projection operators and expressions;
+//   - `ErrGroupUndefinedVariable` when projected variable is not defined.
+func ValidateProjection(projection *types.Document, vars operators.Variables) (*types.Document, bool, error) {
 	validated := types.MakeDocument(0)
 
 	if projection.Len() == 0 {
@@ -151,7 +153,17 @@ func ValidateProjection(projection *types.Document) (*types.Document, bool, erro
 				commonerrors.ErrNotImplemented,
 				fmt.Sprintf("projection expression %s is not supported", types.FormatAnyValue(value)),
 			)
-		case *types.Array, string, types.Binary, types.ObjectID,
+		case string:
+			if strings.HasPrefix(value, "$$") {
+				if _, err = operators.EvaluateVariable(nil, vars, value); err != nil {
+					return nil, false, err
+				}
+			}
+
+			inclusionField = true
+
+			validated.Set(key, value)
+		case *types.Array, types.Binary, types.ObjectID,
 			time.Time, types.NullType, types.Regex, types.Timestamp: // all these types are treated as new fields value
 			inclusionField = true
 
@@ -230,7 +242,7 @@ func ValidateProjection(projection *types.Document) (*types.Document, bool, erro
 // - ErrNotImplemented when the operator is not implemented yet.
 // - ErrOperatorWrongLenOfArgs when the operator has an invalid number of arguments.
 // - ErrInvalidPipelineOperator when an the operator does not exist.
-func ProjectDocument(doc, projection, filter *types.Document, inclusion bool) (*types.Document, error) {
+func ProjectDocument(doc, projection, filter *types.Document, inclusion bool, vars operators.Variables) (*types.Document, error) { //nolint:lll // for readability
 	projected, err := types.NewDocument("_id", must.NotFail(doc.Get("_id")))
 	if err != nil {
 		return nil, err
@@ -250,7 +262,19 @@ func ProjectDocument(doc, projection, filter *types.Document, inclusion bool) (*
 				),
 			)
 
-		case *types.Array, string, types.Binary, types.ObjectID,
+		case string:
+			var value any
+
+			if value, err = evaluateVariable(doc, vars, idValue); err != nil {
+				return nil, err
+			}
+
+			if value != nil {
+				set = true
+				projected.Set("_id", value)
+			}
+
+		case *types.Array, types.Binary, types.ObjectID,
 			time.Time, types.NullType, types.Regex, types.Timestamp: // all this types are treated as new fields value
 			projected.Set("_id", idValue)
 
@@ -267,7 +291,7 @@ func ProjectDocument(doc, projection, filter *types.Document, inclusion bool) (*
 		}
 	}
 
-	projectedWithoutID, err := projectDocumentWithoutID(doc, projection, filter, inclusion)
+	projectedWithoutID, err := projectDocumentWithoutID(doc, projection, filter, inclusion, vars)
 	if err != nil {
 		return nil, err
 	}
@@ -281,7 +305,7 @@ func ProjectDocument(doc, projection, filter *types.Document, inclusion bool) (*
 
 // projectDocumentWithoutID applies projection to the copy of the document and returns projected document.
 // It ignores _id field in the projection.
-func projectDocumentWithoutID(doc *types.Document, projection, filter *types.Document, inclusion bool) (*types.Document, error) {
+func projectDocumentWithoutID(doc *types.Document, projection, filter *types.Document, inclusion bool, vars operators.Variables) (*types.Document, error) { //nolint:lll // for readability
 	projectionWithoutID := projection.DeepCopy()
 	projectionWithoutID.Remove("_id")
 
@@ -321,7 +345,19 @@ func projectDocumentWithoutID(doc *types.Document, projection, filter *types.Doc
 				),
 			)
 
-		case *types.Array, string, types.Binary, types.ObjectID,
+		case string:
+			var v any
+
+			if v, err = evaluateVariable(doc, vars, value); err != nil {
+				return nil, err
+			}
+
+			// missing value (for example, `$$REMOVE`) is not projected
+			if v != nil {
+				projected.Set(key, v)
+			}
+
+		case *types.Array, types.Binary, types.ObjectID,
 			time.Time, types.NullType, types.Regex, types.Timestamp: // all these types are treated as new fields value
 			projected.Set(key, value)
 
@@ -570,6 +606,17 @@ func excludeProjection(path types.Path, projected any) {
 	}
 }
 
+// evaluateVariable returns the value of projected string.
+// Variable expressions like `$$ROOT` are evaluated, other strings are returned as is.
+// Nil is returned for `$$REMOVE`.
+func evaluateVariable(doc *types.Document, vars operators.Variables, value string) (any, error) {
+	if !strings.HasPrefix(value, "$$") {
+		return value, nil
+	}
+
+	return operators.EvaluateVariable(doc, vars, value)
+}
+
 // setBySourceOrder sets the key value field to projected in same field order as the source.
 // Example:
 //
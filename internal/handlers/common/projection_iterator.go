@@ -15,6 +15,7 @@
 package common
 
 import (
+	"github.com/FerretDB/FerretDB/internal/handlers/common/aggregations/operators"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/iterator"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
@@ -26,8 +27,8 @@ import (
 // Next method returns the next projected document.
 //
 // Close method closes the underlying iterator.
-func ProjectionIterator(iter types.DocumentsIterator, closer *iterator.MultiCloser, projection, filter *types.Document) (types.DocumentsIterator, error) { //nolint:lll // for readability
-	projectionValidated, inclusion, err := ValidateProjection(projection)
+func ProjectionIterator(iter types.DocumentsIterator, closer *iterator.MultiCloser, projection, filter *types.Document, vars operators.Variables) (types.DocumentsIterator, error) { //nolint:lll // for readability
+	projectionValidated, inclusion, err := ValidateProjection(projection, vars)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
@@ -37,6 +38,7 @@ func ProjectionIterator(iter types.DocumentsIterator, closer *iterator.MultiClos
 		projection: projectionValidated,
 		filter:     filter,
 		inclusion:  inclusion,
+		vars:       vars,
 	}
 	closer.Add(res)
 
@@ -49,6 +51,7 @@ type projectionIterator struct {
 	projection *types.Document
 	filter     *types.Document // filter is used by positional operator to get first matching array element.
 	inclusion  bool
+	vars       operators.Variables
 }
 
 // Next implements iterator.Interface. See ProjectionIterator for details.
@@ -60,7 +63,7 @@ func (iter *projectionIterator) Next() (struct{}, *types.Document, error) {
 		return unused, nil, lazyerrors.Error(err)
 	}
 
-	projected, err := ProjectDocument(doc, iter.projection, iter.filter, iter.inclusion)
+	projected, err := ProjectDocument(doc, iter.projection, iter.filter, iter.inclusion, iter.vars)
 	if err != nil {
 		return unused, nil, lazyerrors.Error(err)
 	}
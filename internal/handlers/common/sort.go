@@ -73,8 +73,9 @@ func SortDocuments(docs []*types.Document, sortDoc *types.Document) error {
 		return nil
 	}
 
+	// stable sort keeps the natural order for equal documents
 	sorter := &docsSorter{docs: docs, sorts: sortFuncs}
-	sort.Sort(sorter)
+	sort.Stable(sorter)
 
 	return nil
 }
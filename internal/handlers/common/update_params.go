@@ -17,8 +17,10 @@ package common
 import (
 	"go.uber.org/zap"
 
+	"github.com/FerretDB/FerretDB/internal/handlers/commonerrors"
 	"github.com/FerretDB/FerretDB/internal/handlers/commonparams"
 	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
 )
 
 // UpdateParams represents parameters for the update command.
@@ -32,7 +34,7 @@ type UpdateParams struct {
 
 	Comment string `ferretdb:"comment,opt"`
 
-	Let *types.Document `ferretdb:"let,unimplemented"`
+	Let *types.Document `ferretdb:"let,opt"`
 
 	Ordered                  bool            `ferretdb:"ordered,ignored"`
 	BypassDocumentValidation bool            `ferretdb:"bypassDocumentValidation,ignored"`
@@ -58,6 +60,10 @@ type Update struct {
 
 // GetUpdateParams returns parameters for update command.
 func GetUpdateParams(document *types.Document, l *zap.Logger) (*UpdateParams, error) {
+	if err := checkPipelineLet(document); err != nil {
+		return nil, err
+	}
+
 	var params UpdateParams
 
 	err := commonparams.ExtractParams(document, "update", &params, l)
@@ -79,3 +85,38 @@ func GetUpdateParams(document *types.Document, l *zap.Logger) (*UpdateParams, er
 
 	return &params, nil
 }
+
+// checkPipelineLet returns NotImplemented error if let variables are combined with pipeline-style updates,
+// so variables are never silently left unevaluated.
+//
+// TODO https://github.com/FerretDB/FerretDB/issues/2742
+func checkPipelineLet(document *types.Document) error {
+	if !document.Has("let") {
+		return nil
+	}
+
+	v, _ := document.Get("updates")
+
+	updates, ok := v.(*types.Array)
+	if !ok {
+		return nil
+	}
+
+	for i := 0; i < updates.Len(); i++ {
+		update, _ := must.NotFail(updates.Get(i)).(*types.Document)
+		if update == nil {
+			continue
+		}
+
+		u, _ := update.Get("u")
+		if _, ok = u.(*types.Array); ok {
+			return commonerrors.NewCommandErrorMsgWithArgument(
+				commonerrors.ErrNotImplemented,
+				"let variables are not supported with pipeline-style updates yet",
+				"let",
+			)
+		}
+	}
+
+	return nil
+}
@@ -36,6 +36,15 @@ type command struct {
 	//
 	// The passed context is canceled when the client disconnects.
 	Handler func(handlers.Interface, context.Context, *wire.OpMsg) (*wire.OpMsg, error)
+
+	// Anonymous indicates that the command does not require authentication
+	// on listeners that require it.
+	// Such commands are also not scheduled.
+	Anonymous bool
+
+	// Unscheduled indicates that the command runs immediately, bypassing the operation scheduler.
+	// That is used for cheap commands that help to observe a busy server or free its resources.
+	Unscheduled bool
 }
 
 // Commands is a map of Commands that Handler interface can support.
@@ -48,12 +57,18 @@ var Commands = map[string]command{
 		Help:    "Returns aggregated data.",
 		Handler: handlers.Interface.MsgAggregate,
 	},
+	"applyOps": {
+		Help:    "Applies a batch of oplog entries.",
+		Handler: handlers.Interface.MsgApplyOps,
+	},
 	"buildInfo": {
-		Help:    "Returns a summary of the build information.",
-		Handler: handlers.Interface.MsgBuildInfo,
+		Help:      "Returns a summary of the build information.",
+		Handler:   handlers.Interface.MsgBuildInfo,
+		Anonymous: true,
 	},
 	"buildinfo": { // old lowercase variant
-		Handler: handlers.Interface.MsgBuildInfo,
+		Handler:   handlers.Interface.MsgBuildInfo,
+		Anonymous: true,
 	},
 	"collMod": {
 		Help:    "Adds options to a collection or modify view definitions.",
@@ -66,7 +81,8 @@ var Commands = map[string]command{
 	"connectionStatus": {
 		Help: "Returns information about the current connection, " +
 			"specifically the state of authenticated users and their available permissions.",
-		Handler: handlers.Interface.MsgConnectionStatus,
+		Handler:   handlers.Interface.MsgConnectionStatus,
+		Anonymous: true,
 	},
 	"count": {
 		Help:    "Returns the count of documents that's matched by the query.",
@@ -81,8 +97,9 @@ var Commands = map[string]command{
 		Handler: handlers.Interface.MsgCreateIndexes,
 	},
 	"currentOp": {
-		Help:    "Returns information about operations currently in progress.",
-		Handler: handlers.Interface.MsgCurrentOp,
+		Help:        "Returns information about operations currently in progress.",
+		Handler:     handlers.Interface.MsgCurrentOp,
+		Unscheduled: true,
 	},
 	"dataSize": {
 		Help:    "Returns the size of the collection in bytes.",
@@ -119,6 +136,11 @@ var Commands = map[string]command{
 		Help:    "Drops indexes on a collection.",
 		Handler: handlers.Interface.MsgDropIndexes,
 	},
+	"endSessions": {
+		Help:        "Ends logical sessions.",
+		Handler:     handlers.Interface.MsgEndSessions,
+		Unscheduled: true,
+	},
 	"explain": {
 		Help:    "Returns the execution plan.",
 		Handler: handlers.Interface.MsgExplain,
@@ -134,6 +156,10 @@ var Commands = map[string]command{
 	"findandmodify": { // old lowercase variant
 		Handler: handlers.Interface.MsgFindAndModify,
 	},
+	"getClusterParameter": {
+		Help:    "Returns the values of the cluster parameters.",
+		Handler: handlers.Interface.MsgGetClusterParameter,
+	},
 	"getCmdLineOpts": {
 		Help:    "Returns a summary of all runtime and configuration options.",
 		Handler: handlers.Interface.MsgGetCmdLineOpts,
@@ -155,8 +181,9 @@ var Commands = map[string]command{
 		Handler: handlers.Interface.MsgGetParameter,
 	},
 	"hello": {
-		Help:    "Returns the role of the FerretDB instance.",
-		Handler: handlers.Interface.MsgHello,
+		Help:      "Returns the role of the FerretDB instance.",
+		Handler:   handlers.Interface.MsgHello,
+		Anonymous: true,
 	},
 	"hostInfo": {
 		Help:    "Returns a summary of the system information.",
@@ -167,15 +194,18 @@ var Commands = map[string]command{
 		Handler: handlers.Interface.MsgInsert,
 	},
 	"isMaster": {
-		Help:    "Returns the role of the FerretDB instance.",
-		Handler: handlers.Interface.MsgIsMaster,
+		Help:      "Returns the role of the FerretDB instance.",
+		Handler:   handlers.Interface.MsgIsMaster,
+		Anonymous: true,
 	},
 	"ismaster": { // old lowercase variant
-		Handler: handlers.Interface.MsgIsMaster,
+		Handler:   handlers.Interface.MsgIsMaster,
+		Anonymous: true,
 	},
 	"killCursors": {
-		Help:    "Closes server cursors.",
-		Handler: handlers.Interface.MsgKillCursors,
+		Help:        "Closes server cursors.",
+		Handler:     handlers.Interface.MsgKillCursors,
+		Unscheduled: true,
 	},
 	"listCollections": {
 		Help:    "Returns the information of the collections and views in the database.",
@@ -194,24 +224,40 @@ var Commands = map[string]command{
 		Handler: handlers.Interface.MsgListIndexes,
 	},
 	"logout": {
-		Help:    "Logs out from the current session.",
-		Handler: handlers.Interface.MsgLogout,
+		Help:      "Logs out from the current session.",
+		Handler:   handlers.Interface.MsgLogout,
+		Anonymous: true,
 	},
 	"ping": {
-		Help:    "Returns a pong response.",
-		Handler: handlers.Interface.MsgPing,
+		Help:      "Returns a pong response.",
+		Handler:   handlers.Interface.MsgPing,
+		Anonymous: true,
+	},
+	"refreshSessions": {
+		Help:    "Updates the last use time of logical sessions.",
+		Handler: handlers.Interface.MsgRefreshSessions,
 	},
 	"renameCollection": {
 		Help:    "Changes the name of an existing collection.",
 		Handler: handlers.Interface.MsgRenameCollection,
 	},
+	"reshapeCollection": {
+		Help:    "Rewrites the collection into a new physical layout online.",
+		Handler: handlers.Interface.MsgReshapeCollection,
+	},
 	"saslStart": {
-		Help:    "Starts a SASL conversation.",
-		Handler: handlers.Interface.MsgSASLStart,
+		Help:      "Starts a SASL conversation.",
+		Handler:   handlers.Interface.MsgSASLStart,
+		Anonymous: true,
 	},
 	"serverStatus": {
-		Help:    "Returns an overview of the databases state.",
-		Handler: handlers.Interface.MsgServerStatus,
+		Help:        "Returns an overview of the databases state.",
+		Handler:     handlers.Interface.MsgServerStatus,
+		Unscheduled: true,
+	},
+	"setClusterParameter": {
+		Help:    "Modifies the value of the cluster parameter.",
+		Handler: handlers.Interface.MsgSetClusterParameter,
 	},
 	"setFreeMonitoring": {
 		Help:    "Toggles free monitoring.",
@@ -225,6 +271,10 @@ var Commands = map[string]command{
 		Help:    "Validate collection.",
 		Handler: handlers.Interface.MsgValidate,
 	},
+	"validateDBMetadata": {
+		Help:    "Checks that metadata of collections and indexes matches the backend and repairs it.",
+		Handler: handlers.Interface.MsgValidateDBMetadata,
+	},
 	"whatsmyuri": {
 		Help:    "Returns peer information.",
 		Handler: handlers.Interface.MsgWhatsMyURI,
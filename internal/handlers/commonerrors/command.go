@@ -26,9 +26,10 @@ import (
 type CommandError struct {
 	// the order of fields is weird to make the struct smaller due to alignment
 
-	err  error
-	info *ErrInfo
-	code ErrorCode
+	err    error
+	info   *ErrInfo
+	labels []string
+	code   ErrorCode
 }
 
 // There should not be NewCommandError function variant that accepts printf-like format specifiers.
@@ -55,6 +56,16 @@ func NewCommandErrorMsg(code ErrorCode, msg string) error {
 	return NewCommandError(code, errors.New(msg))
 }
 
+// NewCommandErrorMsgWithLabels is variant for NewCommandErrorMsg with error labels
+// (like `RetryableWriteError`) that are returned to the client.
+func NewCommandErrorMsgWithLabels(code ErrorCode, msg string, labels ...string) error {
+	return &CommandError{
+		code:   code,
+		err:    errors.New(msg),
+		labels: labels,
+	}
+}
+
 // NewCommandErrorMsgWithArgument creates a new wire protocol error with an argument that caused the error.
 func NewCommandErrorMsgWithArgument(code ErrorCode, msg string, argument string) error {
 	return &CommandError{
@@ -96,6 +107,15 @@ func (e *CommandError) Document() *types.Document {
 		d.Set("codeName", e.code.String())
 	}
 
+	if len(e.labels) > 0 {
+		labels := types.MakeArray(len(e.labels))
+		for _, l := range e.labels {
+			labels.Append(l)
+		}
+
+		d.Set("errorLabels", labels)
+	}
+
 	return d
 }
 
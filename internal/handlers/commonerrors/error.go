@@ -36,10 +36,14 @@ const (
 	// ErrBadValue indicates wrong input.
 	ErrBadValue = ErrorCode(2) // BadValue
 
+	// ErrNoSuchKey indicates that the requested key (such as a parameter name) does not exist.
+	ErrNoSuchKey = ErrorCode(4) // NoSuchKey
+
 	// ErrFailedToParse indicates user input parsing failure.
 	ErrFailedToParse = ErrorCode(9) // FailedToParse
 
-	// ErrUnauthorized indicates that cursor is not authorized to access another namespace.
+	// ErrUnauthorized indicates that the client is not authorized to run the command
+	// or that cursor is not authorized to access another namespace.
 	ErrUnauthorized = ErrorCode(13) // Unauthorized
 
 	// ErrTypeMismatch for $sort indicates that the expression in the $sort is not an object.
@@ -105,6 +109,9 @@ const (
 	// ErrOperationFailed indicates that the operation failed.
 	ErrOperationFailed = ErrorCode(96) // OperationFailed
 
+	// ErrConflictingOperationInProgress indicates that a conflicting operation is already running.
+	ErrConflictingOperationInProgress = ErrorCode(117) // ConflictingOperationInProgress
+
 	// ErrDocumentValidationFailure indicates that document validation failed.
 	ErrDocumentValidationFailure = ErrorCode(121) // DocumentValidationFailure
 
@@ -114,18 +121,34 @@ const (
 	// ErrInvalidPipelineOperator indicates that provided aggregation operator is invalid.
 	ErrInvalidPipelineOperator = ErrorCode(168) // InvalidPipelineOperator
 
+	// ErrClientMetadataDocumentTooLarge indicates that client metadata document is too large.
+	ErrClientMetadataDocumentTooLarge = ErrorCode(185) // ClientMetadataDocumentTooLarge
+
 	// ErrClientMetadataCannotBeMutated indicates that client metadata cannot be mutated.
 	ErrClientMetadataCannotBeMutated = ErrorCode(186) // ClientMetadataCannotBeMutated
 
 	// ErrNotImplemented indicates that a flag or command is not implemented.
 	ErrNotImplemented = ErrorCode(238) // NotImplemented
 
+	// ErrConversionFailure indicates that the value could not be converted to the requested type.
+	ErrConversionFailure = ErrorCode(241) // ConversionFailure
+
+	// ErrExceededTimeLimit indicates that the operation could not run in time, for example, because the server is busy.
+	// Clients may retry such operations.
+	ErrExceededTimeLimit = ErrorCode(262) // ExceededTimeLimit
+
+	// ErrIndexBuildAborted indicates that the index build was aborted.
+	ErrIndexBuildAborted = ErrorCode(276) // IndexBuildAborted
+
 	// ErrIndexesWrongType indicates that indexes parameter has wrong type.
 	ErrIndexesWrongType = ErrorCode(10065) // Location10065
 
 	// ErrDuplicateKeyInsert indicates duplicate key violation on inserting document.
 	ErrDuplicateKeyInsert = ErrorCode(11000) // Location11000
 
+	// ErrQuotaExceeded indicates that the write would exceed configured storage quota.
+	ErrQuotaExceeded = ErrorCode(12501) // Location12501
+
 	// ErrSetBadExpression indicates set expression is not object.
 	ErrSetBadExpression = ErrorCode(40272) // Location40272
 
@@ -178,18 +201,114 @@ const (
 	// ErrFieldPathInvalidName indicates that FieldPath is invalid.
 	ErrFieldPathInvalidName = ErrorCode(16410) // Location16410
 
+	// ErrCannotConvertToString indicates that the value cannot be converted to string.
+	ErrCannotConvertToString = ErrorCode(16007) // Location16007
+
+	// ErrEmptyVariableName indicates that user-defined variable name is empty.
+	ErrEmptyVariableName = ErrorCode(16866) // Location16866
+
+	// ErrVariableNameInvalidStart indicates that user-defined variable name starts with an invalid character.
+	ErrVariableNameInvalidStart = ErrorCode(16867) // Location16867
+
+	// ErrVariableNameInvalidChar indicates that user-defined variable name contains an invalid character.
+	ErrVariableNameInvalidChar = ErrorCode(16868) // Location16868
+
+	// ErrMapNotObject indicates that the argument of $map is not an object.
+	ErrMapNotObject = ErrorCode(16878) // Location16878
+
+	// ErrMapUnknownArgument indicates that $map has unknown argument.
+	ErrMapUnknownArgument = ErrorCode(16879) // Location16879
+
+	// ErrMapMissingInput indicates that $map has no 'input' argument.
+	ErrMapMissingInput = ErrorCode(16880) // Location16880
+
+	// ErrMapMissingIn indicates that $map has no 'in' argument.
+	ErrMapMissingIn = ErrorCode(16882) // Location16882
+
+	// ErrMapInputNotArray indicates that 'input' of $map is not an array.
+	ErrMapInputNotArray = ErrorCode(16883) // Location16883
+
 	// ErrGroupInvalidFieldPath indicates invalid path is given for group _id.
 	ErrGroupInvalidFieldPath = ErrorCode(16872) // Location16872
 
 	// ErrGroupUndefinedVariable indicates the variable is not defined.
 	ErrGroupUndefinedVariable = ErrorCode(17276) // Location17276
 
+	// ErrZipNotObject indicates that the argument of $zip is not an object.
+	ErrZipNotObject = ErrorCode(34460) // Location34460
+
+	// ErrZipInputsNotArray indicates that 'inputs' of $zip is not an array.
+	ErrZipInputsNotArray = ErrorCode(34461) // Location34461
+
+	// ErrZipDefaultsNotArray indicates that 'defaults' of $zip is not an array.
+	ErrZipDefaultsNotArray = ErrorCode(34462) // Location34462
+
+	// ErrZipUseLongestLengthNotBool indicates that 'useLongestLength' of $zip is not a boolean.
+	ErrZipUseLongestLengthNotBool = ErrorCode(34463) // Location34463
+
+	// ErrZipUnknownArgument indicates that $zip has unknown argument.
+	ErrZipUnknownArgument = ErrorCode(34464) // Location34464
+
+	// ErrZipMissingInputs indicates that $zip has no 'inputs' argument.
+	ErrZipMissingInputs = ErrorCode(34465) // Location34465
+
+	// ErrZipDefaultsWithoutLongest indicates that $zip 'defaults' are set without 'useLongestLength'.
+	ErrZipDefaultsWithoutLongest = ErrorCode(34466) // Location34466
+
+	// ErrZipDefaultsLength indicates that $zip 'defaults' and 'inputs' have different lengths.
+	ErrZipDefaultsLength = ErrorCode(34467) // Location34467
+
+	// ErrZipInputNotArray indicates that one of $zip inputs is not an array.
+	ErrZipInputNotArray = ErrorCode(34468) // Location34468
+
+	// ErrSubstrStartNotNumber indicates that $substrCP starting index is not a number.
+	ErrSubstrStartNotNumber = ErrorCode(34450) // Location34450
+
+	// ErrSubstrStartNotInt32 indicates that $substrCP starting index is not a 32-bit integer.
+	ErrSubstrStartNotInt32 = ErrorCode(34451) // Location34451
+
+	// ErrSubstrLengthNotNumber indicates that $substrCP length is not a number.
+	ErrSubstrLengthNotNumber = ErrorCode(34452) // Location34452
+
+	// ErrSubstrLengthNotInt32 indicates that $substrCP length is not a 32-bit integer.
+	ErrSubstrLengthNotInt32 = ErrorCode(34453) // Location34453
+
+	// ErrSubstrStartNegative indicates that $substrCP starting index is negative.
+	ErrSubstrStartNegative = ErrorCode(34454) // Location34454
+
+	// ErrSubstrLengthNegative indicates that $substrCP length is negative.
+	ErrSubstrLengthNegative = ErrorCode(34455) // Location34455
+
+	// ErrFilterNotObject indicates that the argument of $filter is not an object.
+	ErrFilterNotObject = ErrorCode(28646) // Location28646
+
+	// ErrFilterUnknownArgument indicates that $filter has unknown argument.
+	ErrFilterUnknownArgument = ErrorCode(28647) // Location28647
+
+	// ErrFilterMissingInput indicates that $filter has no 'input' argument.
+	ErrFilterMissingInput = ErrorCode(28648) // Location28648
+
+	// ErrFilterMissingCond indicates that $filter has no 'cond' argument.
+	ErrFilterMissingCond = ErrorCode(28650) // Location28650
+
+	// ErrFilterInputNotArray indicates that 'input' of $filter is not an array.
+	ErrFilterInputNotArray = ErrorCode(28651) // Location28651
+
 	// ErrInvalidArg indicates invalid argument in projection document.
 	ErrInvalidArg = ErrorCode(28667) // Location28667
 
 	// ErrSliceFirstArg for $slice indicates that the first argument is not an array.
 	ErrSliceFirstArg = ErrorCode(28724) // Location28724
 
+	// ErrRegexMissingInput indicates that $regexMatch, $regexFind or $regexFindAll has no 'input' parameter.
+	ErrRegexMissingInput = ErrorCode(31022) // Location31022
+
+	// ErrRegexMissingRegex indicates that $regexMatch, $regexFind or $regexFindAll has no 'regex' parameter.
+	ErrRegexMissingRegex = ErrorCode(31023) // Location31023
+
+	// ErrRegexUnknownArgument indicates that $regexMatch, $regexFind or $regexFindAll has unknown argument.
+	ErrRegexUnknownArgument = ErrorCode(31024) // Location31024
+
 	// ErrStageUnsetNoPath indicates that $unwind aggregation stage is empty.
 	ErrStageUnsetNoPath = ErrorCode(31119) // Location31119
 
@@ -248,6 +367,33 @@ const (
 	// amount of arguments.
 	ErrAddFieldsExpressionWrongAmountOfArgs = ErrorCode(40181) // Location40181
 
+	// ErrSplitInputNotString indicates that the first argument of $split is not a string.
+	ErrSplitInputNotString = ErrorCode(40085) // Location40085
+
+	// ErrSplitSeparatorNotString indicates that the second argument of $split is not a string.
+	ErrSplitSeparatorNotString = ErrorCode(40086) // Location40086
+
+	// ErrSplitEmptySeparator indicates that the second argument of $split is an empty string.
+	ErrSplitEmptySeparator = ErrorCode(40087) // Location40087
+
+	// ErrReduceNotObject indicates that the argument of $reduce is not an object.
+	ErrReduceNotObject = ErrorCode(40075) // Location40075
+
+	// ErrReduceUnknownArgument indicates that $reduce has unknown argument.
+	ErrReduceUnknownArgument = ErrorCode(40076) // Location40076
+
+	// ErrReduceMissingInput indicates that $reduce has no 'input' argument.
+	ErrReduceMissingInput = ErrorCode(40077) // Location40077
+
+	// ErrReduceMissingInitialValue indicates that $reduce has no 'initialValue' argument.
+	ErrReduceMissingInitialValue = ErrorCode(40078) // Location40078
+
+	// ErrReduceMissingIn indicates that $reduce has no 'in' argument.
+	ErrReduceMissingIn = ErrorCode(40079) // Location40079
+
+	// ErrReduceInputNotArray indicates that 'input' of $reduce is not an array.
+	ErrReduceInputNotArray = ErrorCode(40080) // Location40080
+
 	// ErrStageGroupUnaryOperator indicates that $sum is a unary operator.
 	ErrStageGroupUnaryOperator = ErrorCode(40237) // Location40237
 
@@ -275,6 +421,21 @@ const (
 	// ErrCollStatsIsNotFirstStage indicates that $collStats must be the first stage in the pipeline.
 	ErrCollStatsIsNotFirstStage = ErrorCode(40602) // Location40602
 
+	// ErrTrimUnknownField indicates that $trim, $ltrim or $rtrim has unknown field.
+	ErrTrimUnknownField = ErrorCode(50694) // Location50694
+
+	// ErrTrimMissingInput indicates that $trim, $ltrim or $rtrim has no 'input' field.
+	ErrTrimMissingInput = ErrorCode(50695) // Location50695
+
+	// ErrTrimNotObject indicates that the argument of $trim, $ltrim or $rtrim is not an object.
+	ErrTrimNotObject = ErrorCode(50696) // Location50696
+
+	// ErrTrimInputNotString indicates that 'input' of $trim, $ltrim or $rtrim is not a string.
+	ErrTrimInputNotString = ErrorCode(50699) // Location50699
+
+	// ErrTrimCharsNotString indicates that 'chars' of $trim, $ltrim or $rtrim is not a string.
+	ErrTrimCharsNotString = ErrorCode(50700) // Location50700
+
 	// ErrFreeMonitoringDisabled indicates that free monitoring is disabled
 	// by command-line or config file.
 	ErrFreeMonitoringDisabled = ErrorCode(50840) // Location50840
@@ -288,9 +449,52 @@ const (
 	// ErrRegexMissingParen indicates missing parentheses in regex expression.
 	ErrRegexMissingParen = ErrorCode(51091) // Location51091
 
+	// ErrRegexNotObject indicates that the argument of $regexMatch, $regexFind or $regexFindAll is not an object.
+	ErrRegexNotObject = ErrorCode(51103) // Location51103
+
+	// ErrRegexInputNotString indicates that 'input' of $regexMatch, $regexFind or $regexFindAll is not a string.
+	ErrRegexInputNotString = ErrorCode(51104) // Location51104
+
+	// ErrRegexInvalidType indicates that 'regex' of $regexMatch, $regexFind or $regexFindAll
+	// is neither a string nor a regex.
+	ErrRegexInvalidType = ErrorCode(51105) // Location51105
+
+	// ErrRegexOptionsNotString indicates that 'options' of $regexMatch, $regexFind or $regexFindAll is not a string.
+	ErrRegexOptionsNotString = ErrorCode(51106) // Location51106
+
+	// ErrRegexOptionsConflict indicates that regex options are set in both 'regex' and 'options'.
+	ErrRegexOptionsConflict = ErrorCode(51107) // Location51107
+
 	// ErrBadRegexOption indicates bad regex option value passed.
 	ErrBadRegexOption = ErrorCode(51108) // Location51108
 
+	// ErrRegexInvalid indicates that the regular expression of $regexMatch, $regexFind or $regexFindAll is invalid.
+	ErrRegexInvalid = ErrorCode(51111) // Location51111
+
+	// ErrReplaceReplacementNotString indicates that 'replacement' of $replaceOne or $replaceAll is not a string.
+	ErrReplaceReplacementNotString = ErrorCode(51744) // Location51744
+
+	// ErrReplaceFindNotString indicates that 'find' of $replaceOne or $replaceAll is not a string.
+	ErrReplaceFindNotString = ErrorCode(51745) // Location51745
+
+	// ErrReplaceInputNotString indicates that 'input' of $replaceOne or $replaceAll is not a string.
+	ErrReplaceInputNotString = ErrorCode(51746) // Location51746
+
+	// ErrReplaceMissingReplacement indicates that $replaceOne or $replaceAll has no 'replacement' parameter.
+	ErrReplaceMissingReplacement = ErrorCode(51747) // Location51747
+
+	// ErrReplaceMissingFind indicates that $replaceOne or $replaceAll has no 'find' parameter.
+	ErrReplaceMissingFind = ErrorCode(51748) // Location51748
+
+	// ErrReplaceMissingInput indicates that $replaceOne or $replaceAll has no 'input' parameter.
+	ErrReplaceMissingInput = ErrorCode(51749) // Location51749
+
+	// ErrReplaceUnknownArgument indicates that $replaceOne or $replaceAll has unknown argument.
+	ErrReplaceUnknownArgument = ErrorCode(51750) // Location51750
+
+	// ErrReplaceNotObject indicates that the argument of $replaceOne or $replaceAll is not an object.
+	ErrReplaceNotObject = ErrorCode(51751) // Location51751
+
 	// ErrBadPositionalProjection indicates that positional operator could not find a matching element in the array.
 	ErrBadPositionalProjection = ErrorCode(51246) // Location51246
 
@@ -303,6 +507,27 @@ const (
 	// ErrEmptyProject indicates that projection specification must have at least one field.
 	ErrEmptyProject = ErrorCode(51272) // Location51272
 
+	// ErrFilterLimitNotInt32 indicates that 'limit' of $filter is not a 32-bit integer.
+	ErrFilterLimitNotInt32 = ErrorCode(327391) // Location327391
+
+	// ErrFilterLimitNotPositive indicates that 'limit' of $filter is not positive.
+	ErrFilterLimitNotPositive = ErrorCode(327392) // Location327392
+
+	// ErrSortArrayNotObject indicates that the argument of $sortArray is not an object.
+	ErrSortArrayNotObject = ErrorCode(2942500) // Location2942500
+
+	// ErrSortArrayUnknownArgument indicates that $sortArray has unknown argument.
+	ErrSortArrayUnknownArgument = ErrorCode(2942501) // Location2942501
+
+	// ErrSortArrayMissingInput indicates that $sortArray has no 'input' argument.
+	ErrSortArrayMissingInput = ErrorCode(2942502) // Location2942502
+
+	// ErrSortArrayMissingSortBy indicates that $sortArray has no 'sortBy' argument.
+	ErrSortArrayMissingSortBy = ErrorCode(2942503) // Location2942503
+
+	// ErrSortArrayInputNotArray indicates that 'input' of $sortArray is not an array.
+	ErrSortArrayInputNotArray = ErrorCode(2942504) // Location2942504
+
 	// ErrDuplicateField indicates duplicate field is specified.
 	ErrDuplicateField = ErrorCode(4822819) // Location4822819
 
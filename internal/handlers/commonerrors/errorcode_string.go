@@ -11,6 +11,7 @@ func _() {
 	_ = x[errUnset-0]
 	_ = x[errInternalError-1]
 	_ = x[ErrBadValue-2]
+	_ = x[ErrNoSuchKey-4]
 	_ = x[ErrFailedToParse-9]
 	_ = x[ErrUnauthorized-13]
 	_ = x[ErrTypeMismatch-14]
@@ -34,13 +35,19 @@ func _() {
 	_ = x[ErrIndexOptionsConflict-85]
 	_ = x[ErrIndexKeySpecsConflict-86]
 	_ = x[ErrOperationFailed-96]
+	_ = x[ErrConflictingOperationInProgress-117]
 	_ = x[ErrDocumentValidationFailure-121]
 	_ = x[ErrInvalidIndexSpecificationOption-197]
 	_ = x[ErrInvalidPipelineOperator-168]
+	_ = x[ErrClientMetadataDocumentTooLarge-185]
 	_ = x[ErrClientMetadataCannotBeMutated-186]
 	_ = x[ErrNotImplemented-238]
+	_ = x[ErrConversionFailure-241]
+	_ = x[ErrExceededTimeLimit-262]
+	_ = x[ErrIndexBuildAborted-276]
 	_ = x[ErrIndexesWrongType-10065]
 	_ = x[ErrDuplicateKeyInsert-11000]
+	_ = x[ErrQuotaExceeded-12501]
 	_ = x[ErrSetBadExpression-40272]
 	_ = x[ErrStageGroupInvalidFields-15947]
 	_ = x[ErrStageGroupID-15948]
@@ -58,10 +65,42 @@ func _() {
 	_ = x[ErrPathContainsEmptyElement-15998]
 	_ = x[ErrOperatorWrongLenOfArgs-16020]
 	_ = x[ErrFieldPathInvalidName-16410]
+	_ = x[ErrCannotConvertToString-16007]
+	_ = x[ErrEmptyVariableName-16866]
+	_ = x[ErrVariableNameInvalidStart-16867]
+	_ = x[ErrVariableNameInvalidChar-16868]
+	_ = x[ErrMapNotObject-16878]
+	_ = x[ErrMapUnknownArgument-16879]
+	_ = x[ErrMapMissingInput-16880]
+	_ = x[ErrMapMissingIn-16882]
+	_ = x[ErrMapInputNotArray-16883]
 	_ = x[ErrGroupInvalidFieldPath-16872]
 	_ = x[ErrGroupUndefinedVariable-17276]
+	_ = x[ErrZipNotObject-34460]
+	_ = x[ErrZipInputsNotArray-34461]
+	_ = x[ErrZipDefaultsNotArray-34462]
+	_ = x[ErrZipUseLongestLengthNotBool-34463]
+	_ = x[ErrZipUnknownArgument-34464]
+	_ = x[ErrZipMissingInputs-34465]
+	_ = x[ErrZipDefaultsWithoutLongest-34466]
+	_ = x[ErrZipDefaultsLength-34467]
+	_ = x[ErrZipInputNotArray-34468]
+	_ = x[ErrSubstrStartNotNumber-34450]
+	_ = x[ErrSubstrStartNotInt32-34451]
+	_ = x[ErrSubstrLengthNotNumber-34452]
+	_ = x[ErrSubstrLengthNotInt32-34453]
+	_ = x[ErrSubstrStartNegative-34454]
+	_ = x[ErrSubstrLengthNegative-34455]
+	_ = x[ErrFilterNotObject-28646]
+	_ = x[ErrFilterUnknownArgument-28647]
+	_ = x[ErrFilterMissingInput-28648]
+	_ = x[ErrFilterMissingCond-28650]
+	_ = x[ErrFilterInputNotArray-28651]
 	_ = x[ErrInvalidArg-28667]
 	_ = x[ErrSliceFirstArg-28724]
+	_ = x[ErrRegexMissingInput-31022]
+	_ = x[ErrRegexMissingRegex-31023]
+	_ = x[ErrRegexUnknownArgument-31024]
 	_ = x[ErrStageUnsetNoPath-31119]
 	_ = x[ErrStageUnsetArrElementInvalidType-31120]
 	_ = x[ErrStageUnsetInvalidType-31002]
@@ -80,6 +119,15 @@ func _() {
 	_ = x[ErrStageCountBadPrefix-40158]
 	_ = x[ErrStageCountBadValue-40160]
 	_ = x[ErrAddFieldsExpressionWrongAmountOfArgs-40181]
+	_ = x[ErrSplitInputNotString-40085]
+	_ = x[ErrSplitSeparatorNotString-40086]
+	_ = x[ErrSplitEmptySeparator-40087]
+	_ = x[ErrReduceNotObject-40075]
+	_ = x[ErrReduceUnknownArgument-40076]
+	_ = x[ErrReduceMissingInput-40077]
+	_ = x[ErrReduceMissingInitialValue-40078]
+	_ = x[ErrReduceMissingIn-40079]
+	_ = x[ErrReduceInputNotArray-40080]
 	_ = x[ErrStageGroupUnaryOperator-40237]
 	_ = x[ErrStageGroupMultipleAccumulator-40238]
 	_ = x[ErrStageGroupInvalidAccumulator-40234]
@@ -89,118 +137,218 @@ func _() {
 	_ = x[ErrMissingField-40414]
 	_ = x[ErrFailedToParseInput-40415]
 	_ = x[ErrCollStatsIsNotFirstStage-40602]
+	_ = x[ErrTrimUnknownField-50694]
+	_ = x[ErrTrimMissingInput-50695]
+	_ = x[ErrTrimNotObject-50696]
+	_ = x[ErrTrimInputNotString-50699]
+	_ = x[ErrTrimCharsNotString-50700]
 	_ = x[ErrFreeMonitoringDisabled-50840]
 	_ = x[ErrValueNegative-51024]
 	_ = x[ErrRegexOptions-51075]
 	_ = x[ErrRegexMissingParen-51091]
+	_ = x[ErrRegexNotObject-51103]
+	_ = x[ErrRegexInputNotString-51104]
+	_ = x[ErrRegexInvalidType-51105]
+	_ = x[ErrRegexOptionsNotString-51106]
+	_ = x[ErrRegexOptionsConflict-51107]
 	_ = x[ErrBadRegexOption-51108]
+	_ = x[ErrRegexInvalid-51111]
+	_ = x[ErrReplaceReplacementNotString-51744]
+	_ = x[ErrReplaceFindNotString-51745]
+	_ = x[ErrReplaceInputNotString-51746]
+	_ = x[ErrReplaceMissingReplacement-51747]
+	_ = x[ErrReplaceMissingFind-51748]
+	_ = x[ErrReplaceMissingInput-51749]
+	_ = x[ErrReplaceUnknownArgument-51750]
+	_ = x[ErrReplaceNotObject-51751]
 	_ = x[ErrBadPositionalProjection-51246]
 	_ = x[ErrElementMismatchPositionalProjection-51247]
 	_ = x[ErrEmptySubProject-51270]
 	_ = x[ErrEmptyProject-51272]
+	_ = x[ErrFilterLimitNotInt32-327391]
+	_ = x[ErrFilterLimitNotPositive-327392]
+	_ = x[ErrSortArrayNotObject-2942500]
+	_ = x[ErrSortArrayUnknownArgument-2942501]
+	_ = x[ErrSortArrayMissingInput-2942502]
+	_ = x[ErrSortArrayMissingSortBy-2942503]
+	_ = x[ErrSortArrayInputNotArray-2942504]
 	_ = x[ErrDuplicateField-4822819]
 	_ = x[ErrStageSkipBadValue-5107200]
 	_ = x[ErrStageLimitInvalidArg-5107201]
 	_ = x[ErrStageCollStatsInvalidArg-5447000]
 }
 
-const _ErrorCode_name = "UnsetInternalErrorBadValueFailedToParseUnauthorizedTypeMismatchAuthenticationFailedIllegalOperationNamespaceNotFoundIndexNotFoundPathNotViableConflictingUpdateOperatorsCursorNotFoundNamespaceExistsDollarPrefixedFieldNameInvalidIDEmptyFieldNameCommandNotFoundImmutableFieldCannotCreateIndexIndexAlreadyExistsInvalidOptionsInvalidNamespaceIndexOptionsConflictIndexKeySpecsConflictOperationFailedDocumentValidationFailureInvalidPipelineOperatorClientMetadataCannotBeMutatedInvalidIndexSpecificationOptionNotImplementedLocation10065Location11000Location15947Location15948Location15955Location15958Location15959Location15969Location15973Location15974Location15975Location15976Location15981Location15983Location15998Location16020Location16406Location16410Location16872Location17276Location28667Location28724Location28812Location28818Location31002Location31119Location31120Location31249Location31250Location31253Location31254Location31324Location31325Location31394Location31395Location40156Location40157Location40158Location40160Location40181Location40234Location40237Location40238Location40272Location40323Location40352Location40353Location40414Location40415Location40602Location50840Location51024Location51075Location51091Location51108Location51246Location51247Location51270Location51272Location4822819Location5107200Location5107201Location5447000"
+const _ErrorCode_name = "UnsetInternalErrorBadValueNoSuchKeyFailedToParseUnauthorizedTypeMismatchAuthenticationFailedIllegalOperationNamespaceNotFoundIndexNotFoundPathNotViableConflictingUpdateOperatorsCursorNotFoundNamespaceExistsDollarPrefixedFieldNameInvalidIDEmptyFieldNameCommandNotFoundImmutableFieldCannotCreateIndexIndexAlreadyExistsInvalidOptionsInvalidNamespaceIndexOptionsConflictIndexKeySpecsConflictOperationFailedConflictingOperationInProgressDocumentValidationFailureInvalidPipelineOperatorClientMetadataDocumentTooLargeClientMetadataCannotBeMutatedInvalidIndexSpecificationOptionNotImplementedConversionFailureExceededTimeLimitIndexBuildAbortedLocation10065Location11000Location12501Location15947Location15948Location15955Location15958Location15959Location15969Location15973Location15974Location15975Location15976Location15981Location15983Location15998Location16007Location16020Location16406Location16410Location16866Location16867Location16868Location16872Location16878Location16879Location16880Location16882Location16883Location17276Location28646Location28647Location28648Location28650Location28651Location28667Location28724Location28812Location28818Location31002Location31022Location31023Location31024Location31119Location31120Location31249Location31250Location31253Location31254Location31324Location31325Location31394Location31395Location34450Location34451Location34452Location34453Location34454Location34455Location34460Location34461Location34462Location34463Location34464Location34465Location34466Location34467Location34468Location40075Location40076Location40077Location40078Location40079Location40080Location40085Location40086Location40087Location40156Location40157Location40158Location40160Location40181Location40234Location40237Location40238Location40272Location40323Location40352Location40353Location40414Location40415Location40602Location50694Location50695Location50696Location50699Location50700Location50840Location51024Location51075Location51091Location51103Location51104Location51105Location51106Location51107Location51108Location51111Location51246Location51247Location51270Location51272Location51744Location51745Location51746Location51747Location51748Location51749Location51750Location51751Location327391Location327392Location2942500Location2942501Location2942502Location2942503Location2942504Location4822819Location5107200Location5107201Location5447000"
 
 var _ErrorCode_map = map[ErrorCode]string{
 	0:       _ErrorCode_name[0:5],
 	1:       _ErrorCode_name[5:18],
 	2:       _ErrorCode_name[18:26],
-	9:       _ErrorCode_name[26:39],
-	13:      _ErrorCode_name[39:51],
-	14:      _ErrorCode_name[51:63],
-	18:      _ErrorCode_name[63:83],
-	20:      _ErrorCode_name[83:99],
-	26:      _ErrorCode_name[99:116],
-	27:      _ErrorCode_name[116:129],
-	28:      _ErrorCode_name[129:142],
-	40:      _ErrorCode_name[142:168],
-	43:      _ErrorCode_name[168:182],
-	48:      _ErrorCode_name[182:197],
-	52:      _ErrorCode_name[197:220],
-	53:      _ErrorCode_name[220:229],
-	56:      _ErrorCode_name[229:243],
-	59:      _ErrorCode_name[243:258],
-	66:      _ErrorCode_name[258:272],
-	67:      _ErrorCode_name[272:289],
-	68:      _ErrorCode_name[289:307],
-	72:      _ErrorCode_name[307:321],
-	73:      _ErrorCode_name[321:337],
-	85:      _ErrorCode_name[337:357],
-	86:      _ErrorCode_name[357:378],
-	96:      _ErrorCode_name[378:393],
-	121:     _ErrorCode_name[393:418],
-	168:     _ErrorCode_name[418:441],
-	186:     _ErrorCode_name[441:470],
-	197:     _ErrorCode_name[470:501],
-	238:     _ErrorCode_name[501:515],
-	10065:   _ErrorCode_name[515:528],
-	11000:   _ErrorCode_name[528:541],
-	15947:   _ErrorCode_name[541:554],
-	15948:   _ErrorCode_name[554:567],
-	15955:   _ErrorCode_name[567:580],
-	15958:   _ErrorCode_name[580:593],
-	15959:   _ErrorCode_name[593:606],
-	15969:   _ErrorCode_name[606:619],
-	15973:   _ErrorCode_name[619:632],
-	15974:   _ErrorCode_name[632:645],
-	15975:   _ErrorCode_name[645:658],
-	15976:   _ErrorCode_name[658:671],
-	15981:   _ErrorCode_name[671:684],
-	15983:   _ErrorCode_name[684:697],
-	15998:   _ErrorCode_name[697:710],
-	16020:   _ErrorCode_name[710:723],
-	16406:   _ErrorCode_name[723:736],
-	16410:   _ErrorCode_name[736:749],
-	16872:   _ErrorCode_name[749:762],
-	17276:   _ErrorCode_name[762:775],
-	28667:   _ErrorCode_name[775:788],
-	28724:   _ErrorCode_name[788:801],
-	28812:   _ErrorCode_name[801:814],
-	28818:   _ErrorCode_name[814:827],
-	31002:   _ErrorCode_name[827:840],
-	31119:   _ErrorCode_name[840:853],
-	31120:   _ErrorCode_name[853:866],
-	31249:   _ErrorCode_name[866:879],
-	31250:   _ErrorCode_name[879:892],
-	31253:   _ErrorCode_name[892:905],
-	31254:   _ErrorCode_name[905:918],
-	31324:   _ErrorCode_name[918:931],
-	31325:   _ErrorCode_name[931:944],
-	31394:   _ErrorCode_name[944:957],
-	31395:   _ErrorCode_name[957:970],
-	40156:   _ErrorCode_name[970:983],
-	40157:   _ErrorCode_name[983:996],
-	40158:   _ErrorCode_name[996:1009],
-	40160:   _ErrorCode_name[1009:1022],
-	40181:   _ErrorCode_name[1022:1035],
-	40234:   _ErrorCode_name[1035:1048],
-	40237:   _ErrorCode_name[1048:1061],
-	40238:   _ErrorCode_name[1061:1074],
-	40272:   _ErrorCode_name[1074:1087],
-	40323:   _ErrorCode_name[1087:1100],
-	40352:   _ErrorCode_name[1100:1113],
-	40353:   _ErrorCode_name[1113:1126],
-	40414:   _ErrorCode_name[1126:1139],
-	40415:   _ErrorCode_name[1139:1152],
-	40602:   _ErrorCode_name[1152:1165],
-	50840:   _ErrorCode_name[1165:1178],
-	51024:   _ErrorCode_name[1178:1191],
-	51075:   _ErrorCode_name[1191:1204],
-	51091:   _ErrorCode_name[1204:1217],
-	51108:   _ErrorCode_name[1217:1230],
-	51246:   _ErrorCode_name[1230:1243],
-	51247:   _ErrorCode_name[1243:1256],
-	51270:   _ErrorCode_name[1256:1269],
-	51272:   _ErrorCode_name[1269:1282],
-	4822819: _ErrorCode_name[1282:1297],
-	5107200: _ErrorCode_name[1297:1312],
-	5107201: _ErrorCode_name[1312:1327],
-	5447000: _ErrorCode_name[1327:1342],
+	4:       _ErrorCode_name[26:35],
+	9:       _ErrorCode_name[35:48],
+	13:      _ErrorCode_name[48:60],
+	14:      _ErrorCode_name[60:72],
+	18:      _ErrorCode_name[72:92],
+	20:      _ErrorCode_name[92:108],
+	26:      _ErrorCode_name[108:125],
+	27:      _ErrorCode_name[125:138],
+	28:      _ErrorCode_name[138:151],
+	40:      _ErrorCode_name[151:177],
+	43:      _ErrorCode_name[177:191],
+	48:      _ErrorCode_name[191:206],
+	52:      _ErrorCode_name[206:229],
+	53:      _ErrorCode_name[229:238],
+	56:      _ErrorCode_name[238:252],
+	59:      _ErrorCode_name[252:267],
+	66:      _ErrorCode_name[267:281],
+	67:      _ErrorCode_name[281:298],
+	68:      _ErrorCode_name[298:316],
+	72:      _ErrorCode_name[316:330],
+	73:      _ErrorCode_name[330:346],
+	85:      _ErrorCode_name[346:366],
+	86:      _ErrorCode_name[366:387],
+	96:      _ErrorCode_name[387:402],
+	117:     _ErrorCode_name[402:432],
+	121:     _ErrorCode_name[432:457],
+	168:     _ErrorCode_name[457:480],
+	185:     _ErrorCode_name[480:510],
+	186:     _ErrorCode_name[510:539],
+	197:     _ErrorCode_name[539:570],
+	238:     _ErrorCode_name[570:584],
+	241:     _ErrorCode_name[584:601],
+	262:     _ErrorCode_name[601:618],
+	276:     _ErrorCode_name[618:635],
+	10065:   _ErrorCode_name[635:648],
+	11000:   _ErrorCode_name[648:661],
+	12501:   _ErrorCode_name[661:674],
+	15947:   _ErrorCode_name[674:687],
+	15948:   _ErrorCode_name[687:700],
+	15955:   _ErrorCode_name[700:713],
+	15958:   _ErrorCode_name[713:726],
+	15959:   _ErrorCode_name[726:739],
+	15969:   _ErrorCode_name[739:752],
+	15973:   _ErrorCode_name[752:765],
+	15974:   _ErrorCode_name[765:778],
+	15975:   _ErrorCode_name[778:791],
+	15976:   _ErrorCode_name[791:804],
+	15981:   _ErrorCode_name[804:817],
+	15983:   _ErrorCode_name[817:830],
+	15998:   _ErrorCode_name[830:843],
+	16007:   _ErrorCode_name[843:856],
+	16020:   _ErrorCode_name[856:869],
+	16406:   _ErrorCode_name[869:882],
+	16410:   _ErrorCode_name[882:895],
+	16866:   _ErrorCode_name[895:908],
+	16867:   _ErrorCode_name[908:921],
+	16868:   _ErrorCode_name[921:934],
+	16872:   _ErrorCode_name[934:947],
+	16878:   _ErrorCode_name[947:960],
+	16879:   _ErrorCode_name[960:973],
+	16880:   _ErrorCode_name[973:986],
+	16882:   _ErrorCode_name[986:999],
+	16883:   _ErrorCode_name[999:1012],
+	17276:   _ErrorCode_name[1012:1025],
+	28646:   _ErrorCode_name[1025:1038],
+	28647:   _ErrorCode_name[1038:1051],
+	28648:   _ErrorCode_name[1051:1064],
+	28650:   _ErrorCode_name[1064:1077],
+	28651:   _ErrorCode_name[1077:1090],
+	28667:   _ErrorCode_name[1090:1103],
+	28724:   _ErrorCode_name[1103:1116],
+	28812:   _ErrorCode_name[1116:1129],
+	28818:   _ErrorCode_name[1129:1142],
+	31002:   _ErrorCode_name[1142:1155],
+	31022:   _ErrorCode_name[1155:1168],
+	31023:   _ErrorCode_name[1168:1181],
+	31024:   _ErrorCode_name[1181:1194],
+	31119:   _ErrorCode_name[1194:1207],
+	31120:   _ErrorCode_name[1207:1220],
+	31249:   _ErrorCode_name[1220:1233],
+	31250:   _ErrorCode_name[1233:1246],
+	31253:   _ErrorCode_name[1246:1259],
+	31254:   _ErrorCode_name[1259:1272],
+	31324:   _ErrorCode_name[1272:1285],
+	31325:   _ErrorCode_name[1285:1298],
+	31394:   _ErrorCode_name[1298:1311],
+	31395:   _ErrorCode_name[1311:1324],
+	34450:   _ErrorCode_name[1324:1337],
+	34451:   _ErrorCode_name[1337:1350],
+	34452:   _ErrorCode_name[1350:1363],
+	34453:   _ErrorCode_name[1363:1376],
+	34454:   _ErrorCode_name[1376:1389],
+	34455:   _ErrorCode_name[1389:1402],
+	34460:   _ErrorCode_name[1402:1415],
+	34461:   _ErrorCode_name[1415:1428],
+	34462:   _ErrorCode_name[1428:1441],
+	34463:   _ErrorCode_name[1441:1454],
+	34464:   _ErrorCode_name[1454:1467],
+	34465:   _ErrorCode_name[1467:1480],
+	34466:   _ErrorCode_name[1480:1493],
+	34467:   _ErrorCode_name[1493:1506],
+	34468:   _ErrorCode_name[1506:1519],
+	40075:   _ErrorCode_name[1519:1532],
+	40076:   _ErrorCode_name[1532:1545],
+	40077:   _ErrorCode_name[1545:1558],
+	40078:   _ErrorCode_name[1558:1571],
+	40079:   _ErrorCode_name[1571:1584],
+	40080:   _ErrorCode_name[1584:1597],
+	40085:   _ErrorCode_name[1597:1610],
+	40086:   _ErrorCode_name[1610:1623],
+	40087:   _ErrorCode_name[1623:1636],
+	40156:   _ErrorCode_name[1636:1649],
+	40157:   _ErrorCode_name[1649:1662],
+	40158:   _ErrorCode_name[1662:1675],
+	40160:   _ErrorCode_name[1675:1688],
+	40181:   _ErrorCode_name[1688:1701],
+	40234:   _ErrorCode_name[1701:1714],
+	40237:   _ErrorCode_name[1714:1727],
+	40238:   _ErrorCode_name[1727:1740],
+	40272:   _ErrorCode_name[1740:1753],
+	40323:   _ErrorCode_name[1753:1766],
+	40352:   _ErrorCode_name[1766:1779],
+	40353:   _ErrorCode_name[1779:1792],
+	40414:   _ErrorCode_name[1792:1805],
+	40415:   _ErrorCode_name[1805:1818],
+	40602:   _ErrorCode_name[1818:1831],
+	50694:   _ErrorCode_name[1831:1844],
+	50695:   _ErrorCode_name[1844:1857],
+	50696:   _ErrorCode_name[1857:1870],
+	50699:   _ErrorCode_name[1870:1883],
+	50700:   _ErrorCode_name[1883:1896],
+	50840:   _ErrorCode_name[1896:1909],
+	51024:   _ErrorCode_name[1909:1922],
+	51075:   _ErrorCode_name[1922:1935],
+	51091:   _ErrorCode_name[1935:1948],
+	51103:   _ErrorCode_name[1948:1961],
+	51104:   _ErrorCode_name[1961:1974],
+	51105:   _ErrorCode_name[1974:1987],
+	51106:   _ErrorCode_name[1987:2000],
+	51107:   _ErrorCode_name[2000:2013],
+	51108:   _ErrorCode_name[2013:2026],
+	51111:   _ErrorCode_name[2026:2039],
+	51246:   _ErrorCode_name[2039:2052],
+	51247:   _ErrorCode_name[2052:2065],
+	51270:   _ErrorCode_name[2065:2078],
+	51272:   _ErrorCode_name[2078:2091],
+	51744:   _ErrorCode_name[2091:2104],
+	51745:   _ErrorCode_name[2104:2117],
+	51746:   _ErrorCode_name[2117:2130],
+	51747:   _ErrorCode_name[2130:2143],
+	51748:   _ErrorCode_name[2143:2156],
+	51749:   _ErrorCode_name[2156:2169],
+	51750:   _ErrorCode_name[2169:2182],
+	51751:   _ErrorCode_name[2182:2195],
+	327391:  _ErrorCode_name[2195:2209],
+	327392:  _ErrorCode_name[2209:2223],
+	2942500: _ErrorCode_name[2223:2238],
+	2942501: _ErrorCode_name[2238:2253],
+	2942502: _ErrorCode_name[2253:2268],
+	2942503: _ErrorCode_name[2268:2283],
+	2942504: _ErrorCode_name[2283:2298],
+	4822819: _ErrorCode_name[2298:2313],
+	5107200: _ErrorCode_name[2313:2328],
+	5107201: _ErrorCode_name[2328:2343],
+	5447000: _ErrorCode_name[2343:2358],
 }
 
 func (i ErrorCode) String() string {
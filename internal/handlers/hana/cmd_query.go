@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
 	"github.com/FerretDB/FerretDB/internal/handlers/common"
 	"github.com/FerretDB/FerretDB/internal/handlers/commonerrors"
 	"github.com/FerretDB/FerretDB/internal/types"
@@ -46,7 +47,7 @@ func (h *Handler) CmdQuery(ctx context.Context, query *wire.OpQuery) (*wire.OpRe
 					"maxWriteBatchSize", int32(100000),
 					"localTime", time.Now(),
 					// logicalSessionTimeoutMinutes
-					"connectionId", int32(42),
+					"connectionId", conninfo.Get(ctx).ID,
 					"minWireVersion", common.MinWireVersion,
 					"maxWireVersion", common.MaxWireVersion,
 					"readOnly", false,
@@ -78,7 +78,7 @@ func (h *Handler) MsgListDatabases(ctx context.Context, msg *wire.OpMsg) (*wire.
 			"empty", size == 0,
 		))
 
-		matches, err := common.FilterDocument(d, filter)
+		matches, err := common.FilterDocument(d, filter, nil)
 		if err != nil {
 			return nil, err
 		}
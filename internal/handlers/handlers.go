@@ -49,6 +49,9 @@ type Interface interface {
 	// MsgAggregate returns aggregated data.
 	MsgAggregate(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error)
 
+	// MsgApplyOps applies a batch of oplog entries.
+	MsgApplyOps(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error)
+
 	// MsgBuildInfo returns a summary of the build information.
 	MsgBuildInfo(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error)
 
@@ -98,6 +101,9 @@ type Interface interface {
 	// MsgDropDatabase drops production database.
 	MsgDropDatabase(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error)
 
+	// MsgEndSessions ends logical sessions.
+	MsgEndSessions(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error)
+
 	// MsgExplain returns the execution plan.
 	MsgExplain(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error)
 
@@ -107,6 +113,9 @@ type Interface interface {
 	// MsgFindAndModify inserts, updates, or deletes, and returns a document matched by the query.
 	MsgFindAndModify(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error)
 
+	// MsgGetClusterParameter returns the values of the cluster parameters.
+	MsgGetClusterParameter(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error)
+
 	// MsgGetCmdLineOpts returns a summary of all runtime and configuration options.
 	MsgGetCmdLineOpts(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error)
 
@@ -155,15 +164,24 @@ type Interface interface {
 	// MsgPing returns a pong response.
 	MsgPing(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error)
 
+	// MsgRefreshSessions updates the last use time of logical sessions.
+	MsgRefreshSessions(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error)
+
 	// MsgRenameCollection changes the name of an existing collection.
 	MsgRenameCollection(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error)
 
+	// MsgReshapeCollection rewrites the collection into a new physical layout online.
+	MsgReshapeCollection(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error)
+
 	// MsgSASLStart starts the SASL authentication process.
 	MsgSASLStart(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error)
 
 	// MsgServerStatus returns an overview of the databases state.
 	MsgServerStatus(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error)
 
+	// MsgSetClusterParameter modifies the value of the cluster parameter.
+	MsgSetClusterParameter(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error)
+
 	// MsgSetFreeMonitoring toggles free monitoring.
 	MsgSetFreeMonitoring(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error)
 
@@ -173,6 +191,9 @@ type Interface interface {
 	// MsgValidate validates collection.
 	MsgValidate(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error)
 
+	// MsgValidateDBMetadata checks that metadata of collections and indexes matches the backend and repairs it.
+	MsgValidateDBMetadata(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error)
+
 	// MsgWhatsMyURI returns peer information.
 	MsgWhatsMyURI(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error)
 
@@ -0,0 +1,111 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package indexbuild
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// AbortedError is returned by [Build.Err] when the build was aborted.
+type AbortedError struct {
+	Reason string
+}
+
+// Error implements error interface.
+func (e *AbortedError) Error() string {
+	return "index build aborted: " + e.Reason
+}
+
+// Build represents a single index build started by createIndexes command.
+//
+// It runs in the background and is not canceled when the client disconnects.
+type Build struct {
+	// the order of fields is weird to make the struct smaller due to alignment
+
+	started time.Time
+	err     error // set before done is closed
+	cancel  context.CancelCauseFunc
+	done    chan struct{}
+
+	// Command is the createIndexes command document that started the build.
+	Command *types.Document
+
+	DB         string
+	Collection string
+	Indexes    []backends.IndexInfo
+
+	progressDone  atomic.Int64
+	progressTotal atomic.Int64
+
+	ID int32
+}
+
+// Started returns the time when the build was started.
+func (b *Build) Started() time.Time {
+	return b.started
+}
+
+// Done returns a channel that is closed when the build is finished, successfully or not.
+func (b *Build) Done() <-chan struct{} {
+	return b.done
+}
+
+// Err returns the build error, if any.
+//
+// It should be called only after [Build.Done] channel is closed.
+// If the build was aborted, *AbortedError is returned.
+func (b *Build) Err() error {
+	return b.err
+}
+
+// Abort cancels the build with the given reason.
+//
+// It does not wait for the build to finish; use [Build.Done] for that.
+func (b *Build) Abort(reason string) {
+	b.cancel(&AbortedError{Reason: reason})
+}
+
+// Progress returns the amount of already done and total work reported by the backend.
+//
+// Both values are zero if progress is unknown.
+func (b *Build) Progress() (done, total int64) {
+	return b.progressDone.Load(), b.progressTotal.Load()
+}
+
+// SetProgress stores the amount of already done and total work.
+//
+// It can be used as [backends.CreateIndexesParams] Progress function.
+func (b *Build) SetProgress(done, total int64) {
+	b.progressTotal.Store(total)
+	b.progressDone.Store(done)
+}
+
+// hasIndex returns true if the build includes an index with any of the given names.
+func (b *Build) hasIndex(names []string) bool {
+	for _, index := range b.Indexes {
+		for _, name := range names {
+			if index.Name == name {
+				return true
+			}
+		}
+	}
+
+	return false
+}
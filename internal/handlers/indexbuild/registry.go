@@ -0,0 +1,212 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package indexbuild provides access to the registry of in-progress index builds.
+package indexbuild
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/exp/maps"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// Registry stores in-progress index builds.
+//
+//nolint:vet // for readability
+type Registry struct {
+	rw     sync.RWMutex
+	m      map[int32]*Build
+	lastID int32
+
+	l  *zap.Logger
+	wg sync.WaitGroup
+}
+
+// NewRegistry creates a new Registry.
+func NewRegistry(l *zap.Logger) *Registry {
+	return &Registry{
+		m: map[int32]*Build{},
+		l: l,
+	}
+}
+
+// Close aborts all in-progress builds and waits for them to finish.
+func (r *Registry) Close() {
+	for _, b := range r.All() {
+		b.Abort("server is shutting down")
+	}
+
+	r.wg.Wait()
+}
+
+// StartParams represent parameters for Start.
+type StartParams struct {
+	Command    *types.Document
+	DB         string
+	Collection string
+	Indexes    []backends.IndexInfo
+}
+
+// Start registers a new index build and runs the given function in the background.
+//
+// The function is called with the build and a context that keeps values of the given context (for example, connection info),
+// but is canceled only when the build is aborted, not when the given context is canceled.
+// The build is removed from the registry when the function returns.
+func (r *Registry) Start(ctx context.Context, params *StartParams, f func(context.Context, *Build) error) *Build {
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	return r.start(ctx, params, f)
+}
+
+// StartExclusive is like [Registry.Start], but it starts the build only if there are
+// no in-progress builds for the same collection.
+// Otherwise, it returns nil and those builds, so the caller could wait for them and try again.
+//
+// Checking and registering are done under the same lock,
+// so two concurrent calls could not both start builds for the same collection.
+func (r *Registry) StartExclusive(ctx context.Context, params *StartParams, f func(context.Context, *Build) error) (*Build, []*Build) { //nolint:lll // for readability
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	var running []*Build
+
+	for _, b := range r.m {
+		if b.DB == params.DB && b.Collection == params.Collection {
+			running = append(running, b)
+		}
+	}
+
+	if len(running) > 0 {
+		slices.SortFunc(running, func(a, b *Build) int { return int(a.ID - b.ID) })
+		return nil, running
+	}
+
+	return r.start(ctx, params, f), nil
+}
+
+// start registers a new index build and runs the given function in the background.
+//
+// It should be called with the lock held.
+func (r *Registry) start(ctx context.Context, params *StartParams, f func(context.Context, *Build) error) *Build {
+	buildCtx, cancel := context.WithCancelCause(context.WithoutCancel(ctx))
+
+	r.lastID++
+
+	b := &Build{
+		ID:         r.lastID,
+		Command:    params.Command,
+		DB:         params.DB,
+		Collection: params.Collection,
+		Indexes:    params.Indexes,
+		started:    time.Now(),
+		cancel:     cancel,
+		done:       make(chan struct{}),
+	}
+
+	r.l.Debug(
+		"Starting index build",
+		zap.Int32("id", b.ID),
+		zap.String("db", b.DB),
+		zap.String("collection", b.Collection),
+	)
+
+	r.m[b.ID] = b
+
+	r.wg.Add(1)
+
+	go func() {
+		defer r.wg.Done()
+
+		err := f(buildCtx, b)
+
+		// the function may return an unrelated error if it was interrupted
+		if buildCtx.Err() != nil {
+			err = context.Cause(buildCtx)
+		}
+
+		cancel(nil)
+
+		r.delete(b, err)
+	}()
+
+	return b
+}
+
+// All returns all in-progress builds sorted by ID.
+func (r *Registry) All() []*Build {
+	r.rw.RLock()
+	defer r.rw.RUnlock()
+
+	res := maps.Values(r.m)
+	slices.SortFunc(res, func(a, b *Build) int { return int(a.ID - b.ID) })
+
+	return res
+}
+
+// Collection returns in-progress builds for the given collection sorted by ID.
+func (r *Registry) Collection(db, collection string) []*Build {
+	res := r.All()
+
+	return slices.DeleteFunc(res, func(b *Build) bool {
+		return b.DB != db || b.Collection != collection
+	})
+}
+
+// Abort aborts in-progress builds of the given collection that include any of the given indexes.
+// It waits for them to finish unless the context is canceled first.
+func (r *Registry) Abort(ctx context.Context, db, collection string, indexes []string, reason string) error {
+	for _, b := range r.Collection(db, collection) {
+		if !b.hasIndex(indexes) {
+			continue
+		}
+
+		r.l.Debug("Aborting index build", zap.Int32("id", b.ID), zap.String("reason", reason))
+
+		b.Abort(reason)
+
+		select {
+		case <-b.Done():
+		case <-ctx.Done():
+			return context.Cause(ctx)
+		}
+	}
+
+	return nil
+}
+
+// delete removes finished build from the registry.
+func (r *Registry) delete(b *Build, err error) {
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	r.l.Debug(
+		"Index build finished",
+		zap.Int32("id", b.ID),
+		zap.Duration("duration", time.Since(b.started)),
+		zap.Error(err),
+	)
+
+	delete(r.m, b.ID)
+
+	b.err = err
+	close(b.done)
+}
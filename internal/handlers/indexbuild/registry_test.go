@@ -0,0 +1,165 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package indexbuild
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+)
+
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Ctx(t)
+
+	r := NewRegistry(testutil.Logger(t))
+	t.Cleanup(r.Close)
+
+	params := &StartParams{
+		DB:         "db",
+		Collection: "coll",
+		Indexes:    []backends.IndexInfo{{Name: "foo_1"}, {Name: "bar_1"}},
+	}
+
+	t.Run("Finished", func(t *testing.T) {
+		t.Parallel()
+
+		b := r.Start(ctx, params, func(ctx context.Context, b *Build) error {
+			b.SetProgress(10, 10)
+			return nil
+		})
+
+		<-b.Done()
+		require.NoError(t, b.Err())
+
+		done, total := b.Progress()
+		assert.Equal(t, int64(10), done)
+		assert.Equal(t, int64(10), total)
+
+		assert.NotContains(t, r.All(), b)
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		t.Parallel()
+
+		expected := errors.New("failed")
+
+		b := r.Start(ctx, params, func(ctx context.Context, b *Build) error {
+			return expected
+		})
+
+		<-b.Done()
+		require.ErrorIs(t, b.Err(), expected)
+	})
+
+	t.Run("NotCanceledWithContext", func(t *testing.T) {
+		t.Parallel()
+
+		startCtx, cancel := context.WithCancel(ctx)
+		release := make(chan struct{})
+
+		b := r.Start(startCtx, params, func(ctx context.Context, b *Build) error {
+			<-release
+			return ctx.Err()
+		})
+
+		cancel()
+		close(release)
+
+		<-b.Done()
+		require.NoError(t, b.Err())
+	})
+
+	t.Run("Abort", func(t *testing.T) {
+		t.Parallel()
+
+		p := *params
+		p.Collection = "abort"
+
+		started := make(chan struct{})
+
+		b := r.Start(ctx, &p, func(ctx context.Context, b *Build) error {
+			close(started)
+			<-ctx.Done()
+
+			return errors.New("interrupted")
+		})
+
+		<-started
+
+		assert.Equal(t, []*Build{b}, r.Collection("db", "abort"))
+
+		err := r.Abort(ctx, "db", "abort", []string{"baz_1"}, "test")
+		require.NoError(t, err)
+
+		select {
+		case <-b.Done():
+			t.Fatal("build should not be aborted")
+		default:
+		}
+
+		err = r.Abort(ctx, "db", "abort", []string{"bar_1"}, "test")
+		require.NoError(t, err)
+
+		var ae *AbortedError
+		require.ErrorAs(t, b.Err(), &ae)
+		assert.Equal(t, "test", ae.Reason)
+
+		assert.Empty(t, r.Collection("db", "abort"))
+	})
+
+	t.Run("StartExclusive", func(t *testing.T) {
+		t.Parallel()
+
+		p := *params
+		p.Collection = "exclusive"
+
+		release := make(chan struct{})
+
+		b, running := r.StartExclusive(ctx, &p, func(ctx context.Context, b *Build) error {
+			<-release
+			return nil
+		})
+		require.NotNil(t, b)
+		assert.Empty(t, running)
+
+		other, running := r.StartExclusive(ctx, &p, func(ctx context.Context, b *Build) error {
+			panic("should not be called")
+		})
+		assert.Nil(t, other)
+		assert.Equal(t, []*Build{b}, running)
+
+		close(release)
+
+		<-b.Done()
+		require.NoError(t, b.Err())
+
+		other, running = r.StartExclusive(ctx, &p, func(ctx context.Context, b *Build) error {
+			return nil
+		})
+		require.NotNil(t, other)
+		assert.Empty(t, running)
+
+		<-other.Done()
+		require.NoError(t, other.Err())
+	})
+}
@@ -0,0 +1,135 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+)
+
+func TestScheduler(t *testing.T) {
+	t.Parallel()
+
+	s := NewScheduler(testutil.Logger(t), []Task{{
+		Name:     "ok",
+		Interval: 10 * time.Millisecond,
+		Run: func(ctx context.Context) (int64, error) {
+			return 2, nil
+		},
+	}, {
+		Name:     "failed",
+		Interval: 10 * time.Millisecond,
+		Run: func(ctx context.Context) (int64, error) {
+			return 0, errors.New("boom")
+		},
+	}, {
+		Name: "disabled",
+		Run: func(ctx context.Context) (int64, error) {
+			panic("disabled task should not run")
+		},
+	}})
+
+	s.Start(context.Background())
+
+	require.Eventually(t, func() bool {
+		status := s.Status()
+		return status[0].Runs >= 2 && status[1].Runs >= 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	s.Close()
+
+	status := s.Status()
+	require.Len(t, status, 3)
+
+	ok := status[0]
+	assert.Equal(t, "ok", ok.Name)
+	assert.GreaterOrEqual(t, ok.Runs, int64(2))
+	assert.Zero(t, ok.Failures)
+	assert.Equal(t, int64(2), ok.LastProcessed)
+	assert.Equal(t, 2*ok.Runs, ok.TotalProcessed)
+	assert.NoError(t, ok.LastError)
+	assert.False(t, ok.LastStarted.IsZero())
+
+	failed := status[1]
+	assert.Equal(t, "failed", failed.Name)
+	assert.GreaterOrEqual(t, failed.Runs, int64(2))
+	assert.Equal(t, failed.Runs, failed.Failures)
+	assert.EqualError(t, failed.LastError, "boom")
+
+	disabled := status[2]
+	assert.Equal(t, "disabled", disabled.Name)
+	assert.Zero(t, disabled.Interval)
+	assert.Zero(t, disabled.Runs)
+	assert.True(t, disabled.LastStarted.IsZero())
+}
+
+func TestWrites(t *testing.T) {
+	t.Parallel()
+
+	w := NewWrites()
+
+	w.Add("db", "hot", 7)
+	w.Add("db", "hot", 3)
+	w.Add("db", "cold", 9)
+	w.Add("db", "ignored", 0)
+	w.Add("another", "hot", 10)
+
+	assert.Equal(t, []Namespace{{"another", "hot"}, {"db", "hot"}}, w.Hot(10))
+	assert.Empty(t, w.Hot(10))
+
+	w.Add("db", "cold", 1)
+	assert.Equal(t, []Namespace{{"db", "cold"}}, w.Hot(10))
+}
+
+func TestWritesForget(t *testing.T) {
+	t.Parallel()
+
+	w := NewWrites()
+
+	w.Add("db", "foo", 10)
+	w.Add("db", "bar", 10)
+	w.Add("other", "foo", 10)
+
+	w.Forget("db", "foo")
+	assert.Equal(t, []Namespace{{"db", "bar"}, {"other", "foo"}}, w.Hot(10))
+
+	w.Add("db", "foo", 10)
+	w.Add("db", "bar", 10)
+	w.Add("other", "foo", 10)
+
+	w.Forget("db", "")
+	assert.Equal(t, []Namespace{{"other", "foo"}}, w.Hot(10))
+}
+
+func TestWritesLimit(t *testing.T) {
+	t.Parallel()
+
+	w := NewWrites()
+	w.max = 2
+
+	w.Add("db", "hot", 10)
+	w.Add("db", "cold", 1)
+	w.Add("db", "new", 5)
+
+	assert.Len(t, w.counts, 2)
+	assert.Equal(t, []Namespace{{"db", "hot"}, {"db", "new"}}, w.Hot(5))
+}
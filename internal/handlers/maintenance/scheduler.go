@@ -0,0 +1,176 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package maintenance provides a scheduler for periodic background maintenance tasks.
+package maintenance
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Task represents a single periodic maintenance task.
+type Task struct {
+	// Name is used in logs and status.
+	Name string
+
+	// Interval between task runs; zero disables the task.
+	Interval time.Duration
+
+	// Run performs the task and returns the number of processed items
+	// (compacted collections, deleted documents, closed cursors, etc).
+	Run func(ctx context.Context) (int64, error)
+}
+
+// TaskStatus represents the status of a single maintenance task.
+type TaskStatus struct {
+	LastStarted    time.Time
+	LastError      error
+	Name           string
+	Interval       time.Duration
+	LastDuration   time.Duration
+	LastProcessed  int64
+	TotalProcessed int64
+	Runs           int64
+	Failures       int64
+}
+
+// Scheduler runs maintenance tasks on their intervals.
+//
+//nolint:vet // for readability
+type Scheduler struct {
+	rw     sync.RWMutex
+	tasks  []Task
+	status map[string]*TaskStatus
+
+	l      *zap.Logger
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewScheduler creates a new Scheduler for the given tasks.
+//
+// Tasks are not run until Start is called.
+func NewScheduler(l *zap.Logger, tasks []Task) *Scheduler {
+	s := &Scheduler{
+		tasks:  tasks,
+		status: make(map[string]*TaskStatus, len(tasks)),
+		l:      l,
+		cancel: func() {},
+	}
+
+	for _, t := range tasks {
+		s.status[t.Name] = &TaskStatus{
+			Name:     t.Name,
+			Interval: t.Interval,
+		}
+	}
+
+	return s
+}
+
+// Start runs enabled tasks in the background until Close is called.
+//
+// Tasks get a context derived from the given one;
+// it should contain values required by backends, such as connection info.
+func (s *Scheduler) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	for _, t := range s.tasks {
+		if t.Interval <= 0 {
+			s.l.Debug("Maintenance task is disabled", zap.String("task", t.Name))
+			continue
+		}
+
+		s.wg.Add(1)
+
+		go func(t Task) {
+			defer s.wg.Done()
+
+			s.loop(ctx, t)
+		}(t)
+	}
+}
+
+// Close stops all tasks and waits for running ones to finish.
+func (s *Scheduler) Close() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+// Status returns the status of all tasks in the order they were given to NewScheduler.
+func (s *Scheduler) Status() []TaskStatus {
+	s.rw.RLock()
+	defer s.rw.RUnlock()
+
+	res := make([]TaskStatus, len(s.tasks))
+	for i, t := range s.tasks {
+		res[i] = *s.status[t.Name]
+	}
+
+	return res
+}
+
+// loop runs the given task on its interval until ctx is canceled.
+func (s *Scheduler) loop(ctx context.Context, t Task) {
+	ticker := time.NewTicker(t.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		s.run(ctx, t)
+	}
+}
+
+// run runs the given task once and updates its status.
+func (s *Scheduler) run(ctx context.Context, t Task) {
+	l := s.l.With(zap.String("task", t.Name))
+
+	start := time.Now()
+	processed, err := t.Run(ctx)
+	duration := time.Since(start)
+
+	if ctx.Err() != nil {
+		// the server is shutting down, do not record interrupted run
+		return
+	}
+
+	s.rw.Lock()
+	defer s.rw.Unlock()
+
+	status := s.status[t.Name]
+	status.Runs++
+	status.LastStarted = start
+	status.LastDuration = duration
+	status.LastProcessed = processed
+	status.TotalProcessed += processed
+	status.LastError = err
+
+	if err != nil {
+		status.Failures++
+		l.Warn("Maintenance task failed", zap.Duration("duration", duration), zap.Error(err))
+
+		return
+	}
+
+	l.Debug("Maintenance task finished", zap.Duration("duration", duration), zap.Int64("processed", processed))
+}
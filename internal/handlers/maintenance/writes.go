@@ -0,0 +1,130 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maintenance
+
+import (
+	"sort"
+	"sync"
+)
+
+// Namespace represents a collection in a database.
+type Namespace struct {
+	DB         string
+	Collection string
+}
+
+// maxWritesNamespaces is the maximum number of collections tracked by Writes.
+const maxWritesNamespaces = 10_000
+
+// Writes counts modified documents per collection to find hot collections.
+//
+// At most maxWritesNamespaces collections are tracked;
+// when that limit is reached, the collection with the least modifications is forgotten to track a new one.
+type Writes struct {
+	m      sync.Mutex
+	counts map[Namespace]int64
+	max    int
+}
+
+// NewWrites creates a new Writes.
+func NewWrites() *Writes {
+	return &Writes{
+		counts: map[Namespace]int64{},
+		max:    maxWritesNamespaces,
+	}
+}
+
+// Add records that n documents in the given collection were inserted, updated, or deleted.
+func (w *Writes) Add(db, collection string, n int64) {
+	if n <= 0 {
+		return
+	}
+
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	ns := Namespace{DB: db, Collection: collection}
+
+	if _, ok := w.counts[ns]; !ok && len(w.counts) >= w.max {
+		w.evict()
+	}
+
+	w.counts[ns] += n
+}
+
+// evict forgets the collection with the least modifications.
+//
+// It should be called with the lock held.
+func (w *Writes) evict() {
+	var coldest Namespace
+	var least int64
+
+	first := true
+
+	for ns, n := range w.counts {
+		if first || n < least {
+			coldest, least, first = ns, n, false
+		}
+	}
+
+	delete(w.counts, coldest)
+}
+
+// Forget removes the counter of the given collection, for example, when it is dropped.
+// If collection is empty, counters of all collections in the given database are removed.
+func (w *Writes) Forget(db, collection string) {
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	if collection != "" {
+		delete(w.counts, Namespace{DB: db, Collection: collection})
+		return
+	}
+
+	for ns := range w.counts {
+		if ns.DB == db {
+			delete(w.counts, ns)
+		}
+	}
+}
+
+// Hot returns collections with at least threshold modified documents, sorted by namespace,
+// and resets their counters.
+// Counters of other collections are kept so that they could become hot later.
+func (w *Writes) Hot(threshold int64) []Namespace {
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	var res []Namespace
+
+	for ns, n := range w.counts {
+		if n < threshold {
+			continue
+		}
+
+		res = append(res, ns)
+		delete(w.counts, ns)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].DB != res[j].DB {
+			return res[i].DB < res[j].DB
+		}
+
+		return res[i].Collection < res[j].Collection
+	})
+
+	return res
+}
@@ -178,7 +178,7 @@ func (h *Handler) MsgAggregate(ctx context.Context, msg *wire.OpMsg) (*wire.OpMs
 
 		var s aggregations.Stage
 
-		if s, err = stages.NewStage(d); err != nil {
+		if s, err = stages.NewStage(d, nil); err != nil {
 			return nil, err
 		}
 
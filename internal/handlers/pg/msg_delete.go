@@ -43,6 +43,10 @@ func (h *Handler) MsgDelete(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 		return nil, lazyerrors.Error(err)
 	}
 
+	if err = common.Unimplemented(document, "let"); err != nil {
+		return nil, err
+	}
+
 	params, err := common.GetDeleteParams(document, h.L)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
@@ -141,7 +145,7 @@ func execDelete(ctx context.Context, dp *execDeleteParams) (int32, error) {
 			}
 
 			var matches bool
-			if matches, err = common.FilterDocument(doc, filter); err != nil {
+			if matches, err = common.FilterDocument(doc, filter, nil); err != nil {
 				return err
 			}
 
@@ -67,7 +67,7 @@ func (h *Handler) MsgDistinct(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg
 		closer := iterator.NewMultiCloser(iter)
 		defer closer.Close()
 
-		iter = common.FilterIterator(iter, closer, qp.Filter)
+		iter = common.FilterIterator(iter, closer, qp.Filter, nil)
 
 		distinct, err = common.FilterDistinctValues(iter, dp.Key)
 		if err != nil {
@@ -45,6 +45,10 @@ func (h *Handler) MsgFind(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, er
 		return nil, lazyerrors.Error(err)
 	}
 
+	if err = common.Unimplemented(document, "let"); err != nil {
+		return nil, err
+	}
+
 	params, err := common.GetFindParams(document, h.L)
 	if err != nil {
 		return nil, err
@@ -105,7 +109,7 @@ func (h *Handler) MsgFind(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, er
 
 		closer.Add(iter)
 
-		iter = common.FilterIterator(iter, closer, params.Filter)
+		iter = common.FilterIterator(iter, closer, params.Filter, nil)
 
 		if !queryRes.SortPushdown {
 			iter, err = common.SortIterator(iter, closer, params.Sort)
@@ -127,7 +131,7 @@ func (h *Handler) MsgFind(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, er
 
 		iter = common.LimitIterator(iter, closer, params.Limit)
 
-		iter, err = common.ProjectionIterator(iter, closer, params.Projection, params.Filter)
+		iter, err = common.ProjectionIterator(iter, closer, params.Projection, params.Filter, nil)
 		if err != nil {
 			return lazyerrors.Error(err)
 		}
@@ -217,7 +221,7 @@ func fetchAndFilterDocs(ctx context.Context, fp *fetchParams) ([]*types.Document
 	closer := iterator.NewMultiCloser(iter)
 	defer closer.Close()
 
-	f := common.FilterIterator(iter, closer, filter)
+	f := common.FilterIterator(iter, closer, filter, nil)
 
 	return iterator.ConsumeValues(iterator.Interface[struct{}, *types.Document](f))
 }
@@ -43,6 +43,10 @@ func (h *Handler) MsgFindAndModify(ctx context.Context, msg *wire.OpMsg) (*wire.
 		return nil, lazyerrors.Error(err)
 	}
 
+	if err = common.Unimplemented(document, "let"); err != nil {
+		return nil, err
+	}
+
 	params, err := common.GetFindAndModifyParams(document, h.L)
 	if err != nil {
 		return nil, err
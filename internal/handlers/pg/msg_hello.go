@@ -18,6 +18,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
 	"github.com/FerretDB/FerretDB/internal/handlers/common"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
@@ -46,7 +47,7 @@ func (h *Handler) MsgHello(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, e
 			"maxWriteBatchSize", int32(100000),
 			"localTime", time.Now(),
 			// logicalSessionTimeoutMinutes
-			"connectionId", int32(42),
+			"connectionId", int32(conninfo.Get(ctx).ID),
 			"minWireVersion", common.MinWireVersion,
 			"maxWireVersion", common.MaxWireVersion,
 			"readOnly", false,
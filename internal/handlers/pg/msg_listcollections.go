@@ -86,7 +86,7 @@ func (h *Handler) MsgListCollections(ctx context.Context, msg *wire.OpMsg) (*wir
 
 		var matches bool
 
-		if matches, err = common.FilterDocument(d, filter); err != nil {
+		if matches, err = common.FilterDocument(d, filter, nil); err != nil {
 			return nil, lazyerrors.Error(err)
 		}
 
@@ -79,7 +79,7 @@ func (h *Handler) MsgListDatabases(ctx context.Context, msg *wire.OpMsg) (*wire.
 				"empty", sizeOnDisk == 0,
 			))
 
-			matches, err := common.FilterDocument(d, filter)
+			matches, err := common.FilterDocument(d, filter, nil)
 			if err != nil {
 				return err
 			}
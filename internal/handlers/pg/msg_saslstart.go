@@ -22,6 +22,7 @@ import (
 	"github.com/jackc/pgerrcode"
 	"github.com/jackc/pgx/v5/pgconn"
 
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
 	"github.com/FerretDB/FerretDB/internal/handlers/common"
 	"github.com/FerretDB/FerretDB/internal/handlers/commonerrors"
 	"github.com/FerretDB/FerretDB/internal/types"
@@ -41,7 +42,23 @@ func (h *Handler) MsgSASLStart(ctx context.Context, msg *wire.OpMsg) (*wire.OpMs
 		return nil, lazyerrors.Error(err)
 	}
 
+	connInfo := conninfo.Get(ctx)
+
+	// without username, the pool would use credentials from the PostgreSQL URL
+	if username, _ := connInfo.Auth(); username == "" {
+		connInfo.SetAuth("", "")
+
+		return nil, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrAuthenticationFailed,
+			"Username is required.\n"+
+				"See https://docs.ferretdb.io/security/authentication/ for more details.",
+			"payload",
+		)
+	}
+
 	if _, err = h.DBPool(ctx); err != nil {
+		connInfo.SetAuth("", "")
+
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgerrcode.IsInvalidAuthorizationSpecification(pgErr.Code) {
 			msg := "FerretDB failed to authenticate you in PostgreSQL:\n" +
@@ -58,6 +75,8 @@ func (h *Handler) MsgSASLStart(ctx context.Context, msg *wire.OpMsg) (*wire.OpMs
 		return nil, lazyerrors.Error(err)
 	}
 
+	connInfo.SetVerified()
+
 	var emptyPayload types.Binary
 	var reply wire.OpMsg
 	must.NoError(reply.SetSections(wire.OpMsgSection{
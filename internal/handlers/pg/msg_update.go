@@ -42,6 +42,10 @@ func (h *Handler) MsgUpdate(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 		return nil, lazyerrors.Error(err)
 	}
 
+	if err = common.Unimplemented(document, "let"); err != nil {
+		return nil, err
+	}
+
 	params, err := common.GetUpdateParams(document, h.L)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
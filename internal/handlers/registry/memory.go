@@ -0,0 +1,53 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"github.com/FerretDB/FerretDB/internal/handlers"
+	"github.com/FerretDB/FerretDB/internal/handlers/sqlite"
+)
+
+// init registers "memory" handler.
+func init() {
+	registry["memory"] = func(opts *NewHandlerOpts) (handlers.Interface, error) {
+		handlerOpts := &sqlite.NewOpts{
+			Backend: "memory",
+
+			L:             opts.Logger.Named("memory"),
+			ConnMetrics:   opts.ConnMetrics,
+			StateProvider: opts.StateProvider,
+			ConnRegistry:  opts.ConnRegistry,
+			Sessions:      opts.Sessions,
+
+			CompactInterval:  opts.CompactInterval,
+			CompactThreshold: opts.CompactThreshold,
+			TTLInterval:      opts.TTLInterval,
+			CursorsInterval:  opts.CursorsInterval,
+			CursorTimeout:    opts.CursorTimeout,
+			SessionsInterval: opts.SessionsInterval,
+
+			QuotaDatabaseSize:        opts.QuotaOpts.DatabaseSize,
+			QuotaDatabaseDocuments:   opts.QuotaOpts.DatabaseDocuments,
+			QuotaCollectionSize:      opts.QuotaOpts.CollectionSize,
+			QuotaCollectionDocuments: opts.QuotaOpts.CollectionDocuments,
+
+			DisableFilterPushdown: opts.DisableFilterPushdown,
+			EnableSortPushdown:    opts.EnableSortPushdown,
+			EnableOplog:           opts.EnableOplog,
+		}
+
+		return sqlite.New(handlerOpts)
+	}
+}
@@ -17,10 +17,13 @@ package registry
 
 import (
 	"fmt"
+	"time"
 
 	"go.uber.org/zap"
 
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
 	"github.com/FerretDB/FerretDB/internal/clientconn/connmetrics"
+	"github.com/FerretDB/FerretDB/internal/clientconn/session"
 	"github.com/FerretDB/FerretDB/internal/handlers"
 	"github.com/FerretDB/FerretDB/internal/util/state"
 )
@@ -40,6 +43,8 @@ type NewHandlerOpts struct {
 	Logger        *zap.Logger
 	ConnMetrics   *connmetrics.ConnMetrics
 	StateProvider *state.Provider
+	ConnRegistry  *conninfo.Registry
+	Sessions      *session.Registry
 
 	// for `postgresql` handler
 	PostgreSQLURL string
@@ -50,9 +55,33 @@ type NewHandlerOpts struct {
 	// for `hana` handler
 	HANAURL string
 
+	MaintenanceOpts
+	QuotaOpts
 	TestOpts
 }
 
+// MaintenanceOpts represents configuration of background maintenance tasks.
+//
+// Zero intervals disable tasks.
+type MaintenanceOpts struct {
+	CompactInterval  time.Duration
+	CompactThreshold int64
+	TTLInterval      time.Duration
+	CursorsInterval  time.Duration
+	CursorTimeout    time.Duration
+	SessionsInterval time.Duration
+}
+
+// QuotaOpts represents configuration of storage quotas.
+//
+// Zero values disable quotas.
+type QuotaOpts struct {
+	DatabaseSize        int64
+	DatabaseDocuments   int64
+	CollectionSize      int64
+	CollectionDocuments int64
+}
+
 // TestOpts represents experimental configuration options.
 type TestOpts struct {
 	DisableFilterPushdown bool
@@ -82,12 +111,25 @@ func NewHandler(name string, opts *NewHandlerOpts) (handlers.Interface, error) {
 	return newHandler(opts)
 }
 
+// VerifiesCredentials returns true if the handler with the given name verifies
+// client's credentials (by passing them to the backend) during authentication.
+//
+// Other handlers accept any credentials.
+func VerifiesCredentials(name string) bool {
+	switch name {
+	case "postgresql", "pg":
+		return true
+	default:
+		return false
+	}
+}
+
 // Handlers returns a list of all handlers registered at compile-time.
 func Handlers() []string {
 	res := make([]string, 0, len(registry))
 
 	// double check registered names and return them in the right order
-	for _, h := range []string{"postgresql", "sqlite", "hana"} {
+	for _, h := range []string{"postgresql", "sqlite", "hana", "memory"} {
 		if _, ok := registry[h]; !ok {
 			continue
 		}
@@ -55,6 +55,16 @@ func tagPackages(t *testing.T, tag string) []string {
 	return maps.Keys(packages)
 }
 
+func TestVerifiesCredentials(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, VerifiesCredentials("postgresql"))
+	assert.True(t, VerifiesCredentials("pg"))
+	assert.False(t, VerifiesCredentials("sqlite"))
+	assert.False(t, VerifiesCredentials("memory"))
+	assert.False(t, VerifiesCredentials("hana"))
+}
+
 // TestDeps ensures that some packages are imported
 // only when the corresponding backend handler is enabled via Go build tag.
 func TestDeps(t *testing.T) {
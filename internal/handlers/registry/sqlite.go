@@ -29,6 +29,20 @@ func init() {
 			L:             opts.Logger.Named("sqlite"),
 			ConnMetrics:   opts.ConnMetrics,
 			StateProvider: opts.StateProvider,
+			ConnRegistry:  opts.ConnRegistry,
+			Sessions:      opts.Sessions,
+
+			CompactInterval:  opts.CompactInterval,
+			CompactThreshold: opts.CompactThreshold,
+			TTLInterval:      opts.TTLInterval,
+			CursorsInterval:  opts.CursorsInterval,
+			CursorTimeout:    opts.CursorTimeout,
+			SessionsInterval: opts.SessionsInterval,
+
+			QuotaDatabaseSize:        opts.QuotaOpts.DatabaseSize,
+			QuotaDatabaseDocuments:   opts.QuotaOpts.DatabaseDocuments,
+			QuotaCollectionSize:      opts.QuotaOpts.CollectionSize,
+			QuotaCollectionDocuments: opts.QuotaOpts.CollectionDocuments,
 
 			DisableFilterPushdown: opts.DisableFilterPushdown,
 			EnableSortPushdown:    opts.EnableSortPushdown,
@@ -0,0 +1,317 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/clientconn/session"
+	"github.com/FerretDB/FerretDB/internal/handlers/maintenance"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+const (
+	// ttlDeleteBatchSize is the maximum number of documents deleted at once by TTL sweep.
+	ttlDeleteBatchSize = 1000
+
+	// ttlSweepMaxDocuments is the maximum number of documents deleted from a single collection by TTL sweep;
+	// the rest are deleted by the next sweep.
+	ttlSweepMaxDocuments = 100 * ttlDeleteBatchSize
+)
+
+// newMaintenance returns a maintenance scheduler for handler's background tasks.
+func (h *Handler) newMaintenance() *maintenance.Scheduler {
+	return maintenance.NewScheduler(h.L.Named("maintenance"), []maintenance.Task{{
+		Name:     "compact",
+		Interval: h.CompactInterval,
+		Run:      h.compactHot,
+	}, {
+		Name:     "ttl",
+		Interval: h.TTLInterval,
+		Run:      h.sweepTTL,
+	}, {
+		Name:     "cursors",
+		Interval: h.CursorsInterval,
+		Run: func(context.Context) (int64, error) {
+			return int64(h.cursors.CloseIdle(h.CursorTimeout)), nil
+		},
+	}, {
+		Name:     "sessions",
+		Interval: h.SessionsInterval,
+		Run: func(context.Context) (int64, error) {
+			return int64(h.Sessions.Expire(session.DefaultTimeout)), nil
+		},
+	}})
+}
+
+// compactHot compacts (VACUUM ANALYZE for PostgreSQL) collections
+// that had enough modified documents since the last run.
+// It returns the number of compacted collections.
+func (h *Handler) compactHot(ctx context.Context) (int64, error) {
+	var compacted int64
+
+	for _, ns := range h.writes.Hot(h.CompactThreshold) {
+		db, err := h.b.Database(ns.DB)
+		if err != nil {
+			return compacted, lazyerrors.Error(err)
+		}
+
+		c, err := db.Collection(ns.Collection)
+		if err != nil {
+			return compacted, lazyerrors.Error(err)
+		}
+
+		_, err = c.Compact(ctx, new(backends.CompactParams))
+
+		switch {
+		case err == nil:
+			compacted++
+		case backends.ErrorCodeIs(err, backends.ErrorCodeDatabaseDoesNotExist, backends.ErrorCodeCollectionDoesNotExist):
+			// dropped since it was modified
+		default:
+			return compacted, lazyerrors.Error(err)
+		}
+	}
+
+	return compacted, nil
+}
+
+// sweepTTL deletes expired documents from all collections with TTL indexes.
+// It returns the number of deleted documents.
+func (h *Handler) sweepTTL(ctx context.Context) (int64, error) {
+	dbs, err := h.b.ListDatabases(ctx, nil)
+	if err != nil {
+		return 0, lazyerrors.Error(err)
+	}
+
+	var deleted int64
+	var errs []error
+
+	for _, dbInfo := range dbs.Databases {
+		db, err := h.b.Database(dbInfo.Name)
+		if err != nil {
+			return deleted, lazyerrors.Error(err)
+		}
+
+		colls, err := db.ListCollections(ctx, nil)
+		if err != nil {
+			if backends.ErrorCodeIs(err, backends.ErrorCodeDatabaseDoesNotExist) {
+				continue
+			}
+
+			return deleted, lazyerrors.Error(err)
+		}
+
+		for _, cInfo := range colls.Collections {
+			c, err := db.Collection(cInfo.Name)
+			if err != nil {
+				return deleted, lazyerrors.Error(err)
+			}
+
+			// sweep other collections even if one of them fails
+			d, err := h.sweepCollectionTTL(ctx, c, dbInfo.Name, cInfo.Name)
+			deleted += d
+
+			if err != nil {
+				h.L.Warn(
+					"TTL sweep failed",
+					zap.String("db", dbInfo.Name), zap.String("collection", cInfo.Name), zap.Error(err),
+				)
+
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return deleted, errors.Join(errs...)
+}
+
+// ttlIndex represents a TTL index of a collection.
+type ttlIndex struct {
+	path   types.Path
+	cutoff time.Time
+}
+
+// sweepCollectionTTL deletes expired documents from the given collection.
+//
+// A document is expired if the field of the TTL index contains a date
+// (or an array with dates, then the earliest one is used)
+// that is older than index's expireAfterSeconds.
+// Documents without such field or with non-date values never expire.
+//
+// The collection is scanned once for up to ttlSweepMaxDocuments expired documents;
+// the read transaction should be closed before deleting (some backends use a single connection).
+// Then they are deleted in batches of ttlDeleteBatchSize.
+func (h *Handler) sweepCollectionTTL(ctx context.Context, c backends.Collection, dbName, cName string) (int64, error) {
+	indexes, err := c.ListIndexes(ctx, nil)
+	if err != nil {
+		if backends.ErrorCodeIs(err, backends.ErrorCodeCollectionDoesNotExist) {
+			return 0, nil
+		}
+
+		return 0, lazyerrors.Error(err)
+	}
+
+	var ttls []ttlIndex
+
+	now := time.Now()
+
+	for _, index := range indexes.Indexes {
+		if index.ExpireAfterSeconds == nil {
+			continue
+		}
+
+		path, err := types.NewPathFromString(index.Key[0].Field)
+		if err != nil {
+			return 0, lazyerrors.Error(err)
+		}
+
+		ttls = append(ttls, ttlIndex{
+			path:   path,
+			cutoff: now.Add(-time.Duration(*index.ExpireAfterSeconds) * time.Second),
+		})
+	}
+
+	if len(ttls) == 0 {
+		return 0, nil
+	}
+
+	ids, sizes, err := h.ttlExpired(ctx, c, ttls)
+	if err != nil {
+		return 0, lazyerrors.Error(err)
+	}
+
+	var deleted int64
+
+	for len(ids) > 0 {
+		n := min(len(ids), ttlDeleteBatchSize)
+
+		res, err := c.DeleteAll(ctx, &backends.DeleteAllParams{IDs: ids[:n]})
+		if err != nil {
+			return deleted, lazyerrors.Error(err)
+		}
+
+		var size int64
+		for _, s := range sizes[:n] {
+			size += s
+		}
+
+		h.quotaDeleted(dbName, cName, size, n, int(res.Deleted))
+
+		deleted += int64(res.Deleted)
+
+		ids, sizes = ids[n:], sizes[n:]
+	}
+
+	return deleted, nil
+}
+
+// ttlExpired scans the given collection and returns IDs of up to ttlSweepMaxDocuments expired documents
+// and their estimated sizes (zeros if quotas are not configured).
+//
+// A document matching several TTL indexes is returned once.
+func (h *Handler) ttlExpired(ctx context.Context, c backends.Collection, ttls []ttlIndex) ([]any, []int64, error) {
+	q, err := c.Query(ctx, nil)
+	if err != nil {
+		return nil, nil, lazyerrors.Error(err)
+	}
+
+	defer q.Iter.Close()
+
+	var ids []any
+	var sizes []int64
+
+	for len(ids) < ttlSweepMaxDocuments {
+		var doc *types.Document
+
+		if _, doc, err = q.Iter.Next(); err != nil {
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				break
+			}
+
+			return nil, nil, lazyerrors.Error(err)
+		}
+
+		if !ttlIsExpired(doc, ttls) {
+			continue
+		}
+
+		var size int64
+
+		if h.quotasEnabled() {
+			if size, err = documentSize(doc); err != nil {
+				return nil, nil, lazyerrors.Error(err)
+			}
+		}
+
+		ids = append(ids, must.NotFail(doc.Get("_id")))
+		sizes = append(sizes, size)
+	}
+
+	return ids, sizes, nil
+}
+
+// ttlIsExpired returns true if the given document is expired by any of the given TTL indexes.
+func ttlIsExpired(doc *types.Document, ttls []ttlIndex) bool {
+	for _, t := range ttls {
+		if expiresAt, ok := ttlDate(doc, t.path); ok && !expiresAt.After(t.cutoff) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ttlDate returns the date of TTL index field at the given path, if any.
+// For arrays, the earliest date is returned.
+func ttlDate(doc *types.Document, path types.Path) (time.Time, bool) {
+	v, err := doc.GetByPath(path)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	switch v := v.(type) {
+	case time.Time:
+		return v, true
+
+	case *types.Array:
+		var res time.Time
+		var found bool
+
+		for i := 0; i < v.Len(); i++ {
+			d, ok := must.NotFail(v.Get(i)).(time.Time)
+			if !ok {
+				continue
+			}
+
+			if !found || d.Before(res) {
+				res, found = d, true
+			}
+		}
+
+		return res, found
+
+	default:
+		return time.Time{}, false
+	}
+}
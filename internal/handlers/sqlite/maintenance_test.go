@@ -0,0 +1,85 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+)
+
+func TestSweepTTL(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Ctx(t)
+
+	h := newTestHandler(t, &NewOpts{})
+	dbName := testutil.DatabaseName(t)
+
+	indexes := must.NotFail(types.NewArray(
+		must.NotFail(types.NewDocument(
+			"key", must.NotFail(types.NewDocument("expires", int32(1))),
+			"name", "expires_1",
+			"expireAfterSeconds", int32(60),
+		)),
+		must.NotFail(types.NewDocument(
+			"key", must.NotFail(types.NewDocument("created", int32(1))),
+			"name", "created_1",
+			"expireAfterSeconds", int32(60),
+		)),
+	))
+
+	_, err := h.MsgCreateIndexes(ctx, testMsg(t, "createIndexes", "test", "indexes", indexes, "$db", dbName))
+	require.NoError(t, err)
+
+	// more than two batches of expired documents
+	expired := 2*ttlDeleteBatchSize + 10
+
+	docs := types.MakeArray(expired + 2)
+
+	// some documents are expired by both indexes
+	for i := 0; i < expired; i++ {
+		doc := must.NotFail(types.NewDocument("_id", int32(i), "expires", time.Now().Add(-time.Hour)))
+		if i%2 == 0 {
+			doc.Set("created", time.Now().Add(-time.Hour))
+		}
+
+		docs.Append(doc)
+	}
+
+	docs.Append(must.NotFail(types.NewDocument("_id", "fresh", "expires", time.Now())))
+	docs.Append(must.NotFail(types.NewDocument("_id", "never")))
+
+	_, err = h.MsgInsert(ctx, testMsg(t, "insert", "test", "documents", docs, "$db", dbName))
+	require.NoError(t, err)
+
+	deleted, err := h.sweepTTL(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(expired), deleted)
+
+	reply, err := h.MsgCount(ctx, testMsg(t, "count", "test", "$db", dbName))
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), must.NotFail(must.NotFail(reply.Document()).Get("n")))
+
+	deleted, err = h.sweepTTL(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, deleted)
+}
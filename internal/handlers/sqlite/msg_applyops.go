@@ -0,0 +1,402 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/FerretDB/FerretDB/internal/handlers/common"
+	"github.com/FerretDB/FerretDB/internal/handlers/commonerrors"
+	"github.com/FerretDB/FerretDB/internal/handlers/commonparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/wire"
+)
+
+// applyOp represents a single oplog entry of `applyOps` command.
+type applyOp struct {
+	o          *types.Document
+	o2         *types.Document
+	op         string
+	db         string
+	collection string
+	upsert     bool
+}
+
+// MsgApplyOps implements HandlerInterface.
+func (h *Handler) MsgApplyOps(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	document, err := msg.Document()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if err = common.Unimplemented(document, "preCondition"); err != nil {
+		return nil, err
+	}
+
+	common.Ignored(document, h.L, "bypassDocumentValidation", "writeConcern", "comment")
+
+	entries, err := common.GetRequiredParam[*types.Array](document, document.Command())
+	if err != nil {
+		return nil, err
+	}
+
+	alwaysUpsert := true
+	if v, _ := document.Get("alwaysUpsert"); v != nil {
+		if alwaysUpsert, err = commonparams.GetBoolOptionalParam("alwaysUpsert", v); err != nil {
+			return nil, err
+		}
+	}
+
+	allowAtomic := true
+	if v, _ := document.Get("allowAtomic"); v != nil {
+		if allowAtomic, err = commonparams.GetBoolOptionalParam("allowAtomic", v); err != nil {
+			return nil, err
+		}
+	}
+
+	// validate the whole batch before applying anything
+	ops := make([]*applyOp, 0, entries.Len())
+
+	iter := entries.Iterator()
+	defer iter.Close()
+
+	for {
+		i, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		op, err := parseApplyOp(i, v)
+		if err != nil {
+			return nil, err
+		}
+
+		ops = append(ops, op)
+	}
+
+	// Backends do not support transactions, so batches of several operations can't be applied atomically.
+	// Clients should explicitly allow non-atomic application.
+	if allowAtomic && len(ops) > 1 {
+		return nil, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrNotImplemented,
+			"applyOps: batches of several operations can't be applied atomically, set allowAtomic to false",
+			"allowAtomic",
+		)
+	}
+
+	// Batches are applied one at a time.
+	// Operations are applied one by one; if one of them fails, the following operations are not applied,
+	// but previous ones are not rolled back. The reply tells the client which operations were applied.
+	h.applyOpsM.Lock()
+	defer h.applyOpsM.Unlock()
+
+	results := types.MakeArray(len(ops))
+
+	for i, op := range ops {
+		if err = h.applyOp(ctx, op, alwaysUpsert); err != nil {
+			var ce *commonerrors.CommandError
+			if !errors.As(err, &ce) {
+				return nil, lazyerrors.Error(err)
+			}
+
+			results.Append(false)
+
+			var reply wire.OpMsg
+			must.NoError(reply.SetSections(wire.OpMsgSection{
+				Documents: []*types.Document{must.NotFail(types.NewDocument(
+					"applied", int32(i),
+					"code", int32(ce.Code()),
+					"codeName", ce.Code().String(),
+					"errmsg", fmt.Sprintf("applyOps: operation %d failed: %s", i, ce.Err()),
+					"results", results,
+					"ok", float64(0),
+				))},
+			}))
+
+			return &reply, nil
+		}
+
+		results.Append(true)
+	}
+
+	var reply wire.OpMsg
+	must.NoError(reply.SetSections(wire.OpMsgSection{
+		Documents: []*types.Document{must.NotFail(types.NewDocument(
+			"applied", int32(len(ops)),
+			"results", results,
+			"ok", float64(1),
+		))},
+	}))
+
+	return &reply, nil
+}
+
+// parseApplyOp validates the given oplog entry with index i and returns it.
+func parseApplyOp(i int, v any) (*applyOp, error) {
+	entry, ok := v.(*types.Document)
+	if !ok {
+		return nil, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrFailedToParse,
+			fmt.Sprintf("applyOps: operation %d is not an object, found %s", i, commonparams.AliasFromType(v)),
+			"applyOps",
+		)
+	}
+
+	var op applyOp
+	var err error
+
+	if op.op, err = common.GetRequiredParam[string](entry, "op"); err != nil {
+		return nil, err
+	}
+
+	if op.op == "n" {
+		return &op, nil
+	}
+
+	ns, err := common.GetRequiredParam[string](entry, "ns")
+	if err != nil {
+		return nil, err
+	}
+
+	// collection names may contain dots
+	op.db, op.collection, _ = strings.Cut(ns, ".")
+	if op.db == "" || op.collection == "" {
+		return nil, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrInvalidNamespace,
+			fmt.Sprintf("Invalid namespace specified '%s'", ns),
+			"applyOps",
+		)
+	}
+
+	if op.o, err = common.GetRequiredParam[*types.Document](entry, "o"); err != nil {
+		return nil, err
+	}
+
+	if op.o2, err = common.GetOptionalParam[*types.Document](entry, "o2", nil); err != nil {
+		return nil, err
+	}
+
+	if op.upsert, err = common.GetOptionalParam(entry, "b", false); err != nil {
+		return nil, err
+	}
+
+	switch op.op {
+	case "i", "d":
+		if !op.o.Has("_id") {
+			return nil, commonerrors.NewCommandErrorMsgWithArgument(
+				commonerrors.ErrNoSuchKey,
+				fmt.Sprintf("applyOps: operation %d: missing _id in 'o' field", i),
+				"applyOps",
+			)
+		}
+
+	case "u":
+		if op.o.Has("$v") {
+			if op.o.Has("diff") {
+				return nil, commonerrors.NewCommandErrorMsgWithArgument(
+					commonerrors.ErrNotImplemented,
+					fmt.Sprintf("applyOps: operation %d: delta update format is not supported", i),
+					"applyOps",
+				)
+			}
+
+			// the first version of the format is a regular update with operators
+			op.o.Remove("$v")
+		}
+
+		// FerretDB's own oplog stores whole documents without 'o2'
+		if op.o2 == nil && op.o.Has("_id") {
+			op.o2 = must.NotFail(types.NewDocument("_id", must.NotFail(op.o.Get("_id"))))
+		}
+
+		if op.o2 == nil || !op.o2.Has("_id") {
+			return nil, commonerrors.NewCommandErrorMsgWithArgument(
+				commonerrors.ErrNoSuchKey,
+				fmt.Sprintf("applyOps: operation %d: missing _id in 'o2' field", i),
+				"applyOps",
+			)
+		}
+
+	case "c":
+		if op.collection != "$cmd" {
+			return nil, commonerrors.NewCommandErrorMsgWithArgument(
+				commonerrors.ErrInvalidNamespace,
+				fmt.Sprintf("applyOps: operation %d: command namespace should be '%s.$cmd', found '%s'", i, op.db, ns),
+				"applyOps",
+			)
+		}
+
+		switch command := op.o.Command(); command {
+		case "create", "drop", "createIndexes":
+			if _, err = common.GetRequiredParam[string](op.o, command); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, commonerrors.NewCommandErrorMsgWithArgument(
+				commonerrors.ErrNotImplemented,
+				fmt.Sprintf("applyOps: operation %d: command %q is not supported", i, command),
+				"applyOps",
+			)
+		}
+
+	default:
+		return nil, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrBadValue,
+			fmt.Sprintf("applyOps: operation %d: invalid operation type %q", i, op.op),
+			"applyOps",
+		)
+	}
+
+	return &op, nil
+}
+
+// applyOp applies a single validated oplog entry using the regular command handlers.
+//
+// Inserts of existing documents replace them if alwaysUpsert is true.
+func (h *Handler) applyOp(ctx context.Context, op *applyOp, alwaysUpsert bool) error {
+	var doc *types.Document
+	var handler func(context.Context, *wire.OpMsg) (*wire.OpMsg, error)
+
+	switch op.op {
+	case "n":
+		return nil
+
+	case "i":
+		if alwaysUpsert {
+			doc = must.NotFail(types.NewDocument(
+				"update", op.collection,
+				"updates", must.NotFail(types.NewArray(must.NotFail(types.NewDocument(
+					"q", must.NotFail(types.NewDocument("_id", must.NotFail(op.o.Get("_id")))),
+					"u", op.o,
+					"upsert", true,
+				)))),
+			))
+			handler = h.MsgUpdate
+
+			break
+		}
+
+		doc = must.NotFail(types.NewDocument(
+			"insert", op.collection,
+			"documents", must.NotFail(types.NewArray(op.o)),
+		))
+		handler = h.MsgInsert
+
+	case "u":
+		doc = must.NotFail(types.NewDocument(
+			"update", op.collection,
+			"updates", must.NotFail(types.NewArray(must.NotFail(types.NewDocument(
+				"q", op.o2,
+				"u", op.o,
+				"upsert", op.upsert,
+			)))),
+		))
+		handler = h.MsgUpdate
+
+	case "d":
+		doc = must.NotFail(types.NewDocument(
+			"delete", op.collection,
+			"deletes", must.NotFail(types.NewArray(must.NotFail(types.NewDocument(
+				"q", op.o,
+				"limit", int32(1),
+			)))),
+		))
+		handler = h.MsgDelete
+
+	case "c":
+		doc = op.o.DeepCopy()
+
+		switch doc.Command() {
+		case "create":
+			handler = h.MsgCreate
+		case "drop":
+			handler = h.MsgDrop
+		case "createIndexes":
+			// oplog entries contain a single index specification instead of the list
+			if !doc.Has("indexes") {
+				spec := doc.DeepCopy()
+				spec.Remove("createIndexes")
+				spec.Remove("v")
+
+				doc = must.NotFail(types.NewDocument(
+					"createIndexes", must.NotFail(doc.Get("createIndexes")),
+					"indexes", must.NotFail(types.NewArray(spec)),
+				))
+			}
+
+			handler = h.MsgCreateIndexes
+		default:
+			panic(fmt.Sprintf("unexpected command %q", doc.Command()))
+		}
+
+	default:
+		panic(fmt.Sprintf("unexpected operation %q", op.op))
+	}
+
+	doc.Set("$db", op.db)
+
+	var msg wire.OpMsg
+	must.NoError(msg.SetSections(wire.OpMsgSection{
+		Documents: []*types.Document{doc},
+	}))
+
+	reply, err := handler(ctx, &msg)
+	if err != nil {
+		// make replays of already applied commands idempotent
+		var ce *commonerrors.CommandError
+		if op.op == "c" && errors.As(err, &ce) {
+			switch {
+			case doc.Command() == "create" && ce.Code() == commonerrors.ErrNamespaceExists,
+				doc.Command() == "drop" && ce.Code() == commonerrors.ErrNamespaceNotFound:
+				return nil
+			}
+		}
+
+		return err
+	}
+
+	res, err := reply.Document()
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	if v, _ := res.Get("writeErrors"); v != nil {
+		we := must.NotFail(v.(*types.Array).Get(0)).(*types.Document)
+
+		return commonerrors.NewCommandErrorMsg(
+			commonerrors.ErrorCode(must.NotFail(we.Get("code")).(int32)),
+			must.NotFail(we.Get("errmsg")).(string),
+		)
+	}
+
+	if op.op == "u" && !op.upsert && must.NotFail(res.Get("n")).(int32) == 0 {
+		return commonerrors.NewCommandErrorMsg(
+			commonerrors.ErrOperationFailed,
+			fmt.Sprintf("Failed to apply update: no document with _id %s", types.FormatAnyValue(must.NotFail(op.o2.Get("_id")))),
+		)
+	}
+
+	return nil
+}
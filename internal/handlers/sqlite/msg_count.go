@@ -76,7 +76,7 @@ func (h *Handler) MsgCount(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, e
 	closer := iterator.NewMultiCloser(iter)
 	defer closer.Close()
 
-	iter = common.FilterIterator(iter, closer, params.Filter)
+	iter = common.FilterIterator(iter, closer, params.Filter, nil)
 
 	iter = common.SkipIterator(iter, closer, params.Skip)
 
@@ -18,9 +18,13 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/google/uuid"
+
 	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/clientconn/session"
 	"github.com/FerretDB/FerretDB/internal/handlers/common"
 	"github.com/FerretDB/FerretDB/internal/handlers/commonerrors"
+	"github.com/FerretDB/FerretDB/internal/handlers/commonparams"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
 	"github.com/FerretDB/FerretDB/internal/util/must"
@@ -79,6 +83,27 @@ func (h *Handler) MsgCreate(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 		return nil, err
 	}
 
+	var temp bool
+
+	if v, _ := document.Get("temp"); v != nil {
+		if temp, err = commonparams.GetBoolOptionalParam("temp", v); err != nil {
+			return nil, err
+		}
+	}
+
+	var sessionID uuid.UUID
+
+	if temp {
+		var ok bool
+		if sessionID, ok = session.GetID(document); !ok {
+			return nil, commonerrors.NewCommandErrorMsgWithArgument(
+				commonerrors.ErrBadValue,
+				"Temporary collections can be created only within a logical session",
+				"create",
+			)
+		}
+	}
+
 	db, err := h.b.Database(dbName)
 	if err != nil {
 		if backends.ErrorCodeIs(err, backends.ErrorCodeDatabaseNameIsInvalid) {
@@ -89,12 +114,25 @@ func (h *Handler) MsgCreate(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 		return nil, lazyerrors.Error(err)
 	}
 
-	err = db.CreateCollection(ctx, &backends.CreateCollectionParams{
+	params := &backends.CreateCollectionParams{
 		Name: collectionName,
-	})
+	}
+
+	if temp {
+		params.Temp = true
+		params.TempOwner = h.tempOwner()
+	}
+
+	err = db.CreateCollection(ctx, params)
 
 	switch {
 	case err == nil:
+		if temp {
+			if err = h.addTempCollection(ctx, db, dbName, collectionName, sessionID); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+		}
+
 		var reply wire.OpMsg
 		must.NoError(reply.SetSections(wire.OpMsgSection{
 			Documents: []*types.Document{must.NotFail(types.NewDocument(
@@ -18,13 +18,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/FerretDB/FerretDB/internal/backends"
 	"github.com/FerretDB/FerretDB/internal/handlers/common"
 	"github.com/FerretDB/FerretDB/internal/handlers/commonerrors"
 	"github.com/FerretDB/FerretDB/internal/handlers/commonparams"
+	"github.com/FerretDB/FerretDB/internal/handlers/indexbuild"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/iterator"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
@@ -119,45 +122,86 @@ func (h *Handler) MsgCreateIndexes(ctx context.Context, msg *wire.OpMsg) (*wire.
 		return nil, err
 	}
 
+	computed := slices.ContainsFunc(toCreate, func(i backends.IndexInfo) bool { return isComputedIndexKey(i.Key) })
+
+	// other backends can't use computed indexes for queries
+	if computed && h.Backend != "postgresql" {
+		return nil, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrNotImplemented,
+			fmt.Sprintf("Computed index keys are not supported by %s backend", h.Backend),
+			command,
+		)
+	}
+
 	var createCollection bool
-	beforeCreate, err := c.ListIndexes(ctx, new(backends.ListIndexesParams))
-	if err != nil {
-		switch {
-		case backends.ErrorCodeIs(err, backends.ErrorCodeCollectionDoesNotExist):
-			// If the namespace doesn't exist, we just don't need to compare new indexes with existing ones,
-			// the namespace will be created when indexes are created.
-			beforeCreate = &backends.ListIndexesResult{
-				Indexes: []backends.IndexInfo{},
+	var numIndexesBefore int
+	var created []backends.IndexInfo
+
+	// in-progress builds for the same collection are waited for, so the same indexes are not built twice;
+	// a build that was started concurrently after that check is waited for on the next iteration
+	running := h.indexBuilds.Collection(dbName, collection)
+
+	for {
+		for _, b := range running {
+			select {
+			case <-b.Done():
+			case <-ctx.Done():
+				return nil, lazyerrors.Error(context.Cause(ctx))
 			}
-			createCollection = true
+		}
 
-		default:
-			return nil, lazyerrors.Error(err)
+		createCollection = false
+
+		var beforeCreate *backends.ListIndexesResult
+
+		if beforeCreate, err = c.ListIndexes(ctx, new(backends.ListIndexesParams)); err != nil {
+			switch {
+			case backends.ErrorCodeIs(err, backends.ErrorCodeCollectionDoesNotExist):
+				// If the namespace doesn't exist, we just don't need to compare new indexes with existing ones,
+				// the namespace will be created when indexes are created.
+				beforeCreate = &backends.ListIndexesResult{
+					Indexes: []backends.IndexInfo{},
+				}
+				createCollection = true
+
+			default:
+				return nil, lazyerrors.Error(err)
+			}
 		}
-	}
 
-	numIndexesBefore := len(beforeCreate.Indexes)
+		numIndexesBefore = len(beforeCreate.Indexes)
 
-	// for compatibility
-	if numIndexesBefore == 0 {
-		numIndexesBefore = 1
-	}
+		// for compatibility
+		if numIndexesBefore == 0 {
+			numIndexesBefore = 1
+		}
 
-	if toCreate, err = validateIndexesForCreation(command, beforeCreate.Indexes, toCreate); err != nil {
-		return nil, err
-	}
+		if created, err = validateIndexesForCreation(command, beforeCreate.Indexes, toCreate); err != nil {
+			return nil, err
+		}
 
-	_, err = c.CreateIndexes(ctx, &backends.CreateIndexesParams{Indexes: toCreate})
-	if err != nil {
-		return nil, lazyerrors.Error(err)
+		if len(created) == 0 {
+			break
+		}
+
+		var b *indexbuild.Build
+		if b, running = h.startIndexBuild(ctx, c, dbName, collection, document, created); b == nil {
+			continue
+		}
+
+		if err = waitIndexBuild(ctx, b, document); err != nil {
+			return nil, err
+		}
+
+		break
 	}
 
 	resp := new(types.Document)
 
 	resp.Set("numIndexesBefore", int32(numIndexesBefore))
-	resp.Set("numIndexesAfter", int32(numIndexesBefore+len(toCreate)))
+	resp.Set("numIndexesAfter", int32(numIndexesBefore+len(created)))
 
-	if len(toCreate) > 0 {
+	if len(created) > 0 {
 		resp.Set("createdCollectionAutomatically", createCollection)
 	} else {
 		resp.Set("note", "all indexes already exist")
@@ -173,6 +217,55 @@ func (h *Handler) MsgCreateIndexes(ctx context.Context, msg *wire.OpMsg) (*wire.
 	return &reply, nil
 }
 
+// startIndexBuild starts building given indexes in the background,
+// unless there are in-progress builds for the same collection.
+// In the latter case, it returns nil and those builds.
+//
+// The build is visible in currentOp output and could be aborted by dropIndexes.
+// If the context is canceled (for example, the client disconnects), the build continues.
+func (h *Handler) startIndexBuild(ctx context.Context, c backends.Collection, dbName, collection string, command *types.Document, indexes []backends.IndexInfo) (*indexbuild.Build, []*indexbuild.Build) { //nolint:lll // for readability
+	params := &indexbuild.StartParams{
+		Command:    command,
+		DB:         dbName,
+		Collection: collection,
+		Indexes:    indexes,
+	}
+
+	return h.indexBuilds.StartExclusive(ctx, params, func(ctx context.Context, b *indexbuild.Build) error {
+		_, err := c.CreateIndexes(ctx, &backends.CreateIndexesParams{
+			Indexes:  indexes,
+			Progress: b.SetProgress,
+		})
+
+		return err
+	})
+}
+
+// waitIndexBuild waits for the given index build to finish and returns its error as a command error.
+func waitIndexBuild(ctx context.Context, b *indexbuild.Build, command *types.Document) error {
+	select {
+	case <-b.Done():
+	case <-ctx.Done():
+		return lazyerrors.Error(context.Cause(ctx))
+	}
+
+	err := b.Err()
+	if err == nil {
+		return nil
+	}
+
+	var ae *indexbuild.AbortedError
+	if errors.As(err, &ae) {
+		return commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrIndexBuildAborted,
+			fmt.Sprintf("Index build aborted: %d: %s", b.ID, ae.Reason),
+			command.Command(),
+		)
+	}
+
+	return lazyerrors.Error(err)
+}
+
 // processIndexesArray processes the given array of indexes and returns a slice of backends.IndexInfo elements.
 func processIndexesArray(command string, indexesArray *types.Array) ([]backends.IndexInfo, error) {
 	iter := indexesArray.Iterator()
@@ -290,6 +383,10 @@ func processIndex(command string, indexDoc *types.Document) (*backends.IndexInfo
 			return nil, err
 		}
 
+		if err = validateComputedIndexKey(command, index.Key); err != nil {
+			return nil, err
+		}
+
 		v, _ := indexDoc.Get("name")
 		if v == nil {
 			return nil, commonerrors.NewCommandErrorMsgWithArgument(
@@ -347,14 +444,90 @@ func processIndex(command string, indexDoc *types.Document) (*backends.IndexInfo
 				)
 			}
 
+			if unique && isComputedIndexKey(index.Key) {
+				return nil, commonerrors.NewCommandErrorMsgWithArgument(
+					commonerrors.ErrCannotCreateIndex,
+					fmt.Sprintf(
+						"Unique indexes with computed keys are not supported. Index spec: { key: %s, name: %q, unique: true }",
+						types.FormatAnyValue(must.NotFail(indexDoc.Get("key"))), index.Name,
+					),
+					command,
+				)
+			}
+
 			if unique {
 				index.Unique = true
 			}
 
+		case "expireAfterSeconds":
+			v := must.NotFail(indexDoc.Get("expireAfterSeconds"))
+
+			if len(index.Key) == 1 && index.Key[0].Field == "_id" {
+				return nil, commonerrors.NewCommandErrorMsgWithArgument(
+					commonerrors.ErrInvalidIndexSpecificationOption,
+					fmt.Sprintf("The field 'expireAfterSeconds' is not valid for an _id index specification. "+
+						"Specification: { key: %s, name: %q, expireAfterSeconds: %s, v: 2 }",
+						types.FormatAnyValue(must.NotFail(indexDoc.Get("key"))), index.Name, types.FormatAnyValue(v),
+					),
+					command,
+				)
+			}
+
+			if len(index.Key) > 1 {
+				return nil, commonerrors.NewCommandErrorMsgWithArgument(
+					commonerrors.ErrCannotCreateIndex,
+					"TTL indexes are single-field indexes, compound indexes do not support TTL. "+
+						fmt.Sprintf("Index spec: { key: %s, name: %q, expireAfterSeconds: %s }",
+							types.FormatAnyValue(must.NotFail(indexDoc.Get("key"))), index.Name, types.FormatAnyValue(v),
+						),
+					command,
+				)
+			}
+
+			if isComputedIndexKey(index.Key) {
+				return nil, commonerrors.NewCommandErrorMsgWithArgument(
+					commonerrors.ErrCannotCreateIndex,
+					"TTL indexes do not support computed keys. "+
+						fmt.Sprintf("Index spec: { key: %s, name: %q, expireAfterSeconds: %s }",
+							types.FormatAnyValue(must.NotFail(indexDoc.Get("key"))), index.Name, types.FormatAnyValue(v),
+						),
+					command,
+				)
+			}
+
+			seconds, err := commonparams.GetWholeNumberParam(v)
+
+			switch {
+			case errors.Is(err, commonparams.ErrUnexpectedType):
+				return nil, commonerrors.NewCommandErrorMsgWithArgument(
+					commonerrors.ErrCannotCreateIndex,
+					fmt.Sprintf(
+						"TTL index 'expireAfterSeconds' option must be numeric, but received a type of '%s'. "+
+							"Index spec: { key: %s, name: %q, expireAfterSeconds: %s }",
+						commonparams.AliasFromType(v),
+						types.FormatAnyValue(must.NotFail(indexDoc.Get("key"))), index.Name, types.FormatAnyValue(v),
+					),
+					command,
+				)
+			case err != nil, seconds < 0, seconds > math.MaxInt32:
+				return nil, commonerrors.NewCommandErrorMsgWithArgument(
+					commonerrors.ErrCannotCreateIndex,
+					fmt.Sprintf(
+						"TTL index 'expireAfterSeconds' option must be within an acceptable range, "+
+							"try a lower number. Index spec: { key: %s, name: %q, expireAfterSeconds: %s }",
+						types.FormatAnyValue(must.NotFail(indexDoc.Get("key"))), index.Name, types.FormatAnyValue(v),
+					),
+					command,
+				)
+			}
+
+			expireAfterSeconds := int32(seconds)
+			index.ExpireAfterSeconds = &expireAfterSeconds
+
 		case "background":
 			// ignore deprecated options
 
-		case "sparse", "partialFilterExpression", "expireAfterSeconds", "hidden", "storageEngine",
+		case "sparse", "partialFilterExpression", "hidden", "storageEngine",
 			"weights", "default_language", "language_override", "textIndexVersion", "2dsphereIndexVersion",
 			"bits", "min", "max", "bucketSize", "collation", "wildcardProjection":
 			return nil, commonerrors.NewCommandErrorMsgWithArgument(
@@ -374,6 +547,8 @@ func processIndex(command string, indexDoc *types.Document) (*backends.IndexInfo
 }
 
 // processIndexKey processes the document containing the index key (set of "field-order" pairs).
+//
+// For computed keys, the order is wrapped into the document with the expression, e.g. `{email: {$toLower: 1}}`.
 func processIndexKey(command string, keyDoc *types.Document) ([]backends.IndexKeyPair, error) {
 	res := make([]backends.IndexKeyPair, 0, keyDoc.Len())
 
@@ -407,6 +582,22 @@ func processIndexKey(command string, keyDoc *types.Document) ([]backends.IndexKe
 
 		duplicateChecker[field] = struct{}{}
 
+		var expression backends.IndexKeyExpression
+
+		if doc, ok := order.(*types.Document); ok && doc.Len() == 1 {
+			expression = backends.IndexKeyExpression(doc.Command())
+
+			if expression != backends.IndexKeyExpressionToLower {
+				return nil, commonerrors.NewCommandErrorMsgWithArgument(
+					commonerrors.ErrNotImplemented,
+					fmt.Sprintf("Computed index key expression %q is not implemented yet", expression),
+					command,
+				)
+			}
+
+			order = must.NotFail(doc.Get(doc.Command()))
+		}
+
 		var orderParam int64
 
 		if orderParam, err = commonparams.GetWholeNumberParam(order); err != nil {
@@ -435,10 +626,43 @@ func processIndexKey(command string, keyDoc *types.Document) ([]backends.IndexKe
 		res = append(res, backends.IndexKeyPair{
 			Field:      field,
 			Descending: descending,
+			Expression: expression,
 		})
 	}
 }
 
+// validateComputedIndexKey checks that the index key with computed keys could be created.
+//
+// All fields of such an index are looked up with array elements unwrapped,
+// so array positions in dot notation are not supported.
+func validateComputedIndexKey(command string, key []backends.IndexKeyPair) error {
+	if !isComputedIndexKey(key) {
+		return nil
+	}
+
+	for _, pair := range key {
+		for _, part := range strings.Split(pair.Field, ".") {
+			if _, err := strconv.Atoi(part); err == nil {
+				return commonerrors.NewCommandErrorMsgWithArgument(
+					commonerrors.ErrCannotCreateIndex,
+					fmt.Sprintf(
+						"Index key { %s } with computed keys can't contain array positions, found %q",
+						formatIndexKey(key), pair.Field,
+					),
+					command,
+				)
+			}
+		}
+	}
+
+	return nil
+}
+
+// isComputedIndexKey returns true if the given index key has at least one computed key.
+func isComputedIndexKey(key []backends.IndexKeyPair) bool {
+	return slices.ContainsFunc(key, func(pair backends.IndexKeyPair) bool { return pair.Expression != "" })
+}
+
 // formatIndexKey formats the given index key to a string.
 func formatIndexKey(key []backends.IndexKeyPair) string {
 	res := make([]string, len(key))
@@ -449,6 +673,10 @@ func formatIndexKey(key []backends.IndexKeyPair) string {
 			order = "-1"
 		}
 
+		if pair.Expression != "" {
+			order = "{ " + string(pair.Expression) + ": " + order + " }"
+		}
+
 		res[i] = pair.Field + ": " + order
 	}
 
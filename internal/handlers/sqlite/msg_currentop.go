@@ -16,12 +16,151 @@ package sqlite
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
 
-	"github.com/FerretDB/FerretDB/internal/handlers/commoncommands"
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/internal/handlers/common"
+	"github.com/FerretDB/FerretDB/internal/handlers/commonparams"
+	"github.com/FerretDB/FerretDB/internal/handlers/indexbuild"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
 	"github.com/FerretDB/FerretDB/internal/wire"
 )
 
 // MsgCurrentOp implements HandlerInterface.
 func (h *Handler) MsgCurrentOp(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
-	return commoncommands.MsgCurrentOp(ctx, msg)
+	document, err := msg.Document()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	common.Ignored(document, h.L, "$ownOps")
+
+	var all bool
+
+	if v, _ := document.Get("$all"); v != nil {
+		if all, err = commonparams.GetBoolOptionalParam("$all", v); err != nil {
+			return nil, err
+		}
+	}
+
+	// all fields except command options and generic fields like `$db` are used as a filter for operations
+	filter := document.DeepCopy()
+	for _, k := range document.Keys() {
+		switch {
+		case k == document.Command(), k == "comment", k == "lsid":
+			filter.Remove(k)
+		case strings.HasPrefix(k, "$") && !slices.Contains([]string{"$and", "$or", "$nor", "$expr"}, k):
+			filter.Remove(k)
+		}
+	}
+
+	var ops []*types.Document
+
+	for _, b := range h.indexBuilds.All() {
+		ops = append(ops, indexBuildOp(b))
+	}
+
+	// with `$all`, idle connections are returned too
+	if all && h.ConnRegistry != nil {
+		connInfo := conninfo.Get(ctx)
+
+		for _, c := range h.ConnRegistry.All() {
+			var command *types.Document
+			if c == connInfo {
+				command = document
+			}
+
+			ops = append(ops, connOp(c, command))
+		}
+	}
+
+	inprog := types.MakeArray(len(ops))
+
+	for _, op := range ops {
+		matches, err := common.FilterDocument(op, filter, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if matches {
+			inprog.Append(op)
+		}
+	}
+
+	var reply wire.OpMsg
+	must.NoError(reply.SetSections(wire.OpMsgSection{
+		Documents: []*types.Document{must.NotFail(types.NewDocument(
+			"inprog", inprog,
+			"ok", float64(1),
+		))},
+	}))
+
+	return &reply, nil
+}
+
+// indexBuildOp returns currentOp document for the given index build.
+func indexBuildOp(b *indexbuild.Build) *types.Document {
+	running := time.Since(b.Started())
+
+	op := must.NotFail(types.NewDocument(
+		"type", "op",
+		"desc", "IndexBuildsCoordinator",
+		"active", true,
+		"opid", b.ID,
+		"secs_running", int64(running.Seconds()),
+		"microsecs_running", running.Microseconds(),
+		"op", "command",
+		"ns", b.DB+"."+b.Collection,
+		"command", b.Command,
+	))
+
+	done, total := b.Progress()
+	if total == 0 {
+		op.Set("msg", "Index Build: building index")
+		return op
+	}
+
+	op.Set("msg", fmt.Sprintf("Index Build: building index: %d/%d %d%%", done, total, done*100/total))
+	op.Set("progress", must.NotFail(types.NewDocument(
+		"done", done,
+		"total", total,
+	)))
+
+	return op
+}
+
+// connOp returns currentOp document for the given client connection.
+// Command is set for the connection that runs currentOp itself; other connections are reported as idle.
+func connOp(c *conninfo.ConnInfo, command *types.Document) *types.Document {
+	op := must.NotFail(types.NewDocument(
+		"type", "op",
+		"desc", fmt.Sprintf("conn%d", c.ID),
+		"connectionId", c.ID,
+		"client", c.PeerAddr,
+		"active", command != nil,
+	))
+
+	if md := c.ClientMetadata(); md != nil {
+		if appName := c.Client().Application; appName != "" {
+			op.Set("appName", appName)
+		}
+
+		op.Set("clientMetadata", md.DeepCopy())
+	}
+
+	if username, _ := c.Auth(); username != "" {
+		op.Set("effectiveUsers", must.NotFail(types.NewArray(must.NotFail(types.NewDocument("user", username)))))
+	}
+
+	if command != nil {
+		op.Set("op", "command")
+		op.Set("command", command)
+	}
+
+	return op
 }
@@ -152,6 +152,10 @@ func (h *Handler) MsgDBStats(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 		)
 	}
 
+	if quota := h.quotaDocument(scale); quota.Len() > 0 {
+		pairs = append(pairs, "quota", quota)
+	}
+
 	pairs = append(pairs,
 		"scaleFactor", float64(scale),
 		"ok", float64(1),
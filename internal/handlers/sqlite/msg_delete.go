@@ -21,6 +21,7 @@ import (
 
 	"github.com/FerretDB/FerretDB/internal/backends"
 	"github.com/FerretDB/FerretDB/internal/handlers/common"
+	"github.com/FerretDB/FerretDB/internal/handlers/common/aggregations/operators"
 	"github.com/FerretDB/FerretDB/internal/handlers/commonerrors"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/iterator"
@@ -41,6 +42,11 @@ func (h *Handler) MsgDelete(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 		return nil, lazyerrors.Error(err)
 	}
 
+	vars, err := operators.NewVariables(params.Let)
+	if err != nil {
+		return nil, err
+	}
+
 	db, err := h.b.Database(params.DB)
 	if err != nil {
 		if backends.ErrorCodeIs(err, backends.ErrorCodeDatabaseNameIsInvalid) {
@@ -65,7 +71,7 @@ func (h *Handler) MsgDelete(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 	writeErrors := types.MakeArray(0)
 
 	for i, p := range params.Deletes {
-		d, err := h.execDelete(ctx, c, &p)
+		d, err := h.execDelete(ctx, c, params.DB, params.Collection, &p, vars)
 
 		deleted += d
 
@@ -91,6 +97,8 @@ func (h *Handler) MsgDelete(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 		}
 	}
 
+	h.writes.Add(params.DB, params.Collection, int64(deleted))
+
 	res := must.NotFail(types.NewDocument(
 		"n", deleted,
 	))
@@ -109,11 +117,14 @@ func (h *Handler) MsgDelete(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 	return &reply, nil
 }
 
-// execDelete performs a single delete operation.
+// execDelete performs a single delete operation on the given collection.
+// Given variables are available for $expr aggregation expressions in the filter.
 //
 // It returns a number of deleted documents or error.
 // The error is either a (wrapped) *commonerrors.CommandError or something fatal.
-func (h *Handler) execDelete(ctx context.Context, c backends.Collection, p *common.Delete) (int32, error) {
+//
+//nolint:lll // for readability
+func (h *Handler) execDelete(ctx context.Context, c backends.Collection, dbName, cName string, p *common.Delete, vars operators.Variables) (int32, error) {
 	var qp backends.QueryParams
 	if !h.DisableFilterPushdown {
 		qp.Filter = p.Filter
@@ -125,6 +136,8 @@ func (h *Handler) execDelete(ctx context.Context, c backends.Collection, p *comm
 	}
 
 	var ids []any
+	var size int64
+
 	for {
 		var doc *types.Document
 
@@ -139,7 +152,7 @@ func (h *Handler) execDelete(ctx context.Context, c backends.Collection, p *comm
 
 		var matches bool
 
-		if matches, err = common.FilterDocument(doc, p.Filter); err != nil {
+		if matches, err = common.FilterDocument(doc, p.Filter, vars); err != nil {
 			q.Iter.Close()
 			return 0, lazyerrors.Error(err)
 		}
@@ -150,6 +163,16 @@ func (h *Handler) execDelete(ctx context.Context, c backends.Collection, p *comm
 
 		ids = append(ids, must.NotFail(doc.Get("_id")))
 
+		if h.quotasEnabled() {
+			var docSize int64
+			if docSize, err = documentSize(doc); err != nil {
+				q.Iter.Close()
+				return 0, lazyerrors.Error(err)
+			}
+
+			size += docSize
+		}
+
 		if p.Limited {
 			break
 		}
@@ -167,5 +190,7 @@ func (h *Handler) execDelete(ctx context.Context, c backends.Collection, p *comm
 		return 0, lazyerrors.Error(err)
 	}
 
+	h.quotaDeleted(dbName, cName, size, len(ids), int(d.Deleted))
+
 	return d.Deleted, nil
 }
@@ -76,7 +76,7 @@ func (h *Handler) MsgDistinct(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg
 
 	closer.Add(queryRes.Iter)
 
-	iter := common.FilterIterator(queryRes.Iter, closer, params.Filter)
+	iter := common.FilterIterator(queryRes.Iter, closer, params.Filter, nil)
 
 	distinct, err := common.FilterDistinctValues(iter, params.Key)
 	if err != nil {
@@ -75,6 +75,10 @@ func (h *Handler) MsgDrop(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, er
 
 	switch {
 	case err == nil:
+		h.forgetTempCollections(dbName, collectionName)
+		h.quotas.forget(dbName, collectionName)
+		h.writes.Forget(dbName, collectionName)
+
 		var reply wire.OpMsg
 		must.NoError(reply.SetSections(wire.OpMsgSection{
 			Documents: []*types.Document{must.NotFail(types.NewDocument(
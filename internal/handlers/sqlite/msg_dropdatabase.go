@@ -58,6 +58,9 @@ func (h *Handler) MsgDropDatabase(ctx context.Context, msg *wire.OpMsg) (*wire.O
 
 	switch {
 	case err == nil:
+		h.forgetTempCollections(dbName, "")
+		h.quotas.forget(dbName, "")
+		h.writes.Forget(dbName, "")
 		res.Set("dropped", dbName)
 	case backends.ErrorCodeIs(err, backends.ErrorCodeDatabaseNameIsInvalid):
 		// nothing?
@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/FerretDB/FerretDB/internal/backends"
 	"github.com/FerretDB/FerretDB/internal/handlers/common"
@@ -91,11 +92,22 @@ func (h *Handler) MsgDropIndexes(ctx context.Context, msg *wire.OpMsg) (*wire.Op
 		}
 	}
 
-	toDrop, dropAll, err := processDropIndexOptions(command, dbName+"."+collection, indexValue, beforeDrop.Indexes)
+	// indexes that are being built could be dropped too
+	existing := slices.Clone(beforeDrop.Indexes)
+	for _, b := range h.indexBuilds.Collection(dbName, collection) {
+		existing = append(existing, b.Indexes...)
+	}
+
+	toDrop, dropAll, err := processDropIndexOptions(command, dbName+"."+collection, indexValue, existing)
 	if err != nil {
 		return nil, err
 	}
 
+	err = h.indexBuilds.Abort(ctx, dbName, collection, toDrop, "Index build aborted due to dropIndexes command")
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
 	_, err = c.DropIndexes(ctx, &backends.DropIndexesParams{Indexes: toDrop})
 	if err != nil {
 		return nil, lazyerrors.Error(err)
@@ -150,7 +162,7 @@ func processDropIndexOptions(command, ns string, v any, existing []backends.Inde
 			matches := true
 
 			for i, key := range index.Key {
-				if key.Field != spec[i].Field || key.Descending != spec[i].Descending {
+				if key != spec[i] {
 					matches = false
 					break
 				}
@@ -0,0 +1,106 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/FerretDB/FerretDB/internal/clientconn/session"
+	"github.com/FerretDB/FerretDB/internal/handlers/common"
+	"github.com/FerretDB/FerretDB/internal/handlers/commonerrors"
+	"github.com/FerretDB/FerretDB/internal/handlers/commonparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/wire"
+)
+
+// MsgEndSessions implements handlers.Interface.
+func (h *Handler) MsgEndSessions(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	document, err := msg.Document()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	ids, err := getSessionIDs(document)
+	if err != nil {
+		return nil, err
+	}
+
+	h.Sessions.End(ids...)
+
+	var reply wire.OpMsg
+	must.NoError(reply.SetSections(wire.OpMsgSection{
+		Documents: []*types.Document{must.NotFail(types.NewDocument(
+			"ok", float64(1),
+		))},
+	}))
+
+	return &reply, nil
+}
+
+// getSessionIDs returns logical session IDs from the array of `lsid` documents
+// passed as the value of the endSessions or refreshSessions command.
+func getSessionIDs(document *types.Document) ([]uuid.UUID, error) {
+	command := document.Command()
+
+	lsids, err := common.GetRequiredParam[*types.Array](document, command)
+	if err != nil {
+		return nil, err
+	}
+
+	iter := lsids.Iterator()
+	defer iter.Close()
+
+	var ids []uuid.UUID
+
+	for {
+		i, v, err := iter.Next()
+		if err != nil {
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				return ids, nil
+			}
+
+			return nil, lazyerrors.Error(err)
+		}
+
+		if _, ok := v.(*types.Document); !ok {
+			return nil, commonerrors.NewCommandErrorMsgWithArgument(
+				commonerrors.ErrTypeMismatch,
+				fmt.Sprintf(
+					"BSON field '%s.%d' is the wrong type '%s', expected type 'object'",
+					command, i, commonparams.AliasFromType(v),
+				),
+				command,
+			)
+		}
+
+		id, ok := session.ParseID(v)
+		if !ok {
+			return nil, commonerrors.NewCommandErrorMsgWithArgument(
+				commonerrors.ErrMissingField,
+				"BSON field 'LogicalSessionFromClient.id' is missing or is not a UUID",
+				command,
+			)
+		}
+
+		ids = append(ids, id)
+	}
+}
@@ -83,35 +83,27 @@ func (h *Handler) MsgExplain(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 		qp.Filter = params.Filter
 	}
 
-	// Skip sorting if there are more than one sort parameters
-	if h.EnableSortPushdown && params.Sort.Len() == 1 {
-		var order types.SortType
+	var sort *types.Document
 
-		k := params.Sort.Keys()[0]
-		v := params.Sort.Values()[0]
-
-		order, err = common.GetSortType(k, v)
-		if err != nil {
-			return nil, err
-		}
-
-		qp.Sort = &backends.SortField{
-			Key:        k,
-			Descending: order == types.Descending,
-		}
+	if qp.Sort, sort, err = h.prepareSort(params.Sort, params.Hint); err != nil {
+		return nil, err
 	}
 
 	// Limit pushdown is not applied if:
 	//  - `filter` is set, it must fetch all documents to filter them in memory;
-	//  - `sort` is set but `EnableSortPushdown` is not set, it must fetch all documents
-	//  and sort them in memory;
+	//  - `sort` is set but `EnableSortPushdown` is not set (or sort is only partially pushed down with `$natural`),
+	//  it must fetch all documents and sort them in memory;
 	//  - `skip` is non-zero value, skip pushdown is not supported yet.
-	if params.Filter.Len() == 0 && (params.Sort.Len() == 0 || h.EnableSortPushdown) && params.Skip == 0 {
+	if params.Filter.Len() == 0 && (sort.Len() == 0 || h.EnableSortPushdown && !isNatural(qp.Sort)) && params.Skip == 0 {
 		qp.Limit = params.Limit
 	}
 
 	res, err := coll.Explain(ctx, &qp)
 	if err != nil {
+		if backends.ErrorCodeIs(err, backends.ErrorCodeNaturalOrderNotSupported) {
+			return nil, naturalOrderNotSupported(params.DB, params.Collection, document.Command())
+		}
+
 		return nil, lazyerrors.Error(err)
 	}
 
@@ -24,6 +24,7 @@ import (
 	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
 	"github.com/FerretDB/FerretDB/internal/clientconn/cursor"
 	"github.com/FerretDB/FerretDB/internal/handlers/common"
+	"github.com/FerretDB/FerretDB/internal/handlers/common/aggregations/operators"
 	"github.com/FerretDB/FerretDB/internal/handlers/commonerrors"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/iterator"
@@ -44,6 +45,11 @@ func (h *Handler) MsgFind(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, er
 		return nil, err
 	}
 
+	vars, err := operators.NewVariables(params.Let)
+	if err != nil {
+		return nil, err
+	}
+
 	username, _ := conninfo.Get(ctx).Auth()
 
 	db, err := h.b.Database(params.DB)
@@ -80,30 +86,18 @@ func (h *Handler) MsgFind(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, er
 		qp.Filter = params.Filter
 	}
 
-	// Skip sorting if there are more than one sort parameters
-	if h.EnableSortPushdown && params.Sort.Len() == 1 {
-		var order types.SortType
-
-		k := params.Sort.Keys()[0]
-		v := params.Sort.Values()[0]
+	var sort *types.Document
 
-		order, err = common.GetSortType(k, v)
-		if err != nil {
-			return nil, err
-		}
-
-		qp.Sort = &backends.SortField{
-			Key:        k,
-			Descending: order == types.Descending,
-		}
+	if qp.Sort, sort, err = h.prepareSort(params.Sort, params.Hint); err != nil {
+		return nil, err
 	}
 
 	// Limit pushdown is not applied if:
 	//  - `filter` is set, it must fetch all documents to filter them in memory;
-	//  - `sort` is set but `EnableSortPushdown` is not set, it must fetch all documents
-	//  and sort them in memory;
+	//  - `sort` is set but `EnableSortPushdown` is not set (or sort is only partially pushed down with `$natural`),
+	//  it must fetch all documents and sort them in memory;
 	//  - `skip` is non-zero value, skip pushdown is not supported yet.
-	if params.Filter.Len() == 0 && (params.Sort.Len() == 0 || h.EnableSortPushdown) && params.Skip == 0 {
+	if params.Filter.Len() == 0 && (sort.Len() == 0 || h.EnableSortPushdown && !isNatural(qp.Sort)) && params.Skip == 0 {
 		qp.Limit = params.Limit
 	}
 
@@ -120,14 +114,19 @@ func (h *Handler) MsgFind(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, er
 	queryRes, err := c.Query(ctx, qp)
 	if err != nil {
 		closer.Close()
+
+		if backends.ErrorCodeIs(err, backends.ErrorCodeNaturalOrderNotSupported) {
+			return nil, naturalOrderNotSupported(params.DB, params.Collection, document.Command())
+		}
+
 		return nil, lazyerrors.Error(err)
 	}
 
 	closer.Add(queryRes.Iter)
 
-	iter := common.FilterIterator(queryRes.Iter, closer, params.Filter)
+	iter := common.FilterIterator(queryRes.Iter, closer, params.Filter, vars)
 
-	iter, err = common.SortIterator(iter, closer, params.Sort)
+	iter, err = common.SortIterator(iter, closer, sort)
 	if err != nil {
 		closer.Close()
 
@@ -147,7 +146,7 @@ func (h *Handler) MsgFind(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, er
 
 	iter = common.LimitIterator(iter, closer, params.Limit)
 
-	iter, err = common.ProjectionIterator(iter, closer, params.Projection, params.Filter)
+	iter, err = common.ProjectionIterator(iter, closer, params.Projection, params.Filter, vars)
 	if err != nil {
 		closer.Close()
 		return nil, lazyerrors.Error(err)
@@ -199,3 +198,69 @@ func (h *Handler) MsgFind(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, er
 
 	return &reply, nil
 }
+
+// prepareSort returns the sort field for the backend query and the sort document for in-memory sorting.
+//
+// `$natural` sort key (or `$natural` hint if sort is not set) is always pushed down to the backend,
+// and only preceding sort keys are used for the (stable) in-memory sorting;
+// following keys are irrelevant as the natural order has no ties.
+// Otherwise, a single sort key is pushed down if EnableSortPushdown is set.
+func (h *Handler) prepareSort(sort *types.Document, hint any) (*backends.SortField, *types.Document, error) {
+	for i, k := range sort.Keys() {
+		if k != backends.NaturalSortKey {
+			continue
+		}
+
+		order, err := common.GetSortType(k, must.NotFail(sort.Get(k)))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		inMemory := types.MakeDocument(i)
+		for _, k := range sort.Keys()[:i] {
+			inMemory.Set(k, must.NotFail(sort.Get(k)))
+		}
+
+		return &backends.SortField{Key: k, Descending: order == types.Descending}, inMemory, nil
+	}
+
+	if hintDoc, _ := hint.(*types.Document); sort.Len() == 0 && hintDoc.Len() == 1 && hintDoc.Has(backends.NaturalSortKey) {
+		order, err := common.GetSortType(backends.NaturalSortKey, must.NotFail(hintDoc.Get(backends.NaturalSortKey)))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return &backends.SortField{Key: backends.NaturalSortKey, Descending: order == types.Descending}, sort, nil
+	}
+
+	// Skip sorting if there are more than one sort parameters
+	if !h.EnableSortPushdown || sort.Len() != 1 {
+		return nil, sort, nil
+	}
+
+	k := sort.Keys()[0]
+
+	order, err := common.GetSortType(k, sort.Values()[0])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &backends.SortField{Key: k, Descending: order == types.Descending}, sort, nil
+}
+
+// isNatural returns true if the given sort field is for the natural order.
+func isNatural(sort *backends.SortField) bool {
+	return sort != nil && sort.Key == backends.NaturalSortKey
+}
+
+// naturalOrderNotSupported returns an error for `$natural` sort or hint
+// on the collection created by an older version without record IDs.
+func naturalOrderNotSupported(dbName, cName, command string) error {
+	msg := fmt.Sprintf(
+		"$natural sort and hint are not supported for collection %s.%s created by an older version, "+
+			"use validateDBMetadata command with repair to add record IDs",
+		dbName, cName,
+	)
+
+	return commonerrors.NewCommandErrorMsgWithArgument(commonerrors.ErrNotImplemented, msg, command)
+}
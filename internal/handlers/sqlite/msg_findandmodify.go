@@ -22,6 +22,7 @@ import (
 
 	"github.com/FerretDB/FerretDB/internal/backends"
 	"github.com/FerretDB/FerretDB/internal/handlers/common"
+	"github.com/FerretDB/FerretDB/internal/handlers/common/aggregations/operators"
 	"github.com/FerretDB/FerretDB/internal/handlers/commonerrors"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/iterator"
@@ -62,6 +63,8 @@ func (h *Handler) MsgFindAndModify(ctx context.Context, msg *wire.OpMsg) (*wire.
 		return nil, lazyerrors.Error(err)
 	}
 
+	h.writes.Add(params.DB, params.Collection, int64(res.modified))
+
 	lastError := must.NotFail(types.NewDocument(
 		"n", res.modified,
 	))
@@ -98,6 +101,11 @@ func (h *Handler) MsgFindAndModify(ctx context.Context, msg *wire.OpMsg) (*wire.
 // otherwise it updates the document applying operators if any.
 // When no document is found, a document is inserted if `upsert` flag is set.
 func (h *Handler) findAndModifyDocument(ctx context.Context, params *common.FindAndModifyParams) (*findAndModifyResult, error) {
+	vars, err := operators.NewVariables(params.Let)
+	if err != nil {
+		return nil, err
+	}
+
 	db, err := h.b.Database(params.DB)
 	if err != nil {
 		// TODO https://github.com/FerretDB/FerretDB/issues/2168
@@ -120,6 +128,11 @@ func (h *Handler) findAndModifyDocument(ctx context.Context, params *common.Find
 		return nil, lazyerrors.Error(err)
 	}
 
+	q, err := h.newQuota(ctx, db, params.DB, params.Collection)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
 	cancel := func() {}
 	if params.MaxTimeMS != 0 {
 		// TODO https://github.com/FerretDB/FerretDB/issues/2168
@@ -142,7 +155,7 @@ func (h *Handler) findAndModifyDocument(ctx context.Context, params *common.Find
 
 	closer.Add(queryRes.Iter)
 
-	iter := common.FilterIterator(queryRes.Iter, closer, params.Query)
+	iter := common.FilterIterator(queryRes.Iter, closer, params.Query, vars)
 
 	iter, err = common.SortIterator(iter, closer, params.Sort)
 	if err != nil {
@@ -227,9 +240,16 @@ func (h *Handler) findAndModifyDocument(ctx context.Context, params *common.Find
 			writeErrors.Append(we.Document())
 		}
 
+		var refund func()
+		if refund, err = q.insert(doc); err != nil {
+			return nil, err
+		}
+
 		if _, err = c.InsertAll(ctx, &backends.InsertAllParams{
 			Docs: []*types.Document{doc},
 		}); err != nil {
+			refund()
+
 			if backends.ErrorCodeIs(err, backends.ErrorCodeInsertDuplicateID) {
 				// TODO https://github.com/FerretDB/FerretDB/issues/2168
 				we := &writeError{
@@ -268,6 +288,13 @@ func (h *Handler) findAndModifyDocument(ctx context.Context, params *common.Find
 			return nil, lazyerrors.Error(err)
 		}
 
+		var size int64
+		if size, err = q.size(v); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		h.quotaDeleted(params.DB, params.Collection, size, 1, int(delRes.Deleted))
+
 		return &findAndModifyResult{
 			modified: delRes.Deleted,
 			value:    v,
@@ -318,11 +345,27 @@ func (h *Handler) findAndModifyDocument(ctx context.Context, params *common.Find
 		writeErrors.Append(we.Document())
 	}
 
+	oldSize, err := q.size(v)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	refund, err := q.update(oldSize, doc)
+	if err != nil {
+		return nil, err
+	}
+
 	updateRes, err := c.UpdateAll(ctx, &backends.UpdateAllParams{Docs: []*types.Document{doc}})
 	if err != nil {
+		refund()
 		return nil, lazyerrors.Error(err)
 	}
 
+	// document was deleted concurrently
+	if updateRes.Updated == 0 {
+		refund()
+	}
+
 	value := v
 	if params.ReturnNewDocument {
 		value = doc
@@ -0,0 +1,130 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlite
+
+import (
+	"context"
+	"errors"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/handlers/common"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/wire"
+)
+
+// MsgGetClusterParameter implements HandlerInterface.
+func (h *Handler) MsgGetClusterParameter(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	document, err := msg.Document()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	common.Ignored(document, h.L, "comment")
+
+	dbName, err := common.GetRequiredParam[string](document, "$db")
+	if err != nil {
+		return nil, err
+	}
+
+	if err = common.CheckClusterParameterDatabase(document.Command(), dbName); err != nil {
+		return nil, err
+	}
+
+	names, err := common.GetClusterParameterNames(document)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := h.storedClusterParameters(ctx)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	params := types.MakeArray(len(names))
+
+	for _, name := range names {
+		if doc, ok := stored[name]; ok {
+			params.Append(doc)
+			continue
+		}
+
+		params.Append(common.DefaultClusterParameter(name))
+	}
+
+	var reply wire.OpMsg
+	must.NoError(reply.SetSections(wire.OpMsgSection{
+		Documents: []*types.Document{must.NotFail(types.NewDocument(
+			"clusterParameters", params,
+			"ok", float64(1),
+		))},
+	}))
+
+	return &reply, nil
+}
+
+// storedClusterParameters returns cluster parameters set by setClusterParameter command, indexed by name.
+func (h *Handler) storedClusterParameters(ctx context.Context) (map[string]*types.Document, error) {
+	c, err := h.clusterParametersCollection()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	queryRes, err := c.Query(ctx, new(backends.QueryParams))
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	defer queryRes.Iter.Close()
+
+	res := map[string]*types.Document{}
+
+	for {
+		_, doc, err := queryRes.Iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		name, ok := must.NotFail(doc.Get("_id")).(string)
+		if !ok {
+			continue
+		}
+
+		res[name] = doc
+	}
+
+	return res, nil
+}
+
+// clusterParametersCollection returns backend collection where cluster parameters are stored.
+func (h *Handler) clusterParametersCollection() (backends.Collection, error) {
+	db, err := h.b.Database(common.ClusterParametersDatabase)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	c, err := db.Collection(common.ClusterParametersCollection)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return c, nil
+}
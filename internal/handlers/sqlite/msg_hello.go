@@ -18,6 +18,8 @@ import (
 	"context"
 	"time"
 
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/internal/clientconn/session"
 	"github.com/FerretDB/FerretDB/internal/handlers/common"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
@@ -44,7 +46,8 @@ func (h *Handler) MsgHello(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, e
 			"maxMessageSizeBytes", int32(wire.MaxMsgLen),
 			"maxWriteBatchSize", int32(100000),
 			"localTime", time.Now(),
-			"connectionId", int32(42),
+			"logicalSessionTimeoutMinutes", int32(session.DefaultTimeout/time.Minute),
+			"connectionId", conninfo.Get(ctx).ID,
 			"minWireVersion", common.MinWireVersion,
 			"maxWireVersion", common.MaxWireVersion,
 			"readOnly", false,
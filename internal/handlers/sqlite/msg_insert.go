@@ -82,6 +82,11 @@ func (h *Handler) MsgInsert(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 		return nil, lazyerrors.Error(err)
 	}
 
+	q, err := h.newQuota(ctx, db, params.DB, params.Collection)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
 	docsIter := params.Docs.Iterator()
 	defer docsIter.Close()
 
@@ -137,6 +142,22 @@ func (h *Handler) MsgInsert(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 			continue
 		}
 
+		refund, err := q.insert(doc)
+		if err != nil {
+			we := quotaWriteError(err, int32(i))
+			if we == nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			writeErrors.Append(we.Document())
+
+			if params.Ordered {
+				break
+			}
+
+			continue
+		}
+
 		// use bigger batches on a happy path, downgrade to one-document batches on error
 		// TODO https://github.com/FerretDB/FerretDB/issues/3271
 
@@ -144,6 +165,8 @@ func (h *Handler) MsgInsert(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 			Docs: []*types.Document{doc},
 		})
 		if err != nil {
+			refund()
+
 			if backends.ErrorCodeIs(err, backends.ErrorCodeInsertDuplicateID) {
 				we := &writeError{
 					index:  int32(i),
@@ -165,6 +188,8 @@ func (h *Handler) MsgInsert(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 		inserted++
 	}
 
+	h.writes.Add(params.DB, params.Collection, int64(inserted))
+
 	res := must.NotFail(types.NewDocument(
 		"n", inserted,
 	))
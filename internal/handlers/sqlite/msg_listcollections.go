@@ -78,7 +78,7 @@ func (h *Handler) MsgListCollections(ctx context.Context, msg *wire.OpMsg) (*wir
 			"type", "collection",
 		))
 
-		matches, err := common.FilterDocument(d, filter)
+		matches, err := common.FilterDocument(d, filter, nil)
 		if err != nil {
 			return nil, lazyerrors.Error(err)
 		}
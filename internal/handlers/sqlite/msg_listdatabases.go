@@ -87,7 +87,7 @@ func (h *Handler) MsgListDatabases(ctx context.Context, msg *wire.OpMsg) (*wire.
 
 		totalSize += stats.SizeTotal
 
-		matches, err := common.FilterDocument(d, filter)
+		matches, err := common.FilterDocument(d, filter, nil)
 		if err != nil {
 			return nil, lazyerrors.Error(err)
 		}
@@ -87,6 +87,11 @@ func (h *Handler) MsgListIndexes(ctx context.Context, msg *wire.OpMsg) (*wire.Op
 				order = -1
 			}
 
+			if key.Expression != "" {
+				indexKey.Set(key.Field, must.NotFail(types.NewDocument(string(key.Expression), order)))
+				continue
+			}
+
 			indexKey.Set(key.Field, order)
 		}
 
@@ -101,6 +106,10 @@ func (h *Handler) MsgListIndexes(ctx context.Context, msg *wire.OpMsg) (*wire.Op
 			indexDoc.Set("unique", index.Unique)
 		}
 
+		if index.ExpireAfterSeconds != nil {
+			indexDoc.Set("expireAfterSeconds", *index.ExpireAfterSeconds)
+		}
+
 		firstBatch.Append(indexDoc)
 	}
 
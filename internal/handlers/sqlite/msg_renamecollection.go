@@ -123,7 +123,11 @@ func (h *Handler) MsgRenameCollection(ctx context.Context, msg *wire.OpMsg) (*wi
 
 	switch {
 	case err == nil:
-	// do nothing
+		// renamed collection is no longer temporary
+		h.forgetTempCollections(oldDBName, oldCName)
+		h.quotas.forget(oldDBName, oldCName)
+		h.quotas.forget(oldDBName, newCName)
+		h.writes.Forget(oldDBName, oldCName)
 	case backends.ErrorCodeIs(err, backends.ErrorCodeCollectionAlreadyExists):
 		return nil, commonerrors.NewCommandErrorMsgWithArgument(
 			commonerrors.ErrNamespaceExists,
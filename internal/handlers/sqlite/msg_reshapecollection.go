@@ -0,0 +1,584 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/handlers/common"
+	"github.com/FerretDB/FerretDB/internal/handlers/commonerrors"
+	"github.com/FerretDB/FerretDB/internal/handlers/commonparams"
+	"github.com/FerretDB/FerretDB/internal/handlers/maintenance"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/wire"
+)
+
+const (
+	// reshapeDefaultBatchSize is the default number of documents copied at once by reshapeCollection.
+	reshapeDefaultBatchSize = 1000
+
+	// reshapeMaxRounds is the maximum number of catch-up rounds done while writes are allowed.
+	reshapeMaxRounds = 5
+)
+
+// MsgReshapeCollection implements handlers.Interface.
+//
+// It copies all documents and indexes of the collection into a new collection in batches
+// while the collection stays available for reads and writes.
+// Documents modified during the copy are copied again in catch-up rounds.
+// The final catch-up round and the swap of collections are done while writes to that collection are blocked.
+//
+// The only layout change is a one-time reordering: documents are copied in the natural order,
+// or in the order of the _id index if clusteredIndex is given;
+// in the latter case, the natural order of the new collection follows _id index order
+// (until documents are inserted or deleted). Shard keys and other layouts are not supported.
+//
+// Only writes done by this FerretDB instance are captured; writes done by other instances
+// using the same backend during reshaping are lost.
+func (h *Handler) MsgReshapeCollection(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	document, err := msg.Document()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	unimplementedFields := []string{
+		"key",
+		"changeStreamPreAndPostImages",
+	}
+	if err = common.Unimplemented(document, unimplementedFields...); err != nil {
+		return nil, err
+	}
+
+	ignoredFields := []string{
+		"writeConcern",
+		"comment",
+	}
+	common.Ignored(document, h.L, ignoredFields...)
+
+	command := document.Command()
+
+	namespace, err := common.GetRequiredParam[string](document, command)
+	if err != nil {
+		return nil, err
+	}
+
+	dbName, cName, err := commonparams.SplitNamespace(namespace, command)
+	if err != nil {
+		return nil, err
+	}
+
+	clustered, err := getReshapeClusteredIndex(document, command)
+	if err != nil {
+		return nil, err
+	}
+
+	batchSize := int64(reshapeDefaultBatchSize)
+
+	if v, _ := document.Get("batchSize"); v != nil {
+		if batchSize, err = commonparams.GetWholeNumberParam(v); err != nil || batchSize <= 0 {
+			return nil, commonerrors.NewCommandErrorMsgWithArgument(
+				commonerrors.ErrBadValue,
+				fmt.Sprintf("batchSize must be a positive integer, found %s", types.FormatAnyValue(v)),
+				command,
+			)
+		}
+	}
+
+	// use not wrapped backend, so our own writes are not tracked and do not take writes locks
+	db, err := h.reshapes.b.Database(dbName)
+	if err != nil {
+		if backends.ErrorCodeIs(err, backends.ErrorCodeDatabaseNameIsInvalid) {
+			msg := fmt.Sprintf("Invalid namespace specified '%s'", namespace)
+			return nil, commonerrors.NewCommandErrorMsgWithArgument(commonerrors.ErrInvalidNamespace, msg, command)
+		}
+
+		return nil, lazyerrors.Error(err)
+	}
+
+	source, err := db.Collection(cName)
+	if err != nil {
+		if backends.ErrorCodeIs(err, backends.ErrorCodeCollectionNameIsInvalid) {
+			msg := fmt.Sprintf("Invalid namespace specified '%s'", namespace)
+			return nil, commonerrors.NewCommandErrorMsgWithArgument(commonerrors.ErrInvalidNamespace, msg, command)
+		}
+
+		return nil, lazyerrors.Error(err)
+	}
+
+	ns := maintenance.Namespace{DB: dbName, Collection: cName}
+	if !h.reshapes.start(ns) {
+		return nil, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrConflictingOperationInProgress,
+			fmt.Sprintf("Collection %s is already being reshaped", namespace),
+			command,
+		)
+	}
+
+	defer h.reshapes.stop(ns)
+
+	// check existence only after capturing has started, so drop is not missed
+	info, err := reshapeCollectionInfo(ctx, db, cName)
+	if err != nil {
+		return nil, err
+	}
+
+	var indexes *backends.ListIndexesResult
+	if info != nil {
+		indexes, err = source.ListIndexes(ctx, nil)
+	}
+
+	if info == nil || backends.ErrorCodeIs(err, backends.ErrorCodeDatabaseDoesNotExist, backends.ErrorCodeCollectionDoesNotExist) {
+		return nil, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrNamespaceNotFound,
+			fmt.Sprintf("Collection %s does not exist", namespace),
+			command,
+		)
+	}
+
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	// the swap would make the collection permanent
+	if info.Temp {
+		return nil, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrIllegalOperation,
+			fmt.Sprintf("Temporary collection %s can't be reshaped", namespace),
+			command,
+		)
+	}
+
+	tmpName := "tmp.reshape." + uuid.NewString()
+
+	err = db.CreateCollection(ctx, &backends.CreateCollectionParams{
+		Name:            tmpName,
+		CappedSize:      info.CappedSize,
+		CappedDocuments: info.CappedDocuments,
+	})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	var swapped bool
+
+	defer func() {
+		if swapped {
+			return
+		}
+
+		// client's context could be already canceled
+		dropCtx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		if err := db.DropCollection(dropCtx, &backends.DropCollectionParams{Name: tmpName}); err != nil {
+			h.L.Warn("Failed to drop temporary collection", zap.String("collection", tmpName), zap.Error(err))
+		}
+	}()
+
+	target, err := db.Collection(tmpName)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	var specs []backends.IndexInfo
+
+	for _, index := range indexes.Indexes {
+		if index.Name != backends.DefaultIndexName {
+			specs = append(specs, index)
+		}
+	}
+
+	if len(specs) > 0 {
+		if _, err = target.CreateIndexes(ctx, &backends.CreateIndexesParams{Indexes: specs}); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+	}
+
+	sort := &backends.SortField{Key: backends.NaturalSortKey}
+	if clustered {
+		sort.Key = backends.DefaultIndexSortKey
+	}
+
+	copied, err := reshapeCopy(ctx, source, target, sort, batchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	var caughtUp int64
+
+	for round := 0; round < reshapeMaxRounds; round++ {
+		ids, aborted := h.reshapes.take(ns)
+		if aborted != "" {
+			return nil, reshapeAborted(namespace, aborted)
+		}
+
+		var n int64
+		if n, err = reshapeCatchUp(ctx, source, target, ids, batchSize); err != nil {
+			return nil, err
+		}
+
+		caughtUp += n
+
+		// the rest is small enough to be done while writes are blocked
+		if int64(len(ids)) <= batchSize {
+			break
+		}
+	}
+
+	// block writes to that collection only
+	defer h.reshapes.lock(ns)()
+
+	ids, aborted := h.reshapes.take(ns)
+	if aborted != "" {
+		return nil, reshapeAborted(namespace, aborted)
+	}
+
+	n, err := reshapeCatchUp(ctx, source, target, ids, batchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	caughtUp += n
+
+	// the source collection is dropped in the same transaction
+	err = db.RenameCollection(ctx, &backends.RenameCollectionParams{
+		OldName:    tmpName,
+		NewName:    cName,
+		DropTarget: true,
+	})
+	if err != nil {
+		return nil, lazyerrors.Errorf("failed to rename %s.%s to %s: %w", dbName, tmpName, cName, err)
+	}
+
+	swapped = true
+
+	h.L.Info(
+		"Collection reshaped",
+		zap.String("db", dbName), zap.String("collection", cName),
+		zap.Int64("copied", copied), zap.Int64("caught_up", caughtUp),
+	)
+
+	var reply wire.OpMsg
+	must.NoError(reply.SetSections(wire.OpMsgSection{
+		Documents: []*types.Document{must.NotFail(types.NewDocument(
+			"copied", copied,
+			"caughtUp", caughtUp,
+			"ok", float64(1),
+		))},
+	}))
+
+	return &reply, nil
+}
+
+// reshapeAborted returns an error for reshaping that can't be completed for the given reason.
+func reshapeAborted(namespace, reason string) error {
+	return commonerrors.NewCommandErrorMsgWithArgument(
+		commonerrors.ErrOperationFailed,
+		fmt.Sprintf("Reshaping of collection %s was aborted: %s", namespace, reason),
+		"reshapeCollection",
+	)
+}
+
+// getReshapeClusteredIndex returns true if the document has a valid clusteredIndex specification.
+//
+// The only supported specification is the one of the default index: `{key: {_id: 1}, unique: true}`
+// with an optional name.
+func getReshapeClusteredIndex(document *types.Document, command string) (bool, error) {
+	spec, err := common.GetOptionalParam[*types.Document](document, "clusteredIndex", nil)
+	if err != nil || spec == nil {
+		return false, err
+	}
+
+	var key *types.Document
+	var unique bool
+
+	iter := spec.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return false, lazyerrors.Error(err)
+		}
+
+		switch k {
+		case "key":
+			key, _ = v.(*types.Document)
+		case "unique":
+			unique, _ = v.(bool)
+		case "name":
+			if _, ok := v.(string); !ok {
+				return false, commonerrors.NewCommandErrorMsgWithArgument(
+					commonerrors.ErrTypeMismatch,
+					fmt.Sprintf(
+						"BSON field 'clusteredIndex.name' is the wrong type '%s', expected type 'string'",
+						commonparams.AliasFromType(v),
+					),
+					command,
+				)
+			}
+		default:
+			return false, commonerrors.NewCommandErrorMsgWithArgument(
+				commonerrors.ErrBadValue,
+				fmt.Sprintf("BSON field 'clusteredIndex.%s' is an unknown field.", k),
+				command,
+			)
+		}
+	}
+
+	if key == nil || key.Len() != 1 || !key.Has("_id") || !unique {
+		return false, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrBadValue,
+			"The clusteredIndex must be {key: {_id: 1}, unique: true}",
+			command,
+		)
+	}
+
+	if order, err := commonparams.GetWholeNumberParam(must.NotFail(key.Get("_id"))); err != nil || order != 1 {
+		return false, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrBadValue,
+			"The clusteredIndex must be {key: {_id: 1}, unique: true}",
+			command,
+		)
+	}
+
+	return true, nil
+}
+
+// reshapeCollectionInfo returns information about the given collection, or nil if it does not exist.
+func reshapeCollectionInfo(ctx context.Context, db backends.Database, cName string) (*backends.CollectionInfo, error) {
+	list, err := db.ListCollections(ctx, nil)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	for _, c := range list.Collections {
+		if c.Name == cName {
+			return &c, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// reshapeCopy copies all documents from source to target collection in batches in the given order
+// and returns the number of copied documents.
+//
+// Documents are inserted in that order, so it becomes the natural order of the target collection.
+//
+//nolint:lll // for readability
+func reshapeCopy(ctx context.Context, source, target backends.Collection, sort *backends.SortField, batchSize int64) (int64, error) {
+	q, err := source.Query(ctx, &backends.QueryParams{Sort: sort})
+
+	// collections created by older versions are copied in the backend's order
+	if backends.ErrorCodeIs(err, backends.ErrorCodeNaturalOrderNotSupported) {
+		q, err = source.Query(ctx, new(backends.QueryParams))
+	}
+
+	if err != nil {
+		return 0, lazyerrors.Error(err)
+	}
+
+	defer q.Iter.Close()
+
+	var copied int64
+	batch := make([]*types.Document, 0, batchSize)
+
+	for {
+		_, doc, err := q.Iter.Next()
+		if err != nil && !errors.Is(err, iterator.ErrIteratorDone) {
+			return copied, lazyerrors.Error(err)
+		}
+
+		if doc != nil {
+			batch = append(batch, doc)
+		}
+
+		if len(batch) > 0 && (doc == nil || int64(len(batch)) == batchSize) {
+			if _, err := target.InsertAll(ctx, &backends.InsertAllParams{Docs: batch}); err != nil {
+				return copied, lazyerrors.Error(err)
+			}
+
+			copied += int64(len(batch))
+			batch = batch[:0]
+		}
+
+		if doc == nil {
+			return copied, nil
+		}
+	}
+}
+
+// reshapeCatchUp copies documents with the given _id values from source to target collection again.
+// It returns the number of distinct _id values.
+//
+// Previous copies are updated in place, so the natural order of the target collection is kept.
+// Deleted documents are deleted first and new documents are inserted last,
+// so intermediate states do not violate unique indexes.
+func reshapeCatchUp(ctx context.Context, source, target backends.Collection, ids []any, batchSize int64) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	seen := make(map[string]struct{}, len(ids))
+
+	var deleted, updated, inserted []any
+
+	for _, id := range ids {
+		// BSON encoding is used, so values of different types are never equal
+		k := string(must.NotFail(wire.MarshalDocument(must.NotFail(types.NewDocument("_id", id)))))
+		if _, ok := seen[k]; ok {
+			continue
+		}
+
+		seen[k] = struct{}{}
+
+		doc, err := reshapeFindByID(ctx, source, id)
+		if err != nil {
+			return 0, err
+		}
+
+		if doc == nil {
+			deleted = append(deleted, id)
+			continue
+		}
+
+		if doc, err = reshapeFindByID(ctx, target, id); err != nil {
+			return 0, err
+		}
+
+		if doc == nil {
+			inserted = append(inserted, id)
+		} else {
+			updated = append(updated, id)
+		}
+	}
+
+	for _, batch := range reshapeBatches(deleted, batchSize) {
+		if _, err := target.DeleteAll(ctx, &backends.DeleteAllParams{IDs: batch}); err != nil {
+			return 0, lazyerrors.Error(err)
+		}
+	}
+
+	for _, batch := range reshapeBatches(updated, batchSize) {
+		docs, err := reshapeFindAllByID(ctx, source, batch)
+		if err != nil {
+			return 0, err
+		}
+
+		if _, err = target.UpdateAll(ctx, &backends.UpdateAllParams{Docs: docs}); err == nil {
+			continue
+		}
+
+		// for example, documents exchanged values of an unique index;
+		// replace them, losing their natural order
+		if _, err = target.DeleteAll(ctx, &backends.DeleteAllParams{IDs: batch}); err != nil {
+			return 0, lazyerrors.Error(err)
+		}
+
+		if _, err = target.InsertAll(ctx, &backends.InsertAllParams{Docs: docs}); err != nil {
+			return 0, lazyerrors.Error(err)
+		}
+	}
+
+	for _, batch := range reshapeBatches(inserted, batchSize) {
+		docs, err := reshapeFindAllByID(ctx, source, batch)
+		if err != nil {
+			return 0, err
+		}
+
+		if _, err = target.InsertAll(ctx, &backends.InsertAllParams{Docs: docs}); err != nil {
+			return 0, lazyerrors.Error(err)
+		}
+	}
+
+	return int64(len(seen)), nil
+}
+
+// reshapeBatches splits the given _id values into batches of the given size.
+func reshapeBatches(ids []any, batchSize int64) [][]any {
+	var res [][]any
+
+	for len(ids) > 0 {
+		n := min(int64(len(ids)), batchSize)
+		res = append(res, ids[:n])
+		ids = ids[n:]
+	}
+
+	return res
+}
+
+// reshapeFindAllByID returns existing documents with the given _id values.
+//
+// Documents deleted since the given _id values were collected are skipped;
+// their deletions are recorded and caught up later.
+func reshapeFindAllByID(ctx context.Context, c backends.Collection, ids []any) ([]*types.Document, error) {
+	res := make([]*types.Document, 0, len(ids))
+
+	for _, id := range ids {
+		doc, err := reshapeFindByID(ctx, c, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if doc != nil {
+			res = append(res, doc)
+		}
+	}
+
+	return res, nil
+}
+
+// reshapeFindByID returns the document with the given _id value, or nil if it does not exist.
+func reshapeFindByID(ctx context.Context, c backends.Collection, id any) (*types.Document, error) {
+	// filter pushdown is an optimization; backend may return other documents too
+	q, err := c.Query(ctx, &backends.QueryParams{
+		Filter: must.NotFail(types.NewDocument("_id", id)),
+	})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	defer q.Iter.Close()
+
+	for {
+		_, doc, err := q.Iter.Next()
+		if err != nil {
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				return nil, nil
+			}
+
+			return nil, lazyerrors.Error(err)
+		}
+
+		if types.Identical(must.NotFail(doc.Get("_id")), id) {
+			return doc, nil
+		}
+	}
+}
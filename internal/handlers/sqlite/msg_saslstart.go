@@ -17,7 +17,12 @@ package sqlite
 import (
 	"context"
 
+	"go.uber.org/zap"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
 	"github.com/FerretDB/FerretDB/internal/handlers/common"
+	"github.com/FerretDB/FerretDB/internal/handlers/commonerrors"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
 	"github.com/FerretDB/FerretDB/internal/util/must"
@@ -46,6 +51,11 @@ func (h *Handler) MsgSASLStart(ctx context.Context, msg *wire.OpMsg) (*wire.OpMs
 		return nil, lazyerrors.Error(err)
 	}
 
+	if err = h.verifyCredentials(ctx); err != nil {
+		conninfo.Get(ctx).SetAuth("", "")
+		return nil, err
+	}
+
 	var emptyPayload types.Binary
 	var reply wire.OpMsg
 	must.NoError(reply.SetSections(wire.OpMsgSection{
@@ -59,3 +69,41 @@ func (h *Handler) MsgSASLStart(ctx context.Context, msg *wire.OpMsg) (*wire.OpMs
 
 	return &reply, nil
 }
+
+// verifyCredentials checks credentials stored in the connection info by the backend
+// and marks them as verified on success.
+//
+// Only PostgreSQL backend checks credentials; for other backends, they stay unverified.
+func (h *Handler) verifyCredentials(ctx context.Context) error {
+	if h.Backend != "postgresql" {
+		return nil
+	}
+
+	connInfo := conninfo.Get(ctx)
+
+	// without username, the backend would use credentials from the PostgreSQL URL
+	if username, _ := connInfo.Auth(); username == "" {
+		return commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrAuthenticationFailed,
+			"Username is required.\n"+
+				"See https://docs.ferretdb.io/security/authentication/ for more details.",
+			"payload",
+		)
+	}
+
+	// listing databases connects to PostgreSQL with the given credentials
+	if _, err := h.b.ListDatabases(ctx, new(backends.ListDatabasesParams)); err != nil {
+		h.L.Warn("Failed to verify credentials", zap.Error(err))
+
+		return commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrAuthenticationFailed,
+			"FerretDB failed to authenticate you in PostgreSQL.\n"+
+				"See https://docs.ferretdb.io/security/authentication/ for more details.",
+			"payload",
+		)
+	}
+
+	connInfo.SetVerified()
+
+	return nil
+}
@@ -47,6 +47,33 @@ func (h *Handler) MsgServerStatus(ctx context.Context, msg *wire.OpMsg) (*wire.O
 		"internalViews", int32(0),
 	)))
 
+	maintenance := types.MakeDocument(0)
+
+	for _, t := range h.maintenance.Status() {
+		task := must.NotFail(types.NewDocument(
+			"enabled", t.Interval > 0,
+			"intervalSecs", int64(t.Interval.Seconds()),
+			"runs", t.Runs,
+			"failures", t.Failures,
+			"lastDurationMillis", t.LastDuration.Milliseconds(),
+			"lastProcessed", t.LastProcessed,
+			"totalProcessed", t.TotalProcessed,
+		))
+
+		if !t.LastStarted.IsZero() {
+			task.Set("lastStarted", t.LastStarted)
+		}
+
+		// error details are logged, but not exposed to clients
+		if t.LastError != nil {
+			task.Set("lastError", "Maintenance task failed, see FerretDB logs for details")
+		}
+
+		maintenance.Set(t.Name, task)
+	}
+
+	res.Set("maintenance", maintenance)
+
 	var reply wire.OpMsg
 	must.NoError(reply.SetSections(wire.OpMsgSection{
 		Documents: []*types.Document{res},
@@ -21,6 +21,7 @@ import (
 
 	"github.com/FerretDB/FerretDB/internal/backends"
 	"github.com/FerretDB/FerretDB/internal/handlers/common"
+	"github.com/FerretDB/FerretDB/internal/handlers/common/aggregations/operators"
 	"github.com/FerretDB/FerretDB/internal/handlers/commonerrors"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/iterator"
@@ -46,7 +47,7 @@ func (h *Handler) MsgUpdate(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 
 	var we *writeError
 
-	matched, modified, upserted, err := h.updateDocument(ctx, params)
+	matched, modified, upserted, failed, err := h.updateDocument(ctx, params)
 	if err != nil {
 		switch {
 		case backends.ErrorCodeIs(err, backends.ErrorCodeInsertDuplicateID):
@@ -58,6 +59,10 @@ func (h *Handler) MsgUpdate(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 			}
 
 		default:
+			if we = quotaWriteError(err, failed); we != nil {
+				break
+			}
+
 			if we, err = handleValidationError(err); err != nil {
 				return nil, lazyerrors.Error(err)
 			}
@@ -88,18 +93,32 @@ func (h *Handler) MsgUpdate(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 }
 
 // updateDocument iterate through all documents in collection and update them.
-func (h *Handler) updateDocument(ctx context.Context, params *common.UpdateParams) (int32, int32, *types.Array, error) {
-	var matched, modified int32
-	var upserted types.Array
+//
+// On error, it returns the numbers of documents matched and modified, and upserted documents
+// by update statements before the failed one, and the index of the failed update statement.
+//
+//nolint:lll // for readability
+func (h *Handler) updateDocument(ctx context.Context, params *common.UpdateParams) (matched, modified int32, upserted *types.Array, failed int32, err error) {
+	upserted = types.MakeArray(0)
+
+	// count writes done before the failed update statement too
+	defer func() {
+		h.writes.Add(params.DB, params.Collection, int64(modified)+int64(upserted.Len()))
+	}()
+
+	vars, err := operators.NewVariables(params.Let)
+	if err != nil {
+		return 0, 0, upserted, 0, err
+	}
 
 	db, err := h.b.Database(params.DB)
 	if err != nil {
 		if backends.ErrorCodeIs(err, backends.ErrorCodeDatabaseNameIsInvalid) {
 			msg := fmt.Sprintf("Invalid namespace specified '%s.%s'", params.DB, params.Collection)
-			return 0, 0, nil, commonerrors.NewCommandErrorMsgWithArgument(commonerrors.ErrInvalidNamespace, msg, "update")
+			return 0, 0, upserted, 0, commonerrors.NewCommandErrorMsgWithArgument(commonerrors.ErrInvalidNamespace, msg, "update")
 		}
 
-		return 0, 0, nil, lazyerrors.Error(err)
+		return 0, 0, upserted, 0, lazyerrors.Error(err)
 	}
 
 	err = db.CreateCollection(ctx, &backends.CreateCollectionParams{Name: params.Collection})
@@ -111,20 +130,26 @@ func (h *Handler) updateDocument(ctx context.Context, params *common.UpdateParam
 		// nothing
 	case backends.ErrorCodeIs(err, backends.ErrorCodeCollectionNameIsInvalid):
 		msg := fmt.Sprintf("Invalid collection name: %s", params.Collection)
-		return 0, 0, nil, commonerrors.NewCommandErrorMsgWithArgument(commonerrors.ErrInvalidNamespace, msg, "insert")
+		return 0, 0, upserted, 0, commonerrors.NewCommandErrorMsgWithArgument(commonerrors.ErrInvalidNamespace, msg, "insert")
 	default:
-		return 0, 0, nil, lazyerrors.Error(err)
+		return 0, 0, upserted, 0, lazyerrors.Error(err)
 	}
 
-	for _, u := range params.Updates {
+	q, err := h.newQuota(ctx, db, params.DB, params.Collection)
+	if err != nil {
+		return 0, 0, upserted, 0, lazyerrors.Error(err)
+	}
+
+	for i, u := range params.Updates {
 		c, err := db.Collection(params.Collection)
 		if err != nil {
 			if backends.ErrorCodeIs(err, backends.ErrorCodeCollectionNameIsInvalid) {
 				msg := fmt.Sprintf("Invalid collection name: %s", params.Collection)
-				return 0, 0, nil, commonerrors.NewCommandErrorMsgWithArgument(commonerrors.ErrInvalidNamespace, msg, "insert")
+				err = commonerrors.NewCommandErrorMsgWithArgument(commonerrors.ErrInvalidNamespace, msg, "insert")
+				return matched, modified, upserted, int32(i), err
 			}
 
-			return 0, 0, nil, lazyerrors.Error(err)
+			return matched, modified, upserted, int32(i), lazyerrors.Error(err)
 		}
 
 		var qp backends.QueryParams
@@ -134,7 +159,7 @@ func (h *Handler) updateDocument(ctx context.Context, params *common.UpdateParam
 
 		res, err := c.Query(ctx, &qp)
 		if err != nil {
-			return 0, 0, nil, lazyerrors.Error(err)
+			return matched, modified, upserted, int32(i), lazyerrors.Error(err)
 		}
 
 		var resDocs []*types.Document
@@ -150,14 +175,14 @@ func (h *Handler) updateDocument(ctx context.Context, params *common.UpdateParam
 					break
 				}
 
-				return 0, 0, nil, lazyerrors.Error(err)
+				return matched, modified, upserted, int32(i), lazyerrors.Error(err)
 			}
 
 			var matches bool
 
-			matches, err = common.FilterDocument(doc, u.Filter)
+			matches, err = common.FilterDocument(doc, u.Filter, vars)
 			if err != nil {
-				return 0, 0, nil, lazyerrors.Error(err)
+				return matched, modified, upserted, int32(i), lazyerrors.Error(err)
 			}
 
 			if !matches {
@@ -178,7 +203,7 @@ func (h *Handler) updateDocument(ctx context.Context, params *common.UpdateParam
 			// TODO https://github.com/FerretDB/FerretDB/issues/3040
 			hasQueryOperators, err := common.HasQueryOperator(u.Filter)
 			if err != nil {
-				return 0, 0, nil, lazyerrors.Error(err)
+				return matched, modified, upserted, int32(i), lazyerrors.Error(err)
 			}
 
 			var doc *types.Document
@@ -190,13 +215,13 @@ func (h *Handler) updateDocument(ctx context.Context, params *common.UpdateParam
 
 			hasUpdateOperators, err := common.HasSupportedUpdateModifiers("update", u.Update)
 			if err != nil {
-				return 0, 0, nil, err
+				return matched, modified, upserted, int32(i), err
 			}
 
 			if hasUpdateOperators {
 				// TODO https://github.com/FerretDB/FerretDB/issues/3044
 				if _, err = common.UpdateDocument("update", doc, u.Update); err != nil {
-					return 0, 0, nil, err
+					return matched, modified, upserted, int32(i), err
 				}
 			} else {
 				doc = u.Update
@@ -205,14 +230,15 @@ func (h *Handler) updateDocument(ctx context.Context, params *common.UpdateParam
 			if !doc.Has("_id") {
 				doc.Set("_id", types.NewObjectID())
 			}
-			upserted.Append(must.NotFail(types.NewDocument(
-				"index", int32(upserted.Len()),
-				"_id", must.NotFail(doc.Get("_id")),
-			)))
 
 			// TODO https://github.com/FerretDB/FerretDB/issues/3454
 			if err = doc.ValidateData(); err != nil {
-				return 0, 0, nil, err
+				return matched, modified, upserted, int32(i), err
+			}
+
+			refund, err := q.insert(doc)
+			if err != nil {
+				return matched, modified, upserted, int32(i), err
 			}
 
 			// TODO https://github.com/FerretDB/FerretDB/issues/2612
@@ -221,9 +247,15 @@ func (h *Handler) updateDocument(ctx context.Context, params *common.UpdateParam
 				Docs: []*types.Document{doc},
 			})
 			if err != nil {
-				return 0, 0, nil, err
+				refund()
+				return matched, modified, upserted, int32(i), err
 			}
 
+			upserted.Append(must.NotFail(types.NewDocument(
+				"index", int32(upserted.Len()),
+				"_id", must.NotFail(doc.Get("_id")),
+			)))
+
 			matched++
 
 			continue
@@ -233,31 +265,47 @@ func (h *Handler) updateDocument(ctx context.Context, params *common.UpdateParam
 			resDocs = resDocs[:1]
 		}
 
-		matched += int32(len(resDocs))
-
 		for _, doc := range resDocs {
+			oldSize, err := q.size(doc)
+			if err != nil {
+				return matched, modified, upserted, int32(i), lazyerrors.Error(err)
+			}
+
 			changed, err := common.UpdateDocument("update", doc, u.Update)
 			if err != nil {
-				return 0, 0, nil, lazyerrors.Error(err)
+				return matched, modified, upserted, int32(i), lazyerrors.Error(err)
 			}
 
 			if !changed {
+				matched++
 				continue
 			}
 
 			// TODO https://github.com/FerretDB/FerretDB/issues/3454
 			if err = doc.ValidateData(); err != nil {
-				return 0, 0, nil, err
+				return matched, modified, upserted, int32(i), err
+			}
+
+			refund, err := q.update(oldSize, doc)
+			if err != nil {
+				return matched, modified, upserted, int32(i), err
 			}
 
 			updateRes, err := c.UpdateAll(ctx, &backends.UpdateAllParams{Docs: []*types.Document{doc}})
 			if err != nil {
-				return 0, 0, nil, lazyerrors.Error(err)
+				refund()
+				return matched, modified, upserted, int32(i), lazyerrors.Error(err)
+			}
+
+			// document was deleted concurrently
+			if updateRes.Updated == 0 {
+				refund()
 			}
 
+			matched++
 			modified += int32(updateRes.Updated)
 		}
 	}
 
-	return matched, modified, &upserted, nil
+	return matched, modified, upserted, 0, nil
 }
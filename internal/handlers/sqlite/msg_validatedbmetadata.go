@@ -0,0 +1,176 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/handlers/common"
+	"github.com/FerretDB/FerretDB/internal/handlers/commonerrors"
+	"github.com/FerretDB/FerretDB/internal/handlers/commonparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/wire"
+)
+
+// MsgValidateDBMetadata implements HandlerInterface.
+//
+// Unlike MongoDB, it cross-checks FerretDB metadata of collections and indexes
+// against tables and indexes that actually exist in the backend.
+// Found problems are repaired if `repair` is true, unless `dryRun` is also true.
+func (h *Handler) MsgValidateDBMetadata(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	document, err := msg.Document()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	// there are no API version specific restrictions on metadata
+	common.Ignored(document, h.L, "apiParameters")
+
+	command := document.Command()
+
+	dbName, err := common.GetOptionalParam(document, "db", "")
+	if err != nil {
+		return nil, err
+	}
+
+	cName, err := common.GetOptionalParam(document, "collection", "")
+	if err != nil {
+		return nil, err
+	}
+
+	var repair, dryRun bool
+
+	if v, _ := document.Get("repair"); v != nil {
+		if repair, err = commonparams.GetBoolOptionalParam("repair", v); err != nil {
+			return nil, err
+		}
+	}
+
+	if v, _ := document.Get("dryRun"); v != nil {
+		if dryRun, err = commonparams.GetBoolOptionalParam("dryRun", v); err != nil {
+			return nil, err
+		}
+	}
+
+	// repairs are done for the whole database
+	if repair && !dryRun && cName != "" {
+		return nil, commonerrors.NewCommandErrorMsgWithArgument(
+			commonerrors.ErrBadValue,
+			"repair can't be used with collection, use dryRun to see repairs for the collection",
+			command,
+		)
+	}
+
+	dbNames := []string{dbName}
+
+	if dbName == "" {
+		var res *backends.ListDatabasesResult
+
+		if res, err = h.b.ListDatabases(ctx, new(backends.ListDatabasesParams)); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		dbNames = make([]string, len(res.Databases))
+		for i, dbInfo := range res.Databases {
+			dbNames[i] = dbInfo.Name
+		}
+	}
+
+	problems := types.MakeArray(0)
+
+	for _, dbName = range dbNames {
+		var db backends.Database
+
+		if db, err = h.b.Database(dbName); err != nil {
+			if backends.ErrorCodeIs(err, backends.ErrorCodeDatabaseNameIsInvalid) {
+				msg := fmt.Sprintf("Invalid database specified '%s'", dbName)
+				return nil, commonerrors.NewCommandErrorMsgWithArgument(commonerrors.ErrInvalidNamespace, msg, command)
+			}
+
+			return nil, lazyerrors.Error(err)
+		}
+
+		var res *backends.ValidateMetadataResult
+
+		res, err = db.ValidateMetadata(ctx, &backends.ValidateMetadataParams{Repair: repair && !dryRun})
+		if backends.ErrorCodeIs(err, backends.ErrorCodeDatabaseDoesNotExist) {
+			res = new(backends.ValidateMetadataResult)
+			err = nil
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		for _, p := range res.Problems {
+			if cName != "" && p.Collection != cName {
+				continue
+			}
+
+			if p.Repaired {
+				h.L.Warn(
+					"Metadata repaired",
+					zap.String("db", dbName), zap.String("type", string(p.Type)),
+					zap.String("table", p.Table), zap.String("index", p.Index), zap.String("action", p.Action),
+				)
+			}
+
+			problems.Append(metadataProblemDocument(dbName, p))
+		}
+	}
+
+	var reply wire.OpMsg
+	must.NoError(reply.SetSections(wire.OpMsgSection{
+		Documents: []*types.Document{must.NotFail(types.NewDocument(
+			"apiVersionErrors", types.MakeArray(0),
+			"metadataProblems", problems,
+			"ok", float64(1),
+		))},
+	}))
+
+	return &reply, nil
+}
+
+// metadataProblemDocument returns a document describing the given metadata problem.
+func metadataProblemDocument(dbName string, p backends.MetadataProblem) *types.Document {
+	doc := must.NotFail(types.NewDocument(
+		"type", string(p.Type),
+		"db", dbName,
+	))
+
+	if p.Collection != "" {
+		doc.Set("collection", p.Collection)
+	}
+
+	doc.Set("table", p.Table)
+
+	if p.Index != "" {
+		doc.Set("index", p.Index)
+	}
+
+	if p.Action != "" {
+		doc.Set("action", p.Action)
+	}
+
+	doc.Set("repaired", p.Repaired)
+
+	return doc
+}
@@ -0,0 +1,387 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/handlers/commonerrors"
+	"github.com/FerretDB/FerretDB/internal/handlers/maintenance"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/wire"
+)
+
+// quotaUsage represents estimated storage usage of a single database or collection.
+type quotaUsage struct {
+	size int64
+	docs int64
+}
+
+// quotaUsages stores storage usage of databases and collections shared by all write commands.
+//
+// Usage of a collection (or a database) is loaded from backend statistics
+// when it is needed for the first time, and then updated by successful writes.
+// Loaded sizes do not include indexes; sizes of written documents are estimated
+// from their BSON representation, so usage is approximate.
+//
+// Usage that could not be updated precisely (for example, when the collection was dropped)
+// is forgotten and loaded again when needed.
+//
+//nolint:vet // for readability
+type quotaUsages struct {
+	m     sync.Mutex
+	dbs   map[string]*quotaUsage
+	colls map[maintenance.Namespace]*quotaUsage
+}
+
+// newQuotaUsages creates a new quotaUsages.
+func newQuotaUsages() *quotaUsages {
+	return &quotaUsages{
+		dbs:   map[string]*quotaUsage{},
+		colls: map[maintenance.Namespace]*quotaUsage{},
+	}
+}
+
+// database returns usage of the given database, loading it if needed.
+func (qu *quotaUsages) database(ctx context.Context, db backends.Database, dbName string) (*quotaUsage, error) {
+	qu.m.Lock()
+	u := qu.dbs[dbName]
+	qu.m.Unlock()
+
+	if u != nil {
+		return u, nil
+	}
+
+	u = new(quotaUsage)
+
+	stats, err := db.Stats(ctx, &backends.DatabaseStatsParams{Refresh: true})
+
+	switch {
+	case err == nil:
+		u.size, u.docs = stats.SizeCollections, stats.CountDocuments
+	case backends.ErrorCodeIs(err, backends.ErrorCodeDatabaseDoesNotExist):
+		// nothing is used yet
+	default:
+		return nil, lazyerrors.Error(err)
+	}
+
+	qu.m.Lock()
+	defer qu.m.Unlock()
+
+	// keep usage loaded and updated by a concurrent command, if any
+	if existing := qu.dbs[dbName]; existing != nil {
+		return existing, nil
+	}
+
+	qu.dbs[dbName] = u
+
+	return u, nil
+}
+
+// collection returns usage of the given collection, loading it if needed.
+func (qu *quotaUsages) collection(ctx context.Context, db backends.Database, dbName, cName string) (*quotaUsage, error) {
+	ns := maintenance.Namespace{DB: dbName, Collection: cName}
+
+	qu.m.Lock()
+	u := qu.colls[ns]
+	qu.m.Unlock()
+
+	if u != nil {
+		return u, nil
+	}
+
+	c, err := db.Collection(cName)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	u = new(quotaUsage)
+
+	stats, err := c.Stats(ctx, &backends.CollectionStatsParams{Refresh: true})
+
+	switch {
+	case err == nil:
+		u.size, u.docs = stats.SizeCollection, stats.CountDocuments
+	case backends.ErrorCodeIs(err, backends.ErrorCodeCollectionDoesNotExist):
+		// nothing is used yet
+	default:
+		return nil, lazyerrors.Error(err)
+	}
+
+	qu.m.Lock()
+	defer qu.m.Unlock()
+
+	// keep usage loaded and updated by a concurrent command, if any
+	if existing := qu.colls[ns]; existing != nil {
+		return existing, nil
+	}
+
+	qu.colls[ns] = u
+
+	return u, nil
+}
+
+// deleted accounts for deleted documents of the given collection.
+//
+// If not all matched documents were deleted (for example, some of them were deleted concurrently
+// and accounted for by another command), usage of the collection and the database is forgotten.
+func (qu *quotaUsages) deleted(dbName, cName string, size, matched, deleted int64) {
+	if matched != deleted {
+		qu.forget(dbName, cName)
+		return
+	}
+
+	qu.m.Lock()
+	defer qu.m.Unlock()
+
+	for _, u := range []*quotaUsage{qu.dbs[dbName], qu.colls[maintenance.Namespace{DB: dbName, Collection: cName}]} {
+		if u != nil {
+			u.size -= size
+			u.docs -= deleted
+		}
+	}
+}
+
+// forget forgets usage of the given collection (or all collections if collection name is empty)
+// and the database, so it is loaded again when needed.
+func (qu *quotaUsages) forget(dbName, cName string) {
+	qu.m.Lock()
+	defer qu.m.Unlock()
+
+	delete(qu.dbs, dbName)
+
+	for ns := range qu.colls {
+		if ns.DB == dbName && (cName == "" || ns.Collection == cName) {
+			delete(qu.colls, ns)
+		}
+	}
+}
+
+// quotaDeleted accounts for deleted documents of the given collection if any quota is configured.
+//
+// Size is the total estimated size of all matched documents (see documentSize).
+func (h *Handler) quotaDeleted(dbName, cName string, size int64, matched, deleted int) {
+	if h.quotasEnabled() {
+		h.quotas.deleted(dbName, cName, size, int64(matched), int64(deleted))
+	}
+}
+
+// quota checks storage usage of a single database and collection during a write command.
+//
+// Nil quota (returned when no quotas are configured) allows all writes.
+type quota struct {
+	h      *Handler
+	dbName string
+	cName  string
+
+	db *quotaUsage // nil if database quotas are not configured
+	c  *quotaUsage // nil if collection quotas are not configured
+}
+
+// quotasEnabled returns true if any quota is configured.
+func (h *Handler) quotasEnabled() bool {
+	return h.QuotaDatabaseSize > 0 || h.QuotaDatabaseDocuments > 0 ||
+		h.QuotaCollectionSize > 0 || h.QuotaCollectionDocuments > 0
+}
+
+// newQuota returns quota for the given database and collection,
+// or nil if no quotas are configured.
+func (h *Handler) newQuota(ctx context.Context, db backends.Database, dbName, cName string) (*quota, error) {
+	if !h.quotasEnabled() {
+		return nil, nil
+	}
+
+	q := &quota{
+		h:      h,
+		dbName: dbName,
+		cName:  cName,
+	}
+
+	var err error
+
+	if h.QuotaDatabaseSize > 0 || h.QuotaDatabaseDocuments > 0 {
+		if q.db, err = h.quotas.database(ctx, db, dbName); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+	}
+
+	if h.QuotaCollectionSize > 0 || h.QuotaCollectionDocuments > 0 {
+		if q.c, err = h.quotas.collection(ctx, db, dbName, cName); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+	}
+
+	return q, nil
+}
+
+// insert checks that inserting the given document does not exceed quotas and accounts for it.
+// The returned function should be called if the document was not inserted.
+//
+// It returns *commonerrors.CommandError with ErrQuotaExceeded code if quota is exceeded.
+func (q *quota) insert(doc *types.Document) (refund func(), err error) {
+	if q == nil {
+		return func() {}, nil
+	}
+
+	size, err := documentSize(doc)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return q.checkAndAdd(size, 1)
+}
+
+// update checks that replacing the old document with the new one does not exceed quotas and accounts for it.
+// The returned function should be called if the document was not updated.
+//
+// It returns *commonerrors.CommandError with ErrQuotaExceeded code if quota is exceeded.
+func (q *quota) update(oldSize int64, doc *types.Document) (refund func(), err error) {
+	if q == nil {
+		return func() {}, nil
+	}
+
+	size, err := documentSize(doc)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return q.checkAndAdd(size-oldSize, 0)
+}
+
+// size returns the estimated size of the given document, or 0 if no quotas are configured.
+func (q *quota) size(doc *types.Document) (int64, error) {
+	if q == nil {
+		return 0, nil
+	}
+
+	return documentSize(doc)
+}
+
+// checkAndAdd returns an error if adding the given size and number of documents exceeds any quota;
+// otherwise, it accounts for them and returns a function that reverts that.
+//
+// Shrinking documents is always allowed, even when quota is already exceeded.
+func (q *quota) checkAndAdd(size, docs int64) (refund func(), err error) {
+	q.h.quotas.m.Lock()
+	defer q.h.quotas.m.Unlock()
+
+	var dbSize, dbDocs, cSize, cDocs int64
+
+	if q.db != nil {
+		dbSize, dbDocs = q.db.size, q.db.docs
+	}
+
+	if q.c != nil {
+		cSize, cDocs = q.c.size, q.c.docs
+	}
+
+	for _, l := range []struct {
+		limit int64
+		used  int64
+		add   int64
+		what  string
+	}{
+		{q.h.QuotaDatabaseSize, dbSize, size, fmt.Sprintf("database %s size", q.dbName)},
+		{q.h.QuotaDatabaseDocuments, dbDocs, docs, fmt.Sprintf("database %s documents count", q.dbName)},
+		{q.h.QuotaCollectionSize, cSize, size, fmt.Sprintf("collection %s.%s size", q.dbName, q.cName)},
+		{q.h.QuotaCollectionDocuments, cDocs, docs, fmt.Sprintf("collection %s.%s documents count", q.dbName, q.cName)},
+	} {
+		if l.limit <= 0 || l.add <= 0 {
+			continue
+		}
+
+		if l.used+l.add > l.limit {
+			return nil, commonerrors.NewCommandErrorMsg(
+				commonerrors.ErrQuotaExceeded,
+				fmt.Sprintf("quota exceeded: %s would be %d, limit is %d", l.what, l.used+l.add, l.limit),
+			)
+		}
+	}
+
+	q.add(size, docs)
+
+	return func() {
+		q.h.quotas.m.Lock()
+		defer q.h.quotas.m.Unlock()
+
+		q.add(-size, -docs)
+	}, nil
+}
+
+// add adds the given size and number of documents to usages.
+//
+// Caller should hold quotaUsages' mutex.
+func (q *quota) add(size, docs int64) {
+	for _, u := range []*quotaUsage{q.db, q.c} {
+		if u != nil {
+			u.size += size
+			u.docs += docs
+		}
+	}
+}
+
+// quotaDocument returns a document with configured quotas for dbStats; sizes are divided by scale.
+func (h *Handler) quotaDocument(scale int64) *types.Document {
+	res := must.NotFail(types.NewDocument())
+
+	if h.QuotaDatabaseSize > 0 {
+		res.Set("maxSize", h.QuotaDatabaseSize/scale)
+	}
+
+	if h.QuotaDatabaseDocuments > 0 {
+		res.Set("maxDocuments", h.QuotaDatabaseDocuments)
+	}
+
+	if h.QuotaCollectionSize > 0 {
+		res.Set("maxCollectionSize", h.QuotaCollectionSize/scale)
+	}
+
+	if h.QuotaCollectionDocuments > 0 {
+		res.Set("maxCollectionDocuments", h.QuotaCollectionDocuments)
+	}
+
+	return res
+}
+
+// quotaWriteError converts quota error returned by insert or update methods to *writeError.
+// It returns nil for other errors.
+func quotaWriteError(err error, index int32) *writeError {
+	var ce *commonerrors.CommandError
+	if !errors.As(err, &ce) || ce.Code() != commonerrors.ErrQuotaExceeded {
+		return nil
+	}
+
+	return &writeError{
+		index:  index,
+		code:   ce.Code(),
+		errmsg: ce.Err().Error(),
+	}
+}
+
+// documentSize returns the estimated storage size of the given document.
+func documentSize(doc *types.Document) (int64, error) {
+	size, err := wire.DocumentSize(doc)
+	if err != nil {
+		return 0, lazyerrors.Error(err)
+	}
+
+	return int64(size), nil
+}
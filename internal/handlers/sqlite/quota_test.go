@@ -0,0 +1,277 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlite
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/handlers/commonerrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/util/state"
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+	"github.com/FerretDB/FerretDB/internal/wire"
+)
+
+// newTestHandler returns a new handler for SQLite backend in a temporary directory.
+func newTestHandler(t *testing.T, opts *NewOpts) *Handler {
+	t.Helper()
+
+	sp, err := state.NewProvider("")
+	require.NoError(t, err)
+
+	opts.Backend = "sqlite"
+	opts.URI = "file:" + t.TempDir() + "/"
+	opts.L = testutil.Logger(t)
+	opts.StateProvider = sp
+
+	h, err := New(opts)
+	require.NoError(t, err)
+
+	t.Cleanup(h.Close)
+
+	return h.(*Handler)
+}
+
+// testMsg returns OpMsg with the given command document.
+func testMsg(t *testing.T, pairs ...any) *wire.OpMsg {
+	t.Helper()
+
+	var msg wire.OpMsg
+	require.NoError(t, msg.SetSections(wire.OpMsgSection{
+		Documents: []*types.Document{must.NotFail(types.NewDocument(pairs...))},
+	}))
+
+	return &msg
+}
+
+func TestQuota(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Ctx(t)
+
+	t.Run("CollectionDocuments", func(t *testing.T) {
+		t.Parallel()
+
+		h := newTestHandler(t, &NewOpts{QuotaCollectionDocuments: 2})
+		dbName := testutil.DatabaseName(t)
+
+		docs := must.NotFail(types.NewArray(
+			must.NotFail(types.NewDocument("_id", int32(1))),
+			must.NotFail(types.NewDocument("_id", int32(2))),
+			must.NotFail(types.NewDocument("_id", int32(3))),
+		))
+
+		reply, err := h.MsgInsert(ctx, testMsg(t, "insert", "test", "documents", docs, "$db", dbName))
+		require.NoError(t, err)
+
+		res := must.NotFail(reply.Document())
+		assert.Equal(t, int32(2), must.NotFail(res.Get("n")))
+
+		writeErrors := must.NotFail(res.Get("writeErrors")).(*types.Array)
+		require.Equal(t, 1, writeErrors.Len())
+
+		we := must.NotFail(writeErrors.Get(0)).(*types.Document)
+		assert.Equal(t, int32(2), must.NotFail(we.Get("index")))
+		assert.Equal(t, int32(commonerrors.ErrQuotaExceeded), must.NotFail(we.Get("code")))
+
+		// other collections are not affected
+		reply, err = h.MsgInsert(ctx, testMsg(t, "insert", "other", "documents", docs, "$db", dbName))
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), must.NotFail(must.NotFail(reply.Document()).Get("n")))
+
+		// upsert is rejected too
+		updates := must.NotFail(types.NewArray(must.NotFail(types.NewDocument(
+			"q", must.NotFail(types.NewDocument("_id", int32(4))),
+			"u", must.NotFail(types.NewDocument("v", int32(4))),
+			"upsert", true,
+		))))
+
+		reply, err = h.MsgUpdate(ctx, testMsg(t, "update", "test", "updates", updates, "$db", dbName))
+		require.NoError(t, err)
+
+		res = must.NotFail(reply.Document())
+		assert.Equal(t, int32(0), must.NotFail(res.Get("n")))
+		assert.True(t, res.Has("writeErrors"))
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		t.Parallel()
+
+		h := newTestHandler(t, &NewOpts{QuotaDatabaseDocuments: 2})
+		dbName := testutil.DatabaseName(t)
+
+		insert := func(t *testing.T, id int32) *types.Document {
+			t.Helper()
+
+			docs := must.NotFail(types.NewArray(must.NotFail(types.NewDocument("_id", id))))
+			reply, err := h.MsgInsert(ctx, testMsg(t, "insert", "test", "documents", docs, "$db", dbName))
+			require.NoError(t, err)
+
+			return must.NotFail(reply.Document())
+		}
+
+		assert.Equal(t, int32(1), must.NotFail(insert(t, 1).Get("n")))
+		assert.Equal(t, int32(1), must.NotFail(insert(t, 2).Get("n")))
+		assert.True(t, insert(t, 3).Has("writeErrors"))
+
+		deletes := must.NotFail(types.NewArray(must.NotFail(types.NewDocument(
+			"q", must.NotFail(types.NewDocument("_id", int32(1))),
+			"limit", int32(1),
+		))))
+
+		_, err := h.MsgDelete(ctx, testMsg(t, "delete", "test", "deletes", deletes, "$db", dbName))
+		require.NoError(t, err)
+
+		// usage is updated by deletes
+		assert.Equal(t, int32(1), must.NotFail(insert(t, 3).Get("n")))
+		assert.True(t, insert(t, 4).Has("writeErrors"))
+
+		_, err = h.MsgDrop(ctx, testMsg(t, "drop", "test", "$db", dbName))
+		require.NoError(t, err)
+
+		// usage is reloaded after drop
+		assert.Equal(t, int32(1), must.NotFail(insert(t, 4).Get("n")))
+	})
+
+	t.Run("Refund", func(t *testing.T) {
+		t.Parallel()
+
+		h := newTestHandler(t, &NewOpts{QuotaCollectionDocuments: 2})
+		dbName := testutil.DatabaseName(t)
+
+		insert := func(t *testing.T, id int32) *types.Document {
+			t.Helper()
+
+			docs := must.NotFail(types.NewArray(must.NotFail(types.NewDocument("_id", id))))
+			reply, err := h.MsgInsert(ctx, testMsg(t, "insert", "test", "documents", docs, "$db", dbName))
+			require.NoError(t, err)
+
+			return must.NotFail(reply.Document())
+		}
+
+		assert.Equal(t, int32(1), must.NotFail(insert(t, 1).Get("n")))
+
+		// failed inserts are not accounted for
+		for i := 0; i < 3; i++ {
+			we := must.NotFail(must.NotFail(insert(t, 1).Get("writeErrors")).(*types.Array).Get(0)).(*types.Document)
+			assert.Equal(t, int32(commonerrors.ErrDuplicateKeyInsert), must.NotFail(we.Get("code")))
+		}
+
+		assert.Equal(t, int32(1), must.NotFail(insert(t, 2).Get("n")))
+		assert.True(t, insert(t, 3).Has("writeErrors"))
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		t.Parallel()
+
+		h := newTestHandler(t, &NewOpts{QuotaCollectionDocuments: 10})
+		dbName := testutil.DatabaseName(t)
+
+		var wg sync.WaitGroup
+		var inserted atomic.Int32
+
+		for i := int32(0); i < 20; i++ {
+			wg.Add(1)
+
+			go func(id int32) {
+				defer wg.Done()
+
+				docs := must.NotFail(types.NewArray(must.NotFail(types.NewDocument("_id", id))))
+				reply, err := h.MsgInsert(ctx, testMsg(t, "insert", "test", "documents", docs, "$db", dbName))
+				if !assert.NoError(t, err) {
+					return
+				}
+
+				inserted.Add(must.NotFail(must.NotFail(reply.Document()).Get("n")).(int32))
+			}(i)
+		}
+
+		wg.Wait()
+
+		// concurrent commands share usage counters
+		assert.Equal(t, int32(10), inserted.Load())
+	})
+
+	t.Run("UpdateIndex", func(t *testing.T) {
+		t.Parallel()
+
+		h := newTestHandler(t, &NewOpts{QuotaCollectionDocuments: 1})
+		dbName := testutil.DatabaseName(t)
+
+		updates := must.NotFail(types.NewArray(
+			must.NotFail(types.NewDocument(
+				"q", must.NotFail(types.NewDocument("_id", int32(1))),
+				"u", must.NotFail(types.NewDocument("v", int32(1))),
+				"upsert", true,
+			)),
+			must.NotFail(types.NewDocument(
+				"q", must.NotFail(types.NewDocument("_id", int32(2))),
+				"u", must.NotFail(types.NewDocument("v", int32(2))),
+				"upsert", true,
+			)),
+		))
+
+		reply, err := h.MsgUpdate(ctx, testMsg(t, "update", "test", "updates", updates, "$db", dbName))
+		require.NoError(t, err)
+
+		res := must.NotFail(reply.Document())
+		assert.Equal(t, int32(1), must.NotFail(res.Get("n")))
+		assert.Equal(t, 1, must.NotFail(res.Get("upserted")).(*types.Array).Len())
+
+		writeErrors := must.NotFail(res.Get("writeErrors")).(*types.Array)
+		require.Equal(t, 1, writeErrors.Len())
+
+		we := must.NotFail(writeErrors.Get(0)).(*types.Document)
+		assert.Equal(t, int32(1), must.NotFail(we.Get("index")))
+		assert.Equal(t, int32(commonerrors.ErrQuotaExceeded), must.NotFail(we.Get("code")))
+	})
+
+	t.Run("DatabaseSize", func(t *testing.T) {
+		t.Parallel()
+
+		h := newTestHandler(t, &NewOpts{QuotaDatabaseSize: 64 * 1024})
+		dbName := testutil.DatabaseName(t)
+
+		big := must.NotFail(types.NewDocument("_id", int32(1), "v", string(make([]byte, 100*1024))))
+
+		reply, err := h.MsgInsert(ctx, testMsg(t, "insert", "test", "documents", must.NotFail(types.NewArray(big)), "$db", dbName))
+		require.NoError(t, err)
+
+		res := must.NotFail(reply.Document())
+		assert.Equal(t, int32(0), must.NotFail(res.Get("n")))
+		assert.True(t, res.Has("writeErrors"))
+
+		small := must.NotFail(types.NewDocument("_id", int32(2)))
+
+		reply, err = h.MsgInsert(ctx, testMsg(t, "insert", "test", "documents", must.NotFail(types.NewArray(small)), "$db", dbName))
+		require.NoError(t, err)
+
+		res = must.NotFail(reply.Document())
+		assert.Equal(t, int32(1), must.NotFail(res.Get("n")))
+		assert.False(t, res.Has("writeErrors"))
+
+		reply, err = h.MsgDBStats(ctx, testMsg(t, "dbStats", int32(1), "scale", int32(1024), "$db", dbName))
+		require.NoError(t, err)
+
+		quota := must.NotFail(must.NotFail(reply.Document()).Get("quota")).(*types.Document)
+		assert.Equal(t, int64(64), must.NotFail(quota.Get("maxSize")))
+	})
+}
@@ -0,0 +1,339 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlite
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/handlers/maintenance"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// reshapes tracks writes to collections that are being reshaped by the reshapeCollection command.
+//
+// Writes to other collections take no locks. Writes to collections being reshaped hold
+// the write lock of their capture for reading, so holding it for writing blocks them
+// while collections are swapped.
+//
+// Modified documents are recorded after writes are done, so writes that started before
+// capturing but finished after it are recorded too.
+//
+// Only writes done by this FerretDB instance are tracked.
+//
+//nolint:vet // for readability
+type reshapes struct {
+	active atomic.Int32 // number of captures; if zero, captures map is not accessed
+
+	m        sync.Mutex
+	captures map[maintenance.Namespace]*reshapeCapture
+
+	b backends.Backend // not wrapped; writes to it are not tracked
+}
+
+// reshapeCapture accumulates modifications of a single collection being reshaped.
+type reshapeCapture struct {
+	writes sync.RWMutex // held for reading by all writes to that collection
+
+	// protected by reshapes.m
+	ids     []any  // _id values of inserted, updated, and deleted documents; may contain duplicates
+	aborted string // if not empty, the reason why reshaping can't be completed
+}
+
+// newReshapes wraps the given backend to track writes to collections being reshaped.
+// It returns wrapped backend that should be used for all operations.
+func newReshapes(b backends.Backend) (*reshapes, backends.Backend) {
+	r := &reshapes{
+		captures: map[maintenance.Namespace]*reshapeCapture{},
+		b:        b,
+	}
+
+	return r, &reshapeBackend{Backend: b, r: r}
+}
+
+// start starts capturing writes to the given collection.
+// It returns false if that collection is already being reshaped.
+func (r *reshapes) start(ns maintenance.Namespace) bool {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if _, ok := r.captures[ns]; ok {
+		return false
+	}
+
+	r.captures[ns] = new(reshapeCapture)
+	r.active.Add(1)
+
+	return true
+}
+
+// stop stops capturing writes to the given collection.
+func (r *reshapes) stop(ns maintenance.Namespace) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if _, ok := r.captures[ns]; ok {
+		delete(r.captures, ns)
+		r.active.Add(-1)
+	}
+}
+
+// get returns the capture of the given collection, or nil if it is not being reshaped.
+func (r *reshapes) get(ns maintenance.Namespace) *reshapeCapture {
+	if r.active.Load() == 0 {
+		return nil
+	}
+
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	return r.captures[ns]
+}
+
+// lock blocks writes to the given collection being reshaped.
+// It returns a function that unblocks them.
+func (r *reshapes) lock(ns maintenance.Namespace) (unlock func()) {
+	c := r.get(ns)
+	if c == nil {
+		return func() {}
+	}
+
+	c.writes.Lock()
+
+	return c.writes.Unlock
+}
+
+// take returns and resets _id values of documents modified since the previous call,
+// and the reason why reshaping was aborted, if any.
+func (r *reshapes) take(ns maintenance.Namespace) ([]any, string) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	c := r.captures[ns]
+	if c == nil {
+		return nil, ""
+	}
+
+	ids := c.ids
+	c.ids = nil
+
+	return ids, c.aborted
+}
+
+// begin should be called before a write to the given collection.
+// The returned function should be called after the write with _id values of modified documents.
+func (r *reshapes) begin(ns maintenance.Namespace) (end func(ids ...any)) {
+	c := r.get(ns)
+	if c != nil {
+		c.writes.RLock()
+	}
+
+	return func(ids ...any) {
+		// record after the write, so documents are re-read only after they are modified
+		r.record(ns, ids...)
+
+		if c != nil {
+			c.writes.RUnlock()
+		}
+	}
+}
+
+// record records _id values of modified documents of the given collection.
+// It does nothing if that collection is not being reshaped.
+func (r *reshapes) record(ns maintenance.Namespace, ids ...any) {
+	if r.active.Load() == 0 {
+		return
+	}
+
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if c := r.captures[ns]; c != nil {
+		c.ids = append(c.ids, ids...)
+	}
+}
+
+// abort marks reshaping of the given collection (or all collections of the database if collection name is empty)
+// as aborted with the given reason, and blocks swapping of those collections.
+//
+// The returned function should be called after the operation that caused abort is done.
+// It marks reshaping that started during that operation as aborted too, and unblocks swapping.
+func (r *reshapes) abort(dbName, cName, reason string) (done func()) {
+	locked := r.markAborted(dbName, cName, reason)
+	for _, c := range locked {
+		c.writes.RLock()
+	}
+
+	return func() {
+		r.markAborted(dbName, cName, reason)
+
+		for _, c := range locked {
+			c.writes.RUnlock()
+		}
+	}
+}
+
+// markAborted marks reshaping of the given collection (or all collections of the database if collection name is empty)
+// as aborted with the given reason and returns their captures.
+func (r *reshapes) markAborted(dbName, cName, reason string) []*reshapeCapture {
+	if r.active.Load() == 0 {
+		return nil
+	}
+
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	var res []*reshapeCapture
+
+	for ns, c := range r.captures {
+		if ns.DB == dbName && (cName == "" || ns.Collection == cName) {
+			c.aborted = reason
+			res = append(res, c)
+		}
+	}
+
+	return res
+}
+
+// reshapeBackend wraps backends.Backend to track writes.
+type reshapeBackend struct {
+	backends.Backend
+	r *reshapes
+}
+
+// Database implements backends.Backend interface.
+func (b *reshapeBackend) Database(name string) (backends.Database, error) {
+	db, err := b.Backend.Database(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &reshapeDatabase{Database: db, name: name, r: b.r}, nil
+}
+
+// DropDatabase implements backends.Backend interface.
+func (b *reshapeBackend) DropDatabase(ctx context.Context, params *backends.DropDatabaseParams) error {
+	defer b.r.abort(params.Name, "", "database was dropped")()
+
+	return b.Backend.DropDatabase(ctx, params)
+}
+
+// reshapeDatabase wraps backends.Database to track writes.
+type reshapeDatabase struct {
+	backends.Database
+	name string
+	r    *reshapes
+}
+
+// Collection implements backends.Database interface.
+func (db *reshapeDatabase) Collection(name string) (backends.Collection, error) {
+	c, err := db.Database.Collection(name)
+	if err != nil {
+		return nil, err
+	}
+
+	ns := maintenance.Namespace{DB: db.name, Collection: name}
+
+	return &reshapeCollection{Collection: c, ns: ns, r: db.r}, nil
+}
+
+// DropCollection implements backends.Database interface.
+func (db *reshapeDatabase) DropCollection(ctx context.Context, params *backends.DropCollectionParams) error {
+	defer db.r.abort(db.name, params.Name, "collection was dropped")()
+
+	return db.Database.DropCollection(ctx, params)
+}
+
+// RenameCollection implements backends.Database interface.
+func (db *reshapeDatabase) RenameCollection(ctx context.Context, params *backends.RenameCollectionParams) error {
+	defer db.r.abort(db.name, params.OldName, "collection was renamed")()
+
+	if params.DropTarget {
+		defer db.r.abort(db.name, params.NewName, "collection was dropped")()
+	}
+
+	return db.Database.RenameCollection(ctx, params)
+}
+
+// reshapeCollection wraps backends.Collection to track writes.
+type reshapeCollection struct {
+	backends.Collection
+	ns maintenance.Namespace
+	r  *reshapes
+}
+
+// InsertAll implements backends.Collection interface.
+func (c *reshapeCollection) InsertAll(ctx context.Context, params *backends.InsertAllParams) (*backends.InsertAllResult, error) {
+	defer c.r.begin(c.ns)(docIDs(params.Docs)...)
+
+	return c.Collection.InsertAll(ctx, params)
+}
+
+// UpdateAll implements backends.Collection interface.
+func (c *reshapeCollection) UpdateAll(ctx context.Context, params *backends.UpdateAllParams) (*backends.UpdateAllResult, error) {
+	defer c.r.begin(c.ns)(docIDs(params.Docs)...)
+
+	return c.Collection.UpdateAll(ctx, params)
+}
+
+// DeleteAll implements backends.Collection interface.
+func (c *reshapeCollection) DeleteAll(ctx context.Context, params *backends.DeleteAllParams) (*backends.DeleteAllResult, error) {
+	if len(params.RecordIDs) > 0 {
+		defer c.r.abort(c.ns.DB, c.ns.Collection, "documents were deleted by record IDs")()
+	}
+
+	defer c.r.begin(c.ns)(params.IDs...)
+
+	return c.Collection.DeleteAll(ctx, params)
+}
+
+// CreateIndexes implements backends.Collection interface.
+//
+//nolint:lll // for readability
+func (c *reshapeCollection) CreateIndexes(ctx context.Context, params *backends.CreateIndexesParams) (*backends.CreateIndexesResult, error) {
+	// indexes are copied only once, at the start
+	defer c.r.abort(c.ns.DB, c.ns.Collection, "indexes were changed")()
+
+	return c.Collection.CreateIndexes(ctx, params)
+}
+
+// DropIndexes implements backends.Collection interface.
+//
+//nolint:lll // for readability
+func (c *reshapeCollection) DropIndexes(ctx context.Context, params *backends.DropIndexesParams) (*backends.DropIndexesResult, error) {
+	defer c.r.abort(c.ns.DB, c.ns.Collection, "indexes were changed")()
+
+	return c.Collection.DropIndexes(ctx, params)
+}
+
+// docIDs returns _id values of the given documents.
+func docIDs(docs []*types.Document) []any {
+	res := make([]any, len(docs))
+	for i, doc := range docs {
+		res[i] = must.NotFail(doc.Get("_id"))
+	}
+
+	return res
+}
+
+// check interfaces
+var (
+	_ backends.Backend    = (*reshapeBackend)(nil)
+	_ backends.Database   = (*reshapeDatabase)(nil)
+	_ backends.Collection = (*reshapeCollection)(nil)
+)
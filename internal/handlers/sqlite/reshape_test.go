@@ -0,0 +1,207 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/handlers/maintenance"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+)
+
+func TestReshape(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Ctx(t)
+
+	// insert inserts documents with given _id values into the collection.
+	insert := func(t *testing.T, c backends.Collection, ids ...int32) {
+		t.Helper()
+
+		docs := make([]*types.Document, len(ids))
+		for i, id := range ids {
+			docs[i] = must.NotFail(types.NewDocument("_id", id, "v", "old"))
+		}
+
+		_, err := c.InsertAll(ctx, &backends.InsertAllParams{Docs: docs})
+		require.NoError(t, err)
+	}
+
+	// naturalOrder returns documents of the collection in the natural order.
+	naturalOrder := func(t *testing.T, c backends.Collection) []*types.Document {
+		t.Helper()
+
+		q, err := c.Query(ctx, &backends.QueryParams{Sort: &backends.SortField{Key: backends.NaturalSortKey}})
+		require.NoError(t, err)
+
+		docs, err := iterator.ConsumeValues(q.Iter)
+		require.NoError(t, err)
+
+		return docs
+	}
+
+	t.Run("CatchUp", func(t *testing.T) {
+		t.Parallel()
+
+		h := newTestHandler(t, new(NewOpts))
+		db, err := h.reshapes.b.Database(testutil.DatabaseName(t))
+		require.NoError(t, err)
+
+		source := must.NotFail(db.Collection("source"))
+		target := must.NotFail(db.Collection("target"))
+
+		insert(t, source, 1, 2, 3, 4)
+		insert(t, target, 1, 2, 3, 4)
+
+		_, err = source.UpdateAll(ctx, &backends.UpdateAllParams{
+			Docs: []*types.Document{must.NotFail(types.NewDocument("_id", int32(2), "v", "new"))},
+		})
+		require.NoError(t, err)
+
+		_, err = source.DeleteAll(ctx, &backends.DeleteAllParams{IDs: []any{int32(3)}})
+		require.NoError(t, err)
+
+		insert(t, source, 5)
+
+		n, err := reshapeCatchUp(ctx, source, target, []any{int32(2), int32(3), int32(5), int32(2)}, 2)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), n)
+
+		// updated document keeps its place
+		docs := naturalOrder(t, target)
+		assert.Equal(t, []any{int32(1), int32(2), int32(4), int32(5)}, docIDs(docs))
+		assert.Equal(t, "new", must.NotFail(docs[1].Get("v")))
+	})
+
+	t.Run("ClusteredIndex", func(t *testing.T) {
+		t.Parallel()
+
+		h := newTestHandler(t, new(NewOpts))
+		dbName := testutil.DatabaseName(t)
+
+		db, err := h.b.Database(dbName)
+		require.NoError(t, err)
+
+		insert(t, must.NotFail(db.Collection("natural")), 3, 1, 2)
+		insert(t, must.NotFail(db.Collection("clustered")), 3, 1, 2)
+
+		_, err = h.MsgReshapeCollection(ctx, testMsg(t, "reshapeCollection", dbName+".natural"))
+		require.NoError(t, err)
+
+		spec := must.NotFail(types.NewDocument("key", must.NotFail(types.NewDocument("_id", int32(1))), "unique", true))
+		_, err = h.MsgReshapeCollection(ctx, testMsg(t, "reshapeCollection", dbName+".clustered", "clusteredIndex", spec))
+		require.NoError(t, err)
+
+		expected := []any{int32(3), int32(1), int32(2)}
+		assert.Equal(t, expected, docIDs(naturalOrder(t, must.NotFail(db.Collection("natural")))))
+
+		expected = []any{int32(1), int32(2), int32(3)}
+		assert.Equal(t, expected, docIDs(naturalOrder(t, must.NotFail(db.Collection("clustered")))))
+
+		spec = must.NotFail(types.NewDocument("key", must.NotFail(types.NewDocument("v", int32(1))), "unique", true))
+		_, err = h.MsgReshapeCollection(ctx, testMsg(t, "reshapeCollection", dbName+".clustered", "clusteredIndex", spec))
+		require.Error(t, err)
+	})
+
+	t.Run("IndexesChanged", func(t *testing.T) {
+		t.Parallel()
+
+		h := newTestHandler(t, new(NewOpts))
+		dbName := testutil.DatabaseName(t)
+
+		db, err := h.b.Database(dbName)
+		require.NoError(t, err)
+
+		c := must.NotFail(db.Collection("test"))
+		insert(t, c, 1)
+
+		ns := maintenance.Namespace{DB: dbName, Collection: "test"}
+
+		require.True(t, h.reshapes.start(ns))
+
+		_, err = c.CreateIndexes(ctx, &backends.CreateIndexesParams{
+			Indexes: []backends.IndexInfo{{Name: "v_1", Key: []backends.IndexKeyPair{{Field: "v"}}}},
+		})
+		require.NoError(t, err)
+
+		_, aborted := h.reshapes.take(ns)
+		assert.Equal(t, "indexes were changed", aborted)
+
+		h.reshapes.stop(ns)
+		require.True(t, h.reshapes.start(ns))
+
+		_, err = c.DropIndexes(ctx, &backends.DropIndexesParams{Indexes: []string{"v_1"}})
+		require.NoError(t, err)
+
+		_, aborted = h.reshapes.take(ns)
+		assert.Equal(t, "indexes were changed", aborted)
+
+		h.reshapes.stop(ns)
+	})
+
+	t.Run("LockScope", func(t *testing.T) {
+		t.Parallel()
+
+		h := newTestHandler(t, new(NewOpts))
+		dbName := testutil.DatabaseName(t)
+
+		db, err := h.b.Database(dbName)
+		require.NoError(t, err)
+
+		ns := maintenance.Namespace{DB: dbName, Collection: "reshaped"}
+
+		require.True(t, h.reshapes.start(ns))
+		defer h.reshapes.stop(ns)
+
+		unlock := h.reshapes.lock(ns)
+
+		// writes to other collections are not blocked
+		other := must.NotFail(db.Collection("other"))
+		insert(t, other, 1)
+
+		done := make(chan struct{})
+
+		go func() {
+			defer close(done)
+
+			doc := must.NotFail(types.NewDocument("_id", int32(1), "v", "old"))
+			_, err := must.NotFail(db.Collection("reshaped")).InsertAll(ctx, &backends.InsertAllParams{
+				Docs: []*types.Document{doc},
+			})
+			assert.NoError(t, err)
+		}()
+
+		select {
+		case <-done:
+			t.Fatal("write to reshaped collection was not blocked")
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		unlock()
+		<-done
+
+		ids, aborted := h.reshapes.take(ns)
+		assert.Equal(t, []any{int32(1)}, ids)
+		assert.Empty(t, aborted)
+	})
+}
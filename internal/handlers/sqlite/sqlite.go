@@ -18,17 +18,26 @@
 package sqlite
 
 import (
+	"context"
+	"sync"
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 
 	"github.com/FerretDB/FerretDB/internal/backends"
 	"github.com/FerretDB/FerretDB/internal/backends/decorators/oplog"
 	"github.com/FerretDB/FerretDB/internal/backends/hana"
+	"github.com/FerretDB/FerretDB/internal/backends/memory"
 	"github.com/FerretDB/FerretDB/internal/backends/postgresql"
 	"github.com/FerretDB/FerretDB/internal/backends/sqlite"
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
 	"github.com/FerretDB/FerretDB/internal/clientconn/connmetrics"
 	"github.com/FerretDB/FerretDB/internal/clientconn/cursor"
+	"github.com/FerretDB/FerretDB/internal/clientconn/session"
 	"github.com/FerretDB/FerretDB/internal/handlers"
+	"github.com/FerretDB/FerretDB/internal/handlers/indexbuild"
+	"github.com/FerretDB/FerretDB/internal/handlers/maintenance"
 	"github.com/FerretDB/FerretDB/internal/util/state"
 )
 
@@ -38,7 +47,19 @@ type Handler struct {
 
 	b backends.Backend
 
-	cursors *cursor.Registry
+	reshapes    *reshapes
+	cursors     *cursor.Registry
+	indexBuilds *indexbuild.Registry
+
+	writes      *maintenance.Writes
+	maintenance *maintenance.Scheduler
+
+	quotas *quotaUsages
+
+	applyOpsM sync.Mutex // serializes applyOps batches
+
+	tempM sync.Mutex
+	temp  map[string]tempCollection // namespace -> temporary collection created by this handler
 }
 
 // NewOpts represents handler configuration.
@@ -51,6 +72,22 @@ type NewOpts struct {
 	L             *zap.Logger
 	ConnMetrics   *connmetrics.ConnMetrics
 	StateProvider *state.Provider
+	ConnRegistry  *conninfo.Registry // if nil, currentOp does not return idle connections
+	Sessions      *session.Registry  // if nil, a new registry is created
+
+	// maintenance options; zero intervals disable tasks
+	CompactInterval  time.Duration
+	CompactThreshold int64
+	TTLInterval      time.Duration
+	CursorsInterval  time.Duration
+	CursorTimeout    time.Duration
+	SessionsInterval time.Duration
+
+	// storage quotas; zero values disable quotas
+	QuotaDatabaseSize        int64
+	QuotaDatabaseDocuments   int64
+	QuotaCollectionSize      int64
+	QuotaCollectionDocuments int64
 
 	// test options
 	DisableFilterPushdown bool
@@ -82,6 +119,11 @@ func New(opts *NewOpts) (handlers.Interface, error) {
 			L:   opts.L,
 			P:   opts.StateProvider,
 		})
+	case "memory":
+		b, err = memory.NewBackend(&memory.NewBackendParams{
+			L: opts.L,
+			P: opts.StateProvider,
+		})
 	default:
 		panic("unknown backend: " + opts.Backend)
 	}
@@ -94,16 +136,47 @@ func New(opts *NewOpts) (handlers.Interface, error) {
 		b = oplog.NewBackend(b, opts.L.Named("oplog"))
 	}
 
-	return &Handler{
-		b:       b,
-		NewOpts: opts,
-		cursors: cursor.NewRegistry(opts.L.Named("cursors")),
-	}, nil
+	if opts.Sessions == nil {
+		opts.Sessions = session.NewRegistry(opts.L.Named("sessions"))
+	}
+
+	reshapes, b := newReshapes(b)
+
+	h := &Handler{
+		b:           b,
+		NewOpts:     opts,
+		reshapes:    reshapes,
+		cursors:     cursor.NewRegistry(opts.L.Named("cursors")),
+		indexBuilds: indexbuild.NewRegistry(opts.L.Named("indexbuild")),
+		writes:      maintenance.NewWrites(),
+		quotas:      newQuotaUsages(),
+		temp:        map[string]tempCollection{},
+	}
+
+	// there are no client credentials at startup; backend URI is used instead
+	ctx, cancel := context.WithTimeout(conninfo.Ctx(context.Background(), conninfo.New()), time.Minute)
+	defer cancel()
+
+	// it is done before any client request, so just created temporary collections are not dropped;
+	// log and continue, they will be dropped on the next start
+	if err = h.dropOrphanTempCollections(ctx); err != nil {
+		opts.L.Warn("Failed to drop orphan temporary collections", zap.Error(err))
+	}
+
+	h.maintenance = h.newMaintenance()
+
+	// like above, tasks are run without client credentials
+	h.maintenance.Start(conninfo.Ctx(context.Background(), conninfo.New()))
+
+	return h, nil
 }
 
 // Close implements handlers.Interface.
 func (h *Handler) Close() {
+	h.maintenance.Close()
+	h.Sessions.EndAll()
 	h.cursors.Close()
+	h.indexBuilds.Close()
 	h.b.Close()
 }
 
@@ -14,8 +14,55 @@
 
 package sqlite
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+)
 
 func TestDummy(t *testing.T) {
 	// we need at least one test per package to correctly calculate coverage
 }
+
+func TestHelloConnectionID(t *testing.T) {
+	t.Parallel()
+
+	h := newTestHandler(t, &NewOpts{})
+
+	connInfo := conninfo.New()
+	connInfo.ID = 123
+	ctx := conninfo.Ctx(testutil.Ctx(t), connInfo)
+
+	res, err := h.MsgHello(ctx, testMsg(t, "hello", int32(1)))
+	require.NoError(t, err)
+	assert.Equal(t, int32(123), must.NotFail(must.NotFail(res.Document()).Get("connectionId")))
+
+	res, err = h.MsgIsMaster(ctx, testMsg(t, "isMaster", int32(1)))
+	require.NoError(t, err)
+	assert.Equal(t, int32(123), must.NotFail(must.NotFail(res.Document()).Get("connectionId")))
+}
+
+func TestSASLStartNotVerified(t *testing.T) {
+	t.Parallel()
+
+	h := newTestHandler(t, &NewOpts{})
+
+	connInfo := conninfo.New()
+	ctx := conninfo.Ctx(testutil.Ctx(t), connInfo)
+
+	payload := types.Binary{B: []byte("\x00username\x00password")}
+
+	_, err := h.MsgSASLStart(ctx, testMsg(t, "saslStart", int32(1), "mechanism", "PLAIN", "payload", payload, "$db", "admin"))
+	require.NoError(t, err)
+
+	// SQLite backend does not check credentials
+	username, _ := connInfo.Auth()
+	assert.Equal(t, "username", username)
+	assert.False(t, connInfo.Verified())
+}
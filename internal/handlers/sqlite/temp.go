@@ -0,0 +1,195 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// tempCollection represents a temporary collection created by this handler.
+type tempCollection struct {
+	uuid string // backend UUID of the collection
+	stop func() // unregisters session end hook
+}
+
+// tempOwner returns the identifier of this FerretDB instance stored in the metadata of temporary collections.
+//
+// The state UUID is used as it survives restarts, but is not shared with other instances.
+func (h *Handler) tempOwner() string {
+	return h.StateProvider.Get().UUID
+}
+
+// addTempCollection registers a session end hook that drops just created temporary collection.
+//
+// The hook drops the collection only if it still has the same backend UUID,
+// so a collection with the same name created later is never dropped.
+// If the session already ended (for example, concurrently with the collection creation),
+// the collection is dropped immediately.
+func (h *Handler) addTempCollection(ctx context.Context, db backends.Database, dbName, collectionName string, sessionID uuid.UUID) error { //nolint:lll // for readability
+	list, err := db.ListCollections(ctx, nil)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	i := slices.IndexFunc(list.Collections, func(c backends.CollectionInfo) bool { return c.Name == collectionName })
+	if i < 0 || !list.Collections[i].Temp {
+		// it was already dropped or renamed by another client
+		return nil
+	}
+
+	collUUID := list.Collections[i].UUID
+	ns := dbName + "." + collectionName
+
+	h.tempM.Lock()
+
+	stop, ok := h.Sessions.OnEnd(sessionID, func() {
+		h.tempM.Lock()
+		if h.temp[ns].uuid == collUUID {
+			delete(h.temp, ns)
+		}
+		h.tempM.Unlock()
+
+		h.dropTempCollection(dbName, collectionName, collUUID)
+	})
+
+	if ok {
+		if prev, found := h.temp[ns]; found {
+			prev.stop()
+		}
+
+		h.temp[ns] = tempCollection{
+			uuid: collUUID,
+			stop: stop,
+		}
+	}
+
+	h.tempM.Unlock()
+
+	if !ok {
+		h.dropTempCollection(dbName, collectionName, collUUID)
+	}
+
+	return nil
+}
+
+// forgetTempCollections unregisters session end hooks of temporary collections
+// that were dropped or renamed.
+//
+// If collectionName is empty, hooks of all collections in the database are unregistered.
+func (h *Handler) forgetTempCollections(dbName, collectionName string) {
+	h.tempM.Lock()
+	defer h.tempM.Unlock()
+
+	for ns, tc := range h.temp {
+		if collectionName == "" {
+			if !strings.HasPrefix(ns, dbName+".") {
+				continue
+			}
+		} else if ns != dbName+"."+collectionName {
+			continue
+		}
+
+		tc.stop()
+		delete(h.temp, ns)
+	}
+}
+
+// dropTempCollection drops temporary collection when the logical session that created it ends.
+//
+// It is called outside of any client request, so it uses its own context and logs errors.
+func (h *Handler) dropTempCollection(dbName, collectionName, collUUID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	l := h.L.With(zap.String("db", dbName), zap.String("collection", collectionName))
+
+	db, err := h.b.Database(dbName)
+	if err != nil {
+		l.Warn("Failed to drop temporary collection", zap.Error(err))
+		return
+	}
+
+	err = db.DropCollection(ctx, &backends.DropCollectionParams{Name: collectionName, UUID: collUUID})
+
+	switch {
+	case err == nil:
+		l.Debug("Temporary collection dropped")
+	case backends.ErrorCodeIs(err, backends.ErrorCodeCollectionDoesNotExist):
+		// already dropped, or replaced by another collection with the same name
+	default:
+		l.Warn("Failed to drop temporary collection", zap.Error(err))
+	}
+}
+
+// dropOrphanTempCollections drops temporary collections left after the previous run of this FerretDB instance.
+// Sessions do not survive restarts, so all temporary collections created by this instance are orphaned.
+// Temporary collections of other instances sharing the same database are not touched.
+//
+// It is called on startup, before any client request.
+func (h *Handler) dropOrphanTempCollections(ctx context.Context) error {
+	dbs, err := h.b.ListDatabases(ctx, nil)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	owner := h.tempOwner()
+
+	var errs []error
+
+	for _, dbInfo := range dbs.Databases {
+		db, err := h.b.Database(dbInfo.Name)
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		colls, err := db.ListCollections(ctx, nil)
+		if err != nil {
+			if backends.ErrorCodeIs(err, backends.ErrorCodeDatabaseDoesNotExist) {
+				continue
+			}
+
+			return lazyerrors.Error(err)
+		}
+
+		for _, cInfo := range colls.Collections {
+			if !cInfo.Temp || cInfo.TempOwner != owner {
+				continue
+			}
+
+			err = db.DropCollection(ctx, &backends.DropCollectionParams{Name: cInfo.Name, UUID: cInfo.UUID})
+			if err != nil && !backends.ErrorCodeIs(err, backends.ErrorCodeCollectionDoesNotExist) {
+				errs = append(errs, lazyerrors.Error(err))
+				continue
+			}
+
+			h.L.Info(
+				"Orphan temporary collection dropped",
+				zap.String("db", dbInfo.Name), zap.String("collection", cInfo.Name),
+			)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,157 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+)
+
+func TestTempCollections(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Ctx(t)
+
+	// collectionNames returns names of collections in the database and whether they are temporary.
+	collectionNames := func(t *testing.T, h *Handler, dbName string) map[string]bool {
+		t.Helper()
+
+		db, err := h.b.Database(dbName)
+		require.NoError(t, err)
+
+		list, err := db.ListCollections(ctx, nil)
+		require.NoError(t, err)
+
+		res := map[string]bool{}
+		for _, c := range list.Collections {
+			res[c.Name] = c.Temp
+		}
+
+		return res
+	}
+
+	// createTemp creates a temporary collection within the session with the given ID.
+	createTemp := func(t *testing.T, h *Handler, dbName, collectionName string, id uuid.UUID) {
+		t.Helper()
+
+		// as done by clientconn for commands with lsid
+		h.Sessions.Touch(id)
+
+		lsid := must.NotFail(types.NewDocument("id", types.Binary{Subtype: types.BinaryUUID, B: id[:]}))
+
+		_, err := h.MsgCreate(ctx, testMsg(t, "create", collectionName, "temp", true, "lsid", lsid, "$db", dbName))
+		require.NoError(t, err)
+	}
+
+	t.Run("Recreated", func(t *testing.T) {
+		t.Parallel()
+
+		h := newTestHandler(t, new(NewOpts))
+		dbName := testutil.DatabaseName(t)
+		id := uuid.New()
+
+		createTemp(t, h, dbName, "test", id)
+		assert.Equal(t, map[string]bool{"test": true}, collectionNames(t, h, dbName))
+
+		// dropped without unregistering the hook, for example, by another FerretDB instance
+		db, err := h.b.Database(dbName)
+		require.NoError(t, err)
+		require.NoError(t, db.DropCollection(ctx, &backends.DropCollectionParams{Name: "test"}))
+
+		_, err = h.MsgCreate(ctx, testMsg(t, "create", "test", "$db", dbName))
+		require.NoError(t, err)
+
+		// collection with the same name created by another client is not dropped
+		h.Sessions.End(id)
+		assert.Equal(t, map[string]bool{"test": false}, collectionNames(t, h, dbName))
+	})
+
+	t.Run("Renamed", func(t *testing.T) {
+		t.Parallel()
+
+		h := newTestHandler(t, new(NewOpts))
+		dbName := testutil.DatabaseName(t)
+		id := uuid.New()
+
+		createTemp(t, h, dbName, "test", id)
+		createTemp(t, h, dbName, "other", id)
+
+		_, err := h.MsgRenameCollection(ctx, testMsg(
+			t, "renameCollection", dbName+".test", "to", dbName+".renamed", "$db", "admin",
+		))
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]bool{"renamed": false, "other": true}, collectionNames(t, h, dbName))
+
+		h.Sessions.End(id)
+		assert.Equal(t, map[string]bool{"renamed": false}, collectionNames(t, h, dbName))
+	})
+
+	t.Run("SessionEnded", func(t *testing.T) {
+		t.Parallel()
+
+		h := newTestHandler(t, new(NewOpts))
+		dbName := testutil.DatabaseName(t)
+		id := uuid.New()
+
+		// session ended before the hook was registered
+		lsid := must.NotFail(types.NewDocument("id", types.Binary{Subtype: types.BinaryUUID, B: id[:]}))
+		_, err := h.MsgCreate(ctx, testMsg(t, "create", "test", "temp", true, "lsid", lsid, "$db", dbName))
+		require.NoError(t, err)
+
+		assert.Empty(t, collectionNames(t, h, dbName))
+		assert.Equal(t, 0, h.Sessions.Len())
+	})
+
+	t.Run("Orphan", func(t *testing.T) {
+		t.Parallel()
+
+		h := newTestHandler(t, new(NewOpts))
+		dbName := testutil.DatabaseName(t)
+
+		db, err := h.b.Database(dbName)
+		require.NoError(t, err)
+
+		// as if it was created before restart
+		err = db.CreateCollection(ctx, &backends.CreateCollectionParams{
+			Name:      "orphan",
+			Temp:      true,
+			TempOwner: h.tempOwner(),
+		})
+		require.NoError(t, err)
+
+		// created by another FerretDB instance that uses the same database
+		err = db.CreateCollection(ctx, &backends.CreateCollectionParams{
+			Name:      "other",
+			Temp:      true,
+			TempOwner: uuid.NewString(),
+		})
+		require.NoError(t, err)
+
+		err = db.CreateCollection(ctx, &backends.CreateCollectionParams{Name: "test"})
+		require.NoError(t, err)
+
+		require.NoError(t, h.dropOrphanTempCollections(ctx))
+		assert.Equal(t, map[string]bool{"other": true, "test": false}, collectionNames(t, h, dbName))
+	})
+}
@@ -22,6 +22,7 @@ import (
 	"net"
 	"net/http"
 	_ "net/http/pprof" // for profiling
+	"sync"
 	"text/template"
 	"time"
 
@@ -33,6 +34,29 @@ import (
 	"github.com/FerretDB/FerretDB/internal/util/must"
 )
 
+// handlers maps paths of debug handlers to their descriptions.
+var (
+	handlersM sync.Mutex
+	handlers  = map[string]string{
+		// custom handlers registered by RunHandler
+		"/debug/graphs":  "Visualize metrics",
+		"/debug/metrics": "Metrics in Prometheus format",
+
+		// stdlib handlers
+		"/debug/vars":  "Expvar package metrics",
+		"/debug/pprof": "Runtime profiling data for pprof",
+	}
+)
+
+// Handle registers additional debug handler for the given path with the given description.
+func Handle(path, description string, handler http.Handler) {
+	handlersM.Lock()
+	defer handlersM.Unlock()
+
+	http.Handle(path, handler)
+	handlers[path] = description
+}
+
 // RunHandler runs debug handler.
 func RunHandler(ctx context.Context, addr string, r prometheus.Registerer, l *zap.Logger) {
 	stdL := must.NotFail(zap.NewStdLogAt(l, zap.WarnLevel))
@@ -52,18 +76,7 @@ func RunHandler(ctx context.Context, addr string, r prometheus.Registerer, l *za
 	}
 	must.NoError(statsviz.Register(http.DefaultServeMux, opts...))
 
-	handlers := map[string]string{
-		// custom handlers registered above
-		"/debug/graphs":  "Visualize metrics",
-		"/debug/metrics": "Metrics in Prometheus format",
-
-		// stdlib handlers
-		"/debug/vars":  "Expvar package metrics",
-		"/debug/pprof": "Runtime profiling data for pprof",
-	}
-
-	var page bytes.Buffer
-	must.NoError(template.Must(template.New("debug").Parse(`
+	pageTemplate := template.Must(template.New("debug").Parse(`
 	<html>
 	<body>
 	<ul>
@@ -73,9 +86,15 @@ func RunHandler(ctx context.Context, addr string, r prometheus.Registerer, l *za
 	</ul>
 	</body>
 	</html>
-	`)).Execute(&page, handlers))
+	`))
 
 	http.HandleFunc("/debug", func(rw http.ResponseWriter, _ *http.Request) {
+		handlersM.Lock()
+		defer handlersM.Unlock()
+
+		var page bytes.Buffer
+		must.NoError(pageTemplate.Execute(&page, handlers))
+
 		rw.Write(page.Bytes())
 	})
 
@@ -135,12 +135,12 @@ func (msg *OpMsg) readFrom(bufr *bufio.Reader) error {
 
 		switch section.Kind {
 		case 0:
-			var doc bson.Document
-			if err := doc.ReadFrom(bufr); err != nil {
+			raw, err := bson.ReadRawDocument(bufr)
+			if err != nil {
 				return lazyerrors.Error(err)
 			}
 
-			d, err := types.ConvertDocument(&doc)
+			d, err := raw.Convert()
 			if err != nil {
 				return lazyerrors.Error(err)
 			}
@@ -161,25 +161,22 @@ func (msg *OpMsg) readFrom(bufr *bufio.Reader) error {
 				return lazyerrors.Errorf("expected %d, read %d: %w", len(sec), n, err)
 			}
 
-			secr := bufio.NewReader(bytes.NewReader(sec))
-
-			var id bson.CString
-			if err := id.ReadFrom(secr); err != nil {
-				return lazyerrors.Error(err)
+			// documents are decoded directly from the section's bytes without intermediate readers
+			id, docs, ok := bytes.Cut(sec, []byte{0})
+			if !ok {
+				return lazyerrors.Errorf("wire.OpMsg.readFrom: %w", io.ErrUnexpectedEOF)
 			}
 			section.Identifier = string(id)
 
-			for {
-				if _, err := secr.Peek(1); err == io.EOF {
-					break
-				}
+			for len(docs) > 0 {
+				var raw bson.RawDocument
+				var err error
 
-				var doc bson.Document
-				if err := doc.ReadFrom(secr); err != nil {
+				if raw, docs, err = bson.CutRawDocument(docs); err != nil {
 					return lazyerrors.Error(err)
 				}
 
-				d, err := types.ConvertDocument(&doc)
+				d, err := raw.Convert()
 				if err != nil {
 					return lazyerrors.Error(err)
 				}
@@ -14,3 +14,34 @@
 
 // Package wire provides wire protocol implementation.
 package wire
+
+import (
+	"github.com/FerretDB/FerretDB/internal/bson"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// MarshalDocument returns BSON encoding of the given document.
+func MarshalDocument(doc *types.Document) ([]byte, error) {
+	d, err := bson.ConvertDocument(doc)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	b, err := d.MarshalBinary()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return b, nil
+}
+
+// DocumentSize returns the size of the given document in BSON encoding.
+func DocumentSize(doc *types.Document) (int, error) {
+	b, err := MarshalDocument(doc)
+	if err != nil {
+		return 0, lazyerrors.Error(err)
+	}
+
+	return len(b), nil
+}